@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	audioBucketName = videoBucketName
+	// 同じ動画の音声を何度も変換しないように、アップロードしたURLをこの間キャッシュする
+	audioURLCacheTTL = 24 * time.Hour
+)
+
+type AudioURLJsonType struct {
+	URL string `json:"url"`
+}
+
+func audioKey(videoID, format string) string {
+	return "audio/" + videoID + "." + format
+}
+
+func audioURLCacheKey(videoID, format string) string {
+	return "audio" + domain.IDSeparator + videoID + domain.IDSeparator + format
+}
+
+var audioCodecs = map[string]struct {
+	codec       string
+	contentType string
+}{
+	domain.AudioFormatMP3: {codec: "libmp3lame", contentType: "audio/mpeg"},
+	domain.AudioFormatAAC: {codec: "aac", contentType: "audio/aac"},
+}
+
+// テストでffmpegの実行と保存先へのアップロードを差し替えられるようにしている
+var (
+	extractAudioFFmpeg = (*Infrastructure).ffmpegFromObjectURL
+	uploadAudio        = (*Infrastructure).uploadObject
+)
+
+// 動画から映像を除いた音声ファイルを作って保存し、そのURLを返す
+// ダウンロードと同じレート制限で回数を数える
+func (i *Infrastructure) ExtractAudio(ctx context.Context, videoID, userID, format string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "ExtractAudio")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID), attribute.String("format", format))
+	defer func() { endSpan(span, err) }()
+
+	err = domain.ValidateAudioFormat(format)
+	if err != nil {
+		return "", err
+	}
+	err = i.TakeDownloadRateLimit(ctx, userID, "")
+	if err != nil {
+		return "", err
+	}
+
+	return i.getOrExtractAudio(ctx, videoID, format)
+}
+
+func (i *Infrastructure) getOrExtractAudio(ctx context.Context, videoID, format string) (string, error) {
+	cacheKey := audioURLCacheKey(videoID, format)
+	var cached AudioURLJsonType
+	hit, err := i.getFromCache(ctx, "audio_url", cacheKey, &cached)
+	if err != nil {
+		return "", err
+	} else if hit {
+		return cached.URL, nil
+	}
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+
+	tempDir, err := os.MkdirTemp("", "audio_*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	audio := audioCodecs[format]
+	outPath := filepath.Join(tempDir, videoID+"."+format)
+	_, err = extractAudioFFmpeg(i, ctx, "audio", video.VideoUrl, []string{"-i", ffmpegInput, "-vn", "-c:a", audio.codec, outPath, "-y"})
+	if err != nil {
+		return "", err
+	}
+
+	key := audioKey(videoID, format)
+	err = uploadAudio(i, ctx, outPath, audioBucketName, key, audio.contentType)
+	if err != nil {
+		return "", err
+	}
+
+	url := i.objectURL(audioBucketName, key)
+	err = setToRedis(ctx, i.redis, cacheKey, audioURLCacheTTL, &AudioURLJsonType{URL: url})
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
@@ -0,0 +1,123 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_音声の抽出(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		wantCodec string
+		wantType  string
+		wantErr   error
+	}{
+		{name: "mp3", format: "mp3", wantCodec: "libmp3lame", wantType: "audio/mpeg"},
+		{name: "aac", format: "aac", wantCodec: "aac", wantType: "audio/aac"},
+		{name: "対応していない形式", format: "flac", wantErr: domain.ErrUnsupportedAudioFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ffmpegArgs [][]string
+			var uploaded []string
+			originalFFmpeg, originalUpload := extractAudioFFmpeg, uploadAudio
+			t.Cleanup(func() { extractAudioFFmpeg, uploadAudio = originalFFmpeg, originalUpload })
+			extractAudioFFmpeg = func(i *Infrastructure, ctx context.Context, operation, url string, args []string) ([]byte, error) {
+				ffmpegArgs = append(ffmpegArgs, args)
+				return nil, nil
+			}
+			uploadAudio = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+				uploaded = append(uploaded, bucketName+"/"+key+" "+contentType)
+				return nil
+			}
+
+			_, client := newTestRedis(t)
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row[:len(row)-2]}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:      &db.DB{Database: sqlc.New(sqlDB)},
+				redis:   client,
+				storage: NewMemoryBackend(),
+				config:  InfrastructureConfig{DownloadRateLimit: RateLimitConfig{Window: time.Hour, Max: 10}},
+			}
+
+			// 2回目はキャッシュしたURLを返し、変換し直さない
+			for n := 0; n < 2; n++ {
+				got, err := i.ExtractAudio(context.Background(), "video_1", "user_1", tt.format)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Infrastructure.ExtractAudio() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr != nil {
+					if len(ffmpegArgs) != 0 {
+						t.Errorf("ffmpeg args = %v, want none", ffmpegArgs)
+					}
+					return
+				}
+				if want := "memory:///audio/video_1." + tt.format; got != want {
+					t.Errorf("Infrastructure.ExtractAudio() = %v, want %v", got, want)
+				}
+			}
+
+			if len(ffmpegArgs) != 1 {
+				t.Fatalf("ffmpeg was run %d times, want 1", len(ffmpegArgs))
+			}
+			args := ffmpegArgs[0]
+			if want := []string{"-i", ffmpegInput, "-vn", "-c:a", tt.wantCodec}; !reflect.DeepEqual(args[:5], want) {
+				t.Errorf("ffmpeg args = %v, want %v", args, want)
+			}
+			if want := []string{"video/audio/video_1." + tt.format + " " + tt.wantType}; !reflect.DeepEqual(uploaded, want) {
+				t.Errorf("uploaded = %v, want %v", uploaded, want)
+			}
+		})
+	}
+}
+
+func Test_音声の抽出のレート制限(t *testing.T) {
+	originalFFmpeg, originalUpload := extractAudioFFmpeg, uploadAudio
+	t.Cleanup(func() { extractAudioFFmpeg, uploadAudio = originalFFmpeg, originalUpload })
+	extractAudioFFmpeg = func(i *Infrastructure, ctx context.Context, operation, url string, args []string) ([]byte, error) {
+		return nil, nil
+	}
+	uploadAudio = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+		return nil
+	}
+
+	_, client := newTestRedis(t)
+	row := relatedVideoRow("video_1", 0, "")
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row[:len(row)-2]}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB)},
+		redis:   client,
+		storage: NewMemoryBackend(),
+		config:  InfrastructureConfig{DownloadRateLimit: RateLimitConfig{Window: time.Hour, Max: 1}},
+	}
+
+	ctx := context.Background()
+	_, err := i.ExtractAudio(ctx, "video_1", "user_1", domain.AudioFormatMP3)
+	if err != nil {
+		t.Fatalf("Infrastructure.ExtractAudio() error = %v", err)
+	}
+	// ダウンロードと同じ回数で数える
+	err = i.TakeDownloadRateLimit(ctx, "user_1", "")
+	if !errors.Is(err, domain.ErrDownloadRateLimitExceeded) {
+		t.Errorf("Infrastructure.TakeDownloadRateLimit() error = %v, want %v", err, domain.ErrDownloadRateLimitExceeded)
+	}
+	_, err = i.ExtractAudio(ctx, "video_1", "user_1", domain.AudioFormatAAC)
+	if !errors.Is(err, domain.ErrDownloadRateLimitExceeded) {
+		t.Errorf("Infrastructure.ExtractAudio() error = %v, want %v", err, domain.ErrDownloadRateLimitExceeded)
+	}
+}
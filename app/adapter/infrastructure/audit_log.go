@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 操作と同じトランザクションで書き込めるようにQueriesを受け取る
+func recordAuditLog(ctx context.Context, q *sqlc.Queries, entry domain.AuditEntry) error {
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	_, err := q.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     entry.Action,
+		ActorID:    entry.ActorID,
+		BeforeJson: auditJSONToDB(entry.Before),
+		AfterJson:  auditJSONToDB(entry.After),
+		OccurredAt: entry.OccurredAt,
+	})
+	return err
+}
+
+// 動画の監査ログを作って同じトランザクションで書き込む
+func recordVideoAuditLog(ctx context.Context, q *sqlc.Queries, videoID, action, actorID string, before, after any) error {
+	entry, err := domain.NewAuditEntry(domain.AuditEntityVideo, videoID, action, actorID, before, after)
+	if err != nil {
+		return err
+	}
+	return recordAuditLog(ctx, q, entry)
+}
+
+// 動画の行をロックしてからupdateで変更し、変更の前後の動画を同じトランザクションで監査ログに残す
+// 変更した後の動画はDBから取得し直すので、updateの中で変わった列も全て記録できる
+func updateVideoWithAuditLog(ctx context.Context, q *sqlc.Queries, videoID, action, actorID string, update func(before sqlc.Video) error) error {
+	before, err := q.GetVideoForUpdate(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+	}
+	if err != nil {
+		return err
+	}
+	err = update(before)
+	if err != nil {
+		return err
+	}
+	after, err := q.GetVideoForUpdate(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	return recordVideoAuditLog(ctx, q, videoID, action, actorID, newVideoFromDB(before), newVideoFromDB(after))
+}
+
+// サーバーが行った動画の更新を、監査ログにAuditActorSystemを残して1つのトランザクションで書き込む
+func (i *Infrastructure) updateVideoAsSystem(ctx context.Context, videoID, action string, update func(q *sqlc.Queries) error) error {
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, action, domain.AuditActorSystem, func(sqlc.Video) error {
+			return update(q)
+		})
+	})
+}
+
+// 条件付きのUPDATEで行が変わらなかった場合に、監査ログを残さずにトランザクションを取り消すために使う
+var errVideoNotUpdated = errors.New("video not updated")
+
+func requireVideoUpdated(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errVideoNotUpdated
+	}
+	return nil
+}
+
+// タグの監査ログに残す値
+type tagAuditValue struct {
+	ID   int32
+	Name string
+}
+
+func recordTagAuditLog(ctx context.Context, q *sqlc.Queries, tagID int32, action, actorID string, before, after any) error {
+	entry, err := domain.NewAuditEntry(domain.AuditEntityTag, strconv.Itoa(int(tagID)), action, actorID, before, after)
+	if err != nil {
+		return err
+	}
+	return recordAuditLog(ctx, q, entry)
+}
+
+// 新しい操作から順に返す
+func (i *Infrastructure) GetAuditLogsForVideo(ctx context.Context, videoID string, page domain.Page) (_ []*domain.AuditEntry, err error) {
+	ctx, span := infraSpan(ctx, "GetAuditLogsForVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.Int("offset", page.Offset), attribute.Int("limit", page.Limit))
+	defer func() { endSpan(span, err) }()
+
+	err = page.Validate()
+	if err != nil {
+		return nil, err
+	}
+	logs, err := i.db.Database.GetAuditLogsByEntity(ctx, sqlc.GetAuditLogsByEntityParams{
+		EntityType: domain.AuditEntityVideo,
+		EntityID:   videoID,
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.AuditEntry, 0, len(logs))
+	for _, l := range logs {
+		entries = append(entries, &domain.AuditEntry{
+			ID:         l.ID,
+			EntityType: l.EntityType,
+			EntityID:   l.EntityID,
+			Action:     l.Action,
+			ActorID:    l.ActorID,
+			Before:     auditJSONFromDB(l.BeforeJson),
+			After:      auditJSONFromDB(l.AfterJson),
+			OccurredAt: l.OccurredAt,
+		})
+	}
+	return entries, nil
+}
+
+func auditJSONToDB(v json.RawMessage) sql.NullString {
+	if len(v) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(v), Valid: true}
+}
+
+func auditJSONFromDB(v sql.NullString) json.RawMessage {
+	if !v.Valid {
+		return nil
+	}
+	return json.RawMessage(v.String)
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_監査ログの記録(t *testing.T) {
+	connector := &rowConnector{}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	entry, err := domain.NewAuditEntry(domain.AuditEntityVideo, "video_1", domain.AuditActionHardDeleteVideo, "admin_1", map[string]string{"title": "old"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = recordAuditLog(context.Background(), i.db.Database, entry)
+	if err != nil {
+		t.Fatalf("recordAuditLog() error = %v", err)
+	}
+	// 削除の後の値はNULLにする
+	want := []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionHardDeleteVideo, "admin_1", `{"title":"old"}`, nil}
+	if got := connector.execs[0][:6]; !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateAuditLog args = %v, want %v", got, want)
+	}
+}
+
+func Test_動画の監査ログの取得(t *testing.T) {
+	occurredAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetAuditLogsByEntity": {
+			{int64(2), domain.AuditEntityVideo, "video_1", domain.AuditActionUpdateVideo, "user_1", `{"Title":"old"}`, `{"Title":"new"}`, occurredAt},
+			{int64(1), domain.AuditEntityVideo, "video_1", domain.AuditActionInsertVideo, "user_1", nil, `{"Title":"old"}`, occurredAt},
+		},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	got, err := i.GetAuditLogsForVideo(context.Background(), "video_1", domain.NewPage(0, 20))
+	if err != nil {
+		t.Fatalf("Infrastructure.GetAuditLogsForVideo() error = %v", err)
+	}
+	want := []*domain.AuditEntry{
+		{ID: 2, EntityType: domain.AuditEntityVideo, EntityID: "video_1", Action: domain.AuditActionUpdateVideo, ActorID: "user_1", Before: json.RawMessage(`{"Title":"old"}`), After: json.RawMessage(`{"Title":"new"}`), OccurredAt: occurredAt},
+		{ID: 1, EntityType: domain.AuditEntityVideo, EntityID: "video_1", Action: domain.AuditActionInsertVideo, ActorID: "user_1", After: json.RawMessage(`{"Title":"old"}`), OccurredAt: occurredAt},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Infrastructure.GetAuditLogsForVideo() = %v, want %v", got, want)
+	}
+
+	_, err = i.GetAuditLogsForVideo(context.Background(), "video_1", domain.NewPage(0, 0))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.GetAuditLogsForVideo() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+}
@@ -0,0 +1,134 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const bookmarkCacheTTL = 5 * time.Minute
+
+type BookmarkJsonType struct {
+	Bookmarked bool `json:"bookmarked"`
+}
+
+func bookmarkKey(videoID, userID string) string {
+	return "bookmark" + domain.IDSeparator + videoID + domain.IDSeparator + userID
+}
+
+// すでにブックマークしている場合は何もしない
+func (i *Infrastructure) BookmarkVideo(ctx context.Context, videoID, userID string) (err error) {
+	ctx, span := infraSpan(ctx, "BookmarkVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	bookmarked, err := i.IsVideoBookmarked(ctx, videoID, userID)
+	if err != nil {
+		return err
+	}
+	if bookmarked {
+		return nil
+	}
+
+	count, err := i.db.Database.CountBookmarksByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= i.config.MaxBookmarksPerUser {
+		return fmt.Errorf("%w: %s already has %d bookmarks", domain.ErrTooManyBookmarks, userID, count)
+	}
+
+	_, err = i.db.Database.CreateBookmark(ctx, sqlc.CreateBookmarkParams{
+		ID:        domain.NewUUID(),
+		UserID:    userID,
+		VideoID:   videoID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return setToRedis(ctx, i.redis, bookmarkKey(videoID, userID), bookmarkCacheTTL, &BookmarkJsonType{Bookmarked: true})
+}
+
+func (i *Infrastructure) UnbookmarkVideo(ctx context.Context, videoID, userID string) (err error) {
+	ctx, span := infraSpan(ctx, "UnbookmarkVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.db.Database.DeleteBookmark(ctx, sqlc.DeleteBookmarkParams{
+		UserID:  userID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return setToRedis(ctx, i.redis, bookmarkKey(videoID, userID), bookmarkCacheTTL, &BookmarkJsonType{Bookmarked: false})
+}
+
+func (i *Infrastructure) IsVideoBookmarked(ctx context.Context, videoID, userID string) (_ bool, err error) {
+	ctx, span := infraSpan(ctx, "IsVideoBookmarked")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	var bookmark BookmarkJsonType
+	hit, err := i.getFromCache(ctx, "bookmark", bookmarkKey(videoID, userID), &bookmark)
+	if err != nil {
+		return false, err
+	} else if hit {
+		return bookmark.Bookmarked, nil
+	}
+
+	count, err := i.db.Database.CountBookmark(ctx, sqlc.CountBookmarkParams{
+		UserID:  userID,
+		VideoID: videoID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	bookmark = BookmarkJsonType{Bookmarked: count > 0}
+	err = setToRedis(ctx, i.redis, bookmarkKey(videoID, userID), bookmarkCacheTTL, &bookmark)
+	if err != nil {
+		return false, err
+	}
+	return bookmark.Bookmarked, nil
+}
+
+// 新しくブックマークした順に返す。非公開になった動画は投稿者本人のブックマークにのみ含める
+func (i *Infrastructure) GetBookmarkedVideosByUser(ctx context.Context, userID string, page domain.Page) (_ []*domain.Video, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetBookmarkedVideosByUser")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := i.db.Database.CountBookmarkedVideosByUser(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dbVideos, err := i.db.Database.GetBookmarkedVideosByUser(ctx, sqlc.GetBookmarkedVideosByUserParams{
+		UserID: userID,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return newVideosWithTags(dbVideos, tags), total, nil
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ブックマークの上限(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int64
+		wantErr error
+	}{
+		{name: "上限の1つ手前", count: 2},
+		{name: "上限に達している", count: 3, wantErr: domain.ErrTooManyBookmarks},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			connector := &rowConnector{values: []driver.Value{tt.count}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB)},
+				redis:  client,
+				config: InfrastructureConfig{MaxBookmarksPerUser: 3},
+			}
+			ctx := context.Background()
+			// DBの件数に関わらずブックマークしていない状態から始める
+			err := setToRedis(ctx, client, bookmarkKey("video_1", "user_1"), bookmarkCacheTTL, &BookmarkJsonType{Bookmarked: false})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = i.BookmarkVideo(ctx, "video_1", "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.BookmarkVideo() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := i.IsVideoBookmarked(ctx, "video_1", "user_1")
+			if err != nil {
+				t.Fatalf("Infrastructure.IsVideoBookmarked() error = %v", err)
+			}
+			if got != (tt.wantErr == nil) {
+				t.Errorf("Infrastructure.IsVideoBookmarked() = %v, want %v", got, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func Test_ブックマーク状態のキャッシュ(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(1)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:     &db.DB{Database: sqlc.New(sqlDB)},
+		redis:  client,
+		config: InfrastructureConfig{MaxBookmarksPerUser: 1000},
+	}
+	ctx := context.Background()
+
+	for n := 0; n < 2; n++ {
+		got, err := i.IsVideoBookmarked(ctx, "video_1", "user_1")
+		if err != nil {
+			t.Fatalf("Infrastructure.IsVideoBookmarked() error = %v", err)
+		}
+		if !got {
+			t.Error("Infrastructure.IsVideoBookmarked() = false, want true")
+		}
+	}
+	if connector.queries != 1 {
+		t.Errorf("IsVideoBookmarked queried the database %d times, want 1", connector.queries)
+	}
+
+	// 解除するとキャッシュも更新される
+	err := i.UnbookmarkVideo(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.UnbookmarkVideo() error = %v", err)
+	}
+	got, err := i.IsVideoBookmarked(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.IsVideoBookmarked() error = %v", err)
+	}
+	if got {
+		t.Error("Infrastructure.IsVideoBookmarked() = true after unbookmark, want false")
+	}
+}
@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// userIDの全ての動画の公開設定を1回のUPDATEで変更し、変更した行数を返す
+// publish_atは変えないため、公開予約はそのまま残る
+// キャッシュはコミットした後に消す
+// 変更した動画ごとに監査ログを残す
+// 本人か管理者のみ変更できる
+func (i *Infrastructure) SetAllVideosByUserPrivacy(ctx context.Context, userID string, isPrivate bool, requestingUserID string) (_ int64, err error) {
+	ctx, span := infraSpan(ctx, "SetAllVideosByUserPrivacy")
+	span.SetAttributes(attribute.String("userID", userID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	if userID != requestingUserID && !i.IsAdmin(requestingUserID) {
+		return 0, fmt.Errorf("%w: %s cannot change the videos of %s", domain.ErrPermissionDenied, requestingUserID, userID)
+	}
+
+	var videos []sqlc.Video
+	var affected int64
+	now := time.Now()
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		// キャッシュを消す動画と監査ログに残す変更前の値を、更新と同じ行のロックを取って決める
+		videos, err = q.ListVideosByUploaderForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		result, err := q.SetVideosPrivacyByUploader(ctx, sqlc.SetVideosPrivacyByUploaderParams{
+			IsPrivate:  isPrivate,
+			UpdatedAt:  now,
+			UploaderID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		for _, video := range videos {
+			before := newVideoFromDB(video)
+			after := *before
+			after.IsPrivate = isPrivate
+			after.UpdatedAt = now
+			err = recordVideoAuditLog(ctx, q, video.ID, domain.AuditActionSetVideoPrivacy, requestingUserID, before, &after)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	videoIDs := make([]string, 0, len(videos))
+	for _, video := range videos {
+		videoIDs = append(videoIDs, video.ID)
+	}
+	i.invalidateVideoCache(ctx, videoIDs...)
+	return affected, nil
+}
@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ユーザーの全動画の公開設定の変更(t *testing.T) {
+	publishAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		requestingUserID string
+		wantErr          error
+	}{
+		{name: "本人", requestingUserID: "user_1"},
+		{name: "管理者", requestingUserID: "admin_1"},
+		{name: "他のユーザー", requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			video1 := relatedVideoRow("video_1", 0, "")
+			video1[23] = publishAt
+			video2 := relatedVideoRow("video_2", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"ListVideosByUploaderForUpdate": {video1[:len(video1)-2], video2[:len(video2)-2]},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:  client,
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			ctx := context.Background()
+			for _, videoID := range []string{"video_1", "video_2", "video_3"} {
+				client.Set(ctx, relatedVideosKey(videoID, 10), "[]", time.Hour)
+				client.Set(ctx, downloadCountKey(videoID), "{}", time.Hour)
+			}
+
+			got, err := i.SetAllVideosByUserPrivacy(ctx, "user_1", true, tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SetAllVideosByUserPrivacy() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 || connector.commits != 0 {
+					t.Errorf("execs = %v, commits = %d, want no changes", connector.execNames, connector.commits)
+				}
+				return
+			}
+			if got != 1 {
+				t.Errorf("Infrastructure.SetAllVideosByUserPrivacy() = %d, want 1", got)
+			}
+
+			// 動画ごとに更新せず1回のUPDATEで変更し、監査ログは動画ごとに残す
+			wantNames := []string{"SetVideosPrivacyByUploader", "CreateAuditLog", "CreateAuditLog"}
+			if !reflect.DeepEqual(connector.execNames, wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+			}
+			args := connector.execs[0]
+			if len(args) != 3 || args[0] != true || args[2] != "user_1" {
+				t.Errorf("SetVideosPrivacyByUploader args = %v, want [true _ user_1]", args)
+			}
+			for n, videoID := range []string{"video_1", "video_2"} {
+				want := []driver.Value{domain.AuditEntityVideo, videoID, domain.AuditActionSetVideoPrivacy, tt.requestingUserID}
+				if got := connector.execs[n+1][:4]; !reflect.DeepEqual(got, want) {
+					t.Errorf("CreateAuditLog args = %v, want %v", got, want)
+				}
+			}
+			// 公開予約は取り消さない
+			var after domain.Video
+			if json.Unmarshal([]byte(connector.execs[1][5].(string)), &after) != nil {
+				t.Fatalf("CreateAuditLog after = %v, want json", connector.execs[1][5])
+			}
+			if !after.IsPrivate || after.PublishAt == nil || !after.PublishAt.Equal(publishAt) {
+				t.Errorf("CreateAuditLog after is_private = %v, publish_at = %v, want true and %v", after.IsPrivate, after.PublishAt, publishAt)
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+
+			// 関連動画は他の動画のキャッシュにも含まれるため全て消す
+			for videoID, want := range map[string]int64{"video_1": 0, "video_2": 0, "video_3": 1} {
+				if n, _ := client.Exists(ctx, relatedVideosKey(videoID, 10)).Result(); n != 0 {
+					t.Errorf("related videos cache of %s exists = %d, want 0", videoID, n)
+				}
+				if n, _ := client.Exists(ctx, downloadCountKey(videoID)).Result(); n != want {
+					t.Errorf("download count cache of %s exists = %d, want %d", videoID, n, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 動画の情報が変わったことを全てのインスタンスに伝えるチャンネル
+// メッセージは,で区切った動画のIDで、空の場合は一覧のキャッシュだけを消す
+const videoCacheInvalidationChannel = "cache-invalidate:video"
+
+// 動画の情報が変わったときに、StartCacheInvalidationSubscriberを動かしている全てのインスタンスにキャッシュを消させる
+// videoIDsが空の場合は一覧のキャッシュだけを消す
+// 購読しているインスタンスがない場合や伝えられなかった場合は、このインスタンスで消す
+// キャッシュは有効期限でも更新されるため、消すのに失敗しても変更は失敗にしない
+// テストで作成したInfrastructureはRedisを持たないことがあるため消さない
+func (i *Infrastructure) invalidateVideoCache(ctx context.Context, videoIDs ...string) {
+	if i.redis == nil {
+		return
+	}
+
+	receivers, err := i.redis.Publish(ctx, videoCacheInvalidationChannel, strings.Join(videoIDs, ",")).Result()
+	if err == nil && receivers > 0 {
+		return
+	}
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to publish video cache invalidation", "videoIDs", videoIDs, "error", err)
+	}
+	err = i.deleteVideoCache(ctx, videoIDs...)
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to delete video cache", "videoIDs", videoIDs, "error", err)
+	}
+}
+
+// invalidateVideoCacheで伝えられた動画のキャッシュを消す。ctxが終わるまで戻らない
+// Redisとの接続が切れた場合はgo-redisが再接続して購読し直す
+func (i *Infrastructure) StartCacheInvalidationSubscriber(ctx context.Context) {
+	pubsub := i.redis.Subscribe(ctx, videoCacheInvalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var videoIDs []string
+			if message.Payload != "" {
+				videoIDs = strings.Split(message.Payload, ",")
+			}
+			err := i.deleteVideoCache(ctx, videoIDs...)
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to delete video cache", "videoIDs", videoIDs, "error", err)
+			}
+		}
+	}
+}
+
+// 動画の情報から作ったキャッシュを消す
+// まだDBに反映していない再生回数や、視聴済みかどうかなどのユーザーごとの記録は消さない
+func (i *Infrastructure) deleteVideoCache(ctx context.Context, videoIDs ...string) (err error) {
+	ctx, span := infraSpan(ctx, "deleteVideoCache")
+	span.SetAttributes(attribute.String("videoIDs", strings.Join(videoIDs, ",")))
+	defer func() { endSpan(span, err) }()
+
+	var keys []string
+	var patterns []string
+	for _, videoID := range videoIDs {
+		keys = append(keys,
+			"watchcount"+domain.IDSeparator+videoID,
+			downloadCountKey(videoID),
+			reactionCountsKey(videoID),
+			videoAnalysisKey(videoID),
+		)
+		patterns = append(patterns, "audio"+domain.IDSeparator+videoID+domain.IDSeparator+"*")
+	}
+	if len(keys) > 0 {
+		err = i.redis.Del(ctx, keys...).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	// 一覧のキャッシュはどの動画を含むかわからないため全て消す
+	// 関連動画は他の動画のキャッシュにも含まれる
+	patterns = append(patterns,
+		"related"+domain.IDSeparator+"*",
+		"trending"+domain.IDSeparator+"*",
+	)
+	for _, pattern := range patterns {
+		err = deleteFromRedisByPattern(ctx, i.redis, pattern)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
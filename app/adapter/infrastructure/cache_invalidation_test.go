@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func Test_動画のキャッシュの無効化(t *testing.T) {
+	tests := []struct {
+		name     string
+		videoIDs []string
+		want     []string
+	}{
+		{
+			name:     "動画のキャッシュと一覧のキャッシュ",
+			videoIDs: []string{"video_1", "video_2"},
+			want:     []string{"bookmark_video_1_user_1", "watchcount:video_1", "watchcount_video_3"},
+		},
+		{
+			name: "一覧のキャッシュのみ",
+			want: []string{"analysis_video_1", "audio_video_1_mp3", "bookmark_video_1_user_1", "downloadcount_video_1", "reaction_video_1", "watchcount:video_1", "watchcount_video_1", "watchcount_video_2", "watchcount_video_3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			i := &Infrastructure{redis: client}
+			keys := []string{
+				"watchcount_video_1",
+				"downloadcount_video_1",
+				"reaction_video_1",
+				"analysis_video_1",
+				"audio_video_1_mp3",
+				"watchcount_video_2",
+				// 関連動画と急上昇は他の動画を含むため全て消す
+				"related_video_1_10",
+				"related_video_3_10",
+				"trending_24h0m0s_10",
+				// 消さないキー
+				"watchcount:video_1",
+				"bookmark_video_1_user_1",
+				"watchcount_video_3",
+			}
+			for _, key := range keys {
+				mr.Set(key, "{}")
+			}
+
+			i.invalidateVideoCache(context.Background(), tt.videoIDs...)
+			got := mr.Keys()
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_他のインスタンスのキャッシュの無効化(t *testing.T) {
+	mr, writerClient := newTestRedis(t)
+	// 同じRedisに別の接続を持つインスタンス
+	subscriberClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { subscriberClient.Close() })
+	writer := &Infrastructure{redis: writerClient}
+	subscriber := &Infrastructure{redis: subscriberClient}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		subscriber.StartCacheInvalidationSubscriber(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for mr.PubSubNumSub(videoCacheInvalidationChannel)[videoCacheInvalidationChannel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscriber did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	mr.Set("watchcount_video_1", "{}")
+	mr.Set("related_video_2_10", "{}")
+	mr.Set("watchcount_video_2", "{}")
+	writer.invalidateVideoCache(context.Background(), "video_1")
+
+	// 購読しているインスタンスが消す
+	for mr.Exists("watchcount_video_1") || mr.Exists("related_video_2_10") {
+		if time.Now().After(deadline) {
+			t.Fatalf("keys = %v, want cache of video_1 deleted by the subscriber", mr.Keys())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !mr.Exists("watchcount_video_2") {
+		t.Error("cache of video_2 should not be deleted")
+	}
+}
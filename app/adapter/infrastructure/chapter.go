@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func chaptersKey(videoID string) string {
+	return "chapters/" + videoID + ".vtt"
+}
+
+// テストで保存先へのアップロードとダウンロードを差し替えられるようにしている
+var (
+	uploadChapters   = (*Infrastructure).uploadVTT
+	downloadChapters = (*Infrastructure).downloadObject
+)
+
+// チャプターをWebVTTにしてS3に保存し、そのURLを返す
+// chaptersが空の場合はチャプターを外して空文字を返す
+func (i *Infrastructure) SetVideoChapters(ctx context.Context, videoID string, chapters []domain.Chapter, requestingUserID string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "SetVideoChapters")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.Int("chapters", len(chapters)), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	duration := time.Duration(video.DurationMs) * time.Millisecond
+	err = domain.ValidateChapters(chapters, duration)
+	if err != nil {
+		return "", err
+	}
+
+	url := ""
+	if len(chapters) > 0 {
+		url, err = uploadChapters(i, ctx, chaptersKey(videoID), formatChaptersVTT(chapters, duration))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionSetVideoChapters, requestingUserID, func(sqlc.Video) error {
+			_, err := q.UpdateVideoChaptersURL(ctx, sqlc.UpdateVideoChaptersURLParams{
+				ChaptersUrl: sql.NullString{String: url, Valid: url != ""},
+				ID:          videoID,
+			})
+			return err
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return url, nil
+}
+
+// S3に保存したWebVTTからチャプターを読み込む。設定されていない場合は空のスライスを返す
+func (i *Infrastructure) GetVideoChapters(ctx context.Context, videoID string) (_ []domain.Chapter, err error) {
+	ctx, span := infraSpan(ctx, "GetVideoChapters")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if !video.ChaptersUrl.Valid || video.ChaptersUrl.String == "" {
+		return []domain.Chapter{}, nil
+	}
+
+	body, err := downloadChapters(i, ctx, video.ChaptersUrl.String)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return parseChaptersVTT(body)
+}
+
+// 各チャプターは次のチャプターの開始位置まで、最後のチャプターは動画の終わりまでのキューにする
+func formatChaptersVTT(chapters []domain.Chapter, duration time.Duration) []byte {
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n\n")
+	for n, chapter := range chapters {
+		start := time.Duration(chapter.StartSeconds) * time.Second
+		end := start + time.Second
+		if n+1 < len(chapters) {
+			end = time.Duration(chapters[n+1].StartSeconds) * time.Second
+		} else if duration > start {
+			end = duration
+		}
+		// キューの本文には改行と-->を書けない
+		title := strings.Join(strings.Fields(chapter.Title), " ")
+		title = strings.ReplaceAll(title, "-->", "--&gt;")
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", vttTimestamp(start), vttTimestamp(end), title)
+	}
+	return b.Bytes()
+}
+
+func vttTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+var vttCueTimingPattern = regexp.MustCompile(`^(?:(\d+):)?(\d{2}):(\d{2})\.\d{3} -->`)
+
+// キューの開始位置の秒とその次の行からチャプターを作る
+func parseChaptersVTT(r io.Reader) ([]domain.Chapter, error) {
+	chapters := []domain.Chapter{}
+	scanner := bufio.NewScanner(r)
+	var current *domain.Chapter
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := vttCueTimingPattern.FindStringSubmatch(line); m != nil {
+			hours, _ := strconv.Atoi(m[1])
+			minutes, _ := strconv.Atoi(m[2])
+			seconds, _ := strconv.Atoi(m[3])
+			chapters = append(chapters, domain.Chapter{StartSeconds: hours*3600 + minutes*60 + seconds})
+			current = &chapters[len(chapters)-1]
+			continue
+		}
+		if line == "" {
+			current = nil
+			continue
+		}
+		if current != nil {
+			current.Title = strings.TrimSpace(current.Title + " " + line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return chapters, nil
+}
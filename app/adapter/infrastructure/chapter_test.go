@@ -0,0 +1,127 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func chapterTestVideoRow(durationMs int64, chaptersURL driver.Value) []driver.Value {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[13] = durationMs
+	row[len(row)-7] = chaptersURL
+	return row
+}
+
+func Test_チャプターの設定(t *testing.T) {
+	tests := []struct {
+		name     string
+		chapters []domain.Chapter
+		wantVTT  string
+		wantArgs []driver.Value
+		wantErr  error
+	}{
+		{
+			name:     "WebVTTにして保存する",
+			chapters: []domain.Chapter{{Title: "はじめに", StartSeconds: 0}, {Title: "本編\n--> 後半", StartSeconds: 90}},
+			wantVTT:  "WEBVTT\n\n00:00:00.000 --> 00:01:30.000\nはじめに\n\n00:01:30.000 --> 01:00:00.000\n本編 --&gt; 後半\n\n",
+			wantArgs: []driver.Value{"https://s3.example.com/video/chapters/video_1.vtt", "video_1"},
+		},
+		{name: "空の場合は外す", chapters: nil, wantArgs: []driver.Value{nil, "video_1"}},
+		{name: "順番が不正", chapters: []domain.Chapter{{Title: "a", StartSeconds: 90}, {Title: "b", StartSeconds: 0}}, wantErr: domain.ErrInvalidChapters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploaded string
+			original := uploadChapters
+			t.Cleanup(func() { uploadChapters = original })
+			uploadChapters = func(i *Infrastructure, ctx context.Context, key string, vtt []byte) (string, error) {
+				uploaded = string(vtt)
+				return "https://s3.example.com/video/" + key, nil
+			}
+
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo": {chapterTestVideoRow(3600000, nil)},
+				"GetVideoForUpdate": {chapterTestVideoRow(3600000, nil)},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			got, err := i.SetVideoChapters(context.Background(), "video_1", tt.chapters, "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SetVideoChapters() error = %v, want %v", err, tt.wantErr)
+			}
+			if uploaded != tt.wantVTT {
+				t.Errorf("uploaded = %q, want %q", uploaded, tt.wantVTT)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("execs = %v, want none", connector.execs)
+				}
+				return
+			}
+			if want := []string{"UpdateVideoChaptersURL", "CreateAuditLog"}; !reflect.DeepEqual(connector.execNames, want) {
+				t.Fatalf("exec names = %v, want %v", connector.execNames, want)
+			}
+			if !reflect.DeepEqual(connector.execs[0], tt.wantArgs) {
+				t.Errorf("UpdateVideoChaptersURL args = %v, want %v", connector.execs[0], tt.wantArgs)
+			}
+			if got := connector.execs[1][:4]; !reflect.DeepEqual(got, []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionSetVideoChapters, "user_1"}) {
+				t.Errorf("CreateAuditLog args = %v", got)
+			}
+			if want, _ := tt.wantArgs[0].(string); got != want {
+				t.Errorf("Infrastructure.SetVideoChapters() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_チャプターの取得(t *testing.T) {
+	tests := []struct {
+		name        string
+		chaptersURL driver.Value
+		vtt         string
+		want        []domain.Chapter
+	}{
+		{
+			name:        "WebVTTから読み込む",
+			chaptersURL: "https://s3.example.com/video/chapters/video_1.vtt",
+			vtt:         "WEBVTT\n\n00:00:00.000 --> 00:01:30.000\nはじめに\n\n01:30.000 --> 1:00:00.000\n本編\n\n1:00:05.500 --> 1:00:10.000\n最後\n",
+			want:        []domain.Chapter{{Title: "はじめに", StartSeconds: 0}, {Title: "本編", StartSeconds: 90}, {Title: "最後", StartSeconds: 3605}},
+		},
+		{name: "設定されていない", chaptersURL: nil, want: []domain.Chapter{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := downloadChapters
+			t.Cleanup(func() { downloadChapters = original })
+			downloadChapters = func(i *Infrastructure, ctx context.Context, url string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(tt.vtt)), nil
+			}
+
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {chapterTestVideoRow(0, tt.chaptersURL)}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			got, err := i.GetVideoChapters(context.Background(), "video_1")
+			if err != nil {
+				t.Fatalf("Infrastructure.GetVideoChapters() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Infrastructure.GetVideoChapters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
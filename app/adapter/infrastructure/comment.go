@@ -3,13 +3,21 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/yuorei/video-server/app/domain"
 	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (i *Infrastructure) GetCommentsByVideoIDFromDB(ctx context.Context, videoID string) ([]*domain.Comment, error) {
+func (i *Infrastructure) GetCommentsByVideoIDFromDB(ctx context.Context, videoID string) (_ []*domain.Comment, err error) {
+	ctx, span := infraSpan(ctx, "GetCommentsByVideoIDFromDB")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
 	comment, err := i.db.Database.GetVideoComments(ctx, videoID)
 	if err != nil {
 		return nil, err
@@ -22,8 +30,63 @@ func (i *Infrastructure) GetCommentsByVideoIDFromDB(ctx context.Context, videoID
 	return comments, nil
 }
 
-func (i *Infrastructure) InsertComment(ctx context.Context, postComment *domain.Comment) (*domain.Comment, error) {
-	_, err := i.db.Database.CreateComment(ctx, sqlc.CreateCommentParams{
+// 返信も含めて投稿順に返す
+func (i *Infrastructure) GetCommentsByVideoIDFromDBPaged(ctx context.Context, videoID string, page domain.Page) (_ []*domain.Comment, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetCommentsByVideoIDFromDBPaged")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := i.db.Database.CountVideoComments(ctx, videoID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dbComments, err := i.db.Database.GetVideoCommentsPaged(ctx, sqlc.GetVideoCommentsPagedParams{
+		VideoID: videoID,
+		Limit:   int32(page.Limit),
+		Offset:  int32(page.Offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := make([]*domain.Comment, 0, len(dbComments))
+	for _, c := range dbComments {
+		comment := domain.NewComment(c.ID, c.VideoID, c.Text, c.CreatedAt, c.UpdatedAt, domain.NewUser(c.UserID.String, c.Name, "", []string{}, false, ""))
+		if c.ParentCommentID.Valid {
+			comment.ParentCommentID = &c.ParentCommentID.String
+		}
+		comments = append(comments, comment)
+	}
+	return comments, total, nil
+}
+
+func (i *Infrastructure) InsertComment(ctx context.Context, postComment *domain.Comment) (_ *domain.Comment, err error) {
+	ctx, span := infraSpan(ctx, "InsertComment")
+	defer func() { endSpan(span, err) }()
+
+	var parentCommentID sql.NullString
+	if postComment.ParentCommentID != nil {
+		// 返信先は同じ動画のコメントに限る
+		parent, err := i.getComment(ctx, *postComment.ParentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.VideoID != postComment.VideoID {
+			return nil, fmt.Errorf("%w: parent comment %s belongs to another video", domain.ErrInvalidInput, parent.ID)
+		}
+		parentCommentID = sql.NullString{
+			String: parent.ID,
+			Valid:  true,
+		}
+	}
+
+	_, err = i.db.Database.CreateComment(ctx, sqlc.CreateCommentParams{
 		ID:      postComment.ID,
 		VideoID: postComment.VideoID,
 		Text:    postComment.Text,
@@ -31,11 +94,78 @@ func (i *Infrastructure) InsertComment(ctx context.Context, postComment *domain.
 			String: postComment.User.ID,
 			Valid:  true,
 		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		ParentCommentID: parentCommentID,
 	})
 	if err != nil {
 		return nil, err
 	}
 	return postComment, nil
 }
+
+// 投稿者本人か管理者のみ削除できる。返信とその返信も一緒に削除する
+func (i *Infrastructure) DeleteComment(ctx context.Context, commentID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "DeleteComment")
+	span.SetAttributes(attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	comment, err := i.getComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID.String != requestingUserID && !i.isAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not the author of %s", domain.ErrPermissionDenied, requestingUserID, commentID)
+	}
+
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		ids, err := listCommentSubtreeIDs(ctx, q, commentID)
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteComments(ctx, ids)
+		return err
+	})
+}
+
+// 返信への返信も残らないように、commentIDとその下の全ての返信のIDを階層ごとに集める
+// 削除するまでに返信が付かないように、見つけた返信の行をロックする
+func listCommentSubtreeIDs(ctx context.Context, q *sqlc.Queries, commentID string) ([]string, error) {
+	ids := []string{commentID}
+	seen := map[string]struct{}{commentID: {}}
+	parents := []string{commentID}
+	for len(parents) > 0 {
+		parentIDs := make([]sql.NullString, 0, len(parents))
+		for _, id := range parents {
+			parentIDs = append(parentIDs, sql.NullString{String: id, Valid: true})
+		}
+		replies, err := q.ListReplyCommentIDs(ctx, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		parents = parents[:0]
+		for _, id := range replies {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+			parents = append(parents, id)
+		}
+	}
+	return ids, nil
+}
+
+func (i *Infrastructure) getComment(ctx context.Context, commentID string) (sqlc.Comment, error) {
+	comment, err := i.db.Database.GetComment(ctx, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Comment{}, fmt.Errorf("%w: comment %s not found", domain.ErrInvalidInput, commentID)
+	}
+	return comment, err
+}
+
+func (i *Infrastructure) isAdmin(userID string) bool {
+	return userID != "" && slices.Contains(i.config.AdminUserIDs, userID)
+}
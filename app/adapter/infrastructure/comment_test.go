@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_コメントの削除(t *testing.T) {
+	tests := []struct {
+		name             string
+		requestingUserID string
+		wantErr          error
+	}{
+		{name: "投稿者本人", requestingUserID: "user_1"},
+		{name: "管理者", requestingUserID: "admin_1"},
+		{name: "他のユーザー", requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+		{name: "ユーザーIDが空", requestingUserID: "", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			// id, video_id, text, created_at, updated_at, user_id, parent_comment_id
+			// comment_1への返信がcomment_2とcomment_3、comment_2への返信がcomment_4
+			connector := &rowConnector{
+				values: []driver.Value{"comment_1", "video_1", "text", now, now, "user_1", nil},
+				rowsByQuery: map[string][][]driver.Value{
+					"ListReplyCommentIDs comment_1": {{"comment_2"}, {"comment_3"}},
+					"ListReplyCommentIDs comment_2": {{"comment_4"}},
+					"ListReplyCommentIDs":           {},
+				},
+			}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			err := i.DeleteComment(context.Background(), "comment_1", tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.DeleteComment() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("exec calls = %v, want no calls", connector.execs)
+				}
+				return
+			}
+
+			wantNames := []string{"DeleteComments"}
+			if !reflect.DeepEqual(connector.execNames, wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+			}
+			want := []driver.Value{"comment_1", "comment_2", "comment_3", "comment_4"}
+			if !reflect.DeepEqual(connector.execs[0], want) {
+				t.Errorf("DeleteComments args = %v, want %v", connector.execs[0], want)
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+		})
+	}
+}
+
+func Test_コメントへの返信(t *testing.T) {
+	tests := []struct {
+		name    string
+		videoID string
+		wantErr error
+	}{
+		{name: "同じ動画のコメントへの返信", videoID: "video_1"},
+		{name: "別の動画のコメントへの返信", videoID: "video_2", wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			connector := &rowConnector{values: []driver.Value{"comment_1", "video_1", "text", now, now, "user_1", nil}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db: &db.DB{Database: sqlc.New(sqlDB)},
+			}
+
+			parentID := "comment_1"
+			reply := domain.NewPostComment("comment_2", tt.videoID, "user_2", "name", "reply")
+			reply.ParentCommentID = &parentID
+			_, err := i.InsertComment(context.Background(), reply)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.InsertComment() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// 最後の引数がparent_comment_id
+			args := connector.execs[0]
+			if args[len(args)-1] != "comment_1" {
+				t.Errorf("parent_comment_id = %v, want comment_1", args[len(args)-1])
+			}
+		})
+	}
+}
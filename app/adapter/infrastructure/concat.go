@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 一度に結合できる動画の上限
+const maxConcatVideos = 10
+
+// 複数の動画を順番に結合して新しい動画として登録する
+// 結合できるのはuploaderIDが投稿した公開中の動画のみ
+func (i *Infrastructure) ConcatenateVideos(ctx context.Context, videoIDs []string, outputTitle string, uploaderID string) (_ *domain.UploadVideoResponse, err error) {
+	ctx, span := infraSpan(ctx, "ConcatenateVideos")
+	span.SetAttributes(attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	if len(videoIDs) < 2 {
+		return nil, fmt.Errorf("%w: at least 2 videos are required", domain.ErrInvalidInput)
+	}
+	if len(videoIDs) > maxConcatVideos {
+		return nil, fmt.Errorf("%w: cannot concatenate more than %d videos", domain.ErrInvalidInput, maxConcatVideos)
+	}
+
+	// ffmpegを動かす前に全ての動画を確認する
+	now := time.Now()
+	videos := make([]*domain.Video, 0, len(videoIDs))
+	for _, videoID := range videoIDs {
+		dbVideo, err := i.getUndeletedVideo(ctx, videoID)
+		if err != nil {
+			return nil, err
+		}
+		video := newVideoFromDB(dbVideo)
+		if video.UploaderID != uploaderID {
+			return nil, fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, uploaderID, videoID)
+		}
+		if video.IsPrivate {
+			return nil, fmt.Errorf("%w: %s is private", domain.ErrPermissionDenied, videoID)
+		}
+		if video.IsExpired(now) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrVideoExpired, videoID)
+		}
+		videos = append(videos, video)
+	}
+
+	workDir, err := os.MkdirTemp("", "concat-video-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// 結合後の動画は対象年齢が最も高い元動画に合わせる
+	contentRating := domain.RatingGeneral
+	clipPaths := make([]string, 0, len(videos))
+	for n, video := range videos {
+		contentRating = domain.StricterContentRating(contentRating, video.ContentRating)
+
+		// HLSのままではconcatできないため一度ローカルのmp4にする
+		clipPath := filepath.Join(workDir, fmt.Sprintf("clip_%d.mp4", n))
+		_, err = i.ffmpegFromObjectURL(ctx, "concat", video.VideoURL, []string{"-i", ffmpegInput, "-c", "copy", clipPath, "-y"})
+		if err != nil {
+			return nil, err
+		}
+		clipPaths = append(clipPaths, clipPath)
+	}
+
+	listPath := filepath.Join(workDir, "list.txt")
+	err = os.WriteFile(listPath, []byte(buildConcatList(clipPaths)), 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	id := domain.NewVideoID()
+	tempMp4, err := localVideoPath(id)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tempMp4)
+
+	_, err = runFFmpeg(ctx, "concat", []string{"-f", "concat", "-safe", "0", "-i", listPath, "-c", "copy", tempMp4, "-y"})
+	if err != nil {
+		return nil, err
+	}
+
+	description := ""
+	return i.publishLocalVideo(ctx, id, outputTitle, &description, uploaderID, []string{}, contentRating, false, false, false)
+}
+
+// アップロード時と同じくtemp/<id>.mp4に置く
+func localVideoPath(id string) (string, error) {
+	err := os.MkdirAll("temp", 0755)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join("temp", id+".mp4"), nil
+}
+
+// サーバー上で作ったtemp/<id>.mp4をアップロード時と同じ手順でHLSに変換して登録する
+func (i *Infrastructure) publishLocalVideo(ctx context.Context, id string, title string, description *string, uploaderID string, tags []string, contentRating domain.ContentRating, isPrivate, isExternalCutout, isAd bool) (*domain.UploadVideoResponse, error) {
+	defer os.RemoveAll(filepath.Join("output", id))
+
+	source, err := localVideoPath(id)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := i.ProbeVideoMetadata(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.ConvertVideoHLS(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	videoURL, err := i.UploadVideoForStorage(ctx, domain.NewVideoFile(id, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return i.InsertVideo(ctx, id, videoURL, "", title, description, uploaderID, tags, contentRating, isPrivate, isExternalCutout, isAd, metadata, "", nil)
+}
+
+// ffmpegのconcat demuxerに渡すファイル一覧を作る
+func buildConcatList(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		// シングルクォートで囲むため、パス中のシングルクォートはエスケープする
+		fmt.Fprintf(&b, "file '%s'\n", strings.ReplaceAll(path, "'", `'\''`))
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_結合リストの生成(t *testing.T) {
+	got := buildConcatList([]string{"/tmp/clip_0.mp4", "/tmp/it's.mp4"})
+	want := "file '/tmp/clip_0.mp4'\nfile '/tmp/it'\\''s.mp4'\n"
+	if got != want {
+		t.Errorf("buildConcatList() = %q, want %q", got, want)
+	}
+}
+
+func Test_結合する動画数の上限(t *testing.T) {
+	tests := []struct {
+		name     string
+		videoIDs []string
+	}{
+		{
+			name:     "only one video",
+			videoIDs: []string{"video_1"},
+		},
+		{
+			name:     "too many videos",
+			videoIDs: make([]string, maxConcatVideos+1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Infrastructure{}
+			_, err := i.ConcatenateVideos(context.Background(), tt.videoIDs, "title", "user_1")
+			if !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("Infrastructure.ConcatenateVideos() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func Test_結合できない動画(t *testing.T) {
+	videoRow := func(id string, edit func(row []driver.Value)) [][]driver.Value {
+		row := relatedVideoRow(id, 0, "")
+		row = row[:len(row)-2]
+		if edit != nil {
+			edit(row)
+		}
+		return [][]driver.Value{row}
+	}
+	tests := []struct {
+		name    string
+		video2  [][]driver.Value
+		wantErr error
+	}{
+		{name: "他のユーザーの動画", video2: videoRow("video_2", func(row []driver.Value) { row[10] = "user_2" }), wantErr: domain.ErrPermissionDenied},
+		{name: "非公開の動画", video2: videoRow("video_2", func(row []driver.Value) { row[7] = true }), wantErr: domain.ErrPermissionDenied},
+		{name: "公開期限を過ぎた動画", video2: videoRow("video_2", func(row []driver.Value) { row[len(row)-16] = time.Now().Add(-time.Hour) }), wantErr: domain.ErrVideoExpired},
+		{name: "存在しない動画", video2: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo video_1": videoRow("video_1", nil),
+				"GetUndeletedVideo video_2": tt.video2,
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			_, err := i.ConcatenateVideos(context.Background(), []string{"video_1", "video_2"}, "title", "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Infrastructure.ConcatenateVideos() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 再コンパイルせずに運用者が変更できる設定
+type InfrastructureConfig struct {
+	// アップロードできる動画ファイルの最大バイト数
+	MaxVideoSize int64
+	// アップロードできる動画の長さの範囲
+	MinVideoDuration time.Duration
+	MaxVideoDuration time.Duration
+	// ホバー時に再生するプレビューの長さ
+	PreviewDuration time.Duration
+	Transcoder      TranscoderConfig
+	// ffmpegの実行がこれより長くかかった場合は打ち切る
+	FFmpegTimeout time.Duration
+	// ffmpegが続けて失敗して実行を止めた後、再び試すまでの時間
+	FFmpegCircuitCooldown time.Duration
+	// S3へのアップロードが一時的なエラーで失敗した場合に、リトライを含めて実行する最大の回数
+	S3UploadMaxAttempts int64
+	// ウォーターマークを重ねる位置と動画の端からの余白(px)
+	WatermarkPosition string
+	WatermarkMargin   int64
+	// ユーザー単位とIPアドレス単位のアップロード回数の制限
+	UploadRateLimit   RateLimitConfig
+	UploadIPRateLimit RateLimitConfig
+	// ユーザー単位とIPアドレス単位のダウンロード回数の制限。IPアドレスはログインしていない場合のみ使う
+	DownloadRateLimit   RateLimitConfig
+	DownloadIPRateLimit RateLimitConfig
+	// 他のユーザーのコメントも削除できる管理者のユーザーID
+	AdminUserIDs []string
+	// 1ユーザーがブックマークできる動画の最大数
+	MaxBookmarksPerUser int64
+	// 1つの動画に付けられるタグの最大数
+	MaxTagsPerVideo int64
+	// アップロードされた動画を変換するワーカーの数と、処理待ちにできるジョブの数
+	VideoProcessingWorkers   int64
+	VideoProcessingQueueSize int64
+	// これより長く終わらない変換ジョブはワーカーが落ちたとみなして入れ直す
+	VideoProcessingStalledTimeout time.Duration
+	// user_storage_quotasに行がないユーザーが保存できる動画ファイルの合計バイト数
+	DefaultStorageQuotaBytes int64
+	// 動画のページがあるフロントエンドのURL。共有するリンクやQRコードに使う
+	SiteURL string
+	// QRコードの画像の一辺のピクセル数
+	QRCodeSize int64
+	// HLSの暗号鍵を配信するエンドポイントのURL。プレイリストには<HLSKeyServerURL>/<keyID>を書く
+	HLSKeyServerURL string
+	// 鍵を取得するトークンに署名する鍵と、トークンの有効期間
+	HLSKeyTokenSecret string
+	HLSKeyTokenTTL    time.Duration
+	// どの動画にも付いていないタグを消す間隔
+	TagCleanupInterval time.Duration
+}
+
+const (
+	defaultMaxVideoSize     = 2 * 1024 * 1024 * 1024
+	defaultMinVideoDuration = 1 * time.Second
+	defaultMaxVideoDuration = 3 * time.Hour
+	defaultPreviewDuration  = 3 * time.Second
+	defaultFFmpegTimeout    = 10 * time.Minute
+	defaultWatermarkMargin  = 16
+	// 24時間に1回までアップロードできる
+	defaultUploadRateLimitWindow     = 24 * time.Hour
+	defaultUploadRateLimitMaxUploads = 1
+	// 同じIPアドレスからは1時間に5回までアップロードできる
+	defaultUploadIPRateLimitWindow     = time.Hour
+	defaultUploadIPRateLimitMaxUploads = 5
+	defaultMaxBookmarksPerUser         = 1000
+	defaultMaxTagsPerVideo             = 20
+	defaultVideoProcessingWorkers      = 2
+	defaultVideoProcessingQueueSize    = 100
+	// ffmpegのタイムアウトより十分長くする
+	defaultVideoProcessingStalledTimeout = time.Hour
+	defaultStorageQuotaBytes             = 10 * 1024 * 1024 * 1024
+	// 1時間に10回までダウンロードできる
+	defaultDownloadRateLimitWindow       = time.Hour
+	defaultDownloadRateLimitMaxDownloads = 10
+	defaultFFmpegCircuitCooldown         = 30 * time.Second
+	defaultS3UploadMaxAttempts           = 3
+	defaultSiteURL                       = "https://yuovision.yuorei.com"
+	defaultQRCodeSize                    = 256
+	defaultHLSSegmentDuration            = 6
+	// 30fpsで2秒ごと
+	defaultKeyframeInterval   = 60
+	defaultHLSKeyTokenTTL     = 6 * time.Hour
+	defaultTagCleanupInterval = 24 * time.Hour
+)
+
+// 環境変数から設定を読み込む。未設定の場合はデフォルト値を使う
+func NewInfrastructureConfig() InfrastructureConfig {
+	return InfrastructureConfig{
+		MaxVideoSize:     getEnvInt64("MAX_VIDEO_SIZE", defaultMaxVideoSize),
+		MinVideoDuration: getEnvDuration("MIN_VIDEO_DURATION", defaultMinVideoDuration),
+		MaxVideoDuration: getEnvDuration("MAX_VIDEO_DURATION", defaultMaxVideoDuration),
+		PreviewDuration:  getEnvDuration("PREVIEW_DURATION", defaultPreviewDuration),
+		Transcoder: TranscoderConfig{
+			Backend:            os.Getenv("TRANSCODER_BACKEND"),
+			Device:             os.Getenv("TRANSCODER_DEVICE"),
+			HLSSegmentDuration: int(getEnvInt64("HLS_SEGMENT_DURATION", defaultHLSSegmentDuration)),
+			KeyframeInterval:   int(getEnvInt64("KEYFRAME_INTERVAL", defaultKeyframeInterval)),
+		},
+		FFmpegTimeout:     getEnvDuration("FFMPEG_TIMEOUT", defaultFFmpegTimeout),
+		WatermarkPosition: getEnv("WATERMARK_POSITION", WatermarkBottomRight),
+		WatermarkMargin:   getEnvInt64("WATERMARK_MARGIN", defaultWatermarkMargin),
+		UploadRateLimit: RateLimitConfig{
+			Window: getEnvDuration("UPLOAD_RATE_LIMIT_WINDOW", defaultUploadRateLimitWindow),
+			Max:    int(getEnvInt64("UPLOAD_RATE_LIMIT_MAX_UPLOADS", defaultUploadRateLimitMaxUploads)),
+		},
+		UploadIPRateLimit: RateLimitConfig{
+			Window: getEnvDuration("UPLOAD_IP_RATE_LIMIT_WINDOW", defaultUploadIPRateLimitWindow),
+			Max:    int(getEnvInt64("UPLOAD_IP_RATE_LIMIT_MAX_UPLOADS", defaultUploadIPRateLimitMaxUploads)),
+		},
+		DownloadRateLimit: RateLimitConfig{
+			Window: getEnvDuration("DOWNLOAD_RATE_LIMIT_WINDOW", defaultDownloadRateLimitWindow),
+			Max:    int(getEnvInt64("DOWNLOAD_RATE_LIMIT_MAX_DOWNLOADS", defaultDownloadRateLimitMaxDownloads)),
+		},
+		DownloadIPRateLimit: RateLimitConfig{
+			Window: getEnvDuration("DOWNLOAD_IP_RATE_LIMIT_WINDOW", defaultDownloadRateLimitWindow),
+			Max:    int(getEnvInt64("DOWNLOAD_IP_RATE_LIMIT_MAX_DOWNLOADS", defaultDownloadRateLimitMaxDownloads)),
+		},
+		AdminUserIDs:                  getEnvList("ADMIN_USER_IDS"),
+		MaxBookmarksPerUser:           getEnvInt64("MAX_BOOKMARKS_PER_USER", defaultMaxBookmarksPerUser),
+		MaxTagsPerVideo:               getEnvInt64("MAX_TAGS_PER_VIDEO", defaultMaxTagsPerVideo),
+		VideoProcessingWorkers:        getEnvInt64("VIDEO_PROCESSING_WORKERS", defaultVideoProcessingWorkers),
+		VideoProcessingQueueSize:      getEnvInt64("VIDEO_PROCESSING_QUEUE_SIZE", defaultVideoProcessingQueueSize),
+		VideoProcessingStalledTimeout: getEnvDuration("VIDEO_PROCESSING_STALLED_TIMEOUT", defaultVideoProcessingStalledTimeout),
+		DefaultStorageQuotaBytes:      getEnvInt64("DEFAULT_STORAGE_QUOTA_BYTES", defaultStorageQuotaBytes),
+		FFmpegCircuitCooldown:         getEnvDuration("FFMPEG_CIRCUIT_COOLDOWN", defaultFFmpegCircuitCooldown),
+		S3UploadMaxAttempts:           getEnvInt64("S3_UPLOAD_MAX_ATTEMPTS", defaultS3UploadMaxAttempts),
+		SiteURL:                       getEnv("SITE_URL", defaultSiteURL),
+		QRCodeSize:                    getEnvInt64("QRCODE_SIZE", defaultQRCodeSize),
+		HLSKeyServerURL:               os.Getenv("HLS_KEY_SERVER_URL"),
+		HLSKeyTokenSecret:             os.Getenv("HLS_KEY_TOKEN_SECRET"),
+		HLSKeyTokenTTL:                getEnvDuration("HLS_KEY_TOKEN_TTL", defaultHLSKeyTokenTTL),
+		TagCleanupInterval:            getEnvDuration("TAG_CLEANUP_INTERVAL", defaultTagCleanupInterval),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		slog.Warn("invalid config value, using default", "key", key, "value", value)
+		return defaultValue
+	}
+	return n
+}
+
+// "30s"や"2h"のようなtime.ParseDurationの形式で指定する
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		slog.Warn("invalid config value, using default", "key", key, "value", value)
+		return defaultValue
+	}
+	return d
+}
+
+// カンマ区切りで指定する。空の要素は無視する
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -3,30 +3,37 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (i *Infrastructure) ConvertVideoHLS(ctx context.Context, videoID string) error {
+func (i *Infrastructure) ConvertVideoHLS(ctx context.Context, videoID string) (err error) {
+	ctx, span := infraSpan(ctx, "ConvertVideoHLS")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	return i.convertVideoHLS(ctx, videoID, filepath.Join("temp", videoID+".mp4"))
+}
+
+// sourceの動画をoutput/<videoID>にHLSとして書き出す
+func (i *Infrastructure) convertVideoHLS(ctx context.Context, videoID, source string) error {
+	transcoder := i.config.Transcoder
+	err := transcoder.Validate()
+	if err != nil {
+		return err
+	}
+
 	// HLS変換の実行
 	outputDir := "output/" + videoID
-	err := os.MkdirAll(outputDir, 0755)
+	err = os.MkdirAll(outputDir, 0755)
 	if err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	output := "output_" + videoID + ".m3u8"
 	outputHLS := filepath.Join(outputDir, output)
-	tempMp4 := filepath.Join("temp", videoID+".mp4")
-	cmd := exec.Command("ffmpeg", "-i", tempMp4, "-codec:", "copy", "-start_number", "0", "-hls_time", "10", "-hls_list_size", "0", "-f", "hls", outputHLS, "-y")
-	log.Println(cmd.Args)
-	result, err := cmd.CombinedOutput()
-	log.Println(string(result))
-	if err != nil {
-		return fmt.Errorf("failed to execute ffmpeg command: %w", err)
-	}
-
-	return nil
+	_, err = runFFmpeg(ctx, "hls", transcoder.hlsArgs(source, outputHLS))
+	return err
 }
@@ -0,0 +1,137 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const dashManifest = "manifest.mpd"
+
+func (i *Infrastructure) dashManifestURL(videoID string) string {
+	return i.objectURL(videoBucketName, videoID+"/dash/"+dashManifest)
+}
+
+// テストでffmpegの実行を差し替えられるようにしている
+var runDASHFFmpeg = runFFmpeg
+
+// 保存した元の動画から画質ごとのMPEG-DASHを作って<videoID>/dash/にアップロードし、MPDのURLを返す
+// HLSを再生できないスマートテレビなどのプレイヤー向け
+func (i *Infrastructure) GenerateDASHManifest(ctx context.Context, videoID string, qualities []domain.QualityLevel) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GenerateDASHManifest")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.Int("qualityLevels", len(qualities)))
+	defer func() { endSpan(span, err) }()
+
+	err = domain.ValidateQualityLadder(qualities)
+	if err != nil {
+		return "", err
+	}
+	transcoder := i.config.Transcoder
+	err = transcoder.Validate()
+	if err != nil {
+		return "", err
+	}
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := i.isHLSEncrypted(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	if encrypted {
+		return "", fmt.Errorf("%w: %s has encrypted HLS", domain.ErrInvalidInput, videoID)
+	}
+
+	source, err := i.storage.PresignGetURL(ctx, videoSourceKey(videoID), presignedURLExpires)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join("output", videoID, "dash")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	// 音声のない動画で音声のアダプテーションセットを指定するとffmpegが失敗する
+	hasAudio := video.AudioTracks.Valid
+	_, err = runDASHFFmpeg(ctx, "dash", transcoder.dashArgs(source, filepath.Join(dir, dashManifest), qualities, hasAudio))
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == dashManifest {
+			continue
+		}
+		err = i.uploadStreamingFile(ctx, filepath.Join(dir, entry.Name()), videoID+"/dash/"+entry.Name())
+		if err != nil {
+			return "", err
+		}
+	}
+	// セグメントが揃ってからMPDを公開する
+	err = i.uploadStreamingFile(ctx, filepath.Join(dir, dashManifest), videoID+"/dash/"+dashManifest)
+	if err != nil {
+		return "", err
+	}
+
+	url := i.dashManifestURL(videoID)
+	err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionGenerateDASHManifest, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoDASHManifestURL(ctx, sqlc.UpdateVideoDASHManifestURLParams{
+			DashManifestUrl: sql.NullString{String: url, Valid: true},
+			ID:              videoID,
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// 1回のffmpegで画質ごとの映像を1つのアダプテーションセットにまとめ、プレイヤーが切り替えられるようにする
+func (c TranscoderConfig) dashArgs(source, output string, qualities []domain.QualityLevel, hasAudio bool) []string {
+	args, encoder := c.scalingEncoder()
+	args = append(args, "-i", source)
+	for range qualities {
+		args = append(args, "-map", "0:v:0")
+	}
+	adaptationSets := "id=0,streams=v"
+	if hasAudio {
+		args = append(args, "-map", "0:a:0")
+		adaptationSets += " id=1,streams=a"
+	}
+
+	for n, level := range qualities {
+		stream := strconv.Itoa(n)
+		bitrate := strconv.Itoa(level.BitrateBPS)
+		args = append(args,
+			"-filter:v:"+stream, fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:force_divisible_by=2", level.Width, level.Height),
+			"-b:v:"+stream, bitrate, "-maxrate:v:"+stream, bitrate, "-bufsize:v:"+stream, strconv.Itoa(level.BitrateBPS*2),
+		)
+	}
+
+	// 画質を切り替えられるように全ての画質でキーフレームの位置を揃える
+	gop := strconv.Itoa(c.KeyframeInterval)
+	args = append(args, "-c:v", encoder, "-g", gop, "-keyint_min", gop, "-sc_threshold", "0")
+	if hasAudio {
+		args = append(args, "-c:a", "aac", "-b:a", strconv.Itoa(hlsAudioBitrateBPS))
+	}
+	return append(args,
+		"-seg_duration", strconv.Itoa(c.HLSSegmentDuration), "-use_template", "1", "-use_timeline", "1",
+		"-adaptation_sets", adaptationSets, "-f", "dash", output, "-y",
+	)
+}
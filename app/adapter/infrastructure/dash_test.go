@@ -0,0 +1,169 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_DASHの引数生成(t *testing.T) {
+	config := TranscoderConfig{Backend: TranscoderBackendSoftware, HLSSegmentDuration: 4, KeyframeInterval: 48}
+	qualities := domain.DefaultQualityLadder[:2]
+	scale360 := "scale=640:360:force_original_aspect_ratio=decrease:force_divisible_by=2"
+	scale720 := "scale=1280:720:force_original_aspect_ratio=decrease:force_divisible_by=2"
+	tests := []struct {
+		name     string
+		hasAudio bool
+		want     []string
+	}{
+		{
+			name:     "音声あり",
+			hasAudio: true,
+			want: []string{
+				"-i", "in.mp4", "-map", "0:v:0", "-map", "0:v:0", "-map", "0:a:0",
+				"-filter:v:0", scale360, "-b:v:0", "800000", "-maxrate:v:0", "800000", "-bufsize:v:0", "1600000",
+				"-filter:v:1", scale720, "-b:v:1", "2800000", "-maxrate:v:1", "2800000", "-bufsize:v:1", "5600000",
+				"-c:v", "libx264", "-g", "48", "-keyint_min", "48", "-sc_threshold", "0", "-c:a", "aac", "-b:a", "128000",
+				"-seg_duration", "4", "-use_template", "1", "-use_timeline", "1", "-adaptation_sets", "id=0,streams=v id=1,streams=a", "-f", "dash", "out.mpd", "-y",
+			},
+		},
+		{
+			name:     "音声なし",
+			hasAudio: false,
+			want: []string{
+				"-i", "in.mp4", "-map", "0:v:0", "-map", "0:v:0",
+				"-filter:v:0", scale360, "-b:v:0", "800000", "-maxrate:v:0", "800000", "-bufsize:v:0", "1600000",
+				"-filter:v:1", scale720, "-b:v:1", "2800000", "-maxrate:v:1", "2800000", "-bufsize:v:1", "5600000",
+				"-c:v", "libx264", "-g", "48", "-keyint_min", "48", "-sc_threshold", "0",
+				"-seg_duration", "4", "-use_template", "1", "-use_timeline", "1", "-adaptation_sets", "id=0,streams=v", "-f", "dash", "out.mpd", "-y",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := config.dashArgs("in.mp4", "out.mpd", qualities, tt.hasAudio)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TranscoderConfig.dashArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DASHの生成(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	var uploaded []string
+	originalFFmpeg, originalUpload := runDASHFFmpeg, uploadStreamingObject
+	t.Cleanup(func() { runDASHFFmpeg, uploadStreamingObject = originalFFmpeg, originalUpload })
+	runDASHFFmpeg = func(ctx context.Context, operation string, args []string) ([]byte, error) {
+		manifest := args[len(args)-2]
+		for _, name := range []string{filepath.Base(manifest), "init-stream0.m4s", "chunk-stream0-00001.m4s"} {
+			err := os.WriteFile(filepath.Join(filepath.Dir(manifest), name), nil, 0644)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	uploadStreamingObject = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+		uploaded = append(uploaded, key+" "+contentType)
+		return nil
+	}
+
+	row := relatedVideoRow("video_1", 0, "")
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetUndeletedVideo":               {row[:len(row)-2]},
+		"GetVideoForUpdate":               {row[:len(row)-2]},
+		"CountHLSEncryptionKeysByVideoID": {{int64(0)}},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+		storage: NewMemoryBackend(),
+		config: InfrastructureConfig{
+			Transcoder:          TranscoderConfig{HLSSegmentDuration: 6, KeyframeInterval: 60},
+			S3UploadMaxAttempts: 1,
+		},
+	}
+
+	got, err := i.GenerateDASHManifest(context.Background(), "video_1", domain.DefaultQualityLadder)
+	if err != nil {
+		t.Fatalf("Infrastructure.GenerateDASHManifest() error = %v", err)
+	}
+	want := "memory:///video_1/dash/manifest.mpd"
+	if got != want {
+		t.Errorf("Infrastructure.GenerateDASHManifest() = %v, want %v", got, want)
+	}
+
+	// MPDはセグメントの後にアップロードする
+	wantUploaded := []string{
+		"video_1/dash/chunk-stream0-00001.m4s video/iso.segment",
+		"video_1/dash/init-stream0.m4s video/iso.segment",
+		"video_1/dash/manifest.mpd application/dash+xml",
+	}
+	if !reflect.DeepEqual(uploaded, wantUploaded) {
+		t.Errorf("uploaded = %v, want %v", uploaded, wantUploaded)
+	}
+	// URLの書き込みは監査ログと同じトランザクションで行う
+	wantNames := []string{"UpdateVideoDASHManifestURL", "CreateAuditLog"}
+	if !reflect.DeepEqual(connector.execNames, wantNames) || !reflect.DeepEqual(connector.execs[0], []driver.Value{want, "video_1"}) {
+		t.Errorf("execs = %v %v, want %v", connector.execNames, connector.execs, wantNames)
+	}
+	if connector.commits != 1 {
+		t.Errorf("commits = %d, want 1", connector.commits)
+	}
+	if _, err := os.Stat(filepath.Join("output", "video_1", "dash")); !os.IsNotExist(err) {
+		t.Errorf("dash directory was not removed: %v", err)
+	}
+}
+
+func Test_HLSを暗号化した動画のDASHの生成(t *testing.T) {
+	originalFFmpeg := runDASHFFmpeg
+	t.Cleanup(func() { runDASHFFmpeg = originalFFmpeg })
+	runDASHFFmpeg = func(ctx context.Context, operation string, args []string) ([]byte, error) {
+		t.Error("ffmpeg was run for encrypted video")
+		return nil, nil
+	}
+
+	row := relatedVideoRow("video_1", 0, "")
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetUndeletedVideo":               {row[:len(row)-2]},
+		"CountHLSEncryptionKeysByVideoID": {{int64(1)}},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB)},
+		storage: NewMemoryBackend(),
+		config: InfrastructureConfig{
+			Transcoder: TranscoderConfig{HLSSegmentDuration: 6, KeyframeInterval: 60},
+		},
+	}
+
+	// 暗号化されていないDASHから再生できないようにする
+	_, err := i.GenerateDASHManifest(context.Background(), "video_1", domain.DefaultQualityLadder)
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.GenerateDASHManifest() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+	if len(connector.execs) != 0 {
+		t.Errorf("execs = %v, want none", connector.execNames)
+	}
+}
@@ -0,0 +1,223 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// テストで保存先の削除を差し替えられるようにしている
+var deleteVideoObjects = (*Infrastructure).deleteVideoObjectsFromStorage
+
+// 一覧に表示しないようにするだけで、DBの行やS3のファイルは残す
+// 投稿者本人か管理者のみ削除できる
+func (i *Infrastructure) SoftDeleteVideo(ctx context.Context, videoID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "SoftDeleteVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getVideoForDelete(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if video.UploaderID != requestingUserID && !i.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, requestingUserID, videoID)
+	}
+
+	now := time.Now()
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		_, err := q.SoftDeleteVideo(ctx, sqlc.SoftDeleteVideoParams{
+			UpdatedAt: now,
+			ID:        videoID,
+		})
+		if err != nil {
+			return err
+		}
+		before := newVideoFromDB(video)
+		after := *before
+		after.IsDeleted = true
+		after.UpdatedAt = now
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionSoftDeleteVideo, requestingUserID, before, &after)
+	})
+	if err != nil {
+		return err
+	}
+
+	i.emitWebhookEvent(ctx, domain.WebhookEventVideoDeleted, videoID, map[string]bool{"permanent": false})
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+// 管理者の確認はユースケースで行う
+func (i *Infrastructure) RestoreVideo(ctx context.Context, videoID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "RestoreVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.getVideoForDelete(ctx, videoID)
+	if err != nil {
+		return err
+	}
+
+	// permanent_banにした動画はis_bannedを立てたまま消すので、ロックした行で確認する
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionRestoreVideo, requestingUserID, func(before sqlc.Video) error {
+			if before.IsBanned {
+				return fmt.Errorf("%w: %s is permanently banned", domain.ErrPermissionDenied, videoID)
+			}
+			_, err := q.RestoreVideo(ctx, sqlc.RestoreVideoParams{
+				UpdatedAt: time.Now(),
+				ID:        videoID,
+			})
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+// 誤って消さないように、論理削除済みの動画のみ完全に削除できる
+// 外部キーがあるので関連する行を先に削除し、S3のファイルを消せた場合のみ動画の行を削除する
+// 途中で失敗しても動画の行は残るので、再実行すれば続きから削除できる
+// 削除した動画のファイルサイズは投稿者のストレージの使用量から減らす
+// 管理者の確認はユースケースで行う
+func (i *Infrastructure) HardDeleteVideo(ctx context.Context, videoID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "HardDeleteVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getVideoForDelete(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if !video.IsDeleted {
+		return fmt.Errorf("%w: video %s must be soft deleted before hard delete", domain.ErrInvalidInput, videoID)
+	}
+
+	q := i.db.Database
+	_, err = q.DeleteLikeDislikesByVideoID(ctx, sqlc.DeleteLikeDislikesByVideoIDParams{
+		VideoID:        sql.NullString{String: videoID, Valid: true},
+		CommentVideoID: videoID,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = q.DeleteReportsByVideoID(ctx, sqlc.DeleteReportsByVideoIDParams{
+		VideoID:        sql.NullString{String: videoID, Valid: true},
+		CommentVideoID: videoID,
+	})
+	if err != nil {
+		return err
+	}
+
+	deletes := []func(context.Context, string) (sql.Result, error){
+		q.DeleteCommentsByVideoID,
+		q.DeleteHistoryByVideoID,
+		q.DeletePlaylistVideosByVideoID,
+		q.DeleteVideoCategoriesByVideoID,
+		q.DeleteVideoTagsByVideoID,
+		q.DeleteVideoDescriptionsByVideoID,
+		q.DeleteSubtitleTracksByVideoID,
+		q.DeleteHLSEncryptionKeysByVideoID,
+		q.DeleteThumbnailVariantsByVideoID,
+		q.DeleteVideoReportsByVideoID,
+		q.DeleteReactionsByVideoID,
+		q.DeleteBookmarksByVideoID,
+		q.DeleteWatchCountSnapshotsByVideoID,
+	}
+	for _, del := range deletes {
+		_, err = del(ctx, videoID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = i.storage.Delete(ctx, videoSourceKey(videoID))
+	if err != nil {
+		return fmt.Errorf("failed to delete video source: %w", err)
+	}
+	err = deleteVideoObjects(i, ctx, video)
+	if err != nil {
+		return fmt.Errorf("failed to delete video objects: %w", err)
+	}
+
+	// 動画の行を消したのに使用量が減らないことや監査ログが残らないことがないように同じトランザクションで行う
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		_, err := q.SubtractUserStorageUsage(ctx, sqlc.SubtractUserStorageUsageParams{
+			Bytes:  video.FileSizeBytes,
+			UserID: video.UploaderID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteVideo(ctx, videoID)
+		if err != nil {
+			return err
+		}
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionHardDeleteVideo, requestingUserID, newVideoFromDB(video), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	i.emitWebhookEvent(ctx, domain.WebhookEventVideoDeleted, videoID, map[string]bool{"permanent": true})
+	i.invalidateVideoCache(ctx, videoID)
+	return i.redis.Del(ctx, storageQuotaKey(video.UploaderID)).Err()
+}
+
+func (i *Infrastructure) IsAdmin(userID string) bool {
+	return i.isAdmin(userID)
+}
+
+func (i *Infrastructure) getVideoForDelete(ctx context.Context, videoID string) (sqlc.Video, error) {
+	video, err := i.db.Database.GetVideo(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Video{}, fmt.Errorf("%w: video %s not found", domain.ErrInvalidInput, videoID)
+	}
+	return video, err
+}
+
+// HLSとDASHのプレイリストとセグメント、サムネイル、プレビュー、スプライト、字幕、チャプター、QRコードを削除する
+func (i *Infrastructure) deleteVideoObjectsFromStorage(ctx context.Context, video sqlc.Video) error {
+	// セグメントはプレイリストと同じディレクトリにある
+	if bucketName, key, ok := i.splitObjectURL(video.VideoUrl); ok {
+		err := i.bucket(bucketName).DeletePrefix(ctx, path.Dir(key)+"/")
+		if err != nil {
+			return err
+		}
+	}
+
+	err := i.bucket(subtitleBucketName).DeletePrefix(ctx, subtitlePrefix(video.ID))
+	if err != nil {
+		return err
+	}
+	// 音声は形式ごとにaudio/<id>.<format>にある
+	err = i.bucket(audioBucketName).DeletePrefix(ctx, audioKey(video.ID, ""))
+	if err != nil {
+		return err
+	}
+
+	urls := []string{video.ThumbnailImageUrl, video.PreviewUrl.String, video.ThumbnailVttUrl.String, video.ChaptersUrl.String, video.QrcodeUrl.String}
+	if video.ThumbnailVttUrl.Valid {
+		// スプライト画像はVTTと同じ名前で拡張子だけ違う
+		urls = append(urls, video.ThumbnailVttUrl.String[:len(video.ThumbnailVttUrl.String)-len(path.Ext(video.ThumbnailVttUrl.String))]+".png")
+	}
+	for _, url := range urls {
+		err = i.deleteObject(ctx, url)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
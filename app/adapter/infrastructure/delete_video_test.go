@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// user_1が投稿した動画の行
+func deleteTestVideoRow(isDeleted bool) []driver.Value {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[20] = isDeleted
+	return row
+}
+
+func Test_動画の論理削除(t *testing.T) {
+	tests := []struct {
+		name             string
+		requestingUserID string
+		wantErr          error
+	}{
+		{name: "投稿者本人", requestingUserID: "user_1"},
+		{name: "管理者", requestingUserID: "admin_1"},
+		{name: "他のユーザー", requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{values: deleteTestVideoRow(false)}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			err := i.SoftDeleteVideo(context.Background(), "video_1", tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SoftDeleteVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			wantNames := []string{"SoftDeleteVideo", "CreateAuditLog"}
+			if tt.wantErr != nil {
+				wantNames = nil
+			}
+			if len(connector.execNames) != len(wantNames) {
+				t.Errorf("exec calls = %v, want %v", connector.execNames, wantNames)
+			}
+		})
+	}
+}
+
+func Test_動画の完全な削除(t *testing.T) {
+	errS3 := errors.New("s3 error")
+	tests := []struct {
+		name          string
+		isDeleted     bool
+		s3Err         error
+		wantErr       error
+		wantDeleteRow bool
+	}{
+		{name: "論理削除済み", isDeleted: true, wantDeleteRow: true},
+		{name: "論理削除されていない", isDeleted: false, wantErr: domain.ErrInvalidInput},
+		{name: "S3の削除に失敗", isDeleted: true, s3Err: errS3, wantErr: errS3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{values: deleteTestVideoRow(tt.isDeleted)}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			_, client := newTestRedis(t)
+			storage := NewMemoryBackend()
+			i := &Infrastructure{
+				db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:   client,
+				storage: storage,
+			}
+			ctx := context.Background()
+			storage.Upload(ctx, videoSourceKey("video_1"), "video/mp4", strings.NewReader("video"))
+			client.Set(ctx, storageQuotaKey("user_1"), `{"used_bytes":100,"quota_bytes":1000}`, time.Minute)
+
+			s3Called := false
+			original := deleteVideoObjects
+			deleteVideoObjects = func(i *Infrastructure, ctx context.Context, video sqlc.Video) error {
+				s3Called = true
+				return tt.s3Err
+			}
+			t.Cleanup(func() { deleteVideoObjects = original })
+
+			err := i.HardDeleteVideo(context.Background(), "video_1", "admin_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.HardDeleteVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if !tt.isDeleted {
+				if s3Called || len(connector.execNames) != 0 {
+					t.Errorf("deleted without soft delete: s3 = %v, exec calls = %v", s3Called, connector.execNames)
+				}
+				return
+			}
+
+			// 動画の行は関連する行とS3のファイルを消した後に削除し、同じトランザクションで監査ログを残す
+			deletedRow := len(connector.execNames) > 1 && connector.execNames[len(connector.execNames)-2] == "DeleteVideo" && connector.execNames[len(connector.execNames)-1] == "CreateAuditLog"
+			if deletedRow != tt.wantDeleteRow {
+				t.Errorf("exec calls = %v, want DeleteVideo and CreateAuditLog last = %v", connector.execNames, tt.wantDeleteRow)
+			}
+			if !s3Called {
+				t.Error("video objects were not deleted")
+			}
+			if _, err := storage.Download(ctx, videoSourceKey("video_1")); err == nil {
+				t.Error("video source was not deleted")
+			}
+			if !tt.wantDeleteRow {
+				return
+			}
+
+			// 動画の行の削除と同じトランザクションで投稿者の使用量を減らす
+			subtract := connector.execs[len(connector.execs)-3]
+			if connector.execNames[len(connector.execNames)-3] != "SubtractUserStorageUsage" || subtract[1] != "user_1" {
+				t.Errorf("exec calls = %v, want SubtractUserStorageUsage for user_1 before DeleteVideo", connector.execNames)
+			}
+			audit := connector.execs[len(connector.execs)-1]
+			if audit[1] != "video_1" || audit[2] != domain.AuditActionHardDeleteVideo || audit[3] != "admin_1" || audit[4] == nil || audit[5] != nil {
+				t.Errorf("CreateAuditLog args = %v", audit)
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+			if n, _ := client.Exists(ctx, storageQuotaKey("user_1")).Result(); n != 0 {
+				t.Error("storage quota cache should be deleted")
+			}
+		})
+	}
+}
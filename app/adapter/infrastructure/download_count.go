@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type DownloadCountJsonType struct {
+	Count int `json:"count"`
+}
+
+func downloadCountKey(videoID string) string {
+	return "downloadcount" + domain.IDSeparator + videoID
+}
+
+// 同じユーザーのダウンロードを24時間に1回だけ数えるためのキー
+// 再生回数の判定に使う<videoID>_<userID>と衝突しないようにdlを付ける
+func downloadedKey(videoID, userID string) string {
+	return "dl" + videoID + domain.IDSeparator + userID
+}
+
+func (i *Infrastructure) GetDownloadCount(ctx context.Context, videoID string) (_ int, err error) {
+	ctx, span := infraSpan(ctx, "GetDownloadCount")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	var downloadCountJson DownloadCountJsonType
+	hit, err := i.getFromCache(ctx, "download_count", downloadCountKey(videoID), &downloadCountJson)
+	if err != nil {
+		return 0, err
+	} else if hit {
+		return downloadCountJson.Count, nil
+	}
+
+	downloadCount, err := i.db.Database.GetDownloadCount(ctx, videoID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = setToRedis(ctx, i.redis, downloadCountKey(videoID), 1*time.Hour, &DownloadCountJsonType{
+		Count: int(downloadCount),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(downloadCount), nil
+}
+
+// ダウンロードは再生ほど頻繁ではないため、Redisに溜めずにDBに直接書き込む
+func (i *Infrastructure) IncrementDownloadCount(ctx context.Context, videoID, userID string) (_ int, err error) {
+	ctx, span := infraSpan(ctx, "IncrementDownloadCount")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.db.Database.IncrementDownloadCount(ctx, videoID)
+	if err != nil {
+		return 0, err
+	}
+
+	downloadCount, err := i.db.Database.GetDownloadCount(ctx, videoID)
+	if err != nil {
+		return 0, err
+	}
+
+	downloadCountJsonType := DownloadCountJsonType{
+		Count: int(downloadCount),
+	}
+
+	err = setToRedis(ctx, i.redis, downloadedKey(videoID, userID), 24*time.Hour, &downloadCountJsonType)
+	if err != nil {
+		return 0, err
+	}
+
+	// GetDownloadCountが古い値を返さないようにキャッシュも更新する
+	err = setToRedis(ctx, i.redis, downloadCountKey(videoID), 1*time.Hour, &downloadCountJsonType)
+	if err != nil {
+		return 0, err
+	}
+
+	return downloadCountJsonType.Count, nil
+}
+
+func (i *Infrastructure) CheckDownloadCount(ctx context.Context, videoID, userID string) (_ bool, err error) {
+	ctx, span := infraSpan(ctx, "CheckDownloadCount")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	var downloadCountJson DownloadCountJsonType
+	hit, err := i.getFromCache(ctx, "downloaded", downloadedKey(videoID, userID), &downloadCountJson)
+	if err != nil {
+		return false, err
+	}
+	return !hit, nil
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ダウンロード数の加算(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(3)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(sqlDB)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	ok, err := i.CheckDownloadCount(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.CheckDownloadCount() error = %v", err)
+	}
+	if !ok {
+		t.Error("Infrastructure.CheckDownloadCount() = false, want true before download")
+	}
+
+	got, err := i.IncrementDownloadCount(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.IncrementDownloadCount() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("Infrastructure.IncrementDownloadCount() = %v, want 3", got)
+	}
+	if len(connector.execNames) != 1 || connector.execNames[0] != "IncrementDownloadCount" {
+		t.Errorf("exec names = %v, want [IncrementDownloadCount]", connector.execNames)
+	}
+
+	// 24時間以内の同じユーザーのダウンロードは数えない
+	ok, err = i.CheckDownloadCount(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.CheckDownloadCount() error = %v", err)
+	}
+	if ok {
+		t.Error("Infrastructure.CheckDownloadCount() = true, want false after download")
+	}
+	// 再生回数の判定とはキーを分ける
+	watched, err := i.ChechWatchCount(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.ChechWatchCount() error = %v", err)
+	}
+	if !watched {
+		t.Error("download should not be counted as watch")
+	}
+
+	queries := connector.queries
+	got, err = i.GetDownloadCount(ctx, "video_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.GetDownloadCount() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("Infrastructure.GetDownloadCount() = %v, want 3", got)
+	}
+	// キャッシュから返るのでDBには問い合わせない
+	if connector.queries != queries {
+		t.Errorf("GetDownloadCount queried the database %d times", connector.queries-queries)
+	}
+}
@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ダウンロード用の署名付きURLの有効期限
+const downloadURLExpires = 15 * time.Minute
+
+// ダウンロード用の元のMP4はHLSと同じディレクトリに置き、完全な削除でまとめて消えるようにする
+func videoSourceKey(id string) string {
+	return fmt.Sprintf("%s/source_%s.mp4", id, id)
+}
+
+// 署名付きURLでのみ取得できるように、HLSと違ってpublic-readにしない
+// 保存したファイルの破損を検知できるように、アップロードした内容のSHA-256を返す
+func (i *Infrastructure) uploadVideoSource(ctx context.Context, path, id string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	checksum, err := sha256Hex(file)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	err = i.storage.Upload(ctx, videoSourceKey(id), "video/mp4", file)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload video source: %w", err)
+	}
+	return checksum, nil
+}
+
+// HLSを暗号化した動画は元のMP4から暗号化されていない映像を取得できないようにダウンロードさせない
+func (i *Infrastructure) PresignVideoDownloadURL(ctx context.Context, videoID string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "PresignVideoDownloadURL")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	encrypted, err := i.isHLSEncrypted(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	if encrypted {
+		return "", fmt.Errorf("%w: %s has encrypted HLS", domain.ErrDownloadNotAllowed, videoID)
+	}
+	return i.storage.PresignGetURL(ctx, videoSourceKey(videoID), downloadURLExpires)
+}
@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func newDownloadURLTest(t *testing.T, encryptionKeys int64) *Infrastructure {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_S3_ENDPOINT", "http://s3.example.com")
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"CountHLSEncryptionKeysByVideoID": {{encryptionKeys}}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	return &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}, storage: NewS3Backend("video")}
+}
+
+func Test_ダウンロード用の署名付きURL(t *testing.T) {
+	i := newDownloadURLTest(t, 0)
+
+	got, err := i.PresignVideoDownloadURL(context.Background(), "video_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.PresignVideoDownloadURL() error = %v", err)
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Path != "/video/video_1/source_video_1.mp4" {
+		t.Errorf("path = %s, want /video/video_1/source_video_1.mp4", u.Path)
+	}
+	query := u.Query()
+	if query.Get("X-Amz-Expires") != "900" {
+		t.Errorf("X-Amz-Expires = %s, want 900", query.Get("X-Amz-Expires"))
+	}
+	if query.Get("response-content-disposition") != `attachment; filename="source_video_1.mp4"` {
+		t.Errorf("response-content-disposition = %s", query.Get("response-content-disposition"))
+	}
+}
+
+// 元のMP4は暗号化されていないため、HLSを暗号化した動画はダウンロードさせない
+func Test_HLSを暗号化した動画のダウンロード用の署名付きURL(t *testing.T) {
+	i := newDownloadURLTest(t, 1)
+
+	_, err := i.PresignVideoDownloadURL(context.Background(), "video_1")
+	if !errors.Is(err, domain.ErrDownloadNotAllowed) {
+		t.Errorf("Infrastructure.PresignVideoDownloadURL() error = %v, want %v", err, domain.ErrDownloadNotAllowed)
+	}
+}
@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// ffmpegの引数のうち入力URLに置き換える部分
+	ffmpegInput = "{input}"
+	// ffmpegに渡す署名付きURLの有効期限
+	presignedURLExpires = 15 * time.Minute
+)
+
+// 保存先のURLの動画をffmpegの入力にする
+// 公開されていないオブジェクトは署名付きURLを発行し、ローカルにダウンロードせずに渡す
+// HLSはプレイリストからセグメントを相対パスで参照するため署名せずにそのまま渡す
+func (i *Infrastructure) ffmpegFromObjectURL(ctx context.Context, operation, url string, args []string) ([]byte, error) {
+	input, err := i.ffmpegInputURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return runFFmpeg(ctx, operation, replaceFFmpegInput(args, input))
+}
+
+func (i *Infrastructure) ffmpegInputURL(ctx context.Context, url string) (string, error) {
+	bucketName, key, ok := i.splitObjectURL(url)
+	if !ok || strings.HasSuffix(key, ".m3u8") {
+		return url, nil
+	}
+	return i.bucket(bucketName).PresignGetURL(ctx, key, presignedURLExpires)
+}
+
+// ffmpegが続けて失敗している間はffmpegBreakerが実行せずにErrFFmpegCircuitOpenを返す
+// 実行した場合のみoperationごとに実行時間を記録する
+func runFFmpeg(ctx context.Context, operation string, args []string) ([]byte, error) {
+	var result []byte
+	err := ffmpegBreaker.run(ctx, func() error {
+		start := time.Now()
+		defer func() { ffmpegMetrics.ObserveFFmpegDuration(operation, time.Since(start)) }()
+
+		cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+		slog.DebugContext(ctx, "run ffmpeg", "operation", operation, "args", cmd.Args)
+		var err error
+		result, err = cmd.CombinedOutput()
+		if err != nil {
+			// 失敗した原因はffmpegの出力にしか残らない
+			slog.ErrorContext(ctx, "ffmpeg failed", "operation", operation, "duration", time.Since(start), "output", string(result), "error", err)
+			err = fmt.Errorf("failed to execute ffmpeg command: %w", err)
+			if isFFmpegInputFailure(ctx, err, result) {
+				return &ffmpegInputError{err: err}
+			}
+			return err
+		}
+		return nil
+	})
+	return result, err
+}
+
+func replaceFFmpegInput(args []string, input string) []string {
+	replaced := make([]string, len(args))
+	for n, arg := range args {
+		if arg == ffmpegInput {
+			arg = input
+		}
+		replaced[n] = arg
+	}
+	return replaced
+}
@@ -0,0 +1,194 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	// ffmpegCircuitFailureWindowの間にこの回数続けて失敗したら遮断する
+	ffmpegCircuitFailureThreshold = 5
+	ffmpegCircuitFailureWindow    = 60 * time.Second
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	// 遮断中。cooldownが過ぎるまですべての実行を断る
+	circuitOpen
+	// cooldownが過ぎた後。1つだけ実行して成功すれば閉じ、失敗すれば再び遮断する
+	circuitHalfOpen
+)
+
+// ffmpegが壊れている場合などに、失敗するとわかっている実行でリクエストを待たせないようにする
+type ffmpegCircuitBreaker struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	state    circuitState
+	// 連続した失敗の回数と、その最初の失敗の時刻
+	failures       int
+	firstFailureAt time.Time
+	openedAt       time.Time
+	probing        bool
+	// テストで時刻を差し替えられるようにしている
+	now func() time.Time
+}
+
+func newFFmpegCircuitBreaker(cooldown time.Duration) *ffmpegCircuitBreaker {
+	return &ffmpegCircuitBreaker{
+		cooldown: cooldown,
+		now:      time.Now,
+	}
+}
+
+// すべてのffmpegの実行で共有する。cooldownはNewInfrastructureで設定から変更する
+var ffmpegBreaker = newFFmpegCircuitBreaker(defaultFFmpegCircuitCooldown)
+
+func (b *ffmpegCircuitBreaker) setCooldown(cooldown time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cooldown = cooldown
+}
+
+// 実行してよければnilを返す。遮断中はErrFFmpegCircuitOpenを返す
+func (b *ffmpegCircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = circuitHalfOpen
+	}
+
+	switch b.state {
+	case circuitOpen:
+		return fmt.Errorf("%w: retry after %s", domain.ErrFFmpegCircuitOpen, b.openedAt.Add(b.cooldown).Format(time.RFC3339))
+	case circuitHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w: probing", domain.ErrFFmpegCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// allowで許可した実行の結果を記録する
+func (b *ffmpegCircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.state = circuitClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	now := b.now()
+	if b.state == circuitHalfOpen {
+		b.open(now)
+		return
+	}
+
+	if b.failures == 0 || now.Sub(b.firstFailureAt) > ffmpegCircuitFailureWindow {
+		b.failures = 0
+		b.firstFailureAt = now
+	}
+	b.failures++
+	if b.failures >= ffmpegCircuitFailureThreshold {
+		b.open(now)
+	}
+}
+
+func (b *ffmpegCircuitBreaker) open(now time.Time) {
+	slog.Warn("ffmpeg circuit breaker opened", "failures", b.failures, "cooldown", b.cooldown)
+	b.state = circuitOpen
+	b.openedAt = now
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *ffmpegCircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = circuitClosed
+	b.failures = 0
+	b.firstFailureAt = time.Time{}
+	b.openedAt = time.Time{}
+	b.probing = false
+}
+
+// 呼び出し元がキャンセルした場合はffmpegの失敗として数えない
+// 入力の動画が不正で失敗した場合はffmpegは動いているため成功として記録する
+func (b *ffmpegCircuitBreaker) run(ctx context.Context, f func() error) error {
+	err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	err = f()
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		b.mu.Lock()
+		b.probing = false
+		b.mu.Unlock()
+		return err
+	}
+	var inputErr *ffmpegInputError
+	if errors.As(err, &inputErr) {
+		b.record(nil)
+		return err
+	}
+	b.record(err)
+	return err
+}
+
+// ffmpegが入力を処理できずに終了した場合のエラー
+type ffmpegInputError struct {
+	err error
+}
+
+func (e *ffmpegInputError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ffmpegInputError) Unwrap() error {
+	return e.err
+}
+
+// ffmpegの出力にこれらが含まれる場合は、入力ではなく保存先や実行環境の問題で失敗したとみなす
+// 保存先から署名付きURLで読む場合のHTTPのエラーも含める
+var ffmpegInfrastructureFailureMessages = []string{
+	"Connection refused",
+	"Connection reset",
+	"Connection timed out",
+	"Network is unreachable",
+	"Failed to resolve hostname",
+	"Server returned",
+	"HTTP error",
+	"Input/output error",
+	"No space left on device",
+	"Cannot allocate memory",
+}
+
+// ffmpegを起動できなかった場合、タイムアウトした場合、保存先の読み書きに失敗した場合以外の
+// 0以外の終了コードは入力の問題として扱う
+func isFFmpegInputFailure(ctx context.Context, err error, output []byte) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || ctx.Err() != nil {
+		return false
+	}
+	for _, message := range ffmpegInfrastructureFailureMessages {
+		if bytes.Contains(output, []byte(message)) {
+			return false
+		}
+	}
+	return true
+}
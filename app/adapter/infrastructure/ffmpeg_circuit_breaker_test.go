@@ -0,0 +1,171 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_ffmpegのサーキットブレーカー(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newFFmpegCircuitBreaker(30 * time.Second)
+	b.now = func() time.Time { return now }
+	t.Cleanup(b.Reset)
+
+	ctx := context.Background()
+	errFFmpeg := errors.New("ffmpeg crashed")
+	calls := 0
+	fail := func() error {
+		calls++
+		return errFFmpeg
+	}
+	succeed := func() error {
+		calls++
+		return nil
+	}
+
+	for n := 0; n < ffmpegCircuitFailureThreshold; n++ {
+		if err := b.run(ctx, fail); !errors.Is(err, errFFmpeg) {
+			t.Fatalf("ffmpegCircuitBreaker.run() error = %v, want %v", err, errFFmpeg)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// 遮断中はffmpegを実行しない
+	if err := b.run(ctx, succeed); !errors.Is(err, domain.ErrFFmpegCircuitOpen) {
+		t.Fatalf("ffmpegCircuitBreaker.run() error = %v, want %v", err, domain.ErrFFmpegCircuitOpen)
+	}
+	if calls != ffmpegCircuitFailureThreshold {
+		t.Errorf("calls = %d, want %d", calls, ffmpegCircuitFailureThreshold)
+	}
+
+	// cooldownが過ぎると1つだけ試し、失敗すると再び遮断する
+	now = now.Add(30 * time.Second)
+	if err := b.run(ctx, fail); !errors.Is(err, errFFmpeg) {
+		t.Fatalf("ffmpegCircuitBreaker.run() probe error = %v, want %v", err, errFFmpeg)
+	}
+	if err := b.run(ctx, succeed); !errors.Is(err, domain.ErrFFmpegCircuitOpen) {
+		t.Fatalf("ffmpegCircuitBreaker.run() after failed probe error = %v, want %v", err, domain.ErrFFmpegCircuitOpen)
+	}
+
+	// 試した実行が成功すると閉じる
+	now = now.Add(30 * time.Second)
+	if err := b.run(ctx, succeed); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.run() probe error = %v", err)
+	}
+	if err := b.run(ctx, succeed); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.run() after closed error = %v", err)
+	}
+}
+
+func Test_ffmpegのサーキットブレーカーの半開状態(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newFFmpegCircuitBreaker(30 * time.Second)
+	b.now = func() time.Time { return now }
+	for n := 0; n < ffmpegCircuitFailureThreshold; n++ {
+		b.record(errors.New("ffmpeg crashed"))
+	}
+	now = now.Add(30 * time.Second)
+
+	// 試している間は他の実行を通さない
+	if err := b.allow(); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.allow() probe error = %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, domain.ErrFFmpegCircuitOpen) {
+		t.Errorf("ffmpegCircuitBreaker.allow() while probing error = %v, want %v", err, domain.ErrFFmpegCircuitOpen)
+	}
+
+	b.Reset()
+	if err := b.allow(); err != nil {
+		t.Errorf("ffmpegCircuitBreaker.allow() after reset error = %v", err)
+	}
+}
+
+func Test_ffmpegのサーキットブレーカーの失敗の数え方(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newFFmpegCircuitBreaker(30 * time.Second)
+	b.now = func() time.Time { return now }
+	errFFmpeg := errors.New("ffmpeg crashed")
+
+	// 間に成功を挟むと続けて失敗したとはみなさない
+	for n := 0; n < ffmpegCircuitFailureThreshold-1; n++ {
+		b.record(errFFmpeg)
+	}
+	b.record(nil)
+	b.record(errFFmpeg)
+	if err := b.allow(); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.allow() after success error = %v", err)
+	}
+
+	// ウィンドウより前の失敗は数えない
+	b.Reset()
+	for n := 0; n < ffmpegCircuitFailureThreshold-1; n++ {
+		b.record(errFFmpeg)
+	}
+	now = now.Add(ffmpegCircuitFailureWindow + time.Second)
+	b.record(errFFmpeg)
+	if err := b.allow(); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.allow() after window error = %v", err)
+	}
+
+	// 呼び出し元のキャンセルは失敗として数えない
+	b.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for n := 0; n < ffmpegCircuitFailureThreshold; n++ {
+		_ = b.run(ctx, func() error { return ctx.Err() })
+	}
+	if err := b.allow(); err != nil {
+		t.Errorf("ffmpegCircuitBreaker.allow() after canceled error = %v", err)
+	}
+}
+
+func Test_ffmpegの入力の問題による失敗(t *testing.T) {
+	b := newFFmpegCircuitBreaker(30 * time.Second)
+	ctx := context.Background()
+
+	// 不正な入力で終了した場合は続けて失敗しても遮断しない
+	inputErr := &ffmpegInputError{err: errors.New("exit status 1")}
+	for n := 0; n < ffmpegCircuitFailureThreshold; n++ {
+		if err := b.run(ctx, func() error { return inputErr }); !errors.Is(err, inputErr) {
+			t.Fatalf("ffmpegCircuitBreaker.run() error = %v, want %v", err, inputErr)
+		}
+	}
+	if err := b.allow(); err != nil {
+		t.Fatalf("ffmpegCircuitBreaker.allow() after input errors error = %v", err)
+	}
+}
+
+func Test_ffmpegの失敗の分類(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 1").Run()
+	if exitErr == nil {
+		t.Fatal("want exit error")
+	}
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		output string
+		want   bool
+	}{
+		{name: "不正な入力", ctx: context.Background(), err: exitErr, output: "moov atom not found\nInvalid data found when processing input", want: true},
+		{name: "保存先の読み込みの失敗", ctx: context.Background(), err: exitErr, output: "Server returned 503 Service Unavailable", want: false},
+		{name: "ディスクの容量不足", ctx: context.Background(), err: exitErr, output: "No space left on device", want: false},
+		{name: "タイムアウト", ctx: expired, err: exitErr, want: false},
+		{name: "起動できない", ctx: context.Background(), err: exec.ErrNotFound, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFFmpegInputFailure(tt.ctx, tt.err, []byte(tt.output)); got != tt.want {
+				t.Errorf("isFFmpegInputFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_保存先のURLの分解(t *testing.T) {
+	t.Setenv("AWS_S3_URL", "https://s3.example.com")
+	i := &Infrastructure{buckets: map[string]StorageBackend{
+		videoBucketName:    NewS3Backend(videoBucketName),
+		cutVideoBucketName: NewS3Backend(cutVideoBucketName),
+	}}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantBucket string
+		wantKey    string
+		wantOK     bool
+	}{
+		{
+			name:       "hls",
+			url:        "https://s3.example.com/video/video_1/output_video_1.m3u8",
+			wantBucket: "video",
+			wantKey:    "video_1/output_video_1.m3u8",
+			wantOK:     true,
+		},
+		{
+			name:       "mp4",
+			url:        "https://s3.example.com/cut-video/video_1.mp4",
+			wantBucket: "cut-video",
+			wantKey:    "video_1.mp4",
+			wantOK:     true,
+		},
+		{
+			name:   "other host",
+			url:    "https://cdn.example.com/video/video_1.mp4",
+			wantOK: false,
+		},
+		{
+			name:   "unknown bucket",
+			url:    "https://s3.example.com/other/video_1.mp4",
+			wantOK: false,
+		},
+		{
+			name:   "bucket only",
+			url:    "https://s3.example.com/video",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, key, ok := i.splitObjectURL(tt.url)
+			if bucket != tt.wantBucket || key != tt.wantKey || ok != tt.wantOK {
+				t.Errorf("Infrastructure.splitObjectURL() = (%v, %v, %v), want (%v, %v, %v)", bucket, key, ok, tt.wantBucket, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_ffmpegの入力の置き換え(t *testing.T) {
+	args := []string{"-ss", "1", "-i", ffmpegInput, "-vframes", "1"}
+	want := []string{"-ss", "1", "-i", "https://example.com/video.mp4", "-vframes", "1"}
+	if got := replaceFFmpegInput(args, "https://example.com/video.mp4"); !reflect.DeepEqual(got, want) {
+		t.Errorf("replaceFFmpegInput() = %v, want %v", got, want)
+	}
+	if args[3] != ffmpegInput {
+		t.Errorf("replaceFFmpegInput() modified args: %v", args)
+	}
+}
@@ -0,0 +1,169 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ffprobeOutput struct {
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+		Size     string `json:"size"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+	} `json:"streams"`
+}
+
+type ffprobeAudioOutput struct {
+	Streams []struct {
+		Tags struct {
+			Language string `json:"language"`
+			Title    string `json:"title"`
+		} `json:"tags"`
+	} `json:"streams"`
+}
+
+// audio_tracksはtext型なので、保存するJSONはこの大きさまでにする
+const maxAudioTracksJSONBytes = 64 << 10
+
+// アップロードされてsourceに置いた動画ファイルをffprobeで解析する
+func (i *Infrastructure) ProbeVideoMetadata(ctx context.Context, source string) (_ *domain.VideoMetadata, err error) {
+	ctx, span := infraSpan(ctx, "ProbeVideoMetadata")
+	span.SetAttributes(attribute.String("source", source))
+	defer func() { endSpan(span, err) }()
+
+	tempMp4 := source
+	output, err := runFFprobe(ctx, "-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-select_streams", "v:0", tempMp4)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseFFprobeOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = runFFprobe(ctx, "-v", "quiet", "-print_format", "json", "-show_streams", "-select_streams", "a", tempMp4)
+	if err != nil {
+		return nil, err
+	}
+	metadata.AudioTracks, err = parseFFprobeAudioTracks(output)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func runFFprobe(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe", args...)
+	slog.DebugContext(ctx, "run ffprobe", "args", cmd.Args)
+	output, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: ffprobe not found", domain.ErrInvalidVideo)
+		}
+		return nil, fmt.Errorf("%w: failed to execute ffprobe command: %v", domain.ErrInvalidVideo, err)
+	}
+	return output, nil
+}
+
+func parseFFprobeOutput(output []byte) (*domain.VideoMetadata, error) {
+	var probe ffprobeOutput
+	err := json.Unmarshal(output, &probe)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse ffprobe output: %v", domain.ErrInvalidVideo, err)
+	}
+
+	seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid duration %q", domain.ErrInvalidVideo, probe.Format.Duration)
+	}
+	duration := time.Duration(seconds * float64(time.Second))
+
+	bitrate, err := strconv.ParseInt(probe.Format.BitRate, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid bitrate %q", domain.ErrInvalidVideo, probe.Format.BitRate)
+	}
+
+	var width, height int
+	for _, stream := range probe.Streams {
+		if stream.CodecType == "video" {
+			width = stream.Width
+			height = stream.Height
+			break
+		}
+	}
+
+	metadata := domain.NewVideoMetadata(duration, width, height, bitrate)
+	// サイズはストレージの使用量の計算にのみ使うため、取得できなくてもエラーにしない
+	metadata.Size, _ = strconv.ParseInt(probe.Format.Size, 10, 64)
+	err = metadata.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// ffprobeの言語はISO 639-2("jpn"など)なのでBCP 47にする。"und"や読めない言語は空にする
+func parseFFprobeAudioTracks(output []byte) ([]domain.AudioTrack, error) {
+	var probe ffprobeAudioOutput
+	err := json.Unmarshal(output, &probe)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse ffprobe output: %v", domain.ErrInvalidVideo, err)
+	}
+
+	tracks := make([]domain.AudioTrack, 0, len(probe.Streams))
+	for n, stream := range probe.Streams {
+		lang := ""
+		if stream.Tags.Language != "" && stream.Tags.Language != "und" {
+			lang, _ = domain.NormalizeLanguage(stream.Tags.Language)
+		}
+		tracks = append(tracks, domain.AudioTrack{
+			Index:    n,
+			Language: lang,
+			Label:    stream.Tags.Title,
+		})
+	}
+	return tracks, nil
+}
+
+func marshalAudioTracks(tracks []domain.AudioTrack) (sql.NullString, error) {
+	if len(tracks) == 0 {
+		return sql.NullString{}, nil
+	}
+	bytes, err := json.Marshal(tracks)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if len(bytes) > maxAudioTracksJSONBytes {
+		return sql.NullString{}, fmt.Errorf("%w: audio tracks must be %d bytes or less", domain.ErrInvalidVideo, maxAudioTracksJSONBytes)
+	}
+	return sql.NullString{String: string(bytes), Valid: true}, nil
+}
+
+func parseAudioTracks(s sql.NullString) ([]domain.AudioTrack, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+	var tracks []domain.AudioTrack
+	err := json.Unmarshal([]byte(s.String), &tracks)
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
@@ -0,0 +1,150 @@
+package infrastructure
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_ffprobeの出力の解析(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    *domain.VideoMetadata
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			output:  `{"streams": [{"codec_type": "video", "width": 1920, "height": 1080}], "format": {"filename": "temp/video.mp4", "duration": "12.345000", "bit_rate": "4500000"}}`,
+			want:    domain.NewVideoMetadata(12345*time.Millisecond, 1920, 1080, 4500000),
+			wantErr: false,
+		},
+		{
+			name:    "with size",
+			output:  `{"streams": [{"codec_type": "video", "width": 1920, "height": 1080}], "format": {"duration": "12.345000", "bit_rate": "4500000", "size": "6944062"}}`,
+			want:    &domain.VideoMetadata{Duration: 12345 * time.Millisecond, Width: 1920, Height: 1080, Bitrate: 4500000, Size: 6944062},
+			wantErr: false,
+		},
+		{
+			name:    "portrait 144p",
+			output:  `{"streams": [{"codec_type": "video", "width": 144, "height": 256}], "format": {"duration": "3.0", "bit_rate": "64000"}}`,
+			want:    domain.NewVideoMetadata(3*time.Second, 144, 256, 64000),
+			wantErr: false,
+		},
+		{
+			name:    "duration is zero",
+			output:  `{"streams": [{"codec_type": "video", "width": 1920, "height": 1080}], "format": {"duration": "0.000000", "bit_rate": "4500000"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "duration is empty",
+			output:  `{"streams": [{"codec_type": "video", "width": 1920, "height": 1080}], "format": {"bit_rate": "4500000"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "resolution is below 144p",
+			output:  `{"streams": [{"codec_type": "video", "width": 192, "height": 108}], "format": {"duration": "12.345000", "bit_rate": "4500000"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "bitrate is below 64kbps",
+			output:  `{"streams": [{"codec_type": "video", "width": 1920, "height": 1080}], "format": {"duration": "12.345000", "bit_rate": "63999"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "video stream is missing",
+			output:  `{"streams": [], "format": {"duration": "12.345000", "bit_rate": "4500000"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "output is not json",
+			output:  `Invalid data found when processing input`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFFprobeOutput([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFFprobeOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, domain.ErrInvalidVideo) {
+					t.Errorf("parseFFprobeOutput() error = %v, want %v", err, domain.ErrInvalidVideo)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFFprobeOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ffprobeの音声トラックの解析(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []domain.AudioTrack
+		wantErr bool
+	}{
+		{
+			name:   "multiple tracks",
+			output: `{"streams": [{"index": 1, "codec_type": "audio", "tags": {"language": "jpn"}}, {"index": 2, "codec_type": "audio", "tags": {"language": "eng", "title": "Commentary"}}]}`,
+			want: []domain.AudioTrack{
+				{Index: 0, Language: "ja"},
+				{Index: 1, Language: "en", Label: "Commentary"},
+			},
+		},
+		{
+			name:   "language is unknown",
+			output: `{"streams": [{"index": 1, "codec_type": "audio", "tags": {"language": "und"}}, {"index": 2, "codec_type": "audio"}]}`,
+			want:   []domain.AudioTrack{{Index: 0}, {Index: 1}},
+		},
+		{
+			name:   "no audio",
+			output: `{"streams": []}`,
+			want:   []domain.AudioTrack{},
+		},
+		{
+			name:    "output is not json",
+			output:  `Invalid data found when processing input`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFFprobeAudioTracks([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFFprobeAudioTracks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFFprobeAudioTracks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_音声トラックの保存(t *testing.T) {
+	tracks := []domain.AudioTrack{{Index: 0, Language: "ja"}, {Index: 1, Language: "en", Label: "Commentary"}}
+	stored, err := marshalAudioTracks(tracks)
+	if err != nil {
+		t.Fatalf("marshalAudioTracks() error = %v", err)
+	}
+	got, err := parseAudioTracks(stored)
+	if err != nil {
+		t.Fatalf("parseAudioTracks() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, tracks) {
+		t.Errorf("parseAudioTracks() = %v, want %v", got, tracks)
+	}
+
+	_, err = marshalAudioTracks([]domain.AudioTrack{{Label: strings.Repeat("a", maxAudioTracksJSONBytes)}})
+	if !errors.Is(err, domain.ErrInvalidVideo) {
+		t.Errorf("marshalAudioTracks() error = %v, want %v", err, domain.ErrInvalidVideo)
+	}
+}
@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// 1つのサービスの確認にかける時間。止まっているサービスがあってもヘルスチェック自体は早く返す
+const healthCheckTimeout = 2 * time.Second
+
+// 接続を確認できる保存先のみ確認する
+type storagePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DB、Redis、保存先に接続できるかを確認する
+func (i *Infrastructure) HealthCheck(ctx context.Context) domain.HealthStatus {
+	ctx, span := infraSpan(ctx, "HealthCheck")
+	defer span.End()
+
+	status := domain.HealthStatus{
+		DB: checkComponent(ctx, func(ctx context.Context) error {
+			var one int
+			return i.db.SQL.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+		}),
+		Redis: checkComponent(ctx, func(ctx context.Context) error {
+			return i.redis.Ping(ctx).Err()
+		}),
+		Storage: checkComponent(ctx, func(ctx context.Context) error {
+			pinger, ok := i.storage.(storagePinger)
+			if !ok {
+				return nil
+			}
+			return pinger.Ping(ctx)
+		}),
+	}
+	status.Healthy = status.DB.Status == domain.StatusOK && status.Redis.Status == domain.StatusOK && status.Storage.Status == domain.StatusOK
+	return status
+}
+
+func checkComponent(ctx context.Context, check func(context.Context) error) domain.ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	err := check(ctx)
+	if err != nil {
+		return domain.ComponentStatus{Status: domain.StatusDegraded, Error: err.Error()}
+	}
+	return domain.ComponentStatus{Status: domain.StatusOK}
+}
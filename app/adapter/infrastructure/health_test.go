@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ヘルスチェック(t *testing.T) {
+	tests := []struct {
+		name        string
+		redisDown   bool
+		storageDown bool
+		wantHealthy bool
+	}{
+		{name: "すべて接続できる", wantHealthy: true},
+		{name: "Redisに接続できない", redisDown: true, wantHealthy: false},
+		{name: "保存先に書き込めない", storageDown: true, wantHealthy: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			if tt.redisDown {
+				mr.Close()
+			}
+			baseDir := t.TempDir()
+			if tt.storageDown {
+				// ファイルの下にはディレクトリを作れない
+				file := filepath.Join(baseDir, "file")
+				err := os.WriteFile(file, nil, 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				baseDir = filepath.Join(file, "video")
+			}
+			connector := &rowConnector{values: []driver.Value{int64(1)}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:   client,
+				storage: &LocalFilesystemBackend{BaseDir: baseDir},
+			}
+
+			got := i.HealthCheck(context.Background())
+			if got.Healthy != tt.wantHealthy {
+				t.Errorf("Infrastructure.HealthCheck() Healthy = %v, want %v: %+v", got.Healthy, tt.wantHealthy, got)
+			}
+			if got.DB.Status != domain.StatusOK {
+				t.Errorf("DB = %+v, want ok", got.DB)
+			}
+			if (got.Redis.Status == domain.StatusDegraded) != tt.redisDown || (tt.redisDown && got.Redis.Error == "") {
+				t.Errorf("Redis = %+v, want degraded = %v", got.Redis, tt.redisDown)
+			}
+			if (got.Storage.Status == domain.StatusDegraded) != tt.storageDown || (tt.storageDown && got.Storage.Error == "") {
+				t.Errorf("Storage = %+v, want degraded = %v", got.Storage, tt.storageDown)
+			}
+		})
+	}
+}
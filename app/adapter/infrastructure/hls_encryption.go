@@ -0,0 +1,399 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// keyIDはプレイリストのURIにそのまま書くため、URLで使える文字に限る
+var hlsKeyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// テストで保存先からのダウンロードを差し替えられるようにしている
+var downloadHLSObject = (*Infrastructure).downloadObject
+
+// 暗号化したセグメントを置くディレクトリ。プレイリストと同じディレクトリの下に鍵ごとに作る
+func hlsEncryptedSegmentDir(keyID string) string {
+	return "enc_" + keyID
+}
+
+// 動画のHLSのセグメントをAES-128で暗号化し、プレイリストに鍵のURIを書き込む
+// 鍵はkeyIDでhls_encryption_keysに保存し、GetHLSEncryptionKeyで配信する
+// 暗号化したセグメントは別のディレクトリに置き、全てアップロードしてからプレイリストを差し替えて元のセグメントを消す
+// 途中で失敗しても、プレイリストが暗号化したセグメントと元のセグメントを混ぜて参照することはない
+// DASHと元のMP4は暗号化しないため、DASHがある動画は暗号化せず、暗号化した動画はDASHの生成とダウンロードをさせない
+func (i *Infrastructure) EncryptHLSSegments(ctx context.Context, videoID, keyID string) (err error) {
+	ctx, span := infraSpan(ctx, "EncryptHLSSegments")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("keyID", keyID))
+	defer func() { endSpan(span, err) }()
+
+	if !hlsKeyIDPattern.MatchString(keyID) {
+		return fmt.Errorf("%w: invalid key id %q", domain.ErrInvalidInput, keyID)
+	}
+	if i.config.HLSKeyServerURL == "" {
+		return errors.New("HLS_KEY_SERVER_URL is not set")
+	}
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if video.DashManifestUrl.Valid {
+		return fmt.Errorf("%w: %s has unencrypted DASH output", domain.ErrInvalidInput, videoID)
+	}
+	_, playlistKey, ok := i.splitObjectURL(video.VideoUrl)
+	if !ok {
+		return fmt.Errorf("%w: %s is not stored in storage", domain.ErrInvalidVideo, video.VideoUrl)
+	}
+
+	playlists, err := i.downloadMediaPlaylists(ctx, video.VideoUrl, playlistKey)
+	if err != nil {
+		return err
+	}
+	// 2回暗号化すると元に戻せなくなるため、暗号化済みのプレイリストは元のセグメントが残っていれば消すだけにする
+	var plain []hlsMediaPlaylist
+	for _, playlist := range playlists {
+		if !strings.Contains(playlist.content, "#EXT-X-KEY") {
+			plain = append(plain, playlist)
+			continue
+		}
+		err = i.deletePlainHLSSegments(ctx, playlist)
+		if err != nil {
+			return err
+		}
+	}
+	if len(plain) == 0 {
+		return fmt.Errorf("%w: %s is already encrypted", domain.ErrInvalidInput, videoID)
+	}
+
+	key := make([]byte, aes.BlockSize)
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(key)
+	if err != nil {
+		return err
+	}
+	_, err = rand.Read(iv)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "hls_encryption_*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	encryptedDir := hlsEncryptedSegmentDir(keyID)
+	err = i.uploadEncryptedHLSSegments(ctx, tempDir, encryptedDir, plain, key, iv)
+	if err == nil {
+		// プレイリストを差し替えた後に鍵を失うと再生できなくなるため、先に保存する
+		err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionEncryptHLS, func(q *sqlc.Queries) error {
+			_, err := q.CreateHLSEncryptionKey(ctx, sqlc.CreateHLSEncryptionKeyParams{
+				KeyID:     keyID,
+				VideoID:   videoID,
+				KeyBytes:  key,
+				Iv:        iv,
+				CreatedAt: time.Now(),
+			})
+			return err
+		})
+	}
+	if err != nil {
+		// まだプレイリストから参照されていないため、アップロードした分は消してよい
+		for _, playlist := range plain {
+			deleteErr := i.storage.DeletePrefix(ctx, path.Join(path.Dir(playlist.key), encryptedDir)+"/")
+			if deleteErr != nil {
+				i.log().ErrorContext(ctx, "failed to delete encrypted segments", "videoID", videoID, "error", deleteErr)
+			}
+		}
+		return err
+	}
+
+	keyURI := strings.TrimSuffix(i.config.HLSKeyServerURL, "/") + "/" + keyID
+	encrypted := make([]hlsMediaPlaylist, 0, len(plain))
+	for _, playlist := range plain {
+		content := hlsEncryptedPlaylist(playlist.content, keyURI, encryptedDir, iv)
+		playlistPath := filepath.Join(tempDir, path.Base(playlist.key))
+		err = os.WriteFile(playlistPath, []byte(content), 0644)
+		if err != nil {
+			return err
+		}
+		err = i.uploadStreamingFile(ctx, playlistPath, playlist.key)
+		if err != nil {
+			return err
+		}
+		encrypted = append(encrypted, hlsMediaPlaylist{key: playlist.key, content: content})
+	}
+
+	// 全てのプレイリストを差し替えてから元のセグメントを消す
+	// 失敗した場合は同じ動画に対してもう一度実行すると消せる
+	for _, playlist := range encrypted {
+		err = i.deletePlainHLSSegments(ctx, playlist)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 暗号化した動画はDASHと元のMP4から暗号化されていない映像を取得できないようにする
+func (i *Infrastructure) isHLSEncrypted(ctx context.Context, videoID string) (bool, error) {
+	count, err := i.db.Database.CountHLSEncryptionKeysByVideoID(ctx, videoID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 全てのプレイリストのセグメントを暗号化し、各プレイリストのディレクトリのencryptedDirにアップロードする
+func (i *Infrastructure) uploadEncryptedHLSSegments(ctx context.Context, tempDir, encryptedDir string, playlists []hlsMediaPlaylist, key, iv []byte) error {
+	for _, playlist := range playlists {
+		dir := path.Dir(playlist.key)
+		for _, segment := range hlsPlaylistURIs(playlist.content) {
+			err := i.encryptHLSSegment(ctx, tempDir, path.Join(dir, segment), path.Join(dir, encryptedDir, segment), key, iv)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", segment, err)
+			}
+		}
+	}
+	return nil
+}
+
+// 暗号化済みのプレイリストが参照しているセグメントの、暗号化する前のセグメントを消す
+func (i *Infrastructure) deletePlainHLSSegments(ctx context.Context, playlist hlsMediaPlaylist) error {
+	dir := path.Dir(playlist.key)
+	for _, segment := range hlsPlaylistURIs(playlist.content) {
+		if path.Dir(segment) == "." {
+			continue
+		}
+		err := i.storage.Delete(ctx, path.Join(dir, path.Base(segment)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type hlsMediaPlaylist struct {
+	key     string
+	content string
+}
+
+// マスタープレイリストの場合は各画質のプレイリストを取得する
+func (i *Infrastructure) downloadMediaPlaylists(ctx context.Context, url, key string) ([]hlsMediaPlaylist, error) {
+	content, err := i.downloadHLSText(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(content, "#EXT-X-STREAM-INF") {
+		return []hlsMediaPlaylist{{key: key, content: content}}, nil
+	}
+
+	var playlists []hlsMediaPlaylist
+	for _, uri := range hlsPlaylistURIs(content) {
+		mediaKey := path.Join(path.Dir(key), uri)
+		media, err := i.downloadHLSText(ctx, strings.TrimSuffix(url, key)+mediaKey)
+		if err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, hlsMediaPlaylist{key: mediaKey, content: media})
+	}
+	return playlists, nil
+}
+
+func (i *Infrastructure) downloadHLSText(ctx context.Context, url string) (string, error) {
+	body, err := downloadHLSObject(i, ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// 保存先のsegmentKeyのセグメントを暗号化してencryptedKeyにアップロードする
+func (i *Infrastructure) encryptHLSSegment(ctx context.Context, tempDir, segmentKey, encryptedKey string, key, iv []byte) error {
+	body, err := downloadHLSObject(i, ctx, i.objectURL(videoBucketName, segmentKey))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	plain, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encryptAES128CBC(plain, key, iv)
+	if err != nil {
+		return err
+	}
+	segmentPath := filepath.Join(tempDir, path.Base(segmentKey))
+	err = os.WriteFile(segmentPath, encrypted, 0644)
+	if err != nil {
+		return err
+	}
+	return i.uploadStreamingFile(ctx, segmentPath, encryptedKey)
+}
+
+// HLSのAES-128はPKCS7でパディングしたCBCで暗号化する
+func encryptAES128CBC(plain, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(append([]byte{}, plain...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+	return encrypted, nil
+}
+
+// タグとコメント以外の行はセグメントかプレイリストのURI
+func hlsPlaylistURIs(playlist string) []string {
+	var uris []string
+	scanner := bufio.NewScanner(strings.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			uris = append(uris, line)
+		}
+	}
+	return uris
+}
+
+// 最初のセグメントより前に#EXT-X-KEYを入れ、全てのセグメントに同じ鍵とIVを使う
+// セグメントのURIは暗号化したセグメントを置いたencryptedDirの下に書き換える
+func hlsEncryptedPlaylist(playlist, keyURI, encryptedDir string, iv []byte) string {
+	keyTag := fmt.Sprintf("#EXT-X-KEY:METHOD=AES-128,URI=\"%s\",IV=0x%s", keyURI, hex.EncodeToString(iv))
+	lines := strings.Split(playlist, "\n")
+	inserted := false
+	for n := 0; n < len(lines); n++ {
+		line := strings.TrimSpace(lines[n])
+		if !inserted && strings.HasPrefix(line, "#EXTINF") {
+			lines = append(lines[:n], append([]string{keyTag}, lines[n:]...)...)
+			inserted = true
+			n++
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines[n] = path.Join(encryptedDir, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 再生するユーザーに渡す、keyIDの鍵を取得するためのトークンを作る
+// ログインしていないユーザーはuserIDを空にし、公開されている動画の鍵だけを取得できる
+func (i *Infrastructure) IssueHLSKeyToken(ctx context.Context, keyID, userID string) (_ string, err error) {
+	_, span := infraSpan(ctx, "IssueHLSKeyToken")
+	span.SetAttributes(attribute.String("keyID", keyID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	if !hlsKeyIDPattern.MatchString(keyID) {
+		return "", fmt.Errorf("%w: invalid key id %q", domain.ErrInvalidInput, keyID)
+	}
+	if i.config.HLSKeyTokenSecret == "" {
+		return "", errors.New("HLS_KEY_TOKEN_SECRET is not set")
+	}
+	return hlsKeyToken(i.config.HLSKeyTokenSecret, keyID, userID, time.Now().Add(i.config.HLSKeyTokenTTL)), nil
+}
+
+// 鍵の配信エンドポイントから呼ぶ。トークンのユーザーが動画を見られる場合のみ鍵を返す
+func (i *Infrastructure) GetHLSEncryptionKey(ctx context.Context, keyID, userToken string) (_ []byte, err error) {
+	ctx, span := infraSpan(ctx, "GetHLSEncryptionKey")
+	span.SetAttributes(attribute.String("keyID", keyID))
+	defer func() { endSpan(span, err) }()
+
+	if i.config.HLSKeyTokenSecret == "" {
+		return nil, errors.New("HLS_KEY_TOKEN_SECRET is not set")
+	}
+	tokenKeyID, userID, err := parseHLSKeyToken(i.config.HLSKeyTokenSecret, userToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if tokenKeyID != keyID {
+		return nil, fmt.Errorf("%w: token is not for key %s", domain.ErrPermissionDenied, keyID)
+	}
+
+	key, err := i.db.Database.GetHLSEncryptionKey(ctx, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrHLSKeyNotFound, keyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	video, err := i.getUndeletedVideo(ctx, key.VideoID)
+	if err != nil {
+		return nil, err
+	}
+	if video.IsPrivate && video.UploaderID != userID && !i.isAdmin(userID) {
+		return nil, fmt.Errorf("%w: %s is private", domain.ErrPermissionDenied, key.VideoID)
+	}
+	if video.ExpiresAt.Valid && !video.ExpiresAt.Time.After(time.Now()) {
+		return nil, domain.ErrVideoExpired
+	}
+	return key.KeyBytes, nil
+}
+
+// <keyID>:<userID>:<有効期限のUnix時間>をbase64にし、HMAC-SHA256の署名を付ける
+func hlsKeyToken(secret, keyID, userID string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(keyID + ":" + userID + ":" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return payload + "." + hlsKeyTokenSignature(secret, payload)
+}
+
+func hlsKeyTokenSignature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseHLSKeyToken(secret, token string, now time.Time) (keyID, userID string, err error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(hlsKeyTokenSignature(secret, payload))) {
+		return "", "", fmt.Errorf("%w: invalid hls key token", domain.ErrPermissionDenied)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid hls key token", domain.ErrPermissionDenied)
+	}
+
+	// userIDに:が含まれていても分けられるように、keyIDは最初、有効期限は最後の:で区切る
+	keyID, rest, ok := strings.Cut(string(decoded), ":")
+	sep := strings.LastIndex(rest, ":")
+	if !ok || sep < 0 {
+		return "", "", fmt.Errorf("%w: invalid hls key token", domain.ErrPermissionDenied)
+	}
+	expiresAt, err := strconv.ParseInt(rest[sep+1:], 10, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid hls key token", domain.ErrPermissionDenied)
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return "", "", fmt.Errorf("%w: hls key token has expired", domain.ErrPermissionDenied)
+	}
+	return keyID, rest[:sep], nil
+}
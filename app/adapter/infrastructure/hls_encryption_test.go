@@ -0,0 +1,272 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// 暗号化のテスト用に、マスタープレイリストと1つの画質のHLSを保存した動画を用意する
+func newHLSEncryptionTest(t *testing.T, dashManifestURL driver.Value) (*Infrastructure, *MemoryBackend, *rowConnector) {
+	t.Helper()
+	storage := NewMemoryBackend()
+	objects := map[string]string{
+		"video_1/output_video_1.m3u8": "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-STREAM-INF:BANDWIDTH=928000,RESOLUTION=640x360,NAME=\"360p\"\n360p/index.m3u8\n",
+		"video_1/360p/index.m3u8":     "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0,\nindex0.ts\n#EXTINF:2.5,\nindex1.ts\n#EXT-X-ENDLIST\n",
+		"video_1/360p/index0.ts":      "segment 0",
+		"video_1/360p/index1.ts":      "segment 1",
+	}
+	for key, content := range objects {
+		storage.Upload(context.Background(), key, "", strings.NewReader(content))
+	}
+
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[1] = storage.PublicURL("video_1/output_video_1.m3u8")
+	row[len(row)-3] = dashManifestURL
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row}, "GetVideoForUpdate": {row}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+		storage: storage,
+		config: InfrastructureConfig{
+			HLSKeyServerURL:     "https://api.example.com/hls-key/",
+			S3UploadMaxAttempts: 1,
+		},
+	}
+	return i, storage, connector
+}
+
+func readMemoryObject(t *testing.T, storage *MemoryBackend, key string) (string, bool) {
+	t.Helper()
+	body, err := storage.Download(context.Background(), key)
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content), true
+}
+
+func Test_HLSのセグメントの暗号化(t *testing.T) {
+	i, storage, connector := newHLSEncryptionTest(t, nil)
+	var uploadedKeys []string
+	originalUpload := uploadStreamingObject
+	t.Cleanup(func() { uploadStreamingObject = originalUpload })
+	uploadStreamingObject = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+		uploadedKeys = append(uploadedKeys, key)
+		return originalUpload(i, ctx, path, bucketName, key, contentType)
+	}
+
+	err := i.EncryptHLSSegments(context.Background(), "video_1", "key_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.EncryptHLSSegments() error = %v", err)
+	}
+
+	// 鍵の保存は監査ログと同じトランザクションで行う
+	if !reflect.DeepEqual(connector.execNames, []string{"CreateHLSEncryptionKey", "CreateAuditLog"}) {
+		t.Fatalf("execs = %v, want [CreateHLSEncryptionKey CreateAuditLog]", connector.execNames)
+	}
+	key, iv := connector.execs[0][2].([]byte), connector.execs[0][3].([]byte)
+	if len(key) != 16 || len(iv) != 16 {
+		t.Fatalf("len(key) = %d, len(iv) = %d, want 16", len(key), len(iv))
+	}
+
+	// 暗号化したセグメントは別のディレクトリに置き、プレイリストはセグメントの後にアップロードする
+	wantKeys := []string{"video_1/360p/enc_key_1/index0.ts", "video_1/360p/enc_key_1/index1.ts", "video_1/360p/index.m3u8"}
+	if !reflect.DeepEqual(uploadedKeys, wantKeys) {
+		t.Errorf("uploaded = %v, want %v", uploadedKeys, wantKeys)
+	}
+	for n, name := range []string{"index0.ts", "index1.ts"} {
+		encrypted, _ := readMemoryObject(t, storage, "video_1/360p/enc_key_1/"+name)
+		got := decryptAES128CBC(t, []byte(encrypted), key, iv)
+		if want := fmt.Sprintf("segment %d", n); got != want {
+			t.Errorf("decrypted %s = %q, want %q", name, got, want)
+		}
+		// 差し替えた後は暗号化されていないセグメントを残さない
+		if _, ok := readMemoryObject(t, storage, "video_1/360p/"+name); ok {
+			t.Errorf("plain segment %s was not deleted", name)
+		}
+	}
+
+	wantPlaylist := "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n" +
+		"#EXT-X-KEY:METHOD=AES-128,URI=\"https://api.example.com/hls-key/key_1\",IV=0x" + hex.EncodeToString(iv) + "\n" +
+		"#EXTINF:6.0,\nenc_key_1/index0.ts\n#EXTINF:2.5,\nenc_key_1/index1.ts\n#EXT-X-ENDLIST\n"
+	if got, _ := readMemoryObject(t, storage, "video_1/360p/index.m3u8"); got != wantPlaylist {
+		t.Errorf("playlist = %q, want %q", got, wantPlaylist)
+	}
+}
+
+func Test_HLSのセグメントの暗号化の失敗(t *testing.T) {
+	i, storage, connector := newHLSEncryptionTest(t, nil)
+	storage.Delete(context.Background(), "video_1/360p/index1.ts")
+
+	err := i.EncryptHLSSegments(context.Background(), "video_1", "key_1")
+	if err == nil {
+		t.Fatal("Infrastructure.EncryptHLSSegments() error = nil")
+	}
+	// 鍵を保存せず、プレイリストとセグメントは元のまま残す
+	if len(connector.execs) != 0 {
+		t.Errorf("execs = %v, want none", connector.execNames)
+	}
+	if got, _ := readMemoryObject(t, storage, "video_1/360p/index.m3u8"); strings.Contains(got, "#EXT-X-KEY") {
+		t.Errorf("playlist was replaced: %q", got)
+	}
+	if got, _ := readMemoryObject(t, storage, "video_1/360p/index0.ts"); got != "segment 0" {
+		t.Errorf("plain segment = %q, want segment 0", got)
+	}
+	if _, ok := readMemoryObject(t, storage, "video_1/360p/enc_key_1/index0.ts"); ok {
+		t.Error("encrypted segment was not deleted")
+	}
+}
+
+func Test_暗号化済みのHLSの暗号化(t *testing.T) {
+	i, storage, connector := newHLSEncryptionTest(t, nil)
+	ctx := context.Background()
+	// 元のセグメントを消す前に失敗した状態
+	storage.Upload(ctx, "video_1/360p/index.m3u8", "", strings.NewReader("#EXTM3U\n#EXT-X-KEY:METHOD=AES-128,URI=\"https://api.example.com/hls-key/key_0\"\n#EXTINF:6.0,\nenc_key_0/index0.ts\n#EXTINF:2.5,\nenc_key_0/index1.ts\n"))
+	storage.Upload(ctx, "video_1/360p/enc_key_0/index0.ts", "", strings.NewReader("encrypted 0"))
+	storage.Upload(ctx, "video_1/360p/enc_key_0/index1.ts", "", strings.NewReader("encrypted 1"))
+
+	err := i.EncryptHLSSegments(ctx, "video_1", "key_1")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.EncryptHLSSegments() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+	// 鍵を保存せず、暗号化し直さない
+	if len(connector.execs) != 0 {
+		t.Errorf("execs = %v, want none", connector.execNames)
+	}
+	if got, _ := readMemoryObject(t, storage, "video_1/360p/enc_key_0/index0.ts"); got != "encrypted 0" {
+		t.Errorf("encrypted segment = %q, want encrypted 0", got)
+	}
+	// 残っていた暗号化されていないセグメントは消す
+	for _, name := range []string{"index0.ts", "index1.ts"} {
+		if _, ok := readMemoryObject(t, storage, "video_1/360p/"+name); ok {
+			t.Errorf("plain segment %s was not deleted", name)
+		}
+	}
+}
+
+func Test_DASHがある動画のHLSの暗号化(t *testing.T) {
+	i, storage, connector := newHLSEncryptionTest(t, "memory:///video_1/dash/manifest.mpd")
+
+	err := i.EncryptHLSSegments(context.Background(), "video_1", "key_1")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.EncryptHLSSegments() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+	if len(connector.execs) != 0 {
+		t.Errorf("execs = %v, want none", connector.execNames)
+	}
+	if got, _ := readMemoryObject(t, storage, "video_1/360p/index0.ts"); got != "segment 0" {
+		t.Errorf("plain segment = %q, want segment 0", got)
+	}
+}
+
+func Test_HLSの鍵の取得(t *testing.T) {
+	const secret = "secret"
+	key := []byte("0123456789abcdef")
+	now := time.Now()
+	valid := func(userID string) string { return hlsKeyToken(secret, "key_1", userID, now.Add(time.Hour)) }
+	tests := []struct {
+		name      string
+		isPrivate bool
+		expiresAt driver.Value
+		keyRows   [][]driver.Value
+		token     string
+		wantErr   error
+	}{
+		{name: "公開されている動画", token: valid("")},
+		{name: "投稿者は非公開の動画の鍵も取得できる", isPrivate: true, token: valid("user_1")},
+		{name: "管理者は非公開の動画の鍵も取得できる", isPrivate: true, token: valid("admin_1")},
+		{name: "他のユーザーは非公開の動画の鍵を取得できない", isPrivate: true, token: valid("user_2"), wantErr: domain.ErrPermissionDenied},
+		{name: "公開期限を過ぎた動画", expiresAt: now.Add(-time.Hour), token: valid(""), wantErr: domain.ErrVideoExpired},
+		{name: "別の鍵のトークン", token: hlsKeyToken(secret, "key_2", "", now.Add(time.Hour)), wantErr: domain.ErrPermissionDenied},
+		{name: "有効期限が切れたトークン", token: hlsKeyToken(secret, "key_1", "", now.Add(-time.Second)), wantErr: domain.ErrPermissionDenied},
+		{name: "署名が違うトークン", token: hlsKeyToken("other", "key_1", "", now.Add(time.Hour)), wantErr: domain.ErrPermissionDenied},
+		{name: "形式が違うトークン", token: "token", wantErr: domain.ErrPermissionDenied},
+		{name: "存在しない鍵", keyRows: [][]driver.Value{}, token: valid(""), wantErr: domain.ErrHLSKeyNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			row[7] = tt.isPrivate
+			row[len(row)-2-16] = tt.expiresAt
+			keyRows := tt.keyRows
+			if keyRows == nil {
+				keyRows = [][]driver.Value{{"key_1", "video_1", key, []byte("fedcba9876543210"), now}}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo":   {row[:len(row)-2]},
+				"GetHLSEncryptionKey": keyRows,
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB)},
+				config: InfrastructureConfig{HLSKeyTokenSecret: secret, AdminUserIDs: []string{"admin_1"}},
+			}
+
+			got, err := i.GetHLSEncryptionKey(context.Background(), "key_1", tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.GetHLSEncryptionKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !bytes.Equal(got, key) {
+				t.Errorf("Infrastructure.GetHLSEncryptionKey() = %x, want %x", got, key)
+			}
+		})
+	}
+}
+
+func Test_HLSの鍵のトークンの発行(t *testing.T) {
+	i := &Infrastructure{config: InfrastructureConfig{HLSKeyTokenSecret: "secret", HLSKeyTokenTTL: time.Hour}}
+	token, err := i.IssueHLSKeyToken(context.Background(), "key_1", "user:1")
+	if err != nil {
+		t.Fatalf("Infrastructure.IssueHLSKeyToken() error = %v", err)
+	}
+	keyID, userID, err := parseHLSKeyToken("secret", token, time.Now())
+	if err != nil {
+		t.Fatalf("parseHLSKeyToken() error = %v", err)
+	}
+	if keyID != "key_1" || userID != "user:1" {
+		t.Errorf("parseHLSKeyToken() = %v, %v, want key_1, user:1", keyID, userID)
+	}
+
+	_, err = i.IssueHLSKeyToken(context.Background(), "../key", "user_1")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.IssueHLSKeyToken() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+}
+
+func decryptAES128CBC(t *testing.T, encrypted, key, iv []byte) string {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		t.Fatalf("len(encrypted) = %d, want a multiple of %d", len(encrypted), aes.BlockSize)
+	}
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, encrypted)
+	padding := int(plain[len(plain)-1])
+	return string(plain[:len(plain)-padding])
+}
@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// ffmpegはCPUを多く使うため、同時に変換する画質の数を抑える
+	hlsLadderConcurrency = 2
+	// 画質によらず音声は同じビットレートにする
+	hlsAudioBitrateBPS = 128_000
+	renditionPlaylist  = "index.m3u8"
+)
+
+// テストでffmpegの実行と保存先へのアップロードを差し替えられるようにしている
+var (
+	runRenditionFFmpeg    = runFFmpeg
+	uploadStreamingObject = (*Infrastructure).uploadObject
+)
+
+// sourceの動画を画質ごとのHLSに変換して<videoID>/<name>/にアップロードし、それらをまとめたマスタープレイリストのURLを返す
+// マスタープレイリストは1つの画質の場合と同じ<videoID>/output_<videoID>.m3u8に置き、動画のURLを変えずに済むようにする
+func (i *Infrastructure) TranscodeToHLSLadder(ctx context.Context, videoID, sourceKey string, ladder []domain.QualityLevel) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "TranscodeToHLSLadder")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.Int("qualityLevels", len(ladder)))
+	defer func() { endSpan(span, err) }()
+
+	err = domain.ValidateQualityLadder(ladder)
+	if err != nil {
+		return "", err
+	}
+	transcoder := i.config.Transcoder
+	err = transcoder.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	outputDir := filepath.Join("output", videoID)
+	defer os.RemoveAll(outputDir)
+
+	renditionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	sem := make(chan struct{}, hlsLadderConcurrency)
+	for _, level := range ladder {
+		select {
+		case sem <- struct{}{}:
+		case <-renditionCtx.Done():
+		}
+		if renditionCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(level domain.QualityLevel) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := i.transcodeRendition(renditionCtx, videoID, sourceKey, outputDir, level)
+			if err != nil {
+				fail(fmt.Errorf("failed to transcode %s: %w", level.Name, err))
+			}
+		}(level)
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		return "", firstErr
+	}
+
+	masterPath := filepath.Join(outputDir, "output_"+videoID+".m3u8")
+	err = os.WriteFile(masterPath, []byte(hlsMasterPlaylist(ladder)), 0644)
+	if err != nil {
+		return "", err
+	}
+	err = i.uploadStreamingFile(ctx, masterPath, videoID+"/output_"+videoID+".m3u8")
+	if err != nil {
+		return "", err
+	}
+	return i.videoPlaylistURL(videoID), nil
+}
+
+// 1つの画質をoutputDir/<name>に書き出し、<videoID>/<name>/にアップロードする
+func (i *Infrastructure) transcodeRendition(ctx context.Context, videoID, source, outputDir string, level domain.QualityLevel) error {
+	dir := filepath.Join(outputDir, level.Name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	_, err = runRenditionFFmpeg(ctx, "hls_"+level.Name, i.config.Transcoder.renditionArgs(source, filepath.Join(dir, renditionPlaylist), level))
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		err = i.uploadStreamingFile(ctx, filepath.Join(dir, entry.Name()), videoID+"/"+level.Name+"/"+entry.Name())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var streamingContentTypes = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".mpd":  "application/dash+xml",
+	".m4s":  "video/iso.segment",
+}
+
+// HLSとDASHのファイルを拡張子に合ったContent-Typeでアップロードする
+func (i *Infrastructure) uploadStreamingFile(ctx context.Context, path, key string) error {
+	contentType, ok := streamingContentTypes[filepath.Ext(path)]
+	if !ok {
+		contentType = "application/octet-stream"
+	}
+	return retryWithBackoff(ctx, int(i.config.S3UploadMaxAttempts), func() error {
+		return uploadStreamingObject(i, ctx, path, videoBucketName, key, contentType)
+	})
+}
+
+func (c TranscoderConfig) renditionArgs(source, output string, level domain.QualityLevel) []string {
+	args, encoder := c.scalingEncoder()
+
+	bitrate := strconv.Itoa(level.BitrateBPS)
+	args = append(args, "-i", source,
+		"-vf", fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:force_divisible_by=2", level.Width, level.Height),
+		"-c:v", encoder, "-b:v", bitrate, "-maxrate", bitrate, "-bufsize", strconv.Itoa(level.BitrateBPS*2),
+		"-g", strconv.Itoa(c.KeyframeInterval),
+		"-c:a", "aac", "-b:a", strconv.Itoa(hlsAudioBitrateBPS),
+	)
+	return append(args, "-start_number", "0", "-hls_time", strconv.Itoa(c.HLSSegmentDuration), "-hls_list_size", "0", "-f", "hls", output, "-y")
+}
+
+// 縮小して再エンコードするときの-iより前の引数とエンコーダ
+// 画質ごとに縮小するため、コピーの設定でもエンコーダを使う
+// VAAPIはGPU上のフレームをscaleで縮小できないため、ソフトウェアでエンコードする
+func (c TranscoderConfig) scalingEncoder() ([]string, string) {
+	encoder := c.encoder()
+	if encoder == "" || c.Backend == TranscoderBackendVAAPI {
+		return nil, c.softwareFallback().encoder()
+	}
+	return c.inputArgs(), encoder
+}
+
+// プレイヤーが回線の速さに合わせて画質を切り替えられるように、各画質のプレイリストを並べる
+func hlsMasterPlaylist(ladder []domain.QualityLevel) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, level := range ladder {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n%s/%s\n", level.BitrateBPS+hlsAudioBitrateBPS, level.Width, level.Height, level.Name, level.Name, renditionPlaylist)
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_画質ごとの変換の引数生成(t *testing.T) {
+	level := domain.QualityLevel{Name: "720p", Width: 1280, Height: 720, BitrateBPS: 2_800_000}
+	encodeArgs := []string{"-vf", "scale=1280:720:force_original_aspect_ratio=decrease:force_divisible_by=2", "-b:v", "2800000", "-maxrate", "2800000", "-bufsize", "5600000", "-g", "60", "-c:a", "aac", "-b:a", "128000", "-start_number", "0", "-hls_time", "6", "-hls_list_size", "0", "-f", "hls", "out/index.m3u8", "-y"}
+	tests := []struct {
+		name        string
+		config      TranscoderConfig
+		wantInput   []string
+		wantEncoder string
+	}{
+		{name: "copy", config: TranscoderConfig{}, wantEncoder: "libx264"},
+		{name: "nvenc", config: TranscoderConfig{Backend: TranscoderBackendNVENC, Device: "0"}, wantInput: []string{"-hwaccel", "cuda", "-hwaccel_device", "0"}, wantEncoder: "h264_nvenc"},
+		{name: "vaapi", config: TranscoderConfig{Backend: TranscoderBackendVAAPI, Device: "/dev/dri/renderD128"}, wantEncoder: "libx264"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.HLSSegmentDuration = 6
+			tt.config.KeyframeInterval = 60
+			got := tt.config.renditionArgs("in.mp4", "out/index.m3u8", level)
+
+			want := append(tt.wantInput, "-i", "in.mp4")
+			want = append(want, encodeArgs[:2]...)
+			want = append(want, "-c:v", tt.wantEncoder)
+			want = append(want, encodeArgs[2:]...)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("TranscoderConfig.renditionArgs() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_マスタープレイリストの生成(t *testing.T) {
+	got := hlsMasterPlaylist(domain.DefaultQualityLadder[:2])
+	want := "#EXTM3U\n#EXT-X-VERSION:3\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=928000,RESOLUTION=640x360,NAME=\"360p\"\n360p/index.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720,NAME=\"720p\"\n720p/index.m3u8\n"
+	if got != want {
+		t.Errorf("hlsMasterPlaylist() = %q, want %q", got, want)
+	}
+}
+
+func Test_画質ごとのHLSへの変換(t *testing.T) {
+	// 変換結果はカレントディレクトリのoutputに書き出される
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		name       string
+		failLevel  string
+		wantUpload []string
+		wantErr    bool
+	}{
+		{
+			name: "全ての画質をアップロードする",
+			wantUpload: []string{
+				"video_1/1080p/index.m3u8", "video_1/1080p/index0.ts",
+				"video_1/360p/index.m3u8", "video_1/360p/index0.ts",
+				"video_1/720p/index.m3u8", "video_1/720p/index0.ts",
+				"video_1/output_video_1.m3u8",
+			},
+		},
+		{name: "1つでも失敗したらマスタープレイリストを作らない", failLevel: "720p", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				running  int
+				maxRun   int
+				uploaded []string
+			)
+			originalFFmpeg, originalUpload := runRenditionFFmpeg, uploadStreamingObject
+			t.Cleanup(func() { runRenditionFFmpeg, uploadStreamingObject = originalFFmpeg, originalUpload })
+			runRenditionFFmpeg = func(ctx context.Context, operation string, args []string) ([]byte, error) {
+				mu.Lock()
+				running++
+				maxRun = max(maxRun, running)
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}()
+
+				if operation == "hls_"+tt.failLevel {
+					return nil, errors.New("ffmpeg failed")
+				}
+				playlist := args[len(args)-2]
+				err := os.WriteFile(playlist, []byte("#EXTM3U\n"), 0644)
+				if err != nil {
+					return nil, err
+				}
+				return nil, os.WriteFile(filepath.Join(filepath.Dir(playlist), "index0.ts"), nil, 0644)
+			}
+			uploadStreamingObject = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				uploaded = append(uploaded, key)
+				return nil
+			}
+
+			i := &Infrastructure{
+				storage: NewMemoryBackend(),
+				config: InfrastructureConfig{
+					Transcoder:          TranscoderConfig{HLSSegmentDuration: 6, KeyframeInterval: 60},
+					S3UploadMaxAttempts: 1,
+				},
+			}
+			got, err := i.TranscodeToHLSLadder(context.Background(), "video_1", "in.mp4", domain.DefaultQualityLadder)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infrastructure.TranscodeToHLSLadder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if maxRun > hlsLadderConcurrency {
+				t.Errorf("ffmpeg ran %d at once, want at most %d", maxRun, hlsLadderConcurrency)
+			}
+			if tt.wantErr {
+				for _, key := range uploaded {
+					if key == "video_1/output_video_1.m3u8" {
+						t.Errorf("master playlist was uploaded")
+					}
+				}
+				return
+			}
+			if want := "memory:///video_1/output_video_1.m3u8"; got != want {
+				t.Errorf("Infrastructure.TranscodeToHLSLadder() = %v, want %v", got, want)
+			}
+			sort.Strings(uploaded)
+			if !reflect.DeepEqual(uploaded, tt.wantUpload) {
+				t.Errorf("uploaded = %v, want %v", uploaded, tt.wantUpload)
+			}
+			if _, err := os.Stat(filepath.Join("output", "video_1")); !os.IsNotExist(err) {
+				t.Errorf("output directory was not removed: %v", err)
+			}
+		})
+	}
+}
@@ -6,19 +6,17 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
-	"log"
 	"os"
-	"os/exec"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/kolesa-team/go-webp/webp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (i *Infrastructure) ConvertThumbnailToWebp(ctx context.Context, imageFile *os.File, contentType, id string) (*os.File, error) {
+func (i *Infrastructure) ConvertThumbnailToWebp(ctx context.Context, imageFile *os.File, contentType, id string) (_ *os.File, err error) {
+	ctx, span := infraSpan(ctx, "ConvertThumbnailToWebp")
+	span.SetAttributes(attribute.String("videoID", id))
+	defer func() { endSpan(span, err) }()
+
 	if imageFile == nil {
 		return nil, nil
 	}
@@ -61,7 +59,11 @@ func (i *Infrastructure) ConvertThumbnailToWebp(ctx context.Context, imageFile *
 	return imageTmp, nil
 }
 
-func (i *Infrastructure) UploadImageForStorage(ctx context.Context, id string) (string, error) {
+func (i *Infrastructure) UploadImageForStorage(ctx context.Context, id string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "UploadImageForStorage")
+	span.SetAttributes(attribute.String("videoID", id))
+	defer func() { endSpan(span, err) }()
+
 	imagePath := id + ".webp"
 	defer func() error {
 		err := os.Remove(imagePath)
@@ -70,68 +72,18 @@ func (i *Infrastructure) UploadImageForStorage(ctx context.Context, id string) (
 		}
 		return nil
 	}()
-	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-
-	cred := credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithCredentialsProvider(cred))
+	err = i.uploadObject(ctx, imagePath, thumbnailBucketName, imagePath, "image/webp")
 	if err != nil {
 		return "", err
 	}
-
-	// change object address style
-	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
-		options.UsePathStyle = true
-		options.BaseEndpoint = aws.String(os.Getenv("AWS_S3_ENDPOINT"))
-		options.Region = "ap-northeast-1"
-	})
-
-	// get buckets
-	lbo, err := client.ListBuckets(ctx, nil)
-	if err != nil {
-		return "", err
-	}
-	buckets := make(map[string]struct{}, len(lbo.Buckets))
-	for _, b := range lbo.Buckets {
-		buckets[*b.Name] = struct{}{}
-	}
-
-	// create 'thumbnail-image' bucket if not exist
-	bucketName := "thumbnail-image"
-	if _, ok := buckets[bucketName]; !ok {
-		_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{
-			Bucket: &bucketName,
-			ACL:    types.BucketCannedACLPublicRead,
-		})
-		if err != nil {
-			return "", err
-		}
-	}
-
-	image, err := os.Open(imagePath)
-	if err != nil {
-		return "", err
-	}
-	defer image.Close()
-
-	// bytes.bufferがaws-sdk-go-v2では使えない
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(imagePath),
-		Body:   image,
-		ACL:    types.ObjectCannedACLPublicRead,
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to upload file: %w", err)
-	}
-	log.Println("Successful upload: ", imagePath)
-
-	url := fmt.Sprintf("%s/%s/%s.webp", os.Getenv("AWS_S3_URL"), bucketName, id)
-	return url, nil
+	return i.objectURL(thumbnailBucketName, imagePath), nil
 }
 
-func (i *Infrastructure) CreateThumbnail(ctx context.Context, id string) error {
-	const bucketName = "video"
+func (i *Infrastructure) CreateThumbnail(ctx context.Context, id string) (err error) {
+	ctx, span := infraSpan(ctx, "CreateThumbnail")
+	span.SetAttributes(attribute.String("videoID", id))
+	defer func() { endSpan(span, err) }()
+
 	imagePath := id + ".webp"
 	tmpVideoPath := id + ".mp4"
 	defer func() error {
@@ -145,22 +97,12 @@ func (i *Infrastructure) CreateThumbnail(ctx context.Context, id string) error {
 	// S3から取得HLS
 	// 処理を2回に分けているのはこの方法が早いため
 	// 分けないとffmpegが全てのファイルをダウンロードしてから処理を行うため時間がかかってしまう
-	url := fmt.Sprintf("%s/%s/%s/output_%s.m3u8", os.Getenv("AWS_S3_URL"), bucketName, id, id)
-	cmd := exec.Command("ffmpeg", "-ss", "00:00:00", "-t", "1", "-i", url, tmpVideoPath)
-	log.Println(cmd.Args)
-	result, err := cmd.CombinedOutput()
-	log.Println(string(result))
-	if err != nil {
-		return fmt.Errorf("failed to execute ffmpeg command: %w", err)
-	}
-
-	cmd = exec.Command("ffmpeg", "-i", tmpVideoPath, "-vframes", "1", imagePath)
-	log.Println(cmd.Args)
-	result, err = cmd.CombinedOutput()
-	log.Println(string(result))
+	url := i.videoPlaylistURL(id)
+	_, err = runFFmpeg(ctx, "thumbnail", []string{"-ss", "00:00:00", "-t", "1", "-i", url, tmpVideoPath})
 	if err != nil {
-		return fmt.Errorf("failed to execute ffmpeg command: %w", err)
+		return err
 	}
 
-	return nil
+	_, err = runFFmpeg(ctx, "thumbnail", []string{"-i", tmpVideoPath, "-vframes", "1", imagePath})
+	return err
 }
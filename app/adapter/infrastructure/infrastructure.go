@@ -1,19 +1,73 @@
 package infrastructure
 
 import (
+	"log/slog"
+	"time"
+
 	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
 	"github.com/yuorei/video-server/app/driver/db"
 	r "github.com/yuorei/video-server/app/driver/redis"
 )
 
 type Infrastructure struct {
-	db    *db.DB
-	redis *redis.Client
+	db      *db.DB
+	redis   *redis.Client
+	storage StorageBackend
+	// 動画以外のバケットも含めたバケットごとの保存先
+	buckets map[string]StorageBackend
+	metrics Metrics
+	logger  *slog.Logger
+	config  InfrastructureConfig
+	// StartWebhookDispatcherが送信するイベント
+	webhookEvents chan domain.WebhookEvent
 }
 
-func NewInfrastructure() *Infrastructure {
+func NewInfrastructure(storage StorageBackend, metrics Metrics, logger *slog.Logger) *Infrastructure {
+	config := NewInfrastructureConfig()
+	config.Transcoder = probeTranscoder(config.Transcoder)
+	err := config.Transcoder.Validate()
+	if err != nil {
+		slog.Warn("invalid HLS config, using default", "error", err)
+		config.Transcoder.HLSSegmentDuration = defaultHLSSegmentDuration
+		config.Transcoder.KeyframeInterval = defaultKeyframeInterval
+	}
+	ffmpegBreaker.setCooldown(config.FFmpegCircuitCooldown)
+	ffmpegMetrics = metrics
+
 	return &Infrastructure{
-		db:    db.NewMySQLDB(),
-		redis: r.ConnectRedis(),
+		db:      db.NewMySQLDB(),
+		redis:   r.ConnectRedis(),
+		storage: storage,
+		buckets: newBucketStorages(storage),
+		metrics: metrics,
+		logger:  logger,
+		config:  config,
+
+		webhookEvents: make(chan domain.WebhookEvent, webhookEventBufferSize),
 	}
 }
+
+// テストで作成したInfrastructureはloggerを持たないためデフォルトのloggerを使う
+func (i *Infrastructure) log() *slog.Logger {
+	if i.logger == nil {
+		return slog.Default()
+	}
+	return i.logger
+}
+
+func (i *Infrastructure) MaxVideoSize() int64 {
+	return i.config.MaxVideoSize
+}
+
+func (i *Infrastructure) VideoDurationRange() (time.Duration, time.Duration) {
+	return i.config.MinVideoDuration, i.config.MaxVideoDuration
+}
+
+func (i *Infrastructure) VideoProcessingWorkers() int {
+	return int(i.config.VideoProcessingWorkers)
+}
+
+func (i *Infrastructure) SiteURL() string {
+	return i.config.SiteURL
+}
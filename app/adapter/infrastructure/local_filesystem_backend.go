@@ -0,0 +1,158 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultLocalStorageDir = "storage"
+
+// AWSの認証情報がない開発環境でBaseDirの下にオブジェクトをファイルとして保存する
+type LocalFilesystemBackend struct {
+	BaseDir string
+	BaseURL string
+}
+
+// AWS_S3_URLが空か、STORAGE_BACKEND=localの場合はローカルに保存する
+func NewStorageBackend(bucket string) StorageBackend {
+	if os.Getenv("STORAGE_BACKEND") != "local" && os.Getenv("AWS_S3_URL") != "" {
+		return NewS3Backend(bucket)
+	}
+
+	baseDir := os.Getenv("LOCAL_STORAGE_DIR")
+	if baseDir == "" {
+		baseDir = defaultLocalStorageDir
+	}
+	baseDir = filepath.Join(baseDir, bucket)
+	baseURL := os.Getenv("LOCAL_STORAGE_URL")
+	if baseURL == "" {
+		abs, err := filepath.Abs(baseDir)
+		if err == nil {
+			baseDir = abs
+		}
+		baseURL = "file://" + filepath.ToSlash(baseDir)
+	} else {
+		baseURL = strings.TrimSuffix(baseURL, "/") + "/" + bucket
+	}
+	slog.Info("use local storage", "dir", baseDir)
+	return &LocalFilesystemBackend{BaseDir: baseDir, BaseURL: baseURL}
+}
+
+// 最初のアップロードの前でも確認できるように、BaseDirがなければ作成する
+func (b *LocalFilesystemBackend) Ping(ctx context.Context) error {
+	return os.MkdirAll(b.BaseDir, 0o755)
+}
+
+// keyに..が含まれていてもBaseDirの外のファイルを扱わないようにする
+func (b *LocalFilesystemBackend) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + filepath.FromSlash(key))
+	if cleaned == string(filepath.Separator) {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+	return filepath.Join(b.BaseDir, cleaned), nil
+}
+
+// 書き込み途中のファイルを読まれないように、一時ファイルに書いてから置き換える
+func (b *LocalFilesystemBackend) Upload(ctx context.Context, key, contentType string, r io.Reader) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// ローカルでは公開と非公開を区別しない
+func (b *LocalFilesystemBackend) UploadPublic(ctx context.Context, key, contentType string, r io.Reader) error {
+	return b.Upload(ctx, key, contentType, r)
+}
+
+func (b *LocalFilesystemBackend) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// S3と同じく、ないオブジェクトを消してもエラーにしない
+func (b *LocalFilesystemBackend) Delete(ctx context.Context, key string) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// prefixはaudio/<id>.のようにファイル名の途中で終わることがあるため、prefixのディレクトリの中から探す
+func (b *LocalFilesystemBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	dir, err := b.path(path.Dir(prefix + "_"))
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(b.BaseDir, p)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(filepath.ToSlash(rel), strings.TrimPrefix(prefix, "/")) {
+			return nil
+		}
+		return os.Remove(p)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ローカルでは署名せずにBaseURLの下のURLを返す
+func (b *LocalFilesystemBackend) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	_, err := b.path(key)
+	if err != nil {
+		return "", err
+	}
+	return b.PublicURL(key), nil
+}
+
+func (b *LocalFilesystemBackend) PublicURL(key string) string {
+	return strings.TrimSuffix(b.BaseURL, "/") + "/" + strings.TrimPrefix(key, "/")
+}
@@ -0,0 +1,217 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ローカルのストレージのアップロードからの削除(t *testing.T) {
+	ctx := context.Background()
+	backend := &LocalFilesystemBackend{BaseDir: t.TempDir(), BaseURL: "http://localhost:8080/storage/"}
+	key := videoSourceKey("video_1")
+
+	err := backend.Upload(ctx, key, "video/mp4", strings.NewReader("video"))
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.Upload() error = %v", err)
+	}
+
+	body, err := backend.Download(ctx, key)
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.Download() error = %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "video" {
+		t.Errorf("LocalFilesystemBackend.Download() = %q, want video", got)
+	}
+
+	url, err := backend.PresignGetURL(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.PresignGetURL() error = %v", err)
+	}
+	if url != "http://localhost:8080/storage/video_1/source_video_1.mp4" {
+		t.Errorf("LocalFilesystemBackend.PresignGetURL() = %s", url)
+	}
+
+	err = backend.Delete(ctx, key)
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.Delete() error = %v", err)
+	}
+	_, err = backend.Download(ctx, key)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LocalFilesystemBackend.Download() after delete error = %v, want %v", err, fs.ErrNotExist)
+	}
+	// 消したオブジェクトをもう一度消してもエラーにしない
+	err = backend.Delete(ctx, key)
+	if err != nil {
+		t.Errorf("LocalFilesystemBackend.Delete() twice error = %v", err)
+	}
+}
+
+func Test_ローカルのストレージの外へのアクセス(t *testing.T) {
+	ctx := context.Background()
+	parent := t.TempDir()
+	backend := &LocalFilesystemBackend{BaseDir: parent + "/video"}
+
+	err := backend.Upload(ctx, "../secret.txt", "text/plain", strings.NewReader("secret"))
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.Upload() error = %v", err)
+	}
+	// ..はBaseDirの中に丸められる
+	_, err = (&LocalFilesystemBackend{BaseDir: parent}).Download(ctx, "secret.txt")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("wrote outside of BaseDir: error = %v", err)
+	}
+	body, err := backend.Download(ctx, "secret.txt")
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.Download() error = %v", err)
+	}
+	body.Close()
+}
+
+func Test_ストレージの選択(t *testing.T) {
+	tests := []struct {
+		name           string
+		storageBackend string
+		s3URL          string
+		wantLocal      bool
+	}{
+		{name: "S3", s3URL: "http://s3.example.com", wantLocal: false},
+		{name: "AWS_S3_URLが空", s3URL: "", wantLocal: true},
+		{name: "STORAGE_BACKEND=local", storageBackend: "local", s3URL: "http://s3.example.com", wantLocal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STORAGE_BACKEND", tt.storageBackend)
+			t.Setenv("AWS_S3_URL", tt.s3URL)
+			t.Setenv("LOCAL_STORAGE_DIR", t.TempDir())
+			t.Setenv("LOCAL_STORAGE_URL", "http://localhost:8080/storage")
+
+			got := NewStorageBackend("video")
+			local, isLocal := got.(*LocalFilesystemBackend)
+			if isLocal != tt.wantLocal {
+				t.Fatalf("NewStorageBackend() = %T, want local = %v", got, tt.wantLocal)
+			}
+			if isLocal && local.BaseURL != "http://localhost:8080/storage/video" {
+				t.Errorf("BaseURL = %s, want http://localhost:8080/storage/video", local.BaseURL)
+			}
+		})
+	}
+}
+
+func Test_ローカルのストレージのprefixでの削除(t *testing.T) {
+	ctx := context.Background()
+	backend := &LocalFilesystemBackend{BaseDir: t.TempDir()}
+	keys := []string{"audio/video_1.mp3", "audio/video_1.aac", "audio/video_10.mp3", "subtitles/video_1/ja.vtt"}
+	for _, key := range keys {
+		err := backend.UploadPublic(ctx, key, "application/octet-stream", strings.NewReader(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := backend.DeletePrefix(ctx, audioKey("video_1", ""))
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.DeletePrefix() error = %v", err)
+	}
+	err = backend.DeletePrefix(ctx, subtitlePrefix("video_1"))
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.DeletePrefix() error = %v", err)
+	}
+	// ないディレクトリを指定してもエラーにしない
+	err = backend.DeletePrefix(ctx, subtitlePrefix("video_2"))
+	if err != nil {
+		t.Fatalf("LocalFilesystemBackend.DeletePrefix() error = %v", err)
+	}
+
+	for _, key := range keys {
+		_, err := backend.Download(ctx, key)
+		wantExist := key == "audio/video_10.mp3"
+		if (err == nil) != wantExist {
+			t.Errorf("%s exists = %v, want %v", key, err == nil, wantExist)
+		}
+	}
+}
+
+// AWSの認証情報がなくても、全てのバケットのアップロードから削除までをローカルで行える
+func Test_AWSの認証情報なしでのローカルのストレージ(t *testing.T) {
+	t.Setenv("AWS_S3_URL", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("LOCAL_STORAGE_DIR", t.TempDir())
+	t.Setenv("LOCAL_STORAGE_URL", "http://localhost:8080/storage")
+	storage := NewStorageBackend(videoBucketName)
+	i := &Infrastructure{storage: storage, buckets: newBucketStorages(storage)}
+
+	ctx := context.Background()
+	thumbnailPath := filepath.Join(t.TempDir(), "thumbnail.jpg")
+	err := os.WriteFile(thumbnailPath, []byte("image"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.uploadObject(ctx, thumbnailPath, thumbnailBucketName, "generated_1.jpg", "image/jpeg")
+	if err != nil {
+		t.Fatalf("Infrastructure.uploadObject() error = %v", err)
+	}
+	thumbnailURL := i.objectURL(thumbnailBucketName, "generated_1.jpg")
+	if thumbnailURL != "http://localhost:8080/storage/thumbnail-image/generated_1.jpg" {
+		t.Errorf("Infrastructure.objectURL() = %s", thumbnailURL)
+	}
+	body, err := i.downloadObject(ctx, thumbnailURL)
+	if err != nil {
+		t.Fatalf("Infrastructure.downloadObject() error = %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(got) != "image" {
+		t.Errorf("Infrastructure.downloadObject() = %q, %v, want image", got, err)
+	}
+	// サムネイルは動画のバケットには保存しない
+	_, err = storage.Download(ctx, "generated_1.jpg")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("uploaded to video bucket: error = %v", err)
+	}
+
+	subtitleURL, err := i.uploadVTT(ctx, subtitleKey("video_1", "ja"), []byte("WEBVTT\n"))
+	if err != nil {
+		t.Fatalf("Infrastructure.uploadVTT() error = %v", err)
+	}
+	segmentPath := filepath.Join(t.TempDir(), "output_video_10.ts")
+	err = os.WriteFile(segmentPath, []byte("segment"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.uploadObject(ctx, segmentPath, videoBucketName, "video_1/output_video_10.ts", "video/mp2t")
+	if err != nil {
+		t.Fatalf("Infrastructure.uploadObject() error = %v", err)
+	}
+
+	video := sqlc.Video{
+		ID:                "video_1",
+		VideoUrl:          i.videoPlaylistURL("video_1"),
+		ThumbnailImageUrl: thumbnailURL,
+	}
+	err = i.deleteVideoObjectsFromStorage(ctx, video)
+	if err != nil {
+		t.Fatalf("Infrastructure.deleteVideoObjectsFromStorage() error = %v", err)
+	}
+	for _, url := range []string{thumbnailURL, subtitleURL, i.objectURL(videoBucketName, "video_1/output_video_10.ts")} {
+		_, err = i.downloadObject(ctx, url)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("%s was not deleted: error = %v", url, err)
+		}
+	}
+}
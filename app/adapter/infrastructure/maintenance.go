@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+)
+
+// 定期的にDBを整理するジョブを動かす。ctxが終わるまで戻らない
+func (i *Infrastructure) StartMaintenanceJobs(ctx context.Context) {
+	interval := i.config.TagCleanupInterval
+	if interval <= 0 {
+		interval = defaultTagCleanupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := i.CleanOrphanedTags(ctx)
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to clean orphaned tags", "error", err)
+				continue
+			}
+			i.log().InfoContext(ctx, "cleaned orphaned tags", "deleted", deleted)
+		}
+	}
+}
+
+// 動画を削除してもtagの行は残るため、どの動画にも付いていないタグを消して、消した数を返す
+func (i *Infrastructure) CleanOrphanedTags(ctx context.Context) (_ int64, err error) {
+	ctx, span := infraSpan(ctx, "CleanOrphanedTags")
+	defer func() { endSpan(span, err) }()
+
+	result, err := i.db.Database.DeleteOrphanedTags(ctx)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	// 消したタグが補完の候補に残らないようにする
+	if deleted > 0 {
+		err = i.RebuildTagAutocompleteIndex(ctx)
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// DBを使うテストの接続先。db/atlas/schema.hclを適用したMySQLを指定する
+// 例: root:pass@tcp(localhost:3306)/yuovision?parseTime=true
+const testMySQLDSNEnv = "TEST_MYSQL_DSN"
+
+// テストの中で変更した行が残らないように、トランザクションを返してテストの終わりにロールバックする
+func newTestMySQLTx(t *testing.T) *sql.Tx {
+	t.Helper()
+	dsn := os.Getenv(testMySQLDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testMySQLDSNEnv)
+	}
+	sqlDB, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	tx, err := sqlDB.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func Test_使われていないタグの削除(t *testing.T) {
+	mr, client := newTestRedis(t)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"ListTagUsageCounts": {{"go", int64(2)}}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:     &db.DB{Database: sqlc.New(sqlDB)},
+		redis:  client,
+		config: InfrastructureConfig{TagCleanupInterval: 10 * time.Millisecond},
+	}
+	// 削除したタグが補完の索引から消える
+	err := i.addTagsToAutocompleteIndex(context.Background(), []string{"deleted"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := i.CleanOrphanedTags(context.Background())
+	if err != nil {
+		t.Fatalf("Infrastructure.CleanOrphanedTags() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Infrastructure.CleanOrphanedTags() = %d, want 1", deleted)
+	}
+	if members, _ := mr.ZMembers(tagAutocompleteKey); !reflect.DeepEqual(members, []string{"go"}) {
+		t.Errorf("autocomplete members = %v, want [go]", members)
+	}
+
+	// 定期的なジョブからも削除する
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	i.StartMaintenanceJobs(ctx)
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+	if len(connector.execNames) < 2 || !reflect.DeepEqual(connector.execNames[:2], []string{"DeleteOrphanedTags", "DeleteOrphanedTags"}) {
+		t.Errorf("execs = %v, want DeleteOrphanedTags more than once", connector.execNames)
+	}
+}
+
+func Test_使われていないタグの削除のDB(t *testing.T) {
+	tx := newTestMySQLTx(t)
+	queries := sqlc.New(tx)
+	_, client := newTestRedis(t)
+	i := &Infrastructure{db: &db.DB{Database: queries}, redis: client}
+
+	ctx := context.Background()
+	videoID := domain.NewUUID()
+	now := time.Now()
+	_, err := queries.CreateVideo(ctx, sqlc.CreateVideoParams{
+		ID:               videoID,
+		Title:            "title",
+		ContentRating:    string(domain.RatingGeneral),
+		UploaderID:       "user_1",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		ProcessingStatus: string(domain.StatusReady),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	usedTag, orphanedTag := "used_"+videoID, "orphaned_"+videoID
+	err = insertVideoTags(ctx, queries, videoID, []string{usedTag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = queries.UpsertTag(ctx, orphanedTag)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DBに他のテストのタグが残っている場合があるため、作ったタグだけ確認する
+	deleted, err := i.CleanOrphanedTags(ctx)
+	if err != nil {
+		t.Fatalf("Infrastructure.CleanOrphanedTags() error = %v", err)
+	}
+	if deleted < 1 {
+		t.Errorf("Infrastructure.CleanOrphanedTags() = %d, want at least 1", deleted)
+	}
+	for tag, want := range map[string]int{usedTag: 1, orphanedTag: 0} {
+		var count int
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM tag WHERE tag_name = ?", tag).Scan(&count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("rows of tag %s = %d, want %d", tag, count, want)
+		}
+	}
+}
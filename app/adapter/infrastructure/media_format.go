@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// 形式の判定に読む先頭のバイト数
+// WebMとMKVの区別に使うEBMLヘッダのDocTypeがこの範囲に入る
+const mediaFormatHeaderSize = 32
+
+// 先頭がprefixで、subtypeがある場合は8バイト目からsubtypeが続けばformatとみなす
+var mediaFormatSignatures = []struct {
+	prefix  []byte
+	subtype []byte
+	format  domain.MediaFormat
+}{
+	{prefix: []byte("\x1a\x45\xdf\xa3"), format: domain.MediaFormatWebM},
+	// RIFFはWAVなどにも使われるためサブタイプも確認する
+	{prefix: []byte("RIFF"), subtype: []byte("AVI "), format: domain.MediaFormatAVI},
+	{prefix: []byte("\x89PNG"), format: domain.MediaFormatPNG},
+	{prefix: []byte("\xff\xd8\xff"), format: domain.MediaFormatJPEG},
+	{prefix: []byte("GIF8"), format: domain.MediaFormatGIF},
+}
+
+// ftypボックスのメジャーブランドごとの形式。ここにないブランドはMP4とみなす
+// HEIFやAVIFの静止画もftypボックスを持つためブランドで区別する
+var ftypBrandFormats = map[string]domain.MediaFormat{
+	"qt  ": domain.MediaFormatMOV,
+	"heic": domain.MediaFormatHEIF,
+	"heix": domain.MediaFormatHEIF,
+	"mif1": domain.MediaFormatHEIF,
+	"msf1": domain.MediaFormatHEIF,
+	"avif": domain.MediaFormatAVIF,
+	"avis": domain.MediaFormatAVIF,
+}
+
+// 先頭のマジックバイトから形式を判定し、rを先頭に戻す
+// 判定できない場合はMediaFormatUnknownを返す
+func DetectMediaFormat(r io.ReadSeeker) (domain.MediaFormat, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return domain.MediaFormatUnknown, err
+	}
+
+	header := make([]byte, mediaFormatHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return domain.MediaFormatUnknown, err
+	}
+	header = header[:n]
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return domain.MediaFormatUnknown, err
+	}
+
+	return detectMediaFormat(header), nil
+}
+
+func detectMediaFormat(header []byte) domain.MediaFormat {
+	// ヘッダの4バイト目から'ftyp'が存在するかチェック
+	if hasMagicAt(header, 4, []byte("ftyp")) && len(header) >= 12 {
+		if format, ok := ftypBrandFormats[string(header[8:12])]; ok {
+			return format
+		}
+		return domain.MediaFormatMP4
+	}
+
+	for _, signature := range mediaFormatSignatures {
+		if !hasMagicAt(header, 0, signature.prefix) {
+			continue
+		}
+		if signature.subtype != nil && !hasMagicAt(header, 8, signature.subtype) {
+			continue
+		}
+		// WebMとMKVはどちらもEBMLなのでDocTypeで区別する
+		if signature.format == domain.MediaFormatWebM && bytes.Contains(header, []byte("matroska")) {
+			return domain.MediaFormatMKV
+		}
+		return signature.format
+	}
+	return domain.MediaFormatUnknown
+}
+
+func hasMagicAt(header []byte, offset int, magic []byte) bool {
+	return len(header) >= offset+len(magic) && bytes.Equal(header[offset:offset+len(magic)], magic)
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_マジックバイトによる形式の判定(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   domain.MediaFormat
+	}{
+		{name: "mp4", header: "\x00\x00\x00\x20ftypisom", want: domain.MediaFormatMP4},
+		{name: "mov", header: "\x00\x00\x00\x14ftypqt  ", want: domain.MediaFormatMOV},
+		{name: "heic", header: "\x00\x00\x00\x18ftypheic", want: domain.MediaFormatHEIF},
+		{name: "avif", header: "\x00\x00\x00\x1cftypavif", want: domain.MediaFormatAVIF},
+		{name: "webm", header: "\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x84webm", want: domain.MediaFormatWebM},
+		{name: "mkv", header: "\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska", want: domain.MediaFormatMKV},
+		{name: "avi", header: "RIFF\x00\x10\x00\x00AVI LIST", want: domain.MediaFormatAVI},
+		{name: "wav", header: "RIFF\x00\x10\x00\x00WAVEfmt ", want: domain.MediaFormatUnknown},
+		{name: "png", header: "\x89PNG\r\n\x1a\n", want: domain.MediaFormatPNG},
+		{name: "jpeg", header: "\xff\xd8\xff\xe0", want: domain.MediaFormatJPEG},
+		{name: "gif", header: "GIF89a", want: domain.MediaFormatGIF},
+		{name: "short", header: "\x00\x00", want: domain.MediaFormatUnknown},
+		{name: "empty", header: "", want: domain.MediaFormatUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bytes.NewReader([]byte(tt.header))
+			got, err := DetectMediaFormat(r)
+			if err != nil {
+				t.Fatalf("DetectMediaFormat() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectMediaFormat() = %v, want %v", got, tt.want)
+			}
+			// 判定後は先頭から読めるようにする
+			if offset, _ := r.Seek(0, io.SeekCurrent); offset != 0 {
+				t.Errorf("offset = %d, want 0", offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// テスト用にメモリ上にオブジェクトを保存する
+type MemoryBackend struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{objects: map[string][]byte{}}
+}
+
+func (b *MemoryBackend) Upload(ctx context.Context, key, contentType string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return nil
+}
+
+func (b *MemoryBackend) UploadPublic(ctx context.Context, key, contentType string, r io.Reader) error {
+	return b.Upload(ctx, key, contentType, r)
+}
+
+func (b *MemoryBackend) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", fs.ErrNotExist, key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// S3と同じく、ないオブジェクトを消してもエラーにしない
+func (b *MemoryBackend) Delete(ctx context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *MemoryBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(b.objects, key)
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBackend) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("memory:///%s?expires=%d", key, expires), nil
+}
+
+func (b *MemoryBackend) PublicURL(key string) string {
+	return "memory:///" + key
+}
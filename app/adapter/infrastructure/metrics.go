@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// SLOのアラートに使うメトリクスを記録する
+type Metrics interface {
+	RecordUpload(labels UploadLabels)
+	RecordWatchCountIncrement(labels WatchCountLabels)
+	ObserveFFmpegDuration(operation string, d time.Duration)
+	RecordCacheHit(key string)
+	RecordCacheMiss(key string)
+}
+
+// Prometheusのラベルの名前は固定のため、メトリクスごとに使うラベルをフィールドで持つ
+type UploadLabels struct {
+	// 処理が終わった時点の状態でreadyかfailed
+	Status domain.ProcessingStatus
+}
+
+type WatchCountLabels struct {
+	// ユニーク視聴者数が増えたか
+	NewViewer bool
+}
+
+type PrometheusMetrics struct {
+	uploads              *prometheus.CounterVec
+	watchCountIncrements *prometheus.CounterVec
+	ffmpegDuration       *prometheus.HistogramVec
+	cacheHits            *prometheus.CounterVec
+	cacheMisses          *prometheus.CounterVec
+}
+
+// 同じregistererに2回登録するとpanicするため、起動時に1回だけ作成する
+func NewPrometheusMetrics(registerer prometheus.Registerer) *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "video_uploads_total",
+			Help: "Number of uploaded videos whose processing has finished.",
+		}, []string{"status"}),
+		watchCountIncrements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "video_watch_count_increments_total",
+			Help: "Number of watch count increments.",
+		}, []string{"new_viewer"}),
+		// HLSへの変換は数分かかることがあるため、上限を長めにする
+		ffmpegDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "video_ffmpeg_duration_seconds",
+			Help:    "Duration of ffmpeg executions.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+		}, []string{"operation"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "video_cache_hits_total",
+			Help: "Number of Redis cache hits.",
+		}, []string{"cache"}),
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "video_cache_misses_total",
+			Help: "Number of Redis cache misses.",
+		}, []string{"cache"}),
+	}
+	registerer.MustRegister(m.uploads, m.watchCountIncrements, m.ffmpegDuration, m.cacheHits, m.cacheMisses)
+	return m
+}
+
+func (m *PrometheusMetrics) RecordUpload(labels UploadLabels) {
+	m.uploads.WithLabelValues(string(labels.Status)).Inc()
+}
+
+func (m *PrometheusMetrics) RecordWatchCountIncrement(labels WatchCountLabels) {
+	m.watchCountIncrements.WithLabelValues(strconv.FormatBool(labels.NewViewer)).Inc()
+}
+
+func (m *PrometheusMetrics) ObserveFFmpegDuration(operation string, d time.Duration) {
+	m.ffmpegDuration.WithLabelValues(operation).Observe(d.Seconds())
+}
+
+// keyには動画IDなどを含まないキャッシュの種類を渡す
+func (m *PrometheusMetrics) RecordCacheHit(key string) {
+	m.cacheHits.WithLabelValues(key).Inc()
+}
+
+func (m *PrometheusMetrics) RecordCacheMiss(key string) {
+	m.cacheMisses.WithLabelValues(key).Inc()
+}
+
+// テストなどで記録しない場合に使う
+type NoopMetrics struct{}
+
+func (NoopMetrics) RecordUpload(labels UploadLabels)                        {}
+func (NoopMetrics) RecordWatchCountIncrement(labels WatchCountLabels)       {}
+func (NoopMetrics) ObserveFFmpegDuration(operation string, d time.Duration) {}
+func (NoopMetrics) RecordCacheHit(key string)                               {}
+func (NoopMetrics) RecordCacheMiss(key string)                              {}
+
+// ffmpegBreakerと同じく、ffmpegの実行時間はサーバー全体で1つのMetricsに記録する
+var ffmpegMetrics Metrics = NoopMetrics{}
+
+// テストで作成したInfrastructureはmetricsを持たないため記録しない
+func (i *Infrastructure) recordMetrics() Metrics {
+	if i.metrics == nil {
+		return NoopMetrics{}
+	}
+	return i.metrics
+}
@@ -0,0 +1,92 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_キャッシュのメトリクス(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(10)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	metrics := NewPrometheusMetrics(prometheus.NewRegistry())
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB)},
+		redis:   client,
+		metrics: metrics,
+	}
+
+	// 1回目はDBから取得してキャッシュし、2回目はキャッシュを使う
+	ctx := context.Background()
+	for n := 0; n < 2; n++ {
+		_, err := i.GetWatchCount(ctx, "video_1")
+		if err != nil {
+			t.Fatalf("Infrastructure.GetWatchCount() error = %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(metrics.cacheMisses.WithLabelValues("watch_count")); got != 1 {
+		t.Errorf("cache misses = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.cacheHits.WithLabelValues("watch_count")); got != 1 {
+		t.Errorf("cache hits = %v, want 1", got)
+	}
+}
+
+func Test_再生回数の加算のメトリクス(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(10)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	metrics := NewPrometheusMetrics(prometheus.NewRegistry())
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB)},
+		redis:   client,
+		metrics: metrics,
+	}
+
+	ctx := context.Background()
+	for _, userID := range []string{"user_1", "user_1", "user_2"} {
+		_, err := i.IncrementWatchCount(ctx, "video_1", userID)
+		if err != nil {
+			t.Fatalf("Infrastructure.IncrementWatchCount() error = %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(metrics.watchCountIncrements.WithLabelValues("true")); got != 2 {
+		t.Errorf("increments by new viewers = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.watchCountIncrements.WithLabelValues("false")); got != 1 {
+		t.Errorf("increments by returning viewers = %v, want 1", got)
+	}
+}
+
+func Test_PrometheusMetricsの記録(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := NewPrometheusMetrics(registry)
+
+	metrics.RecordUpload(UploadLabels{Status: domain.StatusReady})
+	metrics.RecordUpload(UploadLabels{Status: domain.StatusFailed})
+	metrics.RecordUpload(UploadLabels{Status: domain.StatusReady})
+	metrics.ObserveFFmpegDuration("hls", 3*time.Second)
+
+	if got := testutil.ToFloat64(metrics.uploads.WithLabelValues("ready")); got != 2 {
+		t.Errorf("ready uploads = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.uploads.WithLabelValues("failed")); got != 1 {
+		t.Errorf("failed uploads = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(registry, "video_ffmpeg_duration_seconds"); got != 1 {
+		t.Errorf("ffmpeg duration series = %d, want 1", got)
+	}
+}
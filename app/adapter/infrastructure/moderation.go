@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 動画への操作、通報を閉じること、moderation_recordsへの記録を1つのトランザクションで行う
+// 管理者の確認はユースケースで行う
+func (i *Infrastructure) ModerateVideo(ctx context.Context, videoID, moderatorID string, action domain.ModerationAction, reason string) (err error) {
+	ctx, span := infraSpan(ctx, "ModerateVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("moderatorID", moderatorID), attribute.String("action", string(action)))
+	defer func() { endSpan(span, err) }()
+
+	err = action.Validate()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		err := updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionModerateVideo, moderatorID, func(sqlc.Video) error {
+			var err error
+			switch action {
+			case domain.ActionRemoveAsAdult:
+				_, err = q.UpdateVideoContentRating(ctx, sqlc.UpdateVideoContentRatingParams{
+					ContentRating: string(domain.RatingAdult),
+					IsAdult:       true,
+					UpdatedAt:     now,
+					ID:            videoID,
+				})
+			case domain.ActionSoftDelete:
+				_, err = q.SoftDeleteVideo(ctx, sqlc.SoftDeleteVideoParams{
+					UpdatedAt: now,
+					ID:        videoID,
+				})
+			case domain.ActionPermanentBan:
+				_, err = q.BanVideo(ctx, sqlc.BanVideoParams{
+					UpdatedAt: now,
+					ID:        videoID,
+				})
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.ResolveVideoReports(ctx, sqlc.ResolveVideoReportsParams{
+			Status:  domain.VideoReportStatusResolved,
+			VideoID: videoID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateModerationRecord(ctx, sqlc.CreateModerationRecordParams{
+			VideoID:     videoID,
+			ModeratorID: moderatorID,
+			Action:      string(action),
+			Reason:      reason,
+			CreatedAt:   now,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if action == domain.ActionSoftDelete || action == domain.ActionPermanentBan {
+		i.emitWebhookEvent(ctx, domain.WebhookEventVideoDeleted, videoID, map[string]bool{"permanent": action == domain.ActionPermanentBan})
+	}
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+// 新しい操作から順に返す
+func (i *Infrastructure) GetModerationHistory(ctx context.Context, videoID string) (_ []*domain.ModerationRecord, err error) {
+	ctx, span := infraSpan(ctx, "GetModerationHistory")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	dbRecords, err := i.db.Database.GetModerationRecordsByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*domain.ModerationRecord, 0, len(dbRecords))
+	for _, r := range dbRecords {
+		records = append(records, &domain.ModerationRecord{
+			ID:          r.ID,
+			VideoID:     r.VideoID,
+			ModeratorID: r.ModeratorID,
+			Action:      domain.ModerationAction(r.Action),
+			Reason:      r.Reason,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画のモデレーション(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        domain.ModerationAction
+		rows          [][]driver.Value
+		wantErr       error
+		wantExecs     []string
+		wantDeleted   bool
+		wantPermanent bool
+	}{
+		{name: "承認", action: domain.ActionApprove, wantExecs: []string{"CreateAuditLog", "ResolveVideoReports", "CreateModerationRecord"}},
+		{name: "成人向けにする", action: domain.ActionRemoveAsAdult, wantExecs: []string{"UpdateVideoContentRating", "CreateAuditLog", "ResolveVideoReports", "CreateModerationRecord"}},
+		{name: "論理削除", action: domain.ActionSoftDelete, wantExecs: []string{"SoftDeleteVideo", "CreateAuditLog", "ResolveVideoReports", "CreateModerationRecord"}, wantDeleted: true},
+		{name: "永久に削除", action: domain.ActionPermanentBan, wantExecs: []string{"BanVideo", "CreateAuditLog", "ResolveVideoReports", "CreateModerationRecord"}, wantDeleted: true, wantPermanent: true},
+		{name: "不明な操作", action: "hide", wantErr: domain.ErrInvalidInput},
+		{name: "動画がない", action: domain.ActionApprove, rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideoForUpdate": rows, "GetVideo": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, webhookEvents: make(chan domain.WebhookEvent, 1)}
+
+			err := i.ModerateVideo(context.Background(), "video_1", "admin_1", tt.action, "reported as spam")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.ModerateVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr != nil {
+				if connector.commits != 0 {
+					t.Errorf("commits = %d, want 0", connector.commits)
+				}
+				return
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+			record := connector.execs[len(connector.execs)-1]
+			if record[0] != "video_1" || record[1] != "admin_1" || record[2] != string(tt.action) || record[3] != "reported as spam" {
+				t.Errorf("CreateModerationRecord args = %v", record)
+			}
+
+			if !tt.wantDeleted {
+				if len(i.webhookEvents) != 0 {
+					t.Errorf("webhook events = %d, want 0", len(i.webhookEvents))
+				}
+				return
+			}
+			event := <-i.webhookEvents
+			if event.Type != domain.WebhookEventVideoDeleted || !reflect.DeepEqual(event.Payload, map[string]bool{"permanent": tt.wantPermanent}) {
+				t.Errorf("webhook event = %+v, want permanent %v", event, tt.wantPermanent)
+			}
+		})
+	}
+}
+
+func Test_永久に削除した動画の復元(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		isBanned bool
+		records  [][]driver.Value
+		wantErr  error
+	}{
+		{name: "論理削除した動画", records: [][]driver.Value{{int64(1), "video_1", "admin_1", string(domain.ActionSoftDelete), "", createdAt}}},
+		{name: "永久に削除した動画", isBanned: true, records: [][]driver.Value{
+			{int64(2), "video_1", "admin_1", string(domain.ActionApprove), "", createdAt},
+			{int64(1), "video_1", "admin_1", string(domain.ActionPermanentBan), "", createdAt},
+		}, wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := deleteTestVideoRow(true)
+			row[len(row)-1] = tt.isBanned
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetVideo":                      {row},
+				"GetVideoForUpdate":             {row},
+				"GetModerationRecordsByVideoID": tt.records,
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.RestoreVideo(context.Background(), "video_1", "admin_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.RestoreVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			wantExecs := []string{"RestoreVideo", "CreateAuditLog"}
+			if tt.wantErr != nil {
+				wantExecs = nil
+				if connector.rollbacks != 1 {
+					t.Errorf("rollbacks = %d, want 1", connector.rollbacks)
+				}
+			}
+			if !reflect.DeepEqual(connector.execNames, wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, wantExecs)
+			}
+
+			history, err := i.GetModerationHistory(context.Background(), "video_1")
+			if err != nil {
+				t.Fatalf("Infrastructure.GetModerationHistory() error = %v", err)
+			}
+			if len(history) != len(tt.records) || history[0].Action != domain.ModerationAction(tt.records[0][3].(string)) {
+				t.Errorf("Infrastructure.GetModerationHistory() = %v", history)
+			}
+		})
+	}
+}
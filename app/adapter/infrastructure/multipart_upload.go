@@ -0,0 +1,187 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// テストで小さいファイルでもマルチパートアップロードを使えるようにしている
+var (
+	// これ以上のサイズのファイルはマルチパートでアップロードする
+	multipartUploadThreshold int64 = 100 * 1024 * 1024
+	// S3のパートの最小サイズは5MB
+	multipartPartSize int64 = 10 * 1024 * 1024
+)
+
+// 同時にアップロードするパートの数。メモリにはこの数+1個分のパートを持つ
+const multipartUploadConcurrency = 4
+
+// ファイル全体をメモリに読み込まずにアップロードする
+// サイズがわからない場合はマルチパートでアップロードする
+func uploadLargeObjectToS3(ctx context.Context, client *s3.Client, bucketName, key string, r io.Reader, contentType string) error {
+	size := readerSize(r)
+	if size >= 0 && size < multipartUploadThreshold {
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(key),
+			Body:        r,
+			ContentType: aws.String(contentType),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upload file: %w", err)
+		}
+		return nil
+	}
+
+	return multipartUploadToS3(ctx, client, bucketName, key, r, contentType)
+}
+
+// Seekできない場合は-1を返す
+func readerSize(r io.Reader) int64 {
+	if file, ok := r.(*os.File); ok {
+		info, err := file.Stat()
+		if err == nil {
+			return info.Size()
+		}
+	}
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return -1
+	}
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	_, err = seeker.Seek(current, io.SeekStart)
+	if err != nil {
+		return -1
+	}
+	return end - current
+}
+
+// パートを並行してアップロードし、すべて成功したらパート番号の順に並べて完了する
+// 途中で失敗した場合はアップロード済みのパートが残らないように中止する
+func multipartUploadToS3(ctx context.Context, client *s3.Client, bucketName, key string, r io.Reader, contentType string) error {
+	created, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	uploadID := created.UploadId
+
+	partCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	sem := make(chan struct{}, multipartUploadConcurrency)
+	for partNumber := int32(1); partCtx.Err() == nil; partNumber++ {
+		buf := make([]byte, multipartPartSize)
+		n, readErr := io.ReadFull(r, buf)
+		last := errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF)
+		if readErr != nil && !last {
+			fail(readErr)
+			break
+		}
+		// 空のファイルでもパートが1つは必要なため、最初のパートは空でもアップロードする
+		if n == 0 && partNumber > 1 {
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-partCtx.Done():
+		}
+		if partCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(partNumber int32, body []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			uploaded, err := client.UploadPart(partCtx, &s3.UploadPartInput{
+				Bucket:     aws.String(bucketName),
+				Key:        aws.String(key),
+				UploadId:   uploadID,
+				PartNumber: aws.Int32(partNumber),
+				Body:       bytes.NewReader(body),
+			})
+			if err != nil {
+				fail(fmt.Errorf("failed to upload part %d: %w", partNumber, err))
+				return
+			}
+
+			mu.Lock()
+			parts = append(parts, types.CompletedPart{ETag: uploaded.ETag, PartNumber: aws.Int32(partNumber)})
+			mu.Unlock()
+		}(partNumber, buf[:n])
+
+		if last {
+			break
+		}
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		// 呼び出し元がキャンセルしていても中止できるようにする
+		_, abortErr := client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucketName),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+		if abortErr != nil {
+			slog.ErrorContext(ctx, "failed to abort multipart upload", "key", key, "error", abortErr)
+		}
+		return firstErr
+	}
+
+	sort.Slice(parts, func(a, b int) bool {
+		return *parts[a].PartNumber < *parts[b].PartNumber
+	})
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucketName),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// マルチパートアップロードのAPIだけを実装したS3
+type fakeMultipartS3 struct {
+	mu        sync.Mutex
+	parts     map[int][]byte
+	completed []int
+	aborted   bool
+	putBody   []byte
+	// このパート番号のアップロードを失敗させる
+	failPart int
+}
+
+func (s *fakeMultipartS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><Bucket>video</Bucket><Key>key</Key><UploadId>upload_1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+		body, _ := io.ReadAll(r.Body)
+		// 先のパートほど遅く終わるようにして、完了の順番とパート番号の順番をずらす
+		time.Sleep(time.Duration(10-partNumber) * 5 * time.Millisecond)
+		if partNumber == s.failPart {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<Error><Code>AccessDenied</Code></Error>`)
+			return
+		}
+		s.mu.Lock()
+		s.parts[partNumber] = body
+		s.mu.Unlock()
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, partNumber))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				ETag       string `xml:"ETag"`
+				PartNumber int    `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		err := xml.Unmarshal(body, &complete)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		for _, part := range complete.Parts {
+			if part.ETag != fmt.Sprintf(`"etag-%d"`, part.PartNumber) {
+				s.mu.Unlock()
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			s.completed = append(s.completed, part.PartNumber)
+		}
+		s.mu.Unlock()
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><Bucket>video</Bucket><Key>key</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		s.mu.Lock()
+		s.aborted = true
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.putBody = body
+		s.mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newFakeMultipartS3(t *testing.T, failPart int) *fakeMultipartS3 {
+	s3 := &fakeMultipartS3{parts: map[int][]byte{}, failPart: failPart}
+	server := httptest.NewServer(s3)
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_S3_ENDPOINT", server.URL)
+
+	originalThreshold, originalPartSize := multipartUploadThreshold, multipartPartSize
+	t.Cleanup(func() { multipartUploadThreshold, multipartPartSize = originalThreshold, originalPartSize })
+	multipartUploadThreshold, multipartPartSize = 20, 10
+	return s3
+}
+
+func Test_マルチパートアップロード(t *testing.T) {
+	s3 := newFakeMultipartS3(t, 0)
+	data := []byte("0123456789abcdefghijABCDEFGHIJxyz")
+	backend := NewS3Backend("video")
+
+	// サイズがわからないReaderはマルチパートでアップロードする
+	err := backend.Upload(context.Background(), "video_1/source_video_1.mp4", "video/mp4", io.MultiReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("S3Backend.Upload() error = %v", err)
+	}
+
+	want := []int{1, 2, 3, 4}
+	if fmt.Sprint(s3.completed) != fmt.Sprint(want) {
+		t.Errorf("completed parts = %v, want %v", s3.completed, want)
+	}
+	var got []byte
+	for _, partNumber := range s3.completed {
+		got = append(got, s3.parts[partNumber]...)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("uploaded = %q, want %q", got, data)
+	}
+	if s3.aborted {
+		t.Error("multipart upload should not be aborted")
+	}
+}
+
+func Test_閾値より小さいファイルのアップロード(t *testing.T) {
+	s3 := newFakeMultipartS3(t, 0)
+	data := []byte("0123456789")
+	backend := NewS3Backend("video")
+
+	err := backend.Upload(context.Background(), "video_1/source_video_1.mp4", "video/mp4", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("S3Backend.Upload() error = %v", err)
+	}
+	if !bytes.Equal(s3.putBody, data) || len(s3.parts) != 0 {
+		t.Errorf("put body = %q, parts = %d, want single part upload", s3.putBody, len(s3.parts))
+	}
+}
+
+func Test_失敗したマルチパートアップロードの中止(t *testing.T) {
+	s3 := newFakeMultipartS3(t, 2)
+	data := bytes.Repeat([]byte("0123456789"), 5)
+	backend := NewS3Backend("video")
+
+	err := backend.Upload(context.Background(), "video_1/source_video_1.mp4", "video/mp4", bytes.NewReader(data))
+	if err == nil {
+		t.Fatal("S3Backend.Upload() error = nil, want error")
+	}
+	if !s3.aborted {
+		t.Error("multipart upload should be aborted")
+	}
+	if len(s3.completed) != 0 {
+		t.Errorf("completed parts = %v, want none", s3.completed)
+	}
+}
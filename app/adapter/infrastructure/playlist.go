@@ -0,0 +1,241 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (i *Infrastructure) CreatePlaylist(ctx context.Context, playlist *domain.Playlist) (_ *domain.Playlist, err error) {
+	ctx, span := infraSpan(ctx, "CreatePlaylist")
+	defer func() { endSpan(span, err) }()
+
+	err = playlist.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// 途中の動画の追加に失敗した場合に、一部の動画だけを含むプレイリストが残らないようにする
+	now := time.Now()
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		_, err := q.CreatePlaylist(ctx, sqlc.CreatePlaylistParams{
+			ID:   playlist.ID,
+			Name: playlist.Title,
+			Description: sql.NullString{
+				String: playlist.Description,
+				Valid:  playlist.Description != "",
+			},
+			UserID:    playlist.OwnerID,
+			IsPublic:  playlist.IsPublic,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, videoID := range playlist.VideoIDs {
+			_, err = q.AddPlaylistVideo(ctx, sqlc.AddPlaylistVideoParams{
+				PlaylistID: playlist.ID,
+				VideoID:    videoID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.CreatedAt = now
+	playlist.UpdatedAt = now
+	return playlist, nil
+}
+
+func (i *Infrastructure) GetPlaylistByID(ctx context.Context, playlistID string) (_ *domain.Playlist, err error) {
+	ctx, span := infraSpan(ctx, "GetPlaylistByID")
+	span.SetAttributes(attribute.String("playlistID", playlistID))
+	defer func() { endSpan(span, err) }()
+
+	playlist, err := i.db.Database.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: playlist %s not found", domain.ErrInvalidInput, playlistID)
+		}
+		return nil, err
+	}
+
+	videoIDs, err := i.db.Database.GetPlaylistVideoIDs(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPlaylist(playlist.ID, playlist.UserID, playlist.Name, playlist.Description.String, playlist.IsPublic, videoIDs, playlist.CreatedAt, playlist.UpdatedAt), nil
+}
+
+// 動画はプレイリストの末尾に追加する
+// 作成者本人か管理者のみ変更できる
+func (i *Infrastructure) AddVideoToPlaylist(ctx context.Context, playlistID, videoID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "AddVideoToPlaylist")
+	span.SetAttributes(attribute.String("playlistID", playlistID), attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		// プレイリストの行をロックし、同時に追加されても上限を超えないようにする
+		err := i.lockPlaylistForOwner(ctx, q, playlistID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		count, err := q.CountPlaylistVideos(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+		if count >= domain.MaxPlaylistVideos {
+			return fmt.Errorf("%w: %s already has %d videos", domain.ErrPlaylistFull, playlistID, count)
+		}
+
+		_, err = q.AddPlaylistVideo(ctx, sqlc.AddPlaylistVideoParams{
+			PlaylistID: playlistID,
+			VideoID:    videoID,
+		})
+		if err != nil {
+			return err
+		}
+		return touchPlaylist(ctx, q, playlistID)
+	})
+}
+
+// 作成者本人か管理者のみ変更できる
+func (i *Infrastructure) RemoveVideoFromPlaylist(ctx context.Context, playlistID, videoID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "RemoveVideoFromPlaylist")
+	span.SetAttributes(attribute.String("playlistID", playlistID), attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		err := i.lockPlaylistForOwner(ctx, q, playlistID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.RemovePlaylistVideo(ctx, sqlc.RemovePlaylistVideoParams{
+			PlaylistID: playlistID,
+			VideoID:    videoID,
+		})
+		if err != nil {
+			return err
+		}
+		return touchPlaylist(ctx, q, playlistID)
+	})
+}
+
+// videoIDsにはプレイリストの全ての動画を新しい順番で渡す
+// 並び替えは1つのUPDATE文で行うので途中の状態が見えることはない
+// 作成者本人か管理者のみ変更できる
+func (i *Infrastructure) ReorderPlaylistVideos(ctx context.Context, playlistID string, videoIDs []string, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "ReorderPlaylistVideos")
+	span.SetAttributes(attribute.String("playlistID", playlistID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	err = domain.ValidatePlaylistVideoIDs(videoIDs)
+	if err != nil {
+		return err
+	}
+
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		err := i.lockPlaylistForOwner(ctx, q, playlistID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		current, err := q.GetPlaylistVideoIDs(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+		if !samePlaylistVideos(current, videoIDs) {
+			return fmt.Errorf("%w: video ids must match the videos in playlist %s", domain.ErrInvalidInput, playlistID)
+		}
+		if len(videoIDs) == 0 {
+			return nil
+		}
+
+		_, err = q.ReorderPlaylistVideos(ctx, sqlc.ReorderPlaylistVideosParams{
+			VideoIds:   videoIDs,
+			PlaylistID: playlistID,
+		})
+		if err != nil {
+			return err
+		}
+		return touchPlaylist(ctx, q, playlistID)
+	})
+}
+
+// 外部キーがあるので先に動画との紐付けを削除する
+// 作成者本人か管理者のみ削除できる
+func (i *Infrastructure) DeletePlaylist(ctx context.Context, playlistID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "DeletePlaylist")
+	span.SetAttributes(attribute.String("playlistID", playlistID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	return i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		err := i.lockPlaylistForOwner(ctx, q, playlistID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.DeletePlaylistVideos(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+		_, err = q.DeletePlaylist(ctx, playlistID)
+		return err
+	})
+}
+
+// プレイリストの行をロックし、requestingUserIDが作成者本人か管理者かを確認する
+func (i *Infrastructure) lockPlaylistForOwner(ctx context.Context, q *sqlc.Queries, playlistID, requestingUserID string) error {
+	playlist, err := q.GetPlaylistForUpdate(ctx, playlistID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: playlist %s not found", domain.ErrInvalidInput, playlistID)
+	}
+	if err != nil {
+		return err
+	}
+	if playlist.UserID != requestingUserID && !i.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not the owner of playlist %s", domain.ErrPermissionDenied, requestingUserID, playlistID)
+	}
+	return nil
+}
+
+func touchPlaylist(ctx context.Context, q *sqlc.Queries, playlistID string) error {
+	_, err := q.TouchPlaylist(ctx, sqlc.TouchPlaylistParams{
+		UpdatedAt: time.Now(),
+		ID:        playlistID,
+	})
+	return err
+}
+
+// 順番を無視して同じ動画の集合かを判定する。重複はValidatePlaylistVideoIDsで弾いている前提
+func samePlaylistVideos(current, videoIDs []string) bool {
+	if len(current) != len(videoIDs) {
+		return false
+	}
+	set := make(map[string]struct{}, len(current))
+	for _, id := range current {
+		set[id] = struct{}{}
+	}
+	for _, id := range videoIDs {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
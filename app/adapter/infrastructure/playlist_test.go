@@ -0,0 +1,191 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// id, name, description, user_id, created_at, is_public, updated_at
+func playlistTestRow() []driver.Value {
+	now := time.Now()
+	return []driver.Value{"playlist_1", "name", nil, "user_1", now, true, now}
+}
+
+func Test_プレイリストの作成(t *testing.T) {
+	tests := []struct {
+		name       string
+		execErrors map[string]error
+		wantErr    bool
+		wantNames  []string
+	}{
+		{name: "動画を含むプレイリスト", wantNames: []string{"CreatePlaylist", "AddPlaylistVideo", "AddPlaylistVideo"}},
+		// 動画の追加に失敗した場合はプレイリストも残さない
+		{name: "動画の追加に失敗", execErrors: map[string]error{"AddPlaylistVideo": errors.New("foreign key")}, wantErr: true, wantNames: []string{"CreatePlaylist", "AddPlaylistVideo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{execErrors: tt.execErrors}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+			}
+
+			playlist := domain.NewPlaylist("playlist_1", "user_1", "name", "", true, []string{"video_1", "video_2"}, time.Time{}, time.Time{})
+			_, err := i.CreatePlaylist(context.Background(), playlist)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infrastructure.CreatePlaylist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(connector.execNames, tt.wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantNames)
+			}
+			wantCommits, wantRollbacks := 1, 0
+			if tt.wantErr {
+				wantCommits, wantRollbacks = 0, 1
+			}
+			if connector.commits != wantCommits || connector.rollbacks != wantRollbacks {
+				t.Errorf("commits = %d, rollbacks = %d, want %d and %d", connector.commits, connector.rollbacks, wantCommits, wantRollbacks)
+			}
+		})
+	}
+}
+
+func Test_プレイリストへの動画の追加(t *testing.T) {
+	tests := []struct {
+		name             string
+		count            int64
+		requestingUserID string
+		wantErr          error
+	}{
+		{name: "空のプレイリスト", count: 0, requestingUserID: "user_1"},
+		{name: "上限の1つ手前", count: domain.MaxPlaylistVideos - 1, requestingUserID: "user_1"},
+		{name: "上限に達している", count: domain.MaxPlaylistVideos, requestingUserID: "user_1", wantErr: domain.ErrPlaylistFull},
+		{name: "管理者", count: 0, requestingUserID: "admin_1"},
+		{name: "他のユーザー", count: 0, requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetPlaylistForUpdate": {playlistTestRow()},
+				"CountPlaylistVideos":  {{tt.count}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			err := i.AddVideoToPlaylist(context.Background(), "playlist_1", "video_1", tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.AddVideoToPlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("exec calls = %v, want none", connector.execs)
+				}
+				return
+			}
+			wantNames := []string{"AddPlaylistVideo", "TouchPlaylist"}
+			if !reflect.DeepEqual(connector.execNames, wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+			}
+		})
+	}
+}
+
+func Test_プレイリストの動画の削除とプレイリストの削除(t *testing.T) {
+	tests := []struct {
+		name             string
+		requestingUserID string
+		rows             [][]driver.Value
+		wantErr          error
+	}{
+		{name: "作成者本人", requestingUserID: "user_1"},
+		{name: "管理者", requestingUserID: "admin_1"},
+		{name: "他のユーザー", requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+		{name: "プレイリストがない", requestingUserID: "user_1", rows: [][]driver.Value{}, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				rows = [][]driver.Value{playlistTestRow()}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetPlaylistForUpdate": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			ctx := context.Background()
+			err := i.RemoveVideoFromPlaylist(ctx, "playlist_1", "video_1", tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.RemoveVideoFromPlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+			err = i.DeletePlaylist(ctx, "playlist_1", tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.DeletePlaylist() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var wantNames []string
+			if tt.wantErr == nil {
+				wantNames = []string{"RemovePlaylistVideo", "TouchPlaylist", "DeletePlaylistVideos", "DeletePlaylist"}
+			}
+			if !reflect.DeepEqual(connector.execNames, wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+			}
+		})
+	}
+}
+
+func Test_プレイリストの並び替え(t *testing.T) {
+	tests := []struct {
+		name             string
+		videoIDs         []string
+		requestingUserID string
+		wantErr          error
+		wantExecs        int
+	}{
+		// 更新日時の更新も含む
+		{name: "同じ動画の集合", videoIDs: []string{"video_1"}, requestingUserID: "user_1", wantExecs: 2},
+		{name: "含まれていない動画", videoIDs: []string{"video_2"}, requestingUserID: "user_1", wantErr: domain.ErrInvalidInput},
+		{name: "動画が足りない", videoIDs: []string{}, requestingUserID: "user_1", wantErr: domain.ErrInvalidInput},
+		{name: "重複した動画", videoIDs: []string{"video_1", "video_1"}, requestingUserID: "user_1", wantErr: domain.ErrInvalidInput},
+		{name: "他のユーザー", videoIDs: []string{"video_1"}, requestingUserID: "user_2", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// プレイリストにはvideo_1だけが含まれている
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetPlaylistForUpdate": {playlistTestRow()},
+				"GetPlaylistVideoIDs":  {{"video_1"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+			}
+
+			err := i.ReorderPlaylistVideos(context.Background(), "playlist_1", tt.videoIDs, tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.ReorderPlaylistVideos() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(connector.execs) != tt.wantExecs {
+				t.Errorf("exec calls = %v, want %d calls", connector.execs, tt.wantExecs)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 動画全体の何割の位置からプレビューを切り出すか
+const previewStartRatio = 0.1
+
+// 動画の一部をループするWebPアニメーションに変換してpreview_urlに保存する
+// ffmpegが入っていない環境ではアップロードを妨げないように何もせず空文字を返す
+func (i *Infrastructure) GenerateAnimatedPreview(ctx context.Context, videoID string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GenerateAnimatedPreview")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	dbVideo, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+
+	tempDir := "temp"
+	err = os.MkdirAll(tempDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	key := "previews/" + videoID + ".webp"
+	previewPath := filepath.Join(tempDir, "preview_"+videoID+".webp")
+	defer os.Remove(previewPath)
+
+	duration := time.Duration(dbVideo.DurationMs) * time.Millisecond
+	start := time.Duration(float64(duration) * previewStartRatio)
+	args := []string{"-ss", formatSeconds(start), "-t", formatSeconds(i.config.PreviewDuration), "-i", ffmpegInput, "-vf", "scale=320:-1", "-loop", "0", "-an", "-f", "webp", previewPath, "-y"}
+	_, err = i.ffmpegFromObjectURL(ctx, "preview", dbVideo.VideoUrl, args)
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			i.log().WarnContext(ctx, "ffmpeg not found, skip generating preview", "videoID", videoID)
+			return "", nil
+		}
+		return "", err
+	}
+
+	err = i.uploadObject(ctx, previewPath, videoBucketName, key, "image/webp")
+	if err != nil {
+		return "", err
+	}
+
+	url := i.objectURL(videoBucketName, key)
+	err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionGeneratePreview, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoPreviewURL(ctx, sqlc.UpdateVideoPreviewURLParams{
+			PreviewUrl: sql.NullString{
+				String: url,
+				Valid:  true,
+			},
+			UpdatedAt: time.Now(),
+			ID:        videoID,
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return url, nil
+}
+
+// ffmpegの-ssや-tに渡す秒数の文字列にする
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
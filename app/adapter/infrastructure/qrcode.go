@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// サムネイルと同じく公開されているバケットに置く
+const qrCodeBucketName = thumbnailBucketName
+
+func qrCodeKey(videoID string) string {
+	return "qrcodes/" + videoID + ".png"
+}
+
+// テストで保存先へのアップロードを差し替えられるようにしている
+var uploadQRCode = (*Infrastructure).uploadQRCodeImage
+
+// 動画のページのリンクのQRコードを返す
+// 一度作ったらqrcode_urlに保存して使い回し、投稿者を変更した場合は作り直す
+func (i *Infrastructure) GetOrGenerateQRCode(ctx context.Context, videoID string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GetOrGenerateQRCode")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	if video.QrcodeUrl.Valid && video.QrcodeUrl.String != "" {
+		return video.QrcodeUrl.String, nil
+	}
+
+	shareURL, err := domain.GenerateShareLink(i.config.SiteURL, videoID, nil)
+	if err != nil {
+		return "", err
+	}
+	png, err := qrcode.Encode(shareURL, qrcode.Medium, int(i.config.QRCodeSize))
+	if err != nil {
+		return "", err
+	}
+
+	url, err := uploadQRCode(i, ctx, qrCodeKey(videoID), png)
+	if err != nil {
+		return "", err
+	}
+
+	err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionGenerateQRCode, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoQRCodeURL(ctx, sqlc.UpdateVideoQRCodeURLParams{
+			QrcodeUrl: sql.NullString{String: url, Valid: true},
+			ID:        videoID,
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+func (i *Infrastructure) uploadQRCodeImage(ctx context.Context, key string, png []byte) (string, error) {
+	err := i.bucket(qrCodeBucketName).UploadPublic(ctx, key, "image/png", bytes.NewReader(png))
+	if err != nil {
+		return "", err
+	}
+	return i.objectURL(qrCodeBucketName, key), nil
+}
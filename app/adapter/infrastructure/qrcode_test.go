@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_QRコードの取得(t *testing.T) {
+	tests := []struct {
+		name          string
+		qrCodeURL     driver.Value
+		rows          [][]driver.Value
+		want          string
+		wantUpload    bool
+		wantExecNames []string
+		wantErr       error
+	}{
+		{
+			name:          "まだ作っていない場合は作る",
+			qrCodeURL:     nil,
+			want:          "https://s3.example.com/thumbnail-image/qrcodes/video_1.png",
+			wantUpload:    true,
+			wantExecNames: []string{"UpdateVideoQRCodeURL", "CreateAuditLog"},
+		},
+		{
+			name:      "作ってある場合は使い回す",
+			qrCodeURL: "https://s3.example.com/cached.png",
+			want:      "https://s3.example.com/cached.png",
+		},
+		{name: "動画がない", rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploadedKey string
+			var uploaded []byte
+			original := uploadQRCode
+			t.Cleanup(func() { uploadQRCode = original })
+			uploadQRCode = func(i *Infrastructure, ctx context.Context, key string, png []byte) (string, error) {
+				uploadedKey = key
+				uploaded = png
+				return "https://s3.example.com/thumbnail-image/" + key, nil
+			}
+
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				row = row[:len(row)-2]
+				row[len(row)-11] = tt.qrCodeURL
+				rows = [][]driver.Value{row}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": rows, "GetVideoForUpdate": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{SiteURL: "https://yuovision.yuorei.com", QRCodeSize: 128},
+			}
+
+			got, err := i.GetOrGenerateQRCode(context.Background(), "video_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.GetOrGenerateQRCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.GetOrGenerateQRCode() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+			if !tt.wantUpload {
+				if uploaded != nil {
+					t.Error("QR code should not be generated")
+				}
+				return
+			}
+
+			if uploadedKey != "qrcodes/video_1.png" {
+				t.Errorf("uploaded key = %v, want qrcodes/video_1.png", uploadedKey)
+			}
+			img, err := png.Decode(bytes.NewReader(uploaded))
+			if err != nil {
+				t.Fatalf("uploaded QR code is not a PNG: %v", err)
+			}
+			if size := img.Bounds().Dx(); size != 128 {
+				t.Errorf("QR code size = %d, want 128", size)
+			}
+			args := connector.execs[0]
+			if args[0] != tt.want || args[1] != "video_1" {
+				t.Errorf("UpdateVideoQRCodeURL args = %v, want [%v video_1]", args, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Windowの間にMax回までアップロードやダウンロードができる
+type RateLimitConfig struct {
+	Window time.Duration
+	Max    int
+}
+
+// アップロードを1回分数え、残りのアップロード回数とカウントがリセットされる時刻、数えた記録を返す
+// アップロードが失敗した場合は記録をReleaseUploadRateLimitで返す
+func (i *Infrastructure) TakeUploadAPIRateLimit(ctx context.Context, id string) (_ int, _ time.Time, _ string, err error) {
+	ctx, span := infraSpan(ctx, "TakeUploadAPIRateLimit")
+	span.SetAttributes(attribute.String("userID", id))
+	defer func() { endSpan(span, err) }()
+
+	return takeRateLimit(ctx, i.redis, uploadRateLimitKey(id), i.config.UploadRateLimit, domain.ErrUploadRateLimitExceeded)
+}
+
+// 複数アカウントを作ってユーザー単位の制限を回避されないようにIPアドレスでも制限する
+func (i *Infrastructure) TakeUploadIPRateLimit(ctx context.Context, ip string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "TakeUploadIPRateLimit")
+	defer func() { endSpan(span, err) }()
+
+	key, err := uploadIPRateLimitKey(ip)
+	if err != nil {
+		return "", err
+	}
+	_, _, taken, err := takeRateLimit(ctx, i.redis, key, i.config.UploadIPRateLimit, domain.ErrUploadRateLimitExceeded)
+	return taken, err
+}
+
+// TakeUploadAPIRateLimitとTakeUploadIPRateLimitで数えた記録を消し、失敗したアップロードを数えないようにする
+// 空の記録は数えていないため何もしない
+func (i *Infrastructure) ReleaseUploadRateLimit(ctx context.Context, userID, userTaken, ip, ipTaken string) (err error) {
+	ctx, span := infraSpan(ctx, "ReleaseUploadRateLimit")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	if userTaken != "" {
+		err = i.redis.ZRem(ctx, uploadRateLimitKey(userID), userTaken).Err()
+		if err != nil {
+			return err
+		}
+	}
+	if ipTaken != "" {
+		key, err := uploadIPRateLimitKey(ip)
+		if err != nil {
+			return err
+		}
+		return i.redis.ZRem(ctx, key, ipTaken).Err()
+	}
+	return nil
+}
+
+// 署名付きURLを共有してユーザー単位の制限を回避されないように、URLの発行の回数を制限する
+// ログインしていない場合はIPアドレスで制限する
+func (i *Infrastructure) TakeDownloadRateLimit(ctx context.Context, userID, ip string) (err error) {
+	ctx, span := infraSpan(ctx, "TakeDownloadRateLimit")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	key, config, err := i.downloadRateLimitKey(userID, ip)
+	if err != nil {
+		return err
+	}
+	_, resetAt, _, err := takeRateLimit(ctx, i.redis, key, config, domain.ErrDownloadRateLimitExceeded)
+	if errors.Is(err, domain.ErrDownloadRateLimitExceeded) {
+		i.log().WarnContext(ctx, "download rate limit exceeded", "userID", userID, "ip", ip, "resetAt", resetAt)
+	}
+	return err
+}
+
+func (i *Infrastructure) downloadRateLimitKey(userID, ip string) (string, RateLimitConfig, error) {
+	if userID != "" {
+		return "download:" + userID, i.config.DownloadRateLimit, nil
+	}
+	key, err := ipRateLimitKey("download-ip", ip)
+	return key, i.config.DownloadIPRateLimit, err
+}
+
+func uploadRateLimitKey(userID string) string {
+	return "upload:" + userID
+}
+
+func uploadIPRateLimitKey(ip string) (string, error) {
+	return ipRateLimitKey("upload-ip", ip)
+}
+
+// IPv6はアドレスを変えながらの回避を防ぐため/64のプレフィックス単位でまとめる
+func ipRateLimitKey(prefix, ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("%w: invalid ip address %q", domain.ErrInvalidInput, ip)
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return prefix + ":" + v4.String(), nil
+	}
+	masked := parsed.Mask(net.CIDRMask(64, 128))
+	return prefix + ":" + masked.String() + "/64", nil
+}
+
+// テストで時刻を差し替えられるようにしている
+var rateLimitNow = time.Now
+
+// 古い記録を消し、上限に達していなければ今回の記録を追加する
+// 同時に呼ばれても上限を超えないように1つのスクリプトで行う
+// 返り値は追加したか(1か0)、追加した後の回数、一番古い記録の時刻(ミリ秒。記録がない場合は空文字)
+var takeRateLimitScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+local count = redis.call('ZCARD', KEYS[1])
+local taken = 0
+if count < tonumber(ARGV[2]) then
+	redis.call('ZADD', KEYS[1], ARGV[3], ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], ARGV[5])
+	count = count + 1
+	taken = 1
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {taken, count, oldest[2] or ''}
+`)
+
+// 直近Windowの間の時刻をスコアにしたソート済みセットで回数を数える(スライディングウィンドウ)
+// 固定ウィンドウのように境界をまたいで2倍の回数を使われることがない
+// 一番古い記録がWindowの外に出た時点で1回分の枠が空く
+// 制限を超えた場合は記録せず、reasonをRateLimitErrorに包んで返す
+// 記録した場合は取り消せるように記録のメンバーを返す
+func takeRateLimit(ctx context.Context, client *redis.Client, key string, config RateLimitConfig, reason error) (int, time.Time, string, error) {
+	now := rateLimitNow()
+	member := strconv.FormatInt(now.UnixNano(), 10) + domain.IDSeparator + domain.NewUUID()
+	result, err := takeRateLimitScript.Run(ctx, client, []string{key},
+		windowStart(now, config.Window),
+		config.Max,
+		now.UnixMilli(),
+		member,
+		config.Window.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return 0, time.Time{}, "", err
+	}
+	if len(result) != 3 {
+		return 0, time.Time{}, "", fmt.Errorf("unexpected rate limit script result: %v", result)
+	}
+	taken, _ := result[0].(int64)
+	count, _ := result[1].(int64)
+	oldest, _ := result[2].(string)
+
+	resetAt, err := rateLimitResetAt(oldest, config.Window)
+	if err != nil {
+		return 0, time.Time{}, "", err
+	}
+	if taken == 0 {
+		return 0, resetAt, "", domain.NewRateLimitError(reason, resetAt)
+	}
+	return max(config.Max-int(count), 0), resetAt, member, nil
+}
+
+// Window以前の記録を削除する範囲の上限(この値を含む)
+func windowStart(now time.Time, window time.Duration) string {
+	return strconv.FormatInt(now.Add(-window).UnixMilli(), 10)
+}
+
+func rateLimitResetAt(oldest string, window time.Duration) (time.Time, error) {
+	if oldest == "" {
+		return time.Time{}, nil
+	}
+	score, err := strconv.ParseFloat(oldest, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(score)).Add(window), nil
+}
@@ -0,0 +1,364 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+	})
+	return mr, client
+}
+
+func Test_アップロードのレート制限(t *testing.T) {
+	tests := []struct {
+		name          string
+		uploads       int
+		wantRemaining int
+		wantErr       error
+	}{
+		{
+			name:          "no uploads",
+			uploads:       0,
+			wantRemaining: 2,
+			wantErr:       nil,
+		},
+		{
+			name:          "last upload",
+			uploads:       2,
+			wantRemaining: 0,
+			wantErr:       nil,
+		},
+		{
+			name:          "exactly at limit",
+			uploads:       3,
+			wantRemaining: 0,
+			wantErr:       domain.ErrUploadRateLimitExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			i := &Infrastructure{
+				redis: client,
+				config: InfrastructureConfig{
+					UploadRateLimit: RateLimitConfig{Window: time.Hour, Max: 3},
+				},
+			}
+
+			ctx := context.Background()
+			for n := 0; n < tt.uploads; n++ {
+				if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1"); err != nil {
+					t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+				}
+			}
+
+			remaining, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Infrastructure.TakeUploadAPIRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if remaining != tt.wantRemaining {
+				t.Errorf("Infrastructure.TakeUploadAPIRateLimit() remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+			// 制限を超えた場合は記録しない
+			if n, _ := client.ZCard(ctx, "upload:user_1").Result(); n != int64(min(tt.uploads+1, 3)) {
+				t.Errorf("recorded uploads = %d, want %d", n, min(tt.uploads+1, 3))
+			}
+			if ttl := mr.TTL("upload:user_1"); ttl <= 0 || ttl > time.Hour {
+				t.Errorf("ttl = %v, want within window", ttl)
+			}
+		})
+	}
+}
+
+func Test_同時のアップロードのレート制限(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit: RateLimitConfig{Window: time.Hour, Max: 3},
+		},
+	}
+
+	// 確認と記録が分かれていると、全て確認を通った後に記録されて上限を超える
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for n := 0; n < 20; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, err := i.TakeUploadAPIRateLimit(context.Background(), "user_1")
+			if err != nil && !errors.Is(err, domain.ErrUploadRateLimitExceeded) {
+				t.Errorf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+			}
+			if err == nil {
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if taken != 3 {
+		t.Errorf("taken = %d, want 3", taken)
+	}
+}
+
+func Test_アップロードのレート制限のリセット(t *testing.T) {
+	mr, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit: RateLimitConfig{Window: time.Hour, Max: 1},
+		},
+	}
+
+	ctx := context.Background()
+	_, takenResetAt, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+	_, resetAt, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+	if !errors.Is(err, domain.ErrUploadRateLimitExceeded) {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v, want ErrUploadRateLimitExceeded", err)
+	}
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.ResetAt.Equal(resetAt) {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() error = %v, want RateLimitError with reset at %v", err, resetAt)
+	}
+	if !resetAt.Equal(takenResetAt) {
+		t.Errorf("reset at = %v, want %v", resetAt, takenResetAt)
+	}
+
+	mr.FastForward(time.Hour)
+	if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() after window error = %v", err)
+	}
+}
+
+func Test_IPアドレスのレート制限キー(t *testing.T) {
+	tests := []struct {
+		name    string
+		ip      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ipv4",
+			ip:   "192.0.2.1",
+			want: "upload-ip:192.0.2.1",
+		},
+		{
+			name: "ipv4 mapped ipv6",
+			ip:   "::ffff:192.0.2.1",
+			want: "upload-ip:192.0.2.1",
+		},
+		{
+			name: "ipv6",
+			ip:   "2001:db8:1:2:3:4:5:6",
+			want: "upload-ip:2001:db8:1:2::/64",
+		},
+		{
+			name: "ipv6 in same /64",
+			ip:   "2001:db8:1:2:ffff:ffff:ffff:ffff",
+			want: "upload-ip:2001:db8:1:2::/64",
+		},
+		{
+			name:    "invalid",
+			ip:      "example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uploadIPRateLimitKey(tt.ip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("uploadIPRateLimitKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("uploadIPRateLimitKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_IPアドレスのアップロードのレート制限(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit:   RateLimitConfig{Window: time.Hour, Max: 1},
+			UploadIPRateLimit: RateLimitConfig{Window: time.Hour, Max: 2},
+		},
+	}
+
+	ctx := context.Background()
+	for _, ip := range []string{"2001:db8::1", "2001:db8::2"} {
+		if _, err := i.TakeUploadIPRateLimit(ctx, ip); err != nil {
+			t.Fatalf("Infrastructure.TakeUploadIPRateLimit() error = %v", err)
+		}
+	}
+
+	if _, err := i.TakeUploadIPRateLimit(ctx, "2001:db8::3"); !errors.Is(err, domain.ErrUploadRateLimitExceeded) {
+		t.Errorf("Infrastructure.TakeUploadIPRateLimit() error = %v, want ErrUploadRateLimitExceeded", err)
+	}
+	if _, err := i.TakeUploadIPRateLimit(ctx, "2001:db8:0:1::1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadIPRateLimit() other prefix error = %v", err)
+	}
+	// ユーザー単位の制限とはキーが分かれている
+	if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "2001:db8::1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+}
+
+func Test_失敗したアップロードのレート制限の返却(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit:   RateLimitConfig{Window: time.Hour, Max: 1},
+			UploadIPRateLimit: RateLimitConfig{Window: time.Hour, Max: 1},
+		},
+	}
+
+	ctx := context.Background()
+	_, _, userTaken, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+	ipTaken, err := i.TakeUploadIPRateLimit(ctx, "192.0.2.1")
+	if err != nil {
+		t.Fatalf("Infrastructure.TakeUploadIPRateLimit() error = %v", err)
+	}
+	// 他のアップロードの記録は消さない
+	_, _, otherTaken, err := i.TakeUploadAPIRateLimit(ctx, "user_2")
+	if err != nil {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+
+	err = i.ReleaseUploadRateLimit(ctx, "user_1", userTaken, "192.0.2.1", ipTaken)
+	if err != nil {
+		t.Fatalf("Infrastructure.ReleaseUploadRateLimit() error = %v", err)
+	}
+	if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() after release error = %v", err)
+	}
+	if _, err := i.TakeUploadIPRateLimit(ctx, "192.0.2.1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadIPRateLimit() after release error = %v", err)
+	}
+	if err := client.ZScore(ctx, "upload:user_2", otherTaken).Err(); err != nil {
+		t.Errorf("other user's upload was released: %v", err)
+	}
+	// 数えていない場合は何もしない
+	if err := i.ReleaseUploadRateLimit(ctx, "user_1", "", "", ""); err != nil {
+		t.Errorf("Infrastructure.ReleaseUploadRateLimit() empty error = %v", err)
+	}
+}
+
+func Test_ウィンドウの境界をまたいだ連続アップロード(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit: RateLimitConfig{Window: time.Hour, Max: 2},
+		},
+	}
+
+	originalNow := rateLimitNow
+	t.Cleanup(func() {
+		rateLimitNow = originalNow
+	})
+	base := time.Date(2024, 1, 1, 0, 59, 0, 0, time.UTC)
+	setNow := func(d time.Duration) {
+		rateLimitNow = func() time.Time { return base.Add(d) }
+	}
+
+	// 固定ウィンドウの終わり際に上限まで使う
+	ctx := context.Background()
+	setNow(0)
+	for n := 0; n < 2; n++ {
+		if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1"); err != nil {
+			t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+		}
+	}
+
+	// 固定ウィンドウなら1:00でリセットされるが、直近1時間に2回アップロードしているので制限される
+	setNow(2 * time.Minute)
+	_, resetAt, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+	if !errors.Is(err, domain.ErrUploadRateLimitExceeded) {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v, want ErrUploadRateLimitExceeded", err)
+	}
+	if want := base.Add(time.Hour); !resetAt.Equal(want) {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() reset at = %v, want %v", resetAt, want)
+	}
+
+	// 最初のアップロードから1時間経つと枠が空く
+	setNow(time.Hour + time.Second)
+	remaining, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() remaining = %v, want 1", remaining)
+	}
+}
+
+func Test_ダウンロードのレート制限(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis: client,
+		config: InfrastructureConfig{
+			UploadRateLimit:     RateLimitConfig{Window: time.Hour, Max: 1},
+			DownloadRateLimit:   RateLimitConfig{Window: time.Hour, Max: 2},
+			DownloadIPRateLimit: RateLimitConfig{Window: time.Hour, Max: 1},
+		},
+	}
+
+	ctx := context.Background()
+	for n := 0; n < 2; n++ {
+		if err := i.TakeDownloadRateLimit(ctx, "user_1", "192.0.2.1"); err != nil {
+			t.Fatalf("Infrastructure.TakeDownloadRateLimit() error = %v", err)
+		}
+	}
+
+	err := i.TakeDownloadRateLimit(ctx, "user_1", "192.0.2.1")
+	if !errors.Is(err, domain.ErrDownloadRateLimitExceeded) {
+		t.Fatalf("Infrastructure.TakeDownloadRateLimit() error = %v, want ErrDownloadRateLimitExceeded", err)
+	}
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.ResetAt.IsZero() {
+		t.Errorf("Infrastructure.TakeDownloadRateLimit() error = %v, want RateLimitError with reset at", err)
+	}
+	if errors.Is(err, domain.ErrUploadRateLimitExceeded) {
+		t.Errorf("download rate limit error should not be upload rate limit error")
+	}
+	// アップロードの制限とはキーが分かれている
+	if _, _, _, err := i.TakeUploadAPIRateLimit(ctx, "user_1"); err != nil {
+		t.Errorf("Infrastructure.TakeUploadAPIRateLimit() error = %v", err)
+	}
+
+	// ログインしていない場合はIPアドレスで制限する
+	if err := i.TakeDownloadRateLimit(ctx, "", "192.0.2.1"); err != nil {
+		t.Fatalf("Infrastructure.TakeDownloadRateLimit() by ip error = %v", err)
+	}
+	if err := i.TakeDownloadRateLimit(ctx, "", "192.0.2.1"); !errors.Is(err, domain.ErrDownloadRateLimitExceeded) {
+		t.Errorf("Infrastructure.TakeDownloadRateLimit() by ip error = %v, want ErrDownloadRateLimitExceeded", err)
+	}
+	if err := i.TakeDownloadRateLimit(ctx, "", "invalid"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.TakeDownloadRateLimit() invalid ip error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 評価数は頻繁に変わるのでキャッシュは短くする
+const reactionCountsCacheTTL = 60 * time.Second
+
+type ReactionCountsJsonType struct {
+	Likes    int64 `json:"likes"`
+	Dislikes int64 `json:"dislikes"`
+}
+
+func reactionCountsKey(videoID string) string {
+	return "reaction" + domain.IDSeparator + videoID
+}
+
+// (video_id, user_id)のユニークインデックスで1ユーザー1評価にし、
+// 高評価から低評価への変更は1つのクエリで置き換える
+func (i *Infrastructure) UpsertReaction(ctx context.Context, videoID, userID string, reaction domain.ReactionType) (err error) {
+	ctx, span := infraSpan(ctx, "UpsertReaction")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	err = reaction.Validate()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = i.db.Database.UpsertReaction(ctx, sqlc.UpsertReactionParams{
+		ID:        domain.NewUUID(),
+		VideoID:   videoID,
+		UserID:    userID,
+		Reaction:  string(reaction),
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return i.redis.Del(ctx, reactionCountsKey(videoID)).Err()
+}
+
+func (i *Infrastructure) GetReactionCounts(ctx context.Context, videoID string) (_ int64, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetReactionCounts")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	var counts ReactionCountsJsonType
+	hit, err := i.getFromCache(ctx, "reaction_counts", reactionCountsKey(videoID), &counts)
+	if err != nil {
+		return 0, 0, err
+	} else if hit {
+		return counts.Likes, counts.Dislikes, nil
+	}
+
+	row, err := i.db.Database.GetReactionCounts(ctx, videoID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	counts = ReactionCountsJsonType{
+		Likes:    row.Likes,
+		Dislikes: row.Dislikes,
+	}
+	err = setToRedis(ctx, i.redis, reactionCountsKey(videoID), reactionCountsCacheTTL, &counts)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return counts.Likes, counts.Dislikes, nil
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の評価の登録(t *testing.T) {
+	tests := []struct {
+		name     string
+		reaction domain.ReactionType
+		wantErr  error
+	}{
+		{name: "高評価", reaction: domain.ReactionLike},
+		{name: "低評価", reaction: domain.ReactionDislike},
+		{name: "不正な評価", reaction: "love", wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			fake := &fakeDBTX{}
+			i := &Infrastructure{
+				db:    &db.DB{Database: sqlc.New(fake)},
+				redis: client,
+			}
+			mr.Set(reactionCountsKey("video_1"), `{"likes":1,"dislikes":0}`)
+
+			err := i.UpsertReaction(context.Background(), "video_1", "user_1", tt.reaction)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.UpsertReaction() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(fake.execs) != 0 {
+					t.Errorf("exec calls = %v, want none", fake.execs)
+				}
+				return
+			}
+
+			if len(fake.execs) != 1 {
+				t.Fatalf("exec calls = %v, want 1 call", fake.execs)
+			}
+			args := fake.execs[0]
+			if args[1] != "video_1" || args[2] != "user_1" || args[3] != string(tt.reaction) {
+				t.Errorf("exec args = %v", args)
+			}
+			// 評価が変わったのでキャッシュは消える
+			if mr.Exists(reactionCountsKey("video_1")) {
+				t.Error("reaction counts cache was not removed")
+			}
+		})
+	}
+}
+
+func Test_動画の評価数のキャッシュ(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(3), int64(1)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(sqlDB)},
+		redis: client,
+	}
+
+	for n := 0; n < 2; n++ {
+		likes, dislikes, err := i.GetReactionCounts(context.Background(), "video_1")
+		if err != nil {
+			t.Fatalf("Infrastructure.GetReactionCounts() error = %v", err)
+		}
+		if likes != 3 || dislikes != 1 {
+			t.Errorf("Infrastructure.GetReactionCounts() = %v, %v, want 3, 1", likes, dislikes)
+		}
+	}
+	// 2回目はキャッシュから返る
+	if connector.queries != 1 {
+		t.Errorf("GetReactionCounts queried the database %d times, want 1", connector.queries)
+	}
+}
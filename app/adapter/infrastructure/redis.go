@@ -31,7 +31,37 @@ func getFromRedis(ctx context.Context, client *redis.Client, key string, data an
 		if err != nil {
 			return false, err
 		}
-	case *UploaderID:
+	case *ReactionCountsJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *BookmarkJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *StorageQuotaJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *DownloadCountJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *AudioURLJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *TagSuggestionsJsonType:
+		err = json.Unmarshal(bytes, v)
+		if err != nil {
+			return false, err
+		}
+	case *domain.VideoAnalysis:
 		err = json.Unmarshal(bytes, v)
 		if err != nil {
 			return false, err
@@ -43,6 +73,20 @@ func getFromRedis(ctx context.Context, client *redis.Client, key string, data an
 	return true, nil
 }
 
+// 取得できたかをcacheの種類ごとに記録する
+func (i *Infrastructure) getFromCache(ctx context.Context, cache, key string, data any) (bool, error) {
+	hit, err := getFromRedis(ctx, i.redis, key, data)
+	if err != nil {
+		return false, err
+	}
+	if hit {
+		i.recordMetrics().RecordCacheHit(cache)
+	} else {
+		i.recordMetrics().RecordCacheMiss(cache)
+	}
+	return hit, nil
+}
+
 func setToRedis(ctx context.Context, client *redis.Client, key string, expiration time.Duration, value any) error {
 	bytes, err := json.Marshal(value)
 	if err != nil {
@@ -60,7 +104,37 @@ func setToRedis(ctx context.Context, client *redis.Client, key string, expiratio
 		if err != nil {
 			return err
 		}
-	case *UploaderID:
+	case *ReactionCountsJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *BookmarkJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *StorageQuotaJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *DownloadCountJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *AudioURLJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *TagSuggestionsJsonType:
+		err = json.Unmarshal(bytes, &v)
+		if err != nil {
+			return err
+		}
+	case *domain.VideoAnalysis:
 		err = json.Unmarshal(bytes, &v)
 		if err != nil {
 			return err
@@ -71,3 +145,22 @@ func setToRedis(ctx context.Context, client *redis.Client, key string, expiratio
 
 	return client.Set(ctx, key, bytes, expiration).Err()
 }
+
+// patternに一致するキーをSCANで探して削除する
+// KEYSと違い、キーが多くてもRedisを長時間ブロックしない
+func deleteFromRedisByPattern(ctx context.Context, client *redis.Client, pattern string) error {
+	var keys []string
+	iter := client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	err := iter.Err()
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// allowed_regionsにはカンマ区切りで保存する
+const regionSeparator = ","
+
+// 動画を視聴できる国を設定する。regionsが空の場合は制限を外す
+func (i *Infrastructure) SetVideoRegionRestrictions(ctx context.Context, videoID string, regions []string, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "SetVideoRegionRestrictions")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.StringSlice("regions", regions), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	regions, err = domain.NormalizeRegions(regions)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return err
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionSetVideoRegions, requestingUserID, func(sqlc.Video) error {
+			_, err := q.UpdateVideoAllowedRegions(ctx, sqlc.UpdateVideoAllowedRegionsParams{
+				AllowedRegions: sql.NullString{String: strings.Join(regions, regionSeparator), Valid: len(regions) > 0},
+				ID:             videoID,
+			})
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+func parseAllowedRegions(s sql.NullString) []string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	return strings.Split(s.String, regionSeparator)
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画を視聴できる国の設定(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		rows     [][]driver.Value
+		wantArgs []driver.Value
+		wantErr  error
+	}{
+		{name: "小文字と重複はまとめる", regions: []string{"us", "JP", "jp"}, wantArgs: []driver.Value{"JP,US", "video_1"}},
+		{name: "空の場合は制限を外す", regions: nil, wantArgs: []driver.Value{nil, "video_1"}},
+		{name: "不正な国コード", regions: []string{"JPN"}, wantErr: domain.ErrInvalidInput},
+		{name: "動画がない", regions: []string{"JP"}, rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideoForUpdate": rows, "GetUndeletedVideo": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.SetVideoRegionRestrictions(context.Background(), "video_1", tt.regions, "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SetVideoRegionRestrictions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("execs = %v, want none", connector.execs)
+				}
+				return
+			}
+			if want := []string{"UpdateVideoAllowedRegions", "CreateAuditLog"}; !reflect.DeepEqual(connector.execNames, want) {
+				t.Fatalf("exec names = %v, want %v", connector.execNames, want)
+			}
+			if !reflect.DeepEqual(connector.execs[0], tt.wantArgs) {
+				t.Errorf("UpdateVideoAllowedRegions args = %v, want %v", connector.execs[0], tt.wantArgs)
+			}
+			if got := connector.execs[1][:4]; !reflect.DeepEqual(got, []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionSetVideoRegions, "user_1"}) {
+				t.Errorf("CreateAuditLog args = %v", got)
+			}
+		})
+	}
+}
+
+func Test_視聴できる国の読み込み(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[len(row)-9] = "JP,US"
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row}, "GetVideoTags": {}, "GetVideoDescriptions": {}, "GetSubtitleTracksByVideoID": {}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	video, err := i.GetVideoFromDB(context.Background(), "video_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.GetVideoFromDB() error = %v", err)
+	}
+	if want := []string{"JP", "US"}; !reflect.DeepEqual(video.AllowedRegions, want) {
+		t.Errorf("AllowedRegions = %v, want %v", video.AllowedRegions, want)
+	}
+}
@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const relatedVideosCacheTTL = 15 * time.Minute
+
+func relatedVideosKey(videoID string, limit int) string {
+	return relatedVideosKeyPrefix(videoID) + strconv.Itoa(limit)
+}
+
+// 件数ごとのキャッシュをまとめて消すときに使う
+func relatedVideosKeyPrefix(videoID string) string {
+	return "related" + domain.IDSeparator + videoID + domain.IDSeparator
+}
+
+// タグの重なりをJaccard係数(共通のタグ数 / どちらかに付いているタグ数)で評価し、似ている順に返す
+// 元の動画のタグの取得と、候補の絞り込み・スコア計算・並び替えをまとめたクエリの2回だけDBに問い合わせる
+func (i *Infrastructure) GetRelatedVideos(ctx context.Context, videoID string, limit int) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetRelatedVideos")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be greater than 0", domain.ErrInvalidInput)
+	}
+
+	key := relatedVideosKey(videoID, limit)
+	var videos []*domain.Video
+	hit, err := i.getFromCache(ctx, "related_videos", key, &videos)
+	if err != nil {
+		return nil, err
+	} else if hit {
+		return videos, nil
+	}
+
+	tags, err := i.db.Database.GetVideoTags(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return []*domain.Video{}, nil
+	}
+
+	tagIDs := make([]int32, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	rows, err := i.db.Database.GetRelatedVideos(ctx, sqlc.GetRelatedVideosParams{
+		TagIds:         tagIDs,
+		SourceTagCount: int64(len(tagIDs)),
+		VideoID:        videoID,
+		Limit:          int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	videos = make([]*domain.Video, 0, len(rows))
+	for _, row := range rows {
+		video := newVideoFromDB(row.Video)
+		if row.TagNames.Valid {
+			video.Tags = strings.Split(row.TagNames.String, "\n")
+		}
+		videos = append(videos, video)
+	}
+
+	err = setToRedis(ctx, i.redis, key, relatedVideosCacheTTL, videos)
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
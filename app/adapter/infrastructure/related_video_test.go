@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// GetRelatedVideosの1行分(videoの全カラム, similarity, tag_names)
+func relatedVideoRow(id string, similarity float64, tagNames string) []driver.Value {
+	now := time.Now()
+	return []driver.Value{
+		id, "https://example.com/" + id + ".m3u8", "https://example.com/" + id + ".webp", id, "", now, now,
+		false, false, false, "user_1", int64(0), false, int64(0), int64(0), int64(0), int64(0), nil, nil, int64(0), false, "ready", nil, nil, nil, int64(0), int64(0), nil, nil, nil, "general", nil, "", nil, nil, nil, nil, nil, int64(0), false,
+		similarity, tagNames,
+	}
+}
+
+func Test_関連動画の取得(t *testing.T) {
+	tests := []struct {
+		name        string
+		rowsByQuery map[string][][]driver.Value
+		want        [][]string
+	}{
+		{
+			name: "タグが重なる動画",
+			rowsByQuery: map[string][][]driver.Value{
+				"GetVideoTags": {{int64(1), "go"}, {int64(2), "grpc"}},
+				"GetRelatedVideos": {
+					relatedVideoRow("video_2", 1, "go\ngrpc"),
+					relatedVideoRow("video_3", 0.5, "go"),
+				},
+			},
+			want: [][]string{{"go", "grpc"}, {"go"}},
+		},
+		{
+			name: "タグがない動画",
+			rowsByQuery: map[string][][]driver.Value{
+				"GetVideoTags": {},
+			},
+			want: [][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: tt.rowsByQuery}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:    &db.DB{Database: sqlc.New(sqlDB)},
+				redis: client,
+			}
+
+			got, err := i.GetRelatedVideos(context.Background(), "video_1", 10)
+			if err != nil {
+				t.Fatalf("Infrastructure.GetRelatedVideos() error = %v", err)
+			}
+			if got == nil {
+				t.Fatal("Infrastructure.GetRelatedVideos() = nil, want empty slice")
+			}
+			gotTags := make([][]string, 0, len(got))
+			for _, video := range got {
+				gotTags = append(gotTags, video.Tags)
+			}
+			if !reflect.DeepEqual(gotTags, tt.want) {
+				t.Errorf("tags = %v, want %v", gotTags, tt.want)
+			}
+			// 問い合わせは2回まで
+			if connector.queries > 2 {
+				t.Errorf("GetRelatedVideos queried the database %d times, want at most 2", connector.queries)
+			}
+		})
+	}
+}
+
+func Test_関連動画のキャッシュ(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetVideoTags":     {{int64(1), "go"}},
+		"GetRelatedVideos": {relatedVideoRow("video_2", 1, "go")},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(sqlDB)},
+		redis: client,
+	}
+
+	for n := 0; n < 2; n++ {
+		got, err := i.GetRelatedVideos(context.Background(), "video_1", 10)
+		if err != nil {
+			t.Fatalf("Infrastructure.GetRelatedVideos() error = %v", err)
+		}
+		if len(got) != 1 || got[0].ID != "video_2" {
+			t.Errorf("Infrastructure.GetRelatedVideos() = %v, want [video_2]", got)
+		}
+	}
+	if connector.queries != 2 {
+		t.Errorf("GetRelatedVideos queried the database %d times, want 2", connector.queries)
+	}
+}
@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// 1回目のリトライまでの待ち時間。テストで短くできるようにしている
+var retryBaseDelay = 500 * time.Millisecond
+
+// fnがリトライできるエラーを返した場合に、最大maxAttempts回まで指数バックオフで実行する
+// 複数のサーバーが同時にリトライしないように、待ち時間は2倍ずつ増やした値の半分から全体の間でランダムにする
+func retryWithBackoff(ctx context.Context, maxAttempts int, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= maxAttempts || !isRetryableError(err) {
+			return err
+		}
+
+		delay := retryBaseDelay << (attempt - 1)
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		slog.DebugContext(ctx, "retrying after error", "attempt", attempt, "maxAttempts", maxAttempts, "delay", delay, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// ネットワークのエラーとS3の5xxと429はリトライする
+// 認証情報の誤りやサイズの超過などの4xxは何度実行しても失敗するためリトライしない
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		status := responseErr.HTTPStatusCode()
+		return status >= 500 || status == 429
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
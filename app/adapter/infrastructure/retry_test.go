@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func s3ResponseError(status int) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+			Err:      errors.New("s3 error"),
+		},
+	}
+}
+
+func Test_指数バックオフでのリトライ(t *testing.T) {
+	original := retryBaseDelay
+	t.Cleanup(func() { retryBaseDelay = original })
+	retryBaseDelay = time.Millisecond
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "成功", errs: []error{nil}, wantCalls: 1},
+		{name: "5xxはリトライする", errs: []error{s3ResponseError(503), s3ResponseError(500), nil}, wantCalls: 3},
+		{name: "429はリトライする", errs: []error{s3ResponseError(429), nil}, wantCalls: 2},
+		{name: "ネットワークのエラーはリトライする", errs: []error{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, nil}, wantCalls: 2},
+		{name: "4xxはリトライしない", errs: []error{s3ResponseError(403), nil}, wantCalls: 1, wantErr: true},
+		{name: "その他のエラーはリトライしない", errs: []error{errors.New("no such file"), nil}, wantCalls: 1, wantErr: true},
+		{name: "最大の回数で諦める", errs: []error{s3ResponseError(500), s3ResponseError(500), s3ResponseError(500), nil}, wantCalls: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryWithBackoff(context.Background(), 3, func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("retryWithBackoff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_キャンセルされたリトライ(t *testing.T) {
+	original := retryBaseDelay
+	t.Cleanup(func() { retryBaseDelay = original })
+	retryBaseDelay = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retryWithBackoff(ctx, 3, func() error {
+		calls++
+		cancel()
+		return s3ResponseError(500)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("retryWithBackoff() error = %v, want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
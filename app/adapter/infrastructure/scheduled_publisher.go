@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// checkIntervalごとに公開時刻を過ぎた予約動画を公開する
+func (i *Infrastructure) StartScheduledPublisher(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := i.publishScheduledVideos(ctx, time.Now())
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to publish scheduled videos", "error", err)
+			}
+		}
+	}
+}
+
+// nowまでに公開時刻を過ぎた動画を公開し、公開した動画のIDを返す
+// 公開したらpublish_atを消し、後から非公開にした動画を再び公開しないようにする
+func (i *Infrastructure) publishScheduledVideos(ctx context.Context, now time.Time) ([]string, error) {
+	videoIDs, err := i.db.Database.ListScheduledVideosToPublish(ctx, sql.NullTime{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]string, 0, len(videoIDs))
+	defer func() {
+		if len(published) > 0 {
+			i.invalidateVideoCache(ctx, published...)
+		}
+	}()
+	for _, videoID := range videoIDs {
+		err := i.updateVideoAsSystem(ctx, videoID, domain.AuditActionPublishScheduledVideo, func(q *sqlc.Queries) error {
+			result, err := q.PublishScheduledVideo(ctx, sqlc.PublishScheduledVideoParams{
+				UpdatedAt: now,
+				ID:        videoID,
+			})
+			if err != nil {
+				return err
+			}
+			return requireVideoUpdated(result)
+		})
+		// 他のサーバーが先に公開した場合や削除された場合は何もしない
+		if errors.Is(err, errVideoNotUpdated) || errors.Is(err, domain.ErrVideoNotFound) {
+			continue
+		}
+		if err != nil {
+			return published, err
+		}
+		published = append(published, videoID)
+		i.emitWebhookEvent(ctx, domain.WebhookEventVideoPublished, videoID, nil)
+	}
+	return published, nil
+}
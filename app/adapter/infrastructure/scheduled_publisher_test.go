@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_公開予約された動画の登録(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name          string
+		isPrivate     bool
+		publishAt     *time.Time
+		wantPrivate   bool
+		wantPublishAt driver.Value
+	}{
+		{name: "予約なし", isPrivate: false, publishAt: nil, wantPrivate: false, wantPublishAt: nil},
+		{name: "未来の時刻は非公開にする", isPrivate: false, publishAt: &future, wantPrivate: true, wantPublishAt: future},
+		{name: "過去の時刻はすぐに公開する", isPrivate: false, publishAt: &past, wantPrivate: false, wantPublishAt: nil},
+		{name: "非公開の動画", isPrivate: true, publishAt: nil, wantPrivate: true, wantPublishAt: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, storage: NewMemoryBackend()}
+
+			description := ""
+			got, err := i.InsertPendingVideo(context.Background(), "video_1", "", "title", &description, "user_1", nil, domain.RatingGeneral, tt.isPrivate, false, false, &domain.VideoMetadata{}, tt.publishAt)
+			if err != nil {
+				t.Fatalf("Infrastructure.InsertPendingVideo() error = %v", err)
+			}
+			if got.IsPrivate != tt.wantPrivate {
+				t.Errorf("Infrastructure.InsertPendingVideo() IsPrivate = %v, want %v", got.IsPrivate, tt.wantPrivate)
+			}
+
+			// CreateVideoの引数のis_privateとpublish_at
+			args := connector.execs[0]
+			if args[5] != tt.wantPrivate {
+				t.Errorf("is_private = %v, want %v", args[5], tt.wantPrivate)
+			}
+			if args[len(args)-3] != tt.wantPublishAt {
+				t.Errorf("publish_at = %v, want %v", args[len(args)-3], tt.wantPublishAt)
+			}
+		})
+	}
+}
+
+func Test_公開予約された動画の公開(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	connector := &rowConnector{
+		rowsByQuery: map[string][][]driver.Value{
+			"ListScheduledVideosToPublish": {{"video_1"}, {"video_2"}, {"video_3"}},
+			"GetVideoForUpdate":            {row[:len(row)-2]},
+		},
+		// video_2は他のサーバーが先に公開した
+		affectedByArgs: func(name string, args []driver.Value) (int64, bool) {
+			if name == "PublishScheduledVideo" && args[1] == "video_2" {
+				return 0, true
+			}
+			return 0, false
+		},
+	}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+	now := time.Now()
+	got, err := i.publishScheduledVideos(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Infrastructure.publishScheduledVideos() error = %v", err)
+	}
+	want := []string{"video_1", "video_3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Infrastructure.publishScheduledVideos() = %v, want %v", got, want)
+	}
+
+	// 公開しなかった動画は監査ログを残さずに取り消す
+	wantNames := []string{"PublishScheduledVideo", "CreateAuditLog", "PublishScheduledVideo", "PublishScheduledVideo", "CreateAuditLog"}
+	if !reflect.DeepEqual(connector.execNames, wantNames) {
+		t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+	}
+	if connector.commits != 2 || connector.rollbacks != 1 {
+		t.Errorf("commits, rollbacks = %d, %d, want 2, 1", connector.commits, connector.rollbacks)
+	}
+	for n, videoID := range []string{"video_1", "video_2", "video_3"} {
+		args := connector.execs[[]int{0, 2, 3}[n]]
+		if args[0] != now || args[1] != videoID {
+			t.Errorf("PublishScheduledVideo args = %v, want [%v %v]", args, now, videoID)
+		}
+	}
+	if action := connector.execs[1][2]; action != domain.AuditActionPublishScheduledVideo {
+		t.Errorf("audit action = %v, want %v", action, domain.AuditActionPublishScheduledVideo)
+	}
+	if actor := connector.execs[1][3]; actor != domain.AuditActorSystem {
+		t.Errorf("audit actor = %v, want %v", actor, domain.AuditActorSystem)
+	}
+}
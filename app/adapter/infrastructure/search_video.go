@@ -0,0 +1,195 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// タイトルと説明文に含まれるキーワードで公開動画を検索する
+// queryが空文字の場合は公開動画を全て返す
+func (i *Infrastructure) SearchVideosFromDB(ctx context.Context, query string, options domain.SearchOptions, page domain.Page) (_ []*domain.Video, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "SearchVideosFromDB")
+	defer func() { endSpan(span, err) }()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return i.GetVideosFromDBPaged(ctx, page)
+	}
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// SQLには文字列結合せずパラメータとして渡す
+	keyword := "%" + escapeLike(query) + "%"
+
+	var total int64
+	var dbVideos []sqlc.Video
+	if options.CaseInsensitive {
+		total, err = i.db.Database.CountSearchPublicAndNonAdultNonAdVideos(ctx, sqlc.CountSearchPublicAndNonAdultNonAdVideosParams{
+			TitleKeyword:       keyword,
+			DescriptionKeyword: sql.NullString{String: keyword, Valid: true},
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		dbVideos, err = i.db.Database.SearchPublicAndNonAdultNonAdVideos(ctx, sqlc.SearchPublicAndNonAdultNonAdVideosParams{
+			TitleKeyword:       keyword,
+			DescriptionKeyword: sql.NullString{String: keyword, Valid: true},
+			Limit:              int32(page.Limit),
+			Offset:             int32(page.Offset),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		total, err = i.db.Database.CountSearchPublicAndNonAdultNonAdVideosCaseSensitive(ctx, sqlc.CountSearchPublicAndNonAdultNonAdVideosCaseSensitiveParams{
+			TitleKeyword:       keyword,
+			DescriptionKeyword: keyword,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		dbVideos, err = i.db.Database.SearchPublicAndNonAdultNonAdVideosCaseSensitive(ctx, sqlc.SearchPublicAndNonAdultNonAdVideosCaseSensitiveParams{
+			TitleKeyword:       keyword,
+			DescriptionKeyword: keyword,
+			Limit:              int32(page.Limit),
+			Offset:             int32(page.Offset),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return newVideosWithTags(dbVideos, tags), total, nil
+}
+
+// matchAllがtrueの場合は全てのタグを持つ動画、falseの場合はいずれかのタグを持つ動画を返す
+func (i *Infrastructure) GetVideosByTagsFromDB(ctx context.Context, tags []string, matchAll bool, page domain.Page) (_ []*domain.Video, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosByTagsFromDB")
+	defer func() { endSpan(span, err) }()
+
+	// タグ指定なしで全件取得してしまわないようにする
+	if len(tags) == 0 {
+		return nil, 0, fmt.Errorf("%w: tags is empty", domain.ErrInvalidInput)
+	}
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 全一致の判定でタグの件数を使うため重複を除く
+	tagNames := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tagNames = append(tagNames, tag)
+	}
+
+	var total int64
+	var dbVideos []sqlc.Video
+	if matchAll {
+		total, err = i.db.Database.CountPublicAndNonAdultNonAdVideosByAllTags(ctx, sqlc.CountPublicAndNonAdultNonAdVideosByAllTagsParams{
+			TagNames: tagNames,
+			TagCount: int32(len(tagNames)),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		dbVideos, err = i.db.Database.GetPublicAndNonAdultNonAdVideosByAllTags(ctx, sqlc.GetPublicAndNonAdultNonAdVideosByAllTagsParams{
+			TagNames: tagNames,
+			TagCount: int32(len(tagNames)),
+			Limit:    int32(page.Limit),
+			Offset:   int32(page.Offset),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		total, err = i.db.Database.CountPublicAndNonAdultNonAdVideosByAnyTags(ctx, tagNames)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		dbVideos, err = i.db.Database.GetPublicAndNonAdultNonAdVideosByAnyTags(ctx, sqlc.GetPublicAndNonAdultNonAdVideosByAnyTagsParams{
+			TagNames: tagNames,
+			Limit:    int32(page.Limit),
+			Offset:   int32(page.Offset),
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	videoTags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return newVideosWithTags(dbVideos, videoTags), total, nil
+}
+
+// fromからtoまでの間にアップロードされた公開動画を取得する
+func (i *Infrastructure) GetVideosFromDBInRange(ctx context.Context, from, to time.Time, page domain.Page) (_ []*domain.Video, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosFromDBInRange")
+	defer func() { endSpan(span, err) }()
+
+	if !from.Before(to) {
+		return nil, 0, fmt.Errorf("%w: from %s must be before to %s", domain.ErrInvalidDateRange, from, to)
+	}
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := i.db.Database.CountPublicAndNonAdultNonAdVideosInRange(ctx, sqlc.CountPublicAndNonAdultNonAdVideosInRangeParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dbVideos, err := i.db.Database.GetPublicAndNonAdultNonAdVideosInRange(ctx, sqlc.GetPublicAndNonAdultNonAdVideosInRangeParams{
+		FromTime: from,
+		ToTime:   to,
+		Limit:    int32(page.Limit),
+		Offset:   int32(page.Offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return newVideosWithTags(dbVideos, tags), total, nil
+}
+
+// LIKEのワイルドカードとして解釈されないように % と _ をエスケープする
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
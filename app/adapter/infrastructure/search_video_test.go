@@ -0,0 +1,44 @@
+package infrastructure
+
+import "testing"
+
+func Test_LIKE検索のエスケープ(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "no wildcard",
+			s:    "golang",
+			want: "golang",
+		},
+		{
+			name: "percent",
+			s:    "100%",
+			want: `100\%`,
+		},
+		{
+			name: "underscore",
+			s:    "video_server",
+			want: `video\_server`,
+		},
+		{
+			name: "backslash",
+			s:    `C:\video`,
+			want: `C:\\video`,
+		},
+		{
+			name: "sql injection",
+			s:    "' OR 1=1 --",
+			want: "' OR 1=1 --",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLike(tt.s); got != tt.want {
+				t.Errorf("escapeLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 動画をsplitAt秒の位置で2つに分けてそれぞれ新しい動画として登録する
+// 元の動画はそのまま残す
+func (i *Infrastructure) SplitVideo(ctx context.Context, videoID string, splitAt int, uploaderID string) (_ [2]*domain.UploadVideoResponse, err error) {
+	ctx, span := infraSpan(ctx, "SplitVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	var responses [2]*domain.UploadVideoResponse
+
+	// permanent_banにした動画も論理削除されているため分割できない
+	dbVideo, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return responses, err
+	}
+	if dbVideo.UploaderID != uploaderID {
+		return responses, fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, uploaderID, videoID)
+	}
+	if splitAt <= 0 || int64(splitAt)*1000 >= dbVideo.DurationMs {
+		return responses, fmt.Errorf("%w: %d seconds is outside of the video", domain.ErrInvalidSplitPoint, splitAt)
+	}
+
+	dbTags, err := i.db.Database.GetVideoTags(ctx, videoID)
+	if err != nil {
+		return responses, err
+	}
+	tags := make([]string, 0, len(dbTags))
+	for _, tag := range dbTags {
+		tags = append(tags, tag.TagName)
+	}
+
+	// 前半は先頭からsplitAtまで、後半はsplitAtから最後まで
+	ranges := [2][]string{
+		{"-i", ffmpegInput, "-to", strconv.Itoa(splitAt)},
+		{"-ss", strconv.Itoa(splitAt), "-i", ffmpegInput},
+	}
+	for n, r := range ranges {
+		id := domain.NewVideoID()
+		tempMp4, err := localVideoPath(id)
+		if err != nil {
+			return responses, err
+		}
+		defer os.Remove(tempMp4)
+
+		args := append(r, "-c", "copy", tempMp4, "-y")
+		_, err = i.ffmpegFromObjectURL(ctx, "split", dbVideo.VideoUrl, args)
+		if err != nil {
+			return responses, err
+		}
+
+		description := dbVideo.Description.String
+		title := fmt.Sprintf("%s (%d/2)", dbVideo.Title, n+1)
+		responses[n], err = i.publishLocalVideo(ctx, id, title, &description, dbVideo.UploaderID, tags, domain.ContentRating(dbVideo.ContentRating), dbVideo.IsPrivate, dbVideo.IsExternalCutout, dbVideo.IsAd)
+		if err != nil {
+			return responses, err
+		}
+	}
+
+	return responses, nil
+}
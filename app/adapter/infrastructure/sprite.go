@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// シークバー用サムネイルを切り出す間隔(秒)のデフォルト値
+	defaultSpriteInterval = 10
+	// スプライト画像の1フレームの幅と1行あたりのフレーム数
+	spriteFrameWidth = 160
+	spriteColumns    = 10
+)
+
+// シーク時に表示するサムネイルのスプライト画像とWebVTTを生成してS3にアップロードする
+func (i *Infrastructure) GenerateThumbnailSprite(ctx context.Context, videoID string, interval int) (spriteURL, vttURL string, err error) {
+	ctx, span := infraSpan(ctx, "GenerateThumbnailSprite")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	if interval <= 0 {
+		interval = defaultSpriteInterval
+	}
+
+	dbVideo, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", "", err
+	}
+
+	framesDir := filepath.Join("temp", "sprite_"+videoID)
+	err = os.MkdirAll(framesDir, 0755)
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(framesDir)
+
+	filter := fmt.Sprintf("fps=1/%d,scale=%d:-1", interval, spriteFrameWidth)
+	_, err = i.ffmpegFromObjectURL(ctx, "sprite", dbVideo.VideoUrl, []string{"-i", ffmpegInput, "-vf", filter, filepath.Join(framesDir, "frame_%04d.png"), "-y"})
+	if err != nil {
+		return "", "", err
+	}
+
+	// ffmpegの出力は連番なのでGlobの結果をそのまま並び順として使える
+	framePaths, err := filepath.Glob(filepath.Join(framesDir, "frame_*.png"))
+	if err != nil {
+		return "", "", err
+	}
+	if len(framePaths) == 0 {
+		return "", "", fmt.Errorf("no frames extracted: %s", videoID)
+	}
+
+	spritePath := filepath.Join(framesDir, "sprite.png")
+	frameWidth, frameHeight, err := createSprite(framePaths, spritePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	spriteKey := "sprites/" + videoID + ".png"
+	err = i.uploadObject(ctx, spritePath, videoBucketName, spriteKey, "image/png")
+	if err != nil {
+		return "", "", err
+	}
+	spriteURL = i.objectURL(videoBucketName, spriteKey)
+
+	duration := time.Duration(dbVideo.DurationMs) * time.Millisecond
+	vtt := buildSpriteVTT(len(framePaths), time.Duration(interval)*time.Second, duration, frameWidth, frameHeight, spriteURL)
+	vttPath := filepath.Join(framesDir, "sprite.vtt")
+	err = os.WriteFile(vttPath, []byte(vtt), 0644)
+	if err != nil {
+		return "", "", err
+	}
+
+	vttKey := "sprites/" + videoID + ".vtt"
+	err = i.uploadObject(ctx, vttPath, videoBucketName, vttKey, "text/vtt")
+	if err != nil {
+		return "", "", err
+	}
+	vttURL = i.objectURL(videoBucketName, vttKey)
+
+	err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionGenerateSprite, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoThumbnailVTTURL(ctx, sqlc.UpdateVideoThumbnailVTTURLParams{
+			ThumbnailVttUrl: sql.NullString{
+				String: vttURL,
+				Valid:  true,
+			},
+			UpdatedAt: time.Now(),
+			ID:        videoID,
+		})
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return spriteURL, vttURL, nil
+}
+
+// フレーム画像を格子状に並べた1枚のPNGを作り、1フレームの大きさを返す
+func createSprite(framePaths []string, spritePath string) (int, int, error) {
+	frames := make([]image.Image, 0, len(framePaths))
+	for _, path := range framePaths {
+		frame, err := decodePNG(path)
+		if err != nil {
+			return 0, 0, err
+		}
+		frames = append(frames, frame)
+	}
+
+	bounds := frames[0].Bounds()
+	frameWidth, frameHeight := bounds.Dx(), bounds.Dy()
+	columns := min(spriteColumns, len(frames))
+	rows := (len(frames) + columns - 1) / columns
+
+	sprite := image.NewRGBA(image.Rect(0, 0, frameWidth*columns, frameHeight*rows))
+	for n, frame := range frames {
+		x := (n % columns) * frameWidth
+		y := (n / columns) * frameHeight
+		draw.Draw(sprite, image.Rect(x, y, x+frameWidth, y+frameHeight), frame, frame.Bounds().Min, draw.Src)
+	}
+
+	file, err := os.Create(spritePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	err = png.Encode(file, sprite)
+	if err != nil {
+		return 0, 0, err
+	}
+	return frameWidth, frameHeight, nil
+}
+
+func decodePNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PNG image: %w", err)
+	}
+	return img, nil
+}
+
+// 各フレームの表示区間とスプライト内の位置を#xywhで指定したWebVTTを作る
+func buildSpriteVTT(frameCount int, interval, duration time.Duration, frameWidth, frameHeight int, spriteURL string) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	columns := min(spriteColumns, frameCount)
+	for n := 0; n < frameCount; n++ {
+		start := time.Duration(n) * interval
+		end := start + interval
+		if duration > 0 && end > duration {
+			end = duration
+		}
+		x := (n % columns) * frameWidth
+		y := (n / columns) * frameHeight
+		fmt.Fprintf(&b, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n", formatVTTTimestamp(start), formatVTTTimestamp(end), spriteURL, x, y, frameWidth, frameHeight)
+	}
+	return b.String()
+}
+
+func formatVTTTimestamp(d time.Duration) string {
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, seconds, d/time.Millisecond)
+}
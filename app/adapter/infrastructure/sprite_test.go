@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_スプライトのVTT生成(t *testing.T) {
+	tests := []struct {
+		name       string
+		frameCount int
+		duration   time.Duration
+		want       string
+	}{
+		{
+			name:       "one frame",
+			frameCount: 1,
+			duration:   5 * time.Second,
+			want: "WEBVTT\n" +
+				"\n00:00:00.000 --> 00:00:05.000\nsprite.png#xywh=0,0,160,90\n",
+		},
+		{
+			name:       "wraps to next row",
+			frameCount: 12,
+			duration:   115 * time.Second,
+			want: "WEBVTT\n" +
+				"\n00:00:00.000 --> 00:00:10.000\nsprite.png#xywh=0,0,160,90\n" +
+				"\n00:00:10.000 --> 00:00:20.000\nsprite.png#xywh=160,0,160,90\n" +
+				"\n00:00:20.000 --> 00:00:30.000\nsprite.png#xywh=320,0,160,90\n" +
+				"\n00:00:30.000 --> 00:00:40.000\nsprite.png#xywh=480,0,160,90\n" +
+				"\n00:00:40.000 --> 00:00:50.000\nsprite.png#xywh=640,0,160,90\n" +
+				"\n00:00:50.000 --> 00:01:00.000\nsprite.png#xywh=800,0,160,90\n" +
+				"\n00:01:00.000 --> 00:01:10.000\nsprite.png#xywh=960,0,160,90\n" +
+				"\n00:01:10.000 --> 00:01:20.000\nsprite.png#xywh=1120,0,160,90\n" +
+				"\n00:01:20.000 --> 00:01:30.000\nsprite.png#xywh=1280,0,160,90\n" +
+				"\n00:01:30.000 --> 00:01:40.000\nsprite.png#xywh=1440,0,160,90\n" +
+				"\n00:01:40.000 --> 00:01:50.000\nsprite.png#xywh=0,90,160,90\n" +
+				"\n00:01:50.000 --> 00:01:55.000\nsprite.png#xywh=160,90,160,90\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSpriteVTT(tt.frameCount, 10*time.Second, tt.duration, 160, 90, "sprite.png")
+			if got != tt.want {
+				t.Errorf("buildSpriteVTT() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
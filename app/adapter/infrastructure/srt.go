@@ -0,0 +1,86 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// 00:00:01,000 --> 00:00:02,500 の形式。後ろに付く位置の指定はWebVTTでは使えないので捨てる
+var srtTimingPattern = regexp.MustCompile(`^(\d{2,}:[0-5]\d:[0-5]\d),(\d{3}) --> (\d{2,}:[0-5]\d:[0-5]\d),(\d{3})(?:\s.*)?$`)
+
+type srtState int
+
+const (
+	srtSequence srtState = iota
+	srtTiming
+	srtText
+)
+
+// SRTをWebVTTに変換する
+// 各ブロックは連番、タイミング、本文の行からなり空行で区切られる。連番はWebVTTでは不要なので除く
+// 不正な行がある場合はその行番号を付けてErrInvalidSubtitleFormatを返す
+func ConvertSRTToVTT(r io.Reader) (io.Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSubtitleBytes)
+
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n\n")
+	state := srtSequence
+	lineNumber := 0
+	cues := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+		}
+
+		switch state {
+		case srtSequence:
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if _, err := strconv.Atoi(strings.TrimSpace(line)); err != nil {
+				return nil, fmt.Errorf("%w: line %d: expected a sequence number: %q", domain.ErrInvalidSubtitleFormat, lineNumber, line)
+			}
+			state = srtTiming
+		case srtTiming:
+			m := srtTimingPattern.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil {
+				return nil, fmt.Errorf("%w: line %d: expected a timing line: %q", domain.ErrInvalidSubtitleFormat, lineNumber, line)
+			}
+			fmt.Fprintf(&b, "%s.%s --> %s.%s\n", m[1], m[2], m[3], m[4])
+			cues++
+			state = srtText
+		case srtText:
+			if strings.TrimSpace(line) == "" {
+				b.WriteString("\n")
+				state = srtSequence
+				continue
+			}
+			// WebVTTの本文には-->を書けない
+			b.WriteString(strings.ReplaceAll(line, "-->", "--&gt;"))
+			b.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidSubtitleFormat, lineNumber+1, err)
+	}
+	if state == srtTiming {
+		return nil, fmt.Errorf("%w: line %d: missing a timing line", domain.ErrInvalidSubtitleFormat, lineNumber)
+	}
+	if cues == 0 {
+		return nil, fmt.Errorf("%w: line %d: no subtitles", domain.ErrInvalidSubtitleFormat, lineNumber)
+	}
+	if state == srtText {
+		b.WriteString("\n")
+	}
+	return &b, nil
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_SRTからWebVTTへの変換(t *testing.T) {
+	tests := []struct {
+		name    string
+		srt     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "複数のブロック",
+			srt:  "1\n00:00:01,000 --> 00:00:02,500\nこんにちは\n\n2\n00:00:03,000 --> 00:00:04,000\n1行目\n2行目\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nこんにちは\n\n00:00:03.000 --> 00:00:04.000\n1行目\n2行目\n\n",
+		},
+		{
+			name: "CRLFとBOMと余分な空行",
+			srt:  "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000 X1:10 X2:20\r\nWorld\r\n\r\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n\n00:00:03.000 --> 00:00:04.000\nWorld\n\n",
+		},
+		{
+			name: "本文の-->はエスケープする",
+			srt:  "1\n00:00:01,000 --> 00:00:02,000\na --> b\n",
+			want: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\na --&gt; b\n\n",
+		},
+		{name: "連番がない", srt: "00:00:01,000 --> 00:00:02,000\nHello\n", wantErr: "line 1"},
+		{name: "タイミングが不正", srt: "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n2\n00:00:61,000 --> 00:01:02,000\n", wantErr: "line 6"},
+		{name: "タイミングがない", srt: "1\n", wantErr: "line 1"},
+		{name: "空", srt: "", wantErr: "line 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ConvertSRTToVTT(strings.NewReader(tt.srt))
+			if tt.wantErr != "" {
+				if !errors.Is(err, domain.ErrInvalidSubtitleFormat) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ConvertSRTToVTT() error = %v, want %v at %s", err, domain.ErrInvalidSubtitleFormat, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertSRTToVTT() error = %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ConvertSRTToVTT() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// 動画の保存先
+// テストではS3に接続せずにMemoryBackendを使う
+type StorageBackend interface {
+	Upload(ctx context.Context, key, contentType string, r io.Reader) error
+	// HLSのセグメントやサムネイルなど、PublicURLで誰でも取得できるオブジェクトをアップロードする
+	UploadPublic(ctx context.Context, key, contentType string, r io.Reader) error
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// キーがprefixで始まるオブジェクトを全て消す
+	DeletePrefix(ctx context.Context, prefix string) error
+	PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	PublicURL(key string) string
+}
+
+// 保存先のバケット
+const (
+	videoBucketName     = "video"
+	thumbnailBucketName = "thumbnail-image"
+	cutVideoBucketName  = "cut-video"
+)
+
+// splitObjectURLでURLから探すバケット
+var storageBuckets = []string{videoBucketName, thumbnailBucketName, watermarkBucketName, cutVideoBucketName}
+
+// NewInfrastructureに渡された動画のバケット以外の保存先を作る
+func newBucketStorages(storage StorageBackend) map[string]StorageBackend {
+	buckets := map[string]StorageBackend{videoBucketName: storage}
+	for _, name := range storageBuckets {
+		if _, ok := buckets[name]; !ok {
+			buckets[name] = NewStorageBackend(name)
+		}
+	}
+	return buckets
+}
+
+// テストで作成したInfrastructureはbucketsを持たないため、全てのバケットでstorageを使う
+func (i *Infrastructure) bucket(name string) StorageBackend {
+	storage, ok := i.buckets[name]
+	if !ok {
+		return i.storage
+	}
+	return storage
+}
+
+func (i *Infrastructure) objectURL(bucketName, key string) string {
+	return i.bucket(bucketName).PublicURL(key)
+}
+
+// objectURLで作ったURLをバケット名とキーに分ける
+func (i *Infrastructure) splitObjectURL(url string) (string, string, bool) {
+	for _, name := range storageBuckets {
+		storage := i.bucket(name)
+		if storage == nil {
+			continue
+		}
+		base := storage.PublicURL("")
+		key := strings.TrimPrefix(url, base)
+		if base != "" && key != url && key != "" {
+			return name, key, true
+		}
+	}
+	return "", "", false
+}
+
+// pathのファイルを公開してアップロードする
+func (i *Infrastructure) uploadObject(ctx context.Context, path, bucketName, key, contentType string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = i.bucket(bucketName).UploadPublic(ctx, key, contentType, file)
+	if err != nil {
+		return err
+	}
+	i.log().InfoContext(ctx, "uploaded file", "path", path, "bucket", bucketName, "key", key)
+	return nil
+}
+
+func (i *Infrastructure) downloadObject(ctx context.Context, url string) (io.ReadCloser, error) {
+	bucketName, key, ok := i.splitObjectURL(url)
+	if !ok {
+		return nil, fmt.Errorf("not a storage url: %s", url)
+	}
+	return i.bucket(bucketName).Download(ctx, key)
+}
+
+// 保存先のURLでなければ何もしない
+func (i *Infrastructure) deleteObject(ctx context.Context, url string) error {
+	bucketName, key, ok := i.splitObjectURL(url)
+	if !ok {
+		return nil
+	}
+	return i.bucket(bucketName).Delete(ctx, key)
+}
+
+// 1つのバケットにオブジェクトを保存する。UploadPublic以外は非公開にする
+type S3Backend struct {
+	bucket string
+}
+
+func NewS3Backend(bucket string) *S3Backend {
+	return &S3Backend{bucket: bucket}
+}
+
+// バケットにアクセスできるかをHEADで確認する
+func (b *S3Backend) Ping(ctx context.Context) error {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(b.bucket),
+	})
+	return err
+}
+
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+
+	cred := credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithCredentialsProvider(cred))
+	if err != nil {
+		return nil, err
+	}
+
+	// change object address style
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.UsePathStyle = true
+		options.BaseEndpoint = aws.String(os.Getenv("AWS_S3_ENDPOINT"))
+		options.Region = "ap-northeast-1"
+	})
+	return client, nil
+}
+
+// 大きいファイルはマルチパートでアップロードする
+func (b *S3Backend) Upload(ctx context.Context, key, contentType string, r io.Reader) error {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return err
+	}
+	return uploadLargeObjectToS3(ctx, client, b.bucket, key, r, contentType)
+}
+
+// バケットがなければ公開バケットとして作成する
+func (b *S3Backend) UploadPublic(ctx context.Context, key, contentType string, r io.Reader) error {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return err
+	}
+	err = b.createBucketIfNotExists(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(b.bucket),
+		Key:         aws.String(key),
+		Body:        r,
+		ACL:         types.ObjectCannedACLPublicRead,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+func (b *S3Backend) createBucketIfNotExists(ctx context.Context, client *s3.Client) error {
+	lbo, err := client.ListBuckets(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range lbo.Buckets {
+		if aws.ToString(bucket.Name) == b.bucket {
+			return nil
+		}
+	}
+
+	_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(b.bucket),
+		ACL:    types.BucketCannedACLPublicRead,
+	})
+	return err
+}
+
+func (b *S3Backend) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (b *S3Backend) DeletePrefix(ctx context.Context, prefix string) error {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+		}
+		_, err = client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(b.bucket),
+			Delete: &types.Delete{Objects: objects},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ブラウザで再生せずにダウンロードさせるためContent-Dispositionをattachmentにする
+func (b *S3Backend) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	client, err := newS3Client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	presignClient := s3.NewPresignClient(client)
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(b.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String(fmt.Sprintf(`attachment; filename="%s"`, path.Base(key))),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign object: %w", err)
+	}
+	return req.URL, nil
+}
+
+// AWS_S3_URL/<bucket>/<key>
+func (b *S3Backend) PublicURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", os.Getenv("AWS_S3_URL"), b.bucket, key)
+}
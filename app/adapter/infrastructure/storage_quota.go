@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 使用量の増減では消すが、他のサーバーが書き込んだ分はこの時間だけ古い値で判定することがある
+const storageQuotaCacheTTL = 30 * time.Second
+
+type StorageQuotaJsonType struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
+
+func storageQuotaKey(userID string) string {
+	return "storage_quota" + domain.IDSeparator + userID
+}
+
+// アップロードを受け付ける前に、pendingBytesを加えても上限を超えないか確認する
+func (i *Infrastructure) CheckUserStorageQuota(ctx context.Context, userID string, pendingBytes int64) (err error) {
+	ctx, span := infraSpan(ctx, "CheckUserStorageQuota")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	quota, err := i.getUserStorageQuota(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if quota.UsedBytes+pendingBytes > quota.QuotaBytes {
+		return fmt.Errorf("%w: %s uses %d of %d bytes and cannot add %d bytes", domain.ErrStorageQuotaExceeded, userID, quota.UsedBytes, quota.QuotaBytes, pendingBytes)
+	}
+	return nil
+}
+
+func (i *Infrastructure) getUserStorageQuota(ctx context.Context, userID string) (StorageQuotaJsonType, error) {
+	var quota StorageQuotaJsonType
+	hit, err := i.getFromCache(ctx, "storage_quota", storageQuotaKey(userID), &quota)
+	if err != nil {
+		return quota, err
+	} else if hit {
+		return quota, nil
+	}
+
+	row, err := i.db.Database.GetUserStorageQuota(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// まだアップロードしていないユーザーはデフォルトの上限を使う
+		quota = StorageQuotaJsonType{QuotaBytes: i.config.DefaultStorageQuotaBytes}
+	} else if err != nil {
+		return quota, err
+	} else {
+		quota = StorageQuotaJsonType{UsedBytes: row.UsedBytes, QuotaBytes: row.QuotaBytes}
+	}
+
+	err = setToRedis(ctx, i.redis, storageQuotaKey(userID), storageQuotaCacheTTL, &quota)
+	if err != nil {
+		return quota, err
+	}
+	return quota, nil
+}
+
+// 登録と同じトランザクションで使用量を増やしてから上限を確認し、超えた場合は登録ごと取り消す
+// 先に行を更新してロックするため、同時に登録しても上限を超えない
+func (i *Infrastructure) chargeUserStorage(ctx context.Context, q *sqlc.Queries, userID string, bytes int64) error {
+	_, err := q.AddUserStorageUsage(ctx, sqlc.AddUserStorageUsageParams{
+		UserID:     userID,
+		UsedBytes:  bytes,
+		QuotaBytes: i.config.DefaultStorageQuotaBytes,
+	})
+	if err != nil {
+		return err
+	}
+	row, err := q.GetUserStorageQuota(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if row.UsedBytes > row.QuotaBytes {
+		return fmt.Errorf("%w: %s uses %d of %d bytes and cannot add %d bytes", domain.ErrStorageQuotaExceeded, userID, row.UsedBytes-bytes, row.QuotaBytes, bytes)
+	}
+	return nil
+}
+
+// アップロードが成功した後に使用量を増やす
+// 行がない場合はデフォルトの上限で作成する
+func (i *Infrastructure) AddToUserStorageUsage(ctx context.Context, userID string, bytes int64) (err error) {
+	ctx, span := infraSpan(ctx, "AddToUserStorageUsage")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.db.Database.AddUserStorageUsage(ctx, sqlc.AddUserStorageUsageParams{
+		UserID:     userID,
+		UsedBytes:  bytes,
+		QuotaBytes: i.config.DefaultStorageQuotaBytes,
+	})
+	if err != nil {
+		return err
+	}
+
+	return i.redis.Del(ctx, storageQuotaKey(userID)).Err()
+}
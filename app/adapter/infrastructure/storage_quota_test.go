@@ -0,0 +1,134 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_ストレージの上限の確認(t *testing.T) {
+	tests := []struct {
+		name         string
+		rows         [][]driver.Value
+		pendingBytes int64
+		wantErr      error
+	}{
+		{name: "上限以内", rows: [][]driver.Value{{int64(600), int64(1000)}}, pendingBytes: 400},
+		{name: "上限を超える", rows: [][]driver.Value{{int64(600), int64(1000)}}, pendingBytes: 401, wantErr: domain.ErrStorageQuotaExceeded},
+		{name: "行がない場合はデフォルトの上限", rows: [][]driver.Value{}, pendingBytes: 500},
+		{name: "行がない場合にデフォルトの上限を超える", rows: [][]driver.Value{}, pendingBytes: 501, wantErr: domain.ErrStorageQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUserStorageQuota": tt.rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB)},
+				redis:  client,
+				config: InfrastructureConfig{DefaultStorageQuotaBytes: 500},
+			}
+
+			ctx := context.Background()
+			err := i.CheckUserStorageQuota(ctx, "user_1", tt.pendingBytes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.CheckUserStorageQuota() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// 2回目はキャッシュを使う
+			err = i.CheckUserStorageQuota(ctx, "user_1", tt.pendingBytes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.CheckUserStorageQuota() error = %v, want %v", err, tt.wantErr)
+			}
+			if connector.queries != 1 {
+				t.Errorf("queried the database %d times, want 1", connector.queries)
+			}
+		})
+	}
+}
+
+func Test_ストレージの使用量の追加(t *testing.T) {
+	mr, client := newTestRedis(t)
+	connector := &rowConnector{}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:     &db.DB{Database: sqlc.New(sqlDB)},
+		redis:  client,
+		config: InfrastructureConfig{DefaultStorageQuotaBytes: 500},
+	}
+
+	ctx := context.Background()
+	mr.Set(storageQuotaKey("user_1"), `{"used_bytes":0,"quota_bytes":500}`)
+
+	err := i.AddToUserStorageUsage(ctx, "user_1", 300)
+	if err != nil {
+		t.Fatalf("Infrastructure.AddToUserStorageUsage() error = %v", err)
+	}
+
+	want := []driver.Value{"user_1", int64(300), int64(500)}
+	if len(connector.execs) != 1 || !reflect.DeepEqual(connector.execs[0], want) {
+		t.Errorf("AddUserStorageUsage args = %v, want %v", connector.execs, want)
+	}
+	// 増やした使用量がすぐに判定に使われるようにキャッシュを消す
+	if mr.Exists(storageQuotaKey("user_1")) {
+		t.Error("storage quota cache should be deleted")
+	}
+}
+
+func Test_サーバー上で作った動画のストレージの使用量(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      [][]driver.Value
+		wantNames []string
+		wantErr   error
+	}{
+		// GetUserStorageQuotaは使用量を増やした後の値を返す
+		{name: "上限以内", rows: [][]driver.Value{{int64(1000), int64(1000)}}, wantNames: []string{"CreateVideo", "AddUserStorageUsage", "CreateAuditLog"}},
+		{name: "上限を超える", rows: [][]driver.Value{{int64(1001), int64(1000)}}, wantNames: []string{"CreateVideo", "AddUserStorageUsage"}, wantErr: domain.ErrStorageQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			mr.Set(storageQuotaKey("user_1"), `{"used_bytes":600,"quota_bytes":1000}`)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUserStorageQuota": tt.rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:  client,
+				config: InfrastructureConfig{DefaultStorageQuotaBytes: 500},
+			}
+
+			description := ""
+			_, err := i.InsertVideo(context.Background(), "video_1", "https://example.com/video_1.m3u8", "https://example.com/video_1.webp", "title", &description, "user_1", nil, domain.RatingGeneral, false, false, false, &domain.VideoMetadata{Size: 400}, "", nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.InsertVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantNames)
+			}
+			// 上限を超えた場合は動画の登録ごと取り消す
+			if tt.wantErr != nil {
+				if connector.rollbacks != 1 || connector.commits != 0 {
+					t.Errorf("commits, rollbacks = %d, %d, want 0, 1", connector.commits, connector.rollbacks)
+				}
+				return
+			}
+			if args := connector.execs[1]; args[0] != "user_1" || args[1] != int64(400) {
+				t.Errorf("AddUserStorageUsage args = %v, want [user_1 400 ...]", args)
+			}
+			if mr.Exists(storageQuotaKey("user_1")) {
+				t.Error("storage quota cache was not deleted")
+			}
+		})
+	}
+}
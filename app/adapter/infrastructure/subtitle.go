@@ -0,0 +1,222 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	subtitleBucketName = videoBucketName
+	// 字幕は文字だけなので、これより大きいファイルは字幕ではないとみなす
+	maxSubtitleBytes = 2 << 20
+)
+
+func subtitlePrefix(videoID string) string {
+	return "subtitles/" + videoID + "/"
+}
+
+func subtitleKey(videoID, language string) string {
+	return subtitlePrefix(videoID) + language + ".vtt"
+}
+
+// テストで保存先へのアップロードと削除を差し替えられるようにしている
+var (
+	uploadSubtitle       = (*Infrastructure).uploadVTT
+	deleteSubtitleObject = (*Infrastructure).deleteObject
+)
+
+// 字幕をWebVTTにして保存する。同じ言語の字幕が既にある場合は置き換える
+func (i *Infrastructure) UploadSubtitleTrack(ctx context.Context, videoID, language, format string, data io.Reader, requestingUserID string) (_ *domain.SubtitleTrack, err error) {
+	ctx, span := infraSpan(ctx, "UploadSubtitleTrack")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("language", language), attribute.String("format", format), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	err = domain.ValidateSubtitleFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	language, err = domain.NormalizeLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	_, err = i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(data, maxSubtitleBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxSubtitleBytes {
+		return nil, fmt.Errorf("%w: subtitle must be %d bytes or less", domain.ErrFileTooLarge, maxSubtitleBytes)
+	}
+	vtt, err := subtitleToVTT(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := uploadSubtitle(i, ctx, subtitleKey(videoID, language), vtt)
+	if err != nil {
+		return nil, err
+	}
+
+	var track *domain.SubtitleTrack
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		var before *domain.SubtitleTrack
+		existing, err := q.GetSubtitleTrackByLanguage(ctx, sqlc.GetSubtitleTrackByLanguageParams{
+			VideoID:  videoID,
+			Language: language,
+		})
+		if err == nil {
+			before = newSubtitleTrackFromDB(existing)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		_, err = q.UpsertSubtitleTrack(ctx, sqlc.UpsertSubtitleTrackParams{
+			ID:        domain.NewSubtitleTrackID(),
+			VideoID:   videoID,
+			Language:  language,
+			Format:    format,
+			Url:       url,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// 置き換えた場合は元のIDのままなので保存した行を取得し直す
+		saved, err := q.GetSubtitleTrackByLanguage(ctx, sqlc.GetSubtitleTrackByLanguageParams{
+			VideoID:  videoID,
+			Language: language,
+		})
+		if err != nil {
+			return err
+		}
+		track = newSubtitleTrackFromDB(saved)
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionUploadSubtitleTrack, requestingUserID, before, track)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return track, nil
+}
+
+func (i *Infrastructure) GetSubtitleTrack(ctx context.Context, trackID string) (_ *domain.SubtitleTrack, err error) {
+	ctx, span := infraSpan(ctx, "GetSubtitleTrack")
+	span.SetAttributes(attribute.String("trackID", trackID))
+	defer func() { endSpan(span, err) }()
+
+	track, err := i.db.Database.GetSubtitleTrack(ctx, trackID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrSubtitleTrackNotFound, trackID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSubtitleTrackFromDB(track), nil
+}
+
+func (i *Infrastructure) GetSubtitleTracksByVideoID(ctx context.Context, videoID string) (_ []*domain.SubtitleTrack, err error) {
+	ctx, span := infraSpan(ctx, "GetSubtitleTracksByVideoID")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	return i.getSubtitleTracks(ctx, videoID)
+}
+
+func (i *Infrastructure) getSubtitleTracks(ctx context.Context, videoID string) ([]*domain.SubtitleTrack, error) {
+	dbTracks, err := i.db.Database.GetSubtitleTracksByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	tracks := make([]*domain.SubtitleTrack, 0, len(dbTracks))
+	for _, track := range dbTracks {
+		tracks = append(tracks, newSubtitleTrackFromDB(track))
+	}
+	return tracks, nil
+}
+
+// 保存先のファイルを消してから行を削除する
+func (i *Infrastructure) DeleteSubtitleTrack(ctx context.Context, trackID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "DeleteSubtitleTrack")
+	span.SetAttributes(attribute.String("trackID", trackID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	track, err := i.db.Database.GetSubtitleTrack(ctx, trackID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", domain.ErrSubtitleTrackNotFound, trackID)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = deleteSubtitleObject(i, ctx, track.Url)
+	if err != nil {
+		return err
+	}
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		_, err := q.DeleteSubtitleTrack(ctx, trackID)
+		if err != nil {
+			return err
+		}
+		return recordVideoAuditLog(ctx, q, track.VideoID, domain.AuditActionDeleteSubtitleTrack, requestingUserID, newSubtitleTrackFromDB(track), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, track.VideoID)
+	return nil
+}
+
+func newSubtitleTrackFromDB(track sqlc.SubtitleTrack) *domain.SubtitleTrack {
+	return &domain.SubtitleTrack{
+		ID:       track.ID,
+		VideoID:  track.VideoID,
+		Language: track.Language,
+		Format:   track.Format,
+		URL:      track.Url,
+	}
+}
+
+var utf8BOM = []byte("\ufeff")
+
+func subtitleToVTT(format string, body []byte) ([]byte, error) {
+	body = bytes.TrimPrefix(body, utf8BOM)
+	switch format {
+	case domain.SubtitleFormatVTT:
+		if !bytes.HasPrefix(body, []byte("WEBVTT")) {
+			return nil, fmt.Errorf("%w: line 1: WebVTT must start with WEBVTT", domain.ErrInvalidSubtitleFormat)
+		}
+		return body, nil
+	case domain.SubtitleFormatSRT:
+		vtt, err := ConvertSRTToVTT(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(vtt)
+	default:
+		return nil, domain.ValidateSubtitleFormat(format)
+	}
+}
+
+func (i *Infrastructure) uploadVTT(ctx context.Context, key string, vtt []byte) (string, error) {
+	err := i.bucket(subtitleBucketName).UploadPublic(ctx, key, "text/vtt", bytes.NewReader(vtt))
+	if err != nil {
+		return "", err
+	}
+	return i.objectURL(subtitleBucketName, key), nil
+}
@@ -0,0 +1,146 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_字幕のアップロード(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		format   string
+		data     string
+		wantKey  string
+		wantVTT  string
+		wantErr  error
+	}{
+		{
+			name:     "WebVTT",
+			language: "ja",
+			format:   "vtt",
+			data:     "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nこんにちは\n",
+			wantKey:  "subtitles/video_1/ja.vtt",
+			wantVTT:  "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nこんにちは\n",
+		},
+		{
+			name:     "SRTはWebVTTに変換する",
+			language: "en-us",
+			format:   "srt",
+			data:     "1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\n",
+			wantKey:  "subtitles/video_1/en-US.vtt",
+			wantVTT:  "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\nHello\n\n",
+		},
+		{name: "対応していない形式", language: "ja", format: "ass", data: "", wantErr: domain.ErrUnsupportedSubtitleFormat},
+		{name: "WEBVTTで始まらない", language: "ja", format: "vtt", data: "hello", wantErr: domain.ErrInvalidSubtitleFormat},
+		{name: "不正なSRT", language: "ja", format: "srt", data: "1\nhello\n", wantErr: domain.ErrInvalidSubtitleFormat},
+		{name: "大きすぎる", language: "ja", format: "vtt", data: "WEBVTT\n" + strings.Repeat("a", maxSubtitleBytes), wantErr: domain.ErrFileTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploadedKey, uploaded string
+			original := uploadSubtitle
+			t.Cleanup(func() { uploadSubtitle = original })
+			uploadSubtitle = func(i *Infrastructure, ctx context.Context, key string, vtt []byte) (string, error) {
+				uploadedKey = key
+				uploaded = string(vtt)
+				return "https://s3.example.com/video/" + key, nil
+			}
+
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo":          {row[:len(row)-2]},
+				"GetSubtitleTrackByLanguage": {{"subtitle_1", "video_1", tt.language, tt.format, "https://s3.example.com/video/" + tt.wantKey, time.Now()}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			got, err := i.UploadSubtitleTrack(context.Background(), "video_1", tt.language, tt.format, strings.NewReader(tt.data), "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.UploadSubtitleTrack() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if uploadedKey != "" || len(connector.execs) != 0 {
+					t.Errorf("uploaded = %v, execs = %v, want none", uploadedKey, connector.execs)
+				}
+				return
+			}
+
+			if uploadedKey != tt.wantKey {
+				t.Errorf("uploaded key = %v, want %v", uploadedKey, tt.wantKey)
+			}
+			if uploaded != tt.wantVTT {
+				t.Errorf("uploaded = %q, want %q", uploaded, tt.wantVTT)
+			}
+			if !reflect.DeepEqual(connector.execNames, []string{"UpsertSubtitleTrack", "CreateAuditLog"}) {
+				t.Fatalf("exec names = %v, want [UpsertSubtitleTrack CreateAuditLog]", connector.execNames)
+			}
+			if got := connector.execs[1][:4]; !reflect.DeepEqual(got, []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionUploadSubtitleTrack, "user_1"}) {
+				t.Errorf("CreateAuditLog args = %v", got)
+			}
+			// 元の形式はそのまま保存する
+			if args := connector.execs[0]; args[3] != tt.format {
+				t.Errorf("format = %v, want %v", args[3], tt.format)
+			}
+			if got.ID != "subtitle_1" {
+				t.Errorf("Infrastructure.UploadSubtitleTrack() ID = %v, want subtitle_1", got.ID)
+			}
+		})
+	}
+}
+
+func Test_字幕の削除(t *testing.T) {
+	tests := []struct {
+		name          string
+		rows          [][]driver.Value
+		wantDeleted   string
+		wantExecNames []string
+		wantErr       error
+	}{
+		{
+			name:          "S3のファイルと行を削除する",
+			rows:          [][]driver.Value{{"subtitle_1", "video_1", "ja", "vtt", "https://s3.example.com/video/subtitles/video_1/ja.vtt", time.Now()}},
+			wantDeleted:   "https://s3.example.com/video/subtitles/video_1/ja.vtt",
+			wantExecNames: []string{"DeleteSubtitleTrack", "CreateAuditLog"},
+		},
+		{name: "字幕がない", rows: [][]driver.Value{}, wantErr: domain.ErrSubtitleTrackNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			original := deleteSubtitleObject
+			t.Cleanup(func() { deleteSubtitleObject = original })
+			deleteSubtitleObject = func(i *Infrastructure, ctx context.Context, url string) error {
+				deleted = url
+				return nil
+			}
+
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetSubtitleTrack": tt.rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.DeleteSubtitleTrack(context.Background(), "subtitle_1", "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.DeleteSubtitleTrack() error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const tagSuggestionsCacheTTL = 10 * time.Minute
+
+type TagSuggestionsJsonType struct {
+	Tags []string `json:"tags"`
+}
+
+// タイトルが長くてもキーの長さが変わらないようにハッシュにする
+func tagSuggestionsKey(title string, maxSuggestions int) string {
+	sum := sha256.Sum256([]byte(title))
+	return "tag_suggestions" + domain.IDSeparator + hex.EncodeToString(sum[:]) + domain.IDSeparator + strconv.Itoa(maxSuggestions)
+}
+
+// tagの行の名前を変え、そのタグが付いた全ての動画に反映する。変更した動画の数を返す
+// 新しい名前のタグがすでにある場合はMergeTagsでまとめる
+// 全ての動画に影響するため管理者のみ変更できる
+func (i *Infrastructure) RenameTag(ctx context.Context, oldName, newName, requestingUserID string) (_ int64, err error) {
+	ctx, span := infraSpan(ctx, "RenameTag")
+	span.SetAttributes(attribute.String("oldName", oldName), attribute.String("newName", newName), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	if !i.IsAdmin(requestingUserID) {
+		return 0, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	normalized := domain.NormalizeTags([]string{newName})
+	if len(normalized) == 0 {
+		return 0, fmt.Errorf("%w: new tag name is empty", domain.ErrInvalidInput)
+	}
+	err = domain.ValidateTags(normalized)
+	if err != nil {
+		return 0, err
+	}
+	newName = normalized[0]
+	// 正規化する前に登録されたタグも変更できるように、古い名前はそのまま使う
+	if newName == oldName {
+		return 0, fmt.Errorf("%w: %q is already the tag name", domain.ErrInvalidInput, oldName)
+	}
+
+	var count int64
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		tag, err := q.GetTagByNameForUpdate(ctx, oldName)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", domain.ErrTagNotFound, oldName)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 照合順序で大文字と小文字を区別しないため、大文字を小文字にするだけの場合は同じ行が見つかる
+		existing, err := q.GetTagByNameForUpdate(ctx, newName)
+		if err == nil && existing.ID != tag.ID {
+			return fmt.Errorf("%w: %s", domain.ErrTagAlreadyExists, newName)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		_, err = q.RenameTag(ctx, sqlc.RenameTagParams{
+			NewName: newName,
+			ID:      tag.ID,
+		})
+		if err != nil {
+			return err
+		}
+		count, err = q.CountVideosByTagID(ctx, tag.ID)
+		if err != nil {
+			return err
+		}
+		return recordTagAuditLog(ctx, q, tag.ID, domain.AuditActionRenameTag, requestingUserID, tagAuditValue{ID: tag.ID, Name: tag.TagName}, tagAuditValue{ID: tag.ID, Name: newName})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// どの動画に付いていたかは調べず、タグを含む一覧のキャッシュを消す
+	i.invalidateVideoCache(ctx)
+	return count, nil
+}
+
+// sourceTagが付いた動画をtargetTagに付け替え、sourceTagのtagの行を消す。付け替えた動画の数を返す
+// 検索のインデックスを更新できるように、統合した後にWebhookで通知する
+func (i *Infrastructure) MergeTags(ctx context.Context, sourceTag, targetTag, requestingUserID string) (_ int64, err error) {
+	ctx, span := infraSpan(ctx, "MergeTags")
+	span.SetAttributes(attribute.String("sourceTag", sourceTag), attribute.String("targetTag", targetTag), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	if !i.IsAdmin(requestingUserID) {
+		return 0, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+
+	var count int64
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		source, err := q.GetTagByNameForUpdate(ctx, sourceTag)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", domain.ErrTagNotFound, sourceTag)
+		}
+		if err != nil {
+			return err
+		}
+		target, err := q.GetTagByNameForUpdate(ctx, targetTag)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", domain.ErrTagNotFound, targetTag)
+		}
+		if err != nil {
+			return err
+		}
+		if source.ID == target.ID {
+			return fmt.Errorf("%w: cannot merge %q into itself", domain.ErrInvalidInput, sourceTag)
+		}
+
+		count, err = q.CountVideosByTagID(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteDuplicateVideoTags(ctx, sqlc.DeleteDuplicateVideoTagsParams{
+			SourceTagID: source.ID,
+			TargetTagID: target.ID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.MoveVideoTags(ctx, sqlc.MoveVideoTagsParams{
+			TargetTagID: target.ID,
+			SourceTagID: source.ID,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteTag(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+		// 統合元のタグは消えるため、統合先のタグを変更の後の値として残す
+		return recordTagAuditLog(ctx, q, source.ID, domain.AuditActionMergeTags, requestingUserID, tagAuditValue{ID: source.ID, Name: source.TagName}, tagAuditValue{ID: target.ID, Name: target.TagName})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	i.emitWebhookEvent(ctx, domain.WebhookEventTagMerged, "", map[string]interface{}{
+		"source_tag":  sourceTag,
+		"target_tag":  targetTag,
+		"video_count": count,
+	})
+	i.invalidateVideoCache(ctx)
+	return count, nil
+}
+
+// タイトルの単語と同じ名前のタグを、付いている動画が多い順にmaxSuggestions個まで返す
+// 一致するタグがない場合は空のスライスを返す
+func (i *Infrastructure) SuggestTagsForTitle(ctx context.Context, title string, maxSuggestions int) (_ []string, err error) {
+	ctx, span := infraSpan(ctx, "SuggestTagsForTitle")
+	span.SetAttributes(attribute.String("title", title), attribute.Int("maxSuggestions", maxSuggestions))
+	defer func() { endSpan(span, err) }()
+
+	if maxSuggestions <= 0 {
+		return nil, fmt.Errorf("%w: maxSuggestions must be positive: %d", domain.ErrInvalidInput, maxSuggestions)
+	}
+	keywords := domain.TitleKeywords(title)
+	if len(keywords) == 0 {
+		return []string{}, nil
+	}
+
+	cacheKey := tagSuggestionsKey(title, maxSuggestions)
+	var cached TagSuggestionsJsonType
+	hit, err := i.getFromCache(ctx, "tag_suggestions", cacheKey, &cached)
+	if err != nil {
+		return nil, err
+	}
+	if hit {
+		return cached.Tags, nil
+	}
+
+	rows, err := i.db.Database.GetTagsByNamesOrderByUsage(ctx, sqlc.GetTagsByNamesOrderByUsageParams{
+		TagNames: keywords,
+		Limit:    int32(maxSuggestions),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, row.TagName)
+	}
+
+	err = setToRedis(ctx, i.redis, cacheKey, tagSuggestionsCacheTTL, &TagSuggestionsJsonType{Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
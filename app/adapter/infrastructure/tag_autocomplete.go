@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// タグ名をメンバー、使われている動画の数をスコアにしたsorted set
+	tagAutocompleteKey = "tag-autocomplete"
+	// ZRANGEBYLEXはスコアが同じメンバーの間でしか辞書順にならないため、
+	// 前方一致の検索にはすべてのスコアを0にした別のsorted setを使う
+	tagAutocompleteLexKey = "tag-autocomplete" + domain.IDSeparator + "lex"
+	// 前方一致したタグのうち、使われている数で並べ替える候補の最大数
+	tagAutocompleteCandidates = 100
+)
+
+// prefixから始まるタグを、使われている動画が多い順にlimit個まで返す
+func (i *Infrastructure) AutocompleteTags(ctx context.Context, prefix string, limit int) (_ []string, err error) {
+	ctx, span := infraSpan(ctx, "AutocompleteTags")
+	span.SetAttributes(attribute.String("prefix", prefix), attribute.Int("limit", limit))
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive: %d", domain.ErrInvalidInput, limit)
+	}
+	// タグは正規化して保存しているため、入力中の文字列も同じように正規化する
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return i.redis.ZRevRange(ctx, tagAutocompleteKey, 0, int64(limit-1)).Result()
+	}
+
+	candidates, err := i.redis.ZRangeByLex(ctx, tagAutocompleteLexKey, &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: tagAutocompleteCandidates,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []string{}, nil
+	}
+	scores, err := i.redis.ZMScore(ctx, tagAutocompleteKey, candidates...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]float64, len(candidates))
+	for n, tag := range candidates {
+		usage[tag] = scores[n]
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return usage[candidates[a]] > usage[candidates[b]]
+	})
+	return candidates[:min(limit, len(candidates))], nil
+}
+
+// 動画に付けたタグを索引に追加し、使われている数を増やす
+func (i *Infrastructure) addTagsToAutocompleteIndex(ctx context.Context, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := i.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.ZAddNX(ctx, tagAutocompleteLexKey, redis.Z{Member: tag})
+			pipe.ZIncrBy(ctx, tagAutocompleteKey, 1, tag)
+		}
+		return nil
+	})
+	return err
+}
+
+// DBのタグと使われている数から索引を作り直す
+// 動画の削除やタグの変更では索引を更新しないため、起動時とCleanOrphanedTagsで揃える
+func (i *Infrastructure) RebuildTagAutocompleteIndex(ctx context.Context) (err error) {
+	ctx, span := infraSpan(ctx, "RebuildTagAutocompleteIndex")
+	defer func() { endSpan(span, err) }()
+
+	rows, err := i.db.Database.ListTagUsageCounts(ctx)
+	if err != nil {
+		return err
+	}
+	lex := make([]redis.Z, 0, len(rows))
+	usage := make([]redis.Z, 0, len(rows))
+	for _, row := range rows {
+		lex = append(lex, redis.Z{Member: row.TagName})
+		usage = append(usage, redis.Z{Score: float64(row.UsageCount), Member: row.TagName})
+	}
+
+	// 作り直している間も検索できるように、1つのトランザクションで入れ替える
+	_, err = i.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tagAutocompleteKey, tagAutocompleteLexKey)
+		if len(rows) > 0 {
+			pipe.ZAdd(ctx, tagAutocompleteLexKey, lex...)
+			pipe.ZAdd(ctx, tagAutocompleteKey, usage...)
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_タグの補完(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"ListTagUsageCounts": {{"go", int64(5)}, {"golang", int64(8)}, {"gopher", int64(1)}, {"grpc", int64(3)}, {"rust", int64(2)}},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}, redis: client}
+
+	ctx := context.Background()
+	err := i.RebuildTagAutocompleteIndex(ctx)
+	if err != nil {
+		t.Fatalf("Infrastructure.RebuildTagAutocompleteIndex() error = %v", err)
+	}
+	// 新しく登録した動画のタグは使われている数に加える
+	err = i.addTagsToAutocompleteIndex(ctx, []string{"gopher", "gopher", "gopher", "gorm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		limit   int
+		want    []string
+		wantErr error
+	}{
+		{name: "使われている順", prefix: "go", limit: 10, want: []string{"golang", "go", "gopher", "gorm"}},
+		{name: "上限の数まで", prefix: "go", limit: 2, want: []string{"golang", "go"}},
+		{name: "大文字と空白", prefix: " GR ", limit: 10, want: []string{"grpc"}},
+		{name: "一致しない", prefix: "zig", limit: 10, want: []string{}},
+		{name: "空の場合はすべてのタグから", prefix: "", limit: 3, want: []string{"golang", "go", "gopher"}},
+		{name: "上限が0", prefix: "go", limit: 0, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.AutocompleteTags(ctx, tt.prefix, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.AutocompleteTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Infrastructure.AutocompleteTags() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
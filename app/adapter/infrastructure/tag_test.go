@@ -0,0 +1,179 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_タグの名前の変更(t *testing.T) {
+	tests := []struct {
+		name             string
+		newName          string
+		requestingUserID string
+		tags             [][]driver.Value
+		wantErr          error
+		wantCount        int64
+		wantExecs        []string
+	}{
+		{name: "変更できる", newName: "  Golang ", requestingUserID: "admin_1", wantCount: 3, wantExecs: []string{"RenameTag", "CreateAuditLog"}},
+		{name: "管理者ではない", newName: "golang", requestingUserID: "user_1", wantErr: domain.ErrPermissionDenied},
+		{name: "新しい名前が空", newName: "  ", requestingUserID: "admin_1", wantErr: domain.ErrInvalidInput},
+		{name: "新しい名前が長すぎる", newName: strings.Repeat("a", domain.MaxTagLength+1), requestingUserID: "admin_1", wantErr: domain.ErrTagTooLong},
+		{name: "名前が変わらない", newName: "Go", requestingUserID: "admin_1", wantErr: domain.ErrInvalidInput},
+		{name: "タグがない", newName: "golang", requestingUserID: "admin_1", tags: [][]driver.Value{}, wantErr: domain.ErrTagNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := tt.tags
+			if tags == nil {
+				tags = [][]driver.Value{{int64(7), "go"}}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetTagByNameForUpdate": tags,
+				"CountVideosByTagID":    {{int64(3)}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			got, err := i.RenameTag(context.Background(), "go", tt.newName, tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.RenameTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantCount {
+				t.Errorf("Infrastructure.RenameTag() = %d, want %d", got, tt.wantCount)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if args := connector.execs[0]; args[0] != "golang" || args[1] != int64(7) {
+				t.Errorf("RenameTag args = %v", args)
+			}
+			want := []driver.Value{domain.AuditEntityTag, "7", domain.AuditActionRenameTag, "admin_1", `{"ID":7,"Name":"go"}`, `{"ID":7,"Name":"golang"}`}
+			if got := connector.execs[1][:6]; !reflect.DeepEqual(got, want) {
+				t.Errorf("CreateAuditLog args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_タグの統合(t *testing.T) {
+	tests := []struct {
+		name             string
+		targetTag        string
+		requestingUserID string
+		wantErr          error
+		wantExecs        []string
+	}{
+		{name: "統合できる", targetTag: "golang", requestingUserID: "admin_1", wantExecs: []string{"DeleteDuplicateVideoTags", "MoveVideoTags", "DeleteTag", "CreateAuditLog"}},
+		{name: "管理者ではない", targetTag: "golang", requestingUserID: "user_1", wantErr: domain.ErrPermissionDenied},
+		{name: "統合先のタグがない", targetTag: "rust", requestingUserID: "admin_1", wantErr: domain.ErrTagNotFound},
+		{name: "同じタグ", targetTag: "go-lang", requestingUserID: "admin_1", wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetTagByNameForUpdate go-lang": {{int64(3), "go-lang"}},
+				"GetTagByNameForUpdate golang":  {{int64(7), "golang"}},
+				"GetTagByNameForUpdate":         {},
+				"CountVideosByTagID":            {{int64(2)}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:            &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config:        InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+				webhookEvents: make(chan domain.WebhookEvent, 1),
+			}
+
+			got, err := i.MergeTags(context.Background(), "go-lang", tt.targetTag, tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.MergeTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr != nil {
+				if connector.commits != 0 || len(i.webhookEvents) != 0 {
+					t.Errorf("commits = %d, events = %d, want 0", connector.commits, len(i.webhookEvents))
+				}
+				return
+			}
+			if got != 2 {
+				t.Errorf("Infrastructure.MergeTags() = %d, want 2", got)
+			}
+			wantArgs := [][]driver.Value{{int64(3), int64(7)}, {int64(7), int64(3)}, {int64(3)}}
+			if !reflect.DeepEqual(connector.execs[:3], wantArgs) {
+				t.Errorf("exec args = %v, want %v", connector.execs[:3], wantArgs)
+			}
+			wantAudit := []driver.Value{domain.AuditEntityTag, "3", domain.AuditActionMergeTags, "admin_1", `{"ID":3,"Name":"go-lang"}`, `{"ID":7,"Name":"golang"}`}
+			if got := connector.execs[3][:6]; !reflect.DeepEqual(got, wantAudit) {
+				t.Errorf("CreateAuditLog args = %v, want %v", got, wantAudit)
+			}
+			event := <-i.webhookEvents
+			if event.Type != domain.WebhookEventTagMerged || event.VideoID != "" {
+				t.Errorf("webhook event = %+v", event)
+			}
+		})
+	}
+}
+
+func Test_タイトルからのタグの提案(t *testing.T) {
+	tests := []struct {
+		name           string
+		title          string
+		maxSuggestions int
+		rows           [][]driver.Value
+		want           []string
+		wantErr        error
+		wantQueries    int
+	}{
+		{name: "使われている順に返す", title: "Go and Docker tutorial", maxSuggestions: 2, rows: [][]driver.Value{{"docker", int64(5)}, {"go", int64(3)}}, want: []string{"docker", "go"}, wantQueries: 1},
+		{name: "一致するタグがない", title: "Go and Docker tutorial", maxSuggestions: 2, rows: [][]driver.Value{}, want: []string{}, wantQueries: 1},
+		{name: "ストップワードだけのタイトル", title: "The and of", maxSuggestions: 2, want: []string{}},
+		{name: "提案する数が0", title: "Go", maxSuggestions: 0, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetTagsByNamesOrderByUsage": tt.rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}, redis: client}
+
+			// 2回目はキャッシュから返す
+			for n := 0; n < 2; n++ {
+				got, err := i.SuggestTagsForTitle(context.Background(), tt.title, tt.maxSuggestions)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Infrastructure.SuggestTagsForTitle() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Infrastructure.SuggestTagsForTitle() = %#v, want %#v", got, tt.want)
+				}
+			}
+			if connector.queries != tt.wantQueries {
+				t.Errorf("queries = %d, want %d", connector.queries, tt.wantQueries)
+			}
+			if tt.wantQueries > 0 {
+				if ttl := mr.TTL(tagSuggestionsKey(tt.title, tt.maxSuggestions)); ttl != tagSuggestionsCacheTTL {
+					t.Errorf("cache ttl = %v, want %v", ttl, tagSuggestionsCacheTTL)
+				}
+			}
+		})
+	}
+}
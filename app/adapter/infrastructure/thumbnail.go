@@ -0,0 +1,54 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// サムネイルが指定されなかった場合に切り出す秒数
+const defaultThumbnailSecond = 1
+
+// 動画のatSecond秒目のフレームをサムネイルとしてアップロードする
+func (i *Infrastructure) GenerateThumbnail(ctx context.Context, videoURL string, atSecond int) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GenerateThumbnail")
+	defer func() { endSpan(span, err) }()
+
+	return i.generateThumbnail(ctx, videoURL, "-ss", strconv.Itoa(atSecond), "-i", ffmpegInput, "-vframes", "1")
+}
+
+// ffmpegのthumbnailフィルタで300フレームごとに代表的なフレームを選んでサムネイルにする
+// 冒頭の暗転などを避けたい場合に使う
+func (i *Infrastructure) GenerateBestThumbnail(ctx context.Context, videoURL string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GenerateBestThumbnail")
+	defer func() { endSpan(span, err) }()
+
+	return i.generateThumbnail(ctx, videoURL, "-i", ffmpegInput, "-vf", "thumbnail=300", "-frames:v", "1")
+}
+
+func (i *Infrastructure) generateThumbnail(ctx context.Context, videoURL string, args ...string) (string, error) {
+	tempDir := "temp"
+	err := os.MkdirAll(tempDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	key := "generated_" + domain.NewUUID() + ".jpg"
+	imagePath := filepath.Join(tempDir, key)
+	defer os.Remove(imagePath)
+
+	args = append(args, "-f", "image2", imagePath, "-y")
+	_, err = i.ffmpegFromObjectURL(ctx, "thumbnail", videoURL, args)
+	if err != nil {
+		return "", err
+	}
+
+	err = i.uploadObject(ctx, imagePath, thumbnailBucketName, key, "image/jpeg")
+	if err != nil {
+		return "", err
+	}
+	return i.objectURL(thumbnailBucketName, key), nil
+}
@@ -0,0 +1,159 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// サムネイルのA/Bテストの候補を追加する
+// 同時に追加しても候補の数の上限を超えないように、動画の行をロックしてから数える
+func (i *Infrastructure) CreateThumbnailVariant(ctx context.Context, videoID, thumbnailURL string, weight float64, requestingUserID string) (_ *domain.ThumbnailVariant, err error) {
+	ctx, span := infraSpan(ctx, "CreateThumbnailVariant")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.Float64("weight", weight), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	variant := &domain.ThumbnailVariant{
+		ID:               domain.NewThumbnailVariantID(),
+		VideoID:          videoID,
+		ThumbnailURL:     thumbnailURL,
+		AssignmentWeight: weight,
+	}
+	err = variant.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		video, err := q.GetVideoForUpdate(ctx, videoID)
+		if errors.Is(err, sql.ErrNoRows) || err == nil && video.IsDeleted {
+			return fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+		}
+		if err != nil {
+			return err
+		}
+		variants, err := q.GetThumbnailVariantsByVideoID(ctx, videoID)
+		if err != nil {
+			return err
+		}
+		if len(variants) >= domain.MaxThumbnailVariantsPerVideo {
+			return fmt.Errorf("%w: video %s already has %d thumbnail variants", domain.ErrInvalidInput, videoID, len(variants))
+		}
+
+		_, err = q.CreateThumbnailVariant(ctx, sqlc.CreateThumbnailVariantParams{
+			ID:               variant.ID,
+			VideoID:          videoID,
+			ThumbnailUrl:     thumbnailURL,
+			AssignmentWeight: weight,
+			CreatedAt:        time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionCreateThumbnailVariant, requestingUserID, nil, variant)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// 同じユーザーには同じ候補を表示する。表示回数とクリック数は返した候補のIDで記録する
+// 候補がない場合はIDが空で動画のサムネイルを持つ候補を返す
+// ログインしていないユーザーは毎回重みに従って選び直す
+func (i *Infrastructure) SelectThumbnailForUser(ctx context.Context, videoID, userID string) (_ *domain.ThumbnailVariant, err error) {
+	ctx, span := infraSpan(ctx, "SelectThumbnailForUser")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	dbVariants, err := i.db.Database.GetThumbnailVariantsByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]domain.ThumbnailVariant, 0, len(dbVariants))
+	for _, v := range dbVariants {
+		variants = append(variants, *newThumbnailVariantFromDB(v))
+	}
+
+	// 動画ごとに振り分けが偏らないように動画IDも含める
+	key := videoID + domain.IDSeparator + userID
+	if userID == "" {
+		key = domain.NewUUID()
+	}
+	variant, ok := domain.SelectThumbnailVariant(variants, key)
+	if !ok {
+		return &domain.ThumbnailVariant{VideoID: videoID, ThumbnailURL: video.ThumbnailImageUrl}, nil
+	}
+	return &variant, nil
+}
+
+func (i *Infrastructure) RecordThumbnailImpression(ctx context.Context, variantID string) (err error) {
+	ctx, span := infraSpan(ctx, "RecordThumbnailImpression")
+	span.SetAttributes(attribute.String("variantID", variantID))
+	defer func() { endSpan(span, err) }()
+
+	return i.incrementThumbnailVariant(ctx, variantID, i.db.Database.IncrementThumbnailVariantImpressions)
+}
+
+func (i *Infrastructure) RecordThumbnailClick(ctx context.Context, variantID string) (err error) {
+	ctx, span := infraSpan(ctx, "RecordThumbnailClick")
+	span.SetAttributes(attribute.String("variantID", variantID))
+	defer func() { endSpan(span, err) }()
+
+	return i.incrementThumbnailVariant(ctx, variantID, i.db.Database.IncrementThumbnailVariantClicks)
+}
+
+func (i *Infrastructure) incrementThumbnailVariant(ctx context.Context, variantID string, increment func(context.Context, string) (sql.Result, error)) error {
+	result, err := increment(ctx, variantID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: thumbnail variant %s not found", domain.ErrInvalidInput, variantID)
+	}
+	return nil
+}
+
+// 候補ごとの表示回数とクリック数を追加した順に返す
+func (i *Infrastructure) GetThumbnailVariantStats(ctx context.Context, videoID string) (_ []domain.ThumbnailStats, err error) {
+	ctx, span := infraSpan(ctx, "GetThumbnailVariantStats")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	variants, err := i.db.Database.GetThumbnailVariantsByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]domain.ThumbnailStats, 0, len(variants))
+	for _, v := range variants {
+		stats = append(stats, domain.ThumbnailStats{
+			VariantID:    v.ID,
+			ThumbnailURL: v.ThumbnailUrl,
+			Impressions:  v.Impressions,
+			Clicks:       v.Clicks,
+		})
+	}
+	return stats, nil
+}
+
+func newThumbnailVariantFromDB(v sqlc.ThumbnailVariant) *domain.ThumbnailVariant {
+	return &domain.ThumbnailVariant{
+		ID:               v.ID,
+		VideoID:          v.VideoID,
+		ThumbnailURL:     v.ThumbnailUrl,
+		AssignmentWeight: v.AssignmentWeight,
+	}
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func thumbnailVariantRow(id, url string, weight float64, impressions, clicks int64) []driver.Value {
+	return []driver.Value{id, "video_1", url, weight, impressions, clicks, time.Now()}
+}
+
+func Test_サムネイルの候補の追加(t *testing.T) {
+	full := make([][]driver.Value, 0, domain.MaxThumbnailVariantsPerVideo)
+	for n := 0; n < domain.MaxThumbnailVariantsPerVideo; n++ {
+		full = append(full, thumbnailVariantRow("thumbnail_1", "https://example.com/a.webp", 1, 0, 0))
+	}
+	tests := []struct {
+		name      string
+		url       string
+		weight    float64
+		variants  [][]driver.Value
+		wantErr   error
+		wantExecs []string
+	}{
+		{name: "追加できる", url: "https://example.com/b.webp", weight: 0.5, variants: [][]driver.Value{}, wantExecs: []string{"CreateThumbnailVariant", "CreateAuditLog"}},
+		{name: "重みが0", url: "https://example.com/b.webp", weight: 0, variants: [][]driver.Value{}, wantErr: domain.ErrInvalidInput},
+		{name: "URLが不正", url: "b.webp", weight: 1, variants: [][]driver.Value{}, wantErr: domain.ErrInvalidInput},
+		{name: "候補の数の上限", url: "https://example.com/b.webp", weight: 1, variants: full, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetVideoForUpdate":             {row[:len(row)-2]},
+				"GetThumbnailVariantsByVideoID": tt.variants,
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			got, err := i.CreateThumbnailVariant(context.Background(), "video_1", tt.url, tt.weight, "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.CreateThumbnailVariant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr == nil && (got.VideoID != "video_1" || got.ThumbnailURL != tt.url || got.AssignmentWeight != tt.weight) {
+				t.Errorf("Infrastructure.CreateThumbnailVariant() = %+v", got)
+			}
+		})
+	}
+}
+
+func Test_ユーザーに表示するサムネイルの選択(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	variants := [][]driver.Value{
+		thumbnailVariantRow("thumbnail_1", "https://example.com/a.webp", 1, 0, 0),
+		thumbnailVariantRow("thumbnail_2", "https://example.com/b.webp", 1, 0, 0),
+	}
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetUndeletedVideo":             {row[:len(row)-2]},
+		"GetThumbnailVariantsByVideoID": variants,
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	ctx := context.Background()
+	selected := map[string]bool{}
+	for _, userID := range []string{"user_1", "user_2", "user_3", "user_4", "user_5", "user_6", "user_7", "user_8"} {
+		first, err := i.SelectThumbnailForUser(ctx, "video_1", userID)
+		if err != nil {
+			t.Fatalf("Infrastructure.SelectThumbnailForUser() error = %v", err)
+		}
+		// 同じユーザーには同じサムネイルを表示する
+		second, err := i.SelectThumbnailForUser(ctx, "video_1", userID)
+		if err != nil {
+			t.Fatalf("Infrastructure.SelectThumbnailForUser() error = %v", err)
+		}
+		if *first != *second {
+			t.Errorf("Infrastructure.SelectThumbnailForUser(%s) = %+v, then %+v", userID, first, second)
+		}
+		selected[first.ID+" "+first.ThumbnailURL] = true
+	}
+	want := map[string]bool{"thumbnail_1 https://example.com/a.webp": true, "thumbnail_2 https://example.com/b.webp": true}
+	if !reflect.DeepEqual(selected, want) {
+		t.Errorf("selected = %v, want %v", selected, want)
+	}
+
+	// 候補がない場合は動画のサムネイル
+	connector.rowsByQuery["GetThumbnailVariantsByVideoID"] = [][]driver.Value{}
+	got, err := i.SelectThumbnailForUser(ctx, "video_1", "user_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.SelectThumbnailForUser() error = %v", err)
+	}
+	if got.ID != "" || got.ThumbnailURL != "https://example.com/video_1.webp" {
+		t.Errorf("Infrastructure.SelectThumbnailForUser() = %+v, want https://example.com/video_1.webp without ID", got)
+	}
+}
+
+func Test_サムネイルの候補ごとの集計(t *testing.T) {
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetThumbnailVariantsByVideoID": {
+			thumbnailVariantRow("thumbnail_1", "https://example.com/a.webp", 1, 200, 10),
+			thumbnailVariantRow("thumbnail_2", "https://example.com/b.webp", 1, 100, 8),
+		},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	ctx := context.Background()
+	err := i.RecordThumbnailImpression(ctx, "thumbnail_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.RecordThumbnailImpression() error = %v", err)
+	}
+	err = i.RecordThumbnailClick(ctx, "thumbnail_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.RecordThumbnailClick() error = %v", err)
+	}
+	wantExecs := []string{"IncrementThumbnailVariantImpressions", "IncrementThumbnailVariantClicks"}
+	if !reflect.DeepEqual(connector.execNames, wantExecs) {
+		t.Errorf("execs = %v, want %v", connector.execNames, wantExecs)
+	}
+
+	got, err := i.GetThumbnailVariantStats(ctx, "video_1")
+	if err != nil {
+		t.Fatalf("Infrastructure.GetThumbnailVariantStats() error = %v", err)
+	}
+	want := []domain.ThumbnailStats{
+		{VariantID: "thumbnail_1", ThumbnailURL: "https://example.com/a.webp", Impressions: 200, Clicks: 10},
+		{VariantID: "thumbnail_2", ThumbnailURL: "https://example.com/b.webp", Impressions: 100, Clicks: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Infrastructure.GetThumbnailVariantStats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package infrastructure
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/yuorei/video-server/app/adapter/infrastructure"
+
+// Infrastructureのメソッドの子spanを始める
+// TracerProviderを設定していない場合は何も記録しない
+func infraSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "Infrastructure."+name)
+}
+
+// エラーで終わった場合はspanに記録してから終える
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
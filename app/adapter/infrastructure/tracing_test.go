@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Infrastructureのspan(t *testing.T) {
+	original := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(original) })
+
+	tests := []struct {
+		name       string
+		rows       [][]driver.Value
+		wantStatus codes.Code
+	}{
+		{name: "成功", wantStatus: codes.Unset},
+		{name: "エラーを記録する", rows: [][]driver.Value{}, wantStatus: codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": rows, "GetVideoTags": {}, "GetVideoDescriptions": {}, "GetSubtitleTracksByVideoID": {}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			_, _ = i.GetVideoFromDB(context.Background(), "video_1")
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != "Infrastructure.GetVideoFromDB" {
+				t.Errorf("span name = %s, want Infrastructure.GetVideoFromDB", span.Name())
+			}
+			wantAttr := attribute.String("videoID", "video_1")
+			found := false
+			for _, attr := range span.Attributes() {
+				if attr == wantAttr {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("span attributes = %v, want %v", span.Attributes(), wantAttr)
+			}
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package infrastructure
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	// 再エンコードせずにストリームをそのままコピーする
+	TranscoderBackendCopy = ""
+	// libx264によるソフトウェアエンコード
+	TranscoderBackendSoftware     = "software"
+	TranscoderBackendNVENC        = "nvenc"
+	TranscoderBackendVAAPI        = "vaapi"
+	TranscoderBackendVideoToolbox = "videotoolbox"
+)
+
+// HLSのセグメントの長さ(秒)の範囲
+const (
+	minHLSSegmentDuration = 2
+	maxHLSSegmentDuration = 30
+)
+
+// ffmpegで使うハードウェアアクセラレーションとHLSの設定
+type TranscoderConfig struct {
+	Backend string
+	// VAAPIの/dev/dri/renderD128やNVENCのGPU番号など
+	Device string
+	// HLSのセグメントの長さ(秒)。モバイル回線では短い方が再生開始が早い
+	HLSSegmentDuration int
+	// キーフレームの間隔(フレーム数)。セグメントはキーフレームでしか区切れないため、セグメントの長さで割り切れるようにする
+	KeyframeInterval int
+}
+
+func (c TranscoderConfig) Validate() error {
+	if c.HLSSegmentDuration < minHLSSegmentDuration || c.HLSSegmentDuration > maxHLSSegmentDuration {
+		return fmt.Errorf("%w: hls segment duration must be between %d and %d seconds: %d", domain.ErrInvalidInput, minHLSSegmentDuration, maxHLSSegmentDuration, c.HLSSegmentDuration)
+	}
+	if c.KeyframeInterval <= 0 {
+		return fmt.Errorf("%w: keyframe interval must be positive: %d", domain.ErrInvalidInput, c.KeyframeInterval)
+	}
+	return nil
+}
+
+// sourceをoutputのHLSに変換するffmpegの引数
+func (c TranscoderConfig) hlsArgs(source, output string) []string {
+	args := append(c.inputArgs(), "-i", source)
+	args = append(args, c.codecArgs()...)
+	return append(args, "-start_number", "0", "-hls_time", strconv.Itoa(c.HLSSegmentDuration), "-hls_list_size", "0", "-f", "hls", output, "-y")
+}
+
+// -iより前に付けるハードウェアデコード用の引数
+func (c TranscoderConfig) inputArgs() []string {
+	var args []string
+	switch c.Backend {
+	case TranscoderBackendNVENC:
+		args = []string{"-hwaccel", "cuda"}
+	case TranscoderBackendVAAPI:
+		args = []string{"-hwaccel", "vaapi", "-hwaccel_output_format", "vaapi"}
+	case TranscoderBackendVideoToolbox:
+		args = []string{"-hwaccel", "videotoolbox"}
+	default:
+		return nil
+	}
+	if c.Device != "" {
+		args = append(args, "-hwaccel_device", c.Device)
+	}
+	return args
+}
+
+// 出力側のコーデック指定の引数
+func (c TranscoderConfig) codecArgs() []string {
+	encoder := c.encoder()
+	if encoder == "" {
+		return []string{"-codec:", "copy"}
+	}
+	args := []string{"-c:v", encoder, "-c:a", "copy"}
+	// コピーする場合はキーフレームの位置を変えられない
+	if c.KeyframeInterval > 0 {
+		args = append(args, "-g", strconv.Itoa(c.KeyframeInterval))
+	}
+	return args
+}
+
+func (c TranscoderConfig) encoder() string {
+	switch c.Backend {
+	case TranscoderBackendSoftware:
+		return "libx264"
+	case TranscoderBackendNVENC:
+		return "h264_nvenc"
+	case TranscoderBackendVAAPI:
+		return "h264_vaapi"
+	case TranscoderBackendVideoToolbox:
+		return "h264_videotoolbox"
+	default:
+		return ""
+	}
+}
+
+// 起動時に指定されたエンコーダがffmpegで使えるか確認し、使えなければlibx264にフォールバックする
+// 変換時に失敗するのではなく起動時に警告を出すため
+func probeTranscoder(c TranscoderConfig) TranscoderConfig {
+	encoder := c.encoder()
+	if encoder == "" || c.Backend == TranscoderBackendSoftware {
+		return c
+	}
+
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-encoders").Output()
+	if err != nil {
+		slog.Warn("failed to list ffmpeg encoders, falling back to libx264", "backend", c.Backend, "error", err)
+		return c.softwareFallback()
+	}
+	if !hasEncoder(string(output), encoder) {
+		slog.Warn("ffmpeg encoder is not available, falling back to libx264", "backend", c.Backend, "encoder", encoder)
+		return c.softwareFallback()
+	}
+	return c
+}
+
+// HLSの設定はそのまま使う
+func (c TranscoderConfig) softwareFallback() TranscoderConfig {
+	c.Backend = TranscoderBackendSoftware
+	c.Device = ""
+	return c
+}
+
+// ffmpeg -encodersの出力は" V....D h264_nvenc  NVIDIA NVENC H.264 encoder"の形式
+func hasEncoder(output, encoder string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[1] == encoder {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_トランスコーダの引数生成(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        TranscoderConfig
+		wantInputArgs []string
+		wantCodecArgs []string
+	}{
+		{
+			name:          "copy",
+			config:        TranscoderConfig{},
+			wantInputArgs: nil,
+			wantCodecArgs: []string{"-codec:", "copy"},
+		},
+		{
+			name:          "software",
+			config:        TranscoderConfig{Backend: TranscoderBackendSoftware},
+			wantInputArgs: nil,
+			wantCodecArgs: []string{"-c:v", "libx264", "-c:a", "copy"},
+		},
+		{
+			name:          "nvenc",
+			config:        TranscoderConfig{Backend: TranscoderBackendNVENC, Device: "0"},
+			wantInputArgs: []string{"-hwaccel", "cuda", "-hwaccel_device", "0"},
+			wantCodecArgs: []string{"-c:v", "h264_nvenc", "-c:a", "copy"},
+		},
+		{
+			name:          "vaapi",
+			config:        TranscoderConfig{Backend: TranscoderBackendVAAPI, Device: "/dev/dri/renderD128"},
+			wantInputArgs: []string{"-hwaccel", "vaapi", "-hwaccel_output_format", "vaapi", "-hwaccel_device", "/dev/dri/renderD128"},
+			wantCodecArgs: []string{"-c:v", "h264_vaapi", "-c:a", "copy"},
+		},
+		{
+			name:          "videotoolbox",
+			config:        TranscoderConfig{Backend: TranscoderBackendVideoToolbox},
+			wantInputArgs: []string{"-hwaccel", "videotoolbox"},
+			wantCodecArgs: []string{"-c:v", "h264_videotoolbox", "-c:a", "copy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.inputArgs(); !reflect.DeepEqual(got, tt.wantInputArgs) {
+				t.Errorf("TranscoderConfig.inputArgs() = %v, want %v", got, tt.wantInputArgs)
+			}
+			if got := tt.config.codecArgs(); !reflect.DeepEqual(got, tt.wantCodecArgs) {
+				t.Errorf("TranscoderConfig.codecArgs() = %v, want %v", got, tt.wantCodecArgs)
+			}
+		})
+	}
+}
+
+func Test_HLS変換の引数生成(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TranscoderConfig
+		want   []string
+	}{
+		{
+			name:   "copy",
+			config: TranscoderConfig{HLSSegmentDuration: 6, KeyframeInterval: 60},
+			want:   []string{"-i", "in.mp4", "-codec:", "copy", "-start_number", "0", "-hls_time", "6", "-hls_list_size", "0", "-f", "hls", "out.m3u8", "-y"},
+		},
+		{
+			name:   "software",
+			config: TranscoderConfig{Backend: TranscoderBackendSoftware, HLSSegmentDuration: 2, KeyframeInterval: 48},
+			want:   []string{"-i", "in.mp4", "-c:v", "libx264", "-c:a", "copy", "-g", "48", "-start_number", "0", "-hls_time", "2", "-hls_list_size", "0", "-f", "hls", "out.m3u8", "-y"},
+		},
+		{
+			name:   "nvenc",
+			config: TranscoderConfig{Backend: TranscoderBackendNVENC, Device: "0", HLSSegmentDuration: 30, KeyframeInterval: 120},
+			want:   []string{"-hwaccel", "cuda", "-hwaccel_device", "0", "-i", "in.mp4", "-c:v", "h264_nvenc", "-c:a", "copy", "-g", "120", "-start_number", "0", "-hls_time", "30", "-hls_list_size", "0", "-f", "hls", "out.m3u8", "-y"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); err != nil {
+				t.Fatalf("TranscoderConfig.Validate() error = %v", err)
+			}
+			if got := tt.config.hlsArgs("in.mp4", "out.m3u8"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TranscoderConfig.hlsArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HLSの設定の検証(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TranscoderConfig
+		wantErr error
+	}{
+		{name: "最短", config: TranscoderConfig{HLSSegmentDuration: 2, KeyframeInterval: 1}},
+		{name: "最長", config: TranscoderConfig{HLSSegmentDuration: 30, KeyframeInterval: 60}},
+		{name: "セグメントが短すぎる", config: TranscoderConfig{HLSSegmentDuration: 1, KeyframeInterval: 60}, wantErr: domain.ErrInvalidInput},
+		{name: "セグメントが長すぎる", config: TranscoderConfig{HLSSegmentDuration: 31, KeyframeInterval: 60}, wantErr: domain.ErrInvalidInput},
+		{name: "キーフレームの間隔が0", config: TranscoderConfig{HLSSegmentDuration: 6}, wantErr: domain.ErrInvalidInput},
+		{name: "キーフレームの間隔が負", config: TranscoderConfig{HLSSegmentDuration: 6, KeyframeInterval: -1}, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("TranscoderConfig.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_エンコーダの存在確認(t *testing.T) {
+	output := `Encoders:
+ V..... = Video
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)
+ V....D h264_nvenc           NVIDIA NVENC H.264 encoder (codec h264)
+`
+	if !hasEncoder(output, "h264_nvenc") {
+		t.Errorf("hasEncoder() = false, want true")
+	}
+	if hasEncoder(output, "h264_vaapi") {
+		t.Errorf("hasEncoder() = true, want false")
+	}
+}
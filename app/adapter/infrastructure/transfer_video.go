@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 投稿者の変更と監査ログの記録を1つのトランザクションで行う
+// 投稿者本人か管理者のみ変更できる
+// アップロード回数の制限はアップロードした時点で数えているため、新しい投稿者の残り回数は変わらない
+func (i *Infrastructure) TransferVideoOwnership(ctx context.Context, videoID, newOwnerID, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "TransferVideoOwnership")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	if newOwnerID == "" {
+		return fmt.Errorf("%w: new owner is required", domain.ErrInvalidInput)
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		video, err := q.GetVideoForUpdate(ctx, videoID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+		}
+		if err != nil {
+			return err
+		}
+		if video.UploaderID != requestingUserID && !i.IsAdmin(requestingUserID) {
+			return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, requestingUserID, videoID)
+		}
+		if video.UploaderID == newOwnerID {
+			return fmt.Errorf("%w: %s already owns %s", domain.ErrInvalidInput, newOwnerID, videoID)
+		}
+
+		_, err = q.UpdateVideoUploader(ctx, sqlc.UpdateVideoUploaderParams{
+			UploaderID: newOwnerID,
+			UpdatedAt:  time.Now(),
+			ID:         videoID,
+		})
+		if err != nil {
+			return err
+		}
+
+		before := newVideoFromDB(video)
+		after := *before
+		after.UploaderID = newOwnerID
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionTransferVideoOwnership, requestingUserID, before, &after)
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の投稿者の変更(t *testing.T) {
+	tests := []struct {
+		name             string
+		rows             [][]driver.Value
+		newOwnerID       string
+		requestingUserID string
+		wantErr          error
+	}{
+		{name: "投稿者本人", newOwnerID: "user_2", requestingUserID: "user_1"},
+		{name: "管理者", newOwnerID: "user_2", requestingUserID: "admin_1"},
+		{name: "他のユーザー", newOwnerID: "user_2", requestingUserID: "user_3", wantErr: domain.ErrPermissionDenied},
+		{name: "同じ投稿者", newOwnerID: "user_1", requestingUserID: "user_1", wantErr: domain.ErrInvalidInput},
+		{name: "動画がない", rows: [][]driver.Value{}, newOwnerID: "user_2", requestingUserID: "user_1", wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			_, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideoForUpdate": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:  client,
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			ctx := context.Background()
+			client.Set(ctx, relatedVideosKey("video_1", 10), "[]", time.Hour)
+			client.Set(ctx, relatedVideosKey("video_10", 10), "[]", time.Hour)
+			client.Set(ctx, downloadCountKey("video_10"), "{}", time.Hour)
+
+			err := i.TransferVideoOwnership(ctx, "video_1", tt.newOwnerID, tt.requestingUserID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.TransferVideoOwnership() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 || connector.commits != 0 {
+					t.Errorf("execs = %v, commits = %d, want no changes", connector.execNames, connector.commits)
+				}
+				return
+			}
+
+			wantNames := []string{"UpdateVideoUploader", "CreateAuditLog"}
+			if !reflect.DeepEqual(connector.execNames, wantNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+			audit := connector.execs[1]
+			wantAudit := []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionTransferVideoOwnership, tt.requestingUserID}
+			if !reflect.DeepEqual(audit[:4], wantAudit) {
+				t.Errorf("CreateAuditLog args = %v, want %v", audit[:4], wantAudit)
+			}
+			var before, after domain.Video
+			if json.Unmarshal([]byte(audit[4].(string)), &before) != nil || json.Unmarshal([]byte(audit[5].(string)), &after) != nil {
+				t.Fatalf("CreateAuditLog before = %v, after = %v, want json", audit[4], audit[5])
+			}
+			if before.UploaderID != "user_1" || after.UploaderID != "user_2" {
+				t.Errorf("CreateAuditLog uploader before = %s, after = %s, want user_1 and user_2", before.UploaderID, after.UploaderID)
+			}
+
+			// 投稿者は他の動画の関連動画のキャッシュにも含まれるため全て消す
+			if n, _ := client.Exists(ctx, relatedVideosKey("video_1", 10), relatedVideosKey("video_10", 10)).Result(); n != 0 {
+				t.Errorf("related videos cache should be deleted")
+			}
+			if n, _ := client.Exists(ctx, downloadCountKey("video_10")).Result(); n != 1 {
+				t.Errorf("download count cache of video_10 should remain")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+const trendingVideosCacheTTL = 5 * time.Minute
+
+func trendingVideosKey(window time.Duration, limit int) string {
+	return "trending" + domain.IDSeparator + window.String() + domain.IDSeparator + strconv.Itoa(limit)
+}
+
+// 再生回数の増え方が速い順に返す
+// 速さは(window内の最後のスナップショットの再生回数 - 最初のスナップショットの再生回数) / windowの時間数で、
+// 同じwindowで比べる限り増えた回数の順と変わらないので、並び替えは増えた回数で行う
+// スナップショットは再生回数をDBに反映するときに記録する
+func (i *Infrastructure) GetTrendingVideos(ctx context.Context, window time.Duration, limit int) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetTrendingVideos")
+	defer func() { endSpan(span, err) }()
+
+	if window <= 0 || window > watchCountSnapshotRetention {
+		return nil, fmt.Errorf("%w: window must be between 0 and %s", domain.ErrInvalidInput, watchCountSnapshotRetention)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be greater than 0", domain.ErrInvalidInput)
+	}
+
+	key := trendingVideosKey(window, limit)
+	var videos []*domain.Video
+	hit, err := i.getFromCache(ctx, "trending", key, &videos)
+	if err != nil {
+		return nil, err
+	} else if hit {
+		return videos, nil
+	}
+
+	rows, err := i.db.Database.GetTrendingVideos(ctx, sqlc.GetTrendingVideosParams{
+		WindowStart: time.Now().Add(-window),
+		Limit:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbVideos := make([]sqlc.Video, 0, len(rows))
+	for _, row := range rows {
+		dbVideos = append(dbVideos, row.Video)
+	}
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, err
+	}
+	videos = newVideosWithTags(dbVideos, tags)
+
+	err = setToRedis(ctx, i.redis, key, trendingVideosCacheTTL, videos)
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_急上昇の動画の取得(t *testing.T) {
+	// videoの全カラムと増えた再生回数
+	trendingRow := func(id string, delta int64) []driver.Value {
+		row := relatedVideoRow(id, 0, "")
+		return append(row[:len(row)-2], delta)
+	}
+
+	tests := []struct {
+		name    string
+		window  time.Duration
+		limit   int
+		wantIDs []string
+		wantErr error
+	}{
+		{name: "24時間", window: 24 * time.Hour, limit: 10, wantIDs: []string{"video_2", "video_1"}},
+		{name: "保持期間より長い", window: 30 * 24 * time.Hour, limit: 10, wantErr: domain.ErrInvalidInput},
+		{name: "期間が0", window: 0, limit: 10, wantErr: domain.ErrInvalidInput},
+		{name: "件数が0", window: time.Hour, limit: 0, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetTrendingVideos":       {trendingRow("video_2", 30), trendingRow("video_1", 5)},
+				"GetVideosTagsByVideoIDs": {{"video_2", int64(1), "go"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:    &db.DB{Database: sqlc.New(sqlDB)},
+				redis: client,
+			}
+
+			for n := 0; n < 2; n++ {
+				got, err := i.GetTrendingVideos(context.Background(), tt.window, tt.limit)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Infrastructure.GetTrendingVideos() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(got) != len(tt.wantIDs) {
+					t.Fatalf("Infrastructure.GetTrendingVideos() = %v, want %v", got, tt.wantIDs)
+				}
+				for k, video := range got {
+					if video.ID != tt.wantIDs[k] {
+						t.Errorf("video[%d] = %s, want %s", k, video.ID, tt.wantIDs[k])
+					}
+				}
+			}
+			// 2回目はキャッシュから返る
+			if tt.wantErr == nil && connector.queries != 2 {
+				t.Errorf("GetTrendingVideos queried the database %d times, want 2", connector.queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// updateで指定された項目とupdated_atを1つのトランザクションで更新する
+// 外したタグはvideo_tagsの行のみ削除し、tagの行は他の動画で使えるように残す
+// 投稿者本人か管理者のみ更新できる
+// versionは編集を始めた時に取得した動画のVersionで、その後に他の人が更新していた場合はErrVersionConflictを返す
+func (i *Infrastructure) UpdateVideo(ctx context.Context, id, requestingUserID string, version int, update domain.VideoUpdate) (_ *domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "UpdateVideo")
+	span.SetAttributes(attribute.String("videoID", id), attribute.String("requestingUserID", requestingUserID), attribute.Int("version", version))
+	defer func() { endSpan(span, err) }()
+
+	err = update.Validate()
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if update.Tags != nil {
+		tags, err = i.normalizeVideoTags(*update.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		video, err := q.GetVideoForUpdate(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", domain.ErrVideoNotFound, id)
+		}
+		if err != nil {
+			return err
+		}
+		if video.UploaderID != requestingUserID && !i.IsAdmin(requestingUserID) {
+			return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, requestingUserID, id)
+		}
+		currentTags, err := q.GetVideoTags(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := newVideoFromDB(video)
+		for _, tag := range currentTags {
+			before.Tags = append(before.Tags, tag.TagName)
+		}
+
+		params := sqlc.UpdateVideoMetadataParams{
+			Title:       video.Title,
+			Description: video.Description,
+			IsPrivate:   video.IsPrivate,
+			Language:    video.Language,
+			UpdatedAt:   time.Now(),
+			ID:          id,
+			Version:     int32(version),
+		}
+		if update.Title != nil {
+			params.Title = *update.Title
+		}
+		if update.Description != nil {
+			params.Description = sql.NullString{String: *update.Description, Valid: true}
+		}
+		if update.IsPrivate != nil {
+			params.IsPrivate = *update.IsPrivate
+		}
+		if update.Language != nil {
+			params.Language = *update.Language
+			if params.Language != "" {
+				// Validateで確認済み
+				params.Language, _ = domain.NormalizeLanguage(params.Language)
+			}
+		}
+		result, err := q.UpdateVideoMetadata(ctx, params)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: %s is at version %d, not %d", domain.ErrVersionConflict, id, video.Version, version)
+		}
+
+		after := newVideoFromDB(video)
+		after.Title = params.Title
+		after.Description = &params.Description.String
+		after.IsPrivate = params.IsPrivate
+		after.Language = params.Language
+		after.UpdatedAt = params.UpdatedAt
+		after.Version = version + 1
+		after.Tags = before.Tags
+		if update.Tags != nil {
+			err = updateVideoTags(ctx, q, id, currentTags, tags)
+			if err != nil {
+				return err
+			}
+			after.Tags = tags
+		}
+		return recordVideoAuditLog(ctx, q, id, domain.AuditActionUpdateVideo, requestingUserID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 公開期限を過ぎた動画でも更新した内容は返す
+	video, err := i.GetVideoFromDB(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrVideoExpired) {
+		return nil, err
+	}
+	i.emitWebhookEvent(ctx, domain.WebhookEventVideoUpdated, id, video)
+	i.invalidateVideoCache(ctx, id)
+	return video, nil
+}
+
+// 今のタグとの差分だけvideo_tagsを追加、削除する
+func updateVideoTags(ctx context.Context, q *sqlc.Queries, videoID string, current []sqlc.Tag, tags []string) error {
+	want := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		want[tag] = struct{}{}
+	}
+	have := make(map[string]struct{}, len(current))
+	for _, tag := range current {
+		have[tag.TagName] = struct{}{}
+		if _, ok := want[tag.TagName]; ok {
+			continue
+		}
+		_, err := q.DeleteVideoTag(ctx, sqlc.DeleteVideoTagParams{
+			VideoID: videoID,
+			TagID:   tag.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range tags {
+		if _, ok := have[tag]; ok {
+			continue
+		}
+		// 既にあるタグの場合はそのIDが返る
+		result, err := q.UpsertTag(ctx, tag)
+		if err != nil {
+			return err
+		}
+		tagID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateVideoTags(ctx, sqlc.CreateVideoTagsParams{
+			VideoID: videoID,
+			TagID:   int32(tagID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
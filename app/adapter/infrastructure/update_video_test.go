@@ -0,0 +1,214 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の情報の更新(t *testing.T) {
+	title := "new title"
+	emptyTitle := " "
+	isPrivate := true
+	tags := []string{"go", "new", " go ", ""}
+	tooManyTags := make([]string, defaultMaxTagsPerVideo+1)
+	for n := range tooManyTags {
+		tooManyTags[n] = fmt.Sprintf("tag%d", n)
+	}
+	tests := []struct {
+		name             string
+		requestingUserID string
+		update           domain.VideoUpdate
+		rows             [][]driver.Value
+		wantExecNames    []string
+		wantErr          error
+		wantRollback     bool
+	}{
+		{
+			name:             "タイトルと公開設定",
+			requestingUserID: "user_1",
+			update:           domain.VideoUpdate{Title: &title, IsPrivate: &isPrivate},
+			wantExecNames:    []string{"UpdateVideoMetadata", "CreateAuditLog"},
+		},
+		{
+			name:             "タグの差分のみ更新する",
+			requestingUserID: "user_1",
+			update:           domain.VideoUpdate{Tags: &tags},
+			wantExecNames:    []string{"UpdateVideoMetadata", "DeleteVideoTag", "UpsertTag", "CreateVideoTags", "CreateAuditLog"},
+		},
+		{
+			name:             "管理者",
+			requestingUserID: "admin_1",
+			update:           domain.VideoUpdate{Title: &title},
+			wantExecNames:    []string{"UpdateVideoMetadata", "CreateAuditLog"},
+		},
+		{
+			name:             "他のユーザー",
+			requestingUserID: "user_2",
+			update:           domain.VideoUpdate{Title: &title},
+			wantErr:          domain.ErrPermissionDenied,
+			wantRollback:     true,
+		},
+		{
+			name:             "タグが多すぎる",
+			requestingUserID: "user_1",
+			update:           domain.VideoUpdate{Tags: &tooManyTags},
+			wantErr:          domain.ErrTooManyTags,
+		},
+		{
+			name:             "空のタイトル",
+			requestingUserID: "user_1",
+			update:           domain.VideoUpdate{Title: &emptyTitle},
+			wantErr:          domain.ErrInvalidInput,
+		},
+		{
+			name:             "動画がない",
+			requestingUserID: "user_1",
+			update:           domain.VideoUpdate{Title: &title},
+			rows:             [][]driver.Value{},
+			wantErr:          domain.ErrVideoNotFound,
+			wantRollback:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			row = row[:len(row)-2]
+			connector := &rowConnector{
+				values: row,
+				rowsByQuery: map[string][][]driver.Value{
+					"GetVideoTags":               {{int64(1), "go"}, {int64(2), "old"}},
+					"GetVideoDescriptions":       {},
+					"GetSubtitleTracksByVideoID": {},
+				},
+			}
+			if tt.rows != nil {
+				connector.rowsByQuery["GetVideoForUpdate"] = tt.rows
+			}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			_, client := newTestRedis(t)
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:  client,
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			got, err := i.UpdateVideo(context.Background(), "video_1", tt.requestingUserID, 0, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.UpdateVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+			if tt.wantRollback && (connector.rollbacks != 1 || connector.commits != 0) {
+				t.Errorf("commits = %d, rollbacks = %d, want rollback", connector.commits, connector.rollbacks)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+			if got == nil || got.ID != "video_1" {
+				t.Errorf("Infrastructure.UpdateVideo() = %+v, want video_1", got)
+			}
+		})
+	}
+}
+
+func Test_動画の情報の更新の引数(t *testing.T) {
+	title := "new title"
+	description := "new description"
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	connector := &rowConnector{values: row, rowsByQuery: map[string][][]driver.Value{"GetVideoTags": {}, "GetVideoDescriptions": {}, "GetSubtitleTracksByVideoID": {}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	_, client := newTestRedis(t)
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, redis: client}
+
+	_, err := i.UpdateVideo(context.Background(), "video_1", "user_1", 0, domain.VideoUpdate{Title: &title, Description: &description})
+	if err != nil {
+		t.Fatalf("Infrastructure.UpdateVideo() error = %v", err)
+	}
+
+	// 指定されていない公開設定と言語は今の値のまま更新する
+	args := connector.execs[0]
+	want := []driver.Value{title, description, false, ""}
+	if !reflect.DeepEqual(args[:4], want) {
+		t.Errorf("UpdateVideoMetadata args = %v, want %v", args[:4], want)
+	}
+	if args[5] != "video_1" || args[6] != int64(0) {
+		t.Errorf("UpdateVideoMetadata id, version = %v, %v, want video_1, 0", args[5], args[6])
+	}
+}
+
+func Test_同じ動画の情報の同時更新(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	// 行ロックの代わりに、バージョンが一致した場合だけ更新したとみなす
+	var version int64
+	connector := &rowConnector{
+		values:      row,
+		rowsByQuery: map[string][][]driver.Value{"GetVideoTags": {}, "GetVideoDescriptions": {}, "GetSubtitleTracksByVideoID": {}},
+		execDelay:   time.Millisecond,
+		affectedByArgs: func(name string, args []driver.Value) (int64, bool) {
+			if name != "UpdateVideoMetadata" {
+				return 0, false
+			}
+			if args[6] != version {
+				return 0, true
+			}
+			version++
+			return 1, true
+		},
+	}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	_, client := newTestRedis(t)
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, redis: client}
+
+	titles := []string{"title by editor 1", "title by editor 2"}
+	errs := make([]error, len(titles))
+	var wg sync.WaitGroup
+	for n := range titles {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			_, errs[n] = i.UpdateVideo(context.Background(), "video_1", "user_1", 0, domain.VideoUpdate{Title: &titles[n]})
+		}(n)
+	}
+	wg.Wait()
+
+	var succeeded, conflicted int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, domain.ErrVersionConflict):
+			conflicted++
+		default:
+			t.Fatalf("Infrastructure.UpdateVideo() error = %v", err)
+		}
+	}
+	if succeeded != 1 || conflicted != 1 {
+		t.Errorf("succeeded = %d, conflicted = %d, want 1 and 1", succeeded, conflicted)
+	}
+	if connector.commits != 1 || connector.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want 1 and 1", connector.commits, connector.rollbacks)
+	}
+	if version != 1 {
+		t.Errorf("version = %d, want 1", version)
+	}
+}
@@ -3,21 +3,18 @@ package infrastructure
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/yuorei/video-server/app/domain"
 )
 
-func (i *Infrastructure) UploadVideoForStorage(ctx context.Context, video *domain.VideoFile) (string, error) {
-	err := filepath.Walk("output/"+video.ID, func(path string, info os.FileInfo, err error) error {
+func (i *Infrastructure) UploadVideoForStorage(ctx context.Context, video *domain.VideoFile) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "UploadVideoForStorage")
+	defer func() { endSpan(span, err) }()
+
+	err = filepath.Walk("output/"+video.ID, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -32,8 +29,7 @@ func (i *Infrastructure) UploadVideoForStorage(ctx context.Context, video *domai
 				}
 				return nil
 			}()
-			bucketName := "video"
-			err := uploadVideoForS3(path, bucketName)
+			err := i.uploadStreamingFile(ctx, path, video.ID+"/"+filepath.Base(path))
 			if err != nil {
 				return err
 			}
@@ -45,66 +41,10 @@ func (i *Infrastructure) UploadVideoForStorage(ctx context.Context, video *domai
 		return "", fmt.Errorf("failed to remove output files: %w", err)
 	}
 
-	bucketName := "video"
-	url := fmt.Sprintf("%s/%s/%s/output_%s.m3u8", os.Getenv("AWS_S3_URL"), bucketName, video.ID, video.ID)
-	return url, nil
+	return i.videoPlaylistURL(video.ID), nil
 }
 
-func uploadVideoForS3(path, bucketName string) error {
-	ctx := context.Background()
-	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-
-	cred := credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithCredentialsProvider(cred))
-	if err != nil {
-		return err
-	}
-
-	// change object address style
-	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
-		options.UsePathStyle = true
-		options.BaseEndpoint = aws.String(os.Getenv("AWS_S3_ENDPOINT"))
-		options.Region = "ap-northeast-1"
-	})
-
-	// get buckets
-	lbo, err := client.ListBuckets(ctx, nil)
-	if err != nil {
-		return err
-	}
-	buckets := make(map[string]struct{}, len(lbo.Buckets))
-	for _, b := range lbo.Buckets {
-		buckets[*b.Name] = struct{}{}
-	}
-
-	if _, ok := buckets[bucketName]; !ok {
-		_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{
-			Bucket: &bucketName,
-			ACL:    types.BucketCannedACLPublicRead,
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	// put object
-	_, err = client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(strings.Split(path, "/")[1] + "/" + strings.Split(path, "/")[2]),
-		Body:   file,
-		ACL:    types.ObjectCannedACLPublicRead,
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to upload file: %w", err)
-	}
-	log.Println("Successful upload: ", path)
-
-	return nil
+// アップロード後のHLSのプレイリストのURL
+func (i *Infrastructure) videoPlaylistURL(id string) string {
+	return i.objectURL(videoBucketName, id+"/output_"+id+".m3u8")
 }
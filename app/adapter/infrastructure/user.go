@@ -5,15 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 
 	"github.com/yuorei/video-server/app/domain"
 	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (i *Infrastructure) GetUserFromDB(ctx context.Context, id string) (*domain.User, error) {
+func (i *Infrastructure) GetUserFromDB(ctx context.Context, id string) (_ *domain.User, err error) {
+	ctx, span := infraSpan(ctx, "GetUserFromDB")
+	span.SetAttributes(attribute.String("userID", id))
+	defer func() { endSpan(span, err) }()
+
 	user, err := i.db.Database.GetUser(ctx, id)
 	if err != nil {
 		return nil, err
@@ -32,7 +36,10 @@ func (i *Infrastructure) GetUserFromDB(ctx context.Context, id string) (*domain.
 	}, nil
 }
 
-func (i *Infrastructure) InsertUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+func (i *Infrastructure) InsertUser(ctx context.Context, user *domain.User) (_ *domain.User, err error) {
+	ctx, span := infraSpan(ctx, "InsertUser")
+	defer func() { endSpan(span, err) }()
+
 	result, err := i.db.Database.CreatetUser(ctx, sqlc.CreatetUserParams{
 		ID:              user.ID,
 		Name:            user.Name,
@@ -44,12 +51,16 @@ func (i *Infrastructure) InsertUser(ctx context.Context, user *domain.User) (*do
 	if result == nil {
 		return nil, fmt.Errorf("failed to insert user")
 	}
-	log.Println("inserted user", user.ID)
+	i.log().InfoContext(ctx, "inserted user", "userID", user.ID)
 	return user, nil
 
 }
 
-func (i *Infrastructure) GetProfileImageURL(ctx context.Context, id string) (string, error) {
+func (i *Infrastructure) GetProfileImageURL(ctx context.Context, id string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GetProfileImageURL")
+	span.SetAttributes(attribute.String("userID", id))
+	defer func() { endSpan(span, err) }()
+
 	resp, err := http.Get(os.Getenv("AUTH_URL") + "/profile-image/" + id)
 	if err != nil {
 		return "", err
@@ -71,7 +82,10 @@ func (i *Infrastructure) GetProfileImageURL(ctx context.Context, id string) (str
 	return profileImageURL.URL, nil
 }
 
-func (i *Infrastructure) AddSubscribeChannelForDB(ctx context.Context, subscribeChannel *domain.SubscribeChannel) (*domain.SubscribeChannel, error) {
+func (i *Infrastructure) AddSubscribeChannelForDB(ctx context.Context, subscribeChannel *domain.SubscribeChannel) (_ *domain.SubscribeChannel, err error) {
+	ctx, span := infraSpan(ctx, "AddSubscribeChannelForDB")
+	defer func() { endSpan(span, err) }()
+
 	id, err := i.db.Database.GetUserSubscriptionID(ctx, sqlc.GetUserSubscriptionIDParams{
 		UserID:    subscribeChannel.UserID,
 		ChannelID: subscribeChannel.ChannelID,
@@ -98,7 +112,10 @@ func (i *Infrastructure) AddSubscribeChannelForDB(ctx context.Context, subscribe
 	return subscribeChannel, nil
 }
 
-func (i *Infrastructure) UnSubscribeChannelForDB(ctx context.Context, subscribeChannel *domain.SubscribeChannel) (*domain.SubscribeChannel, error) {
+func (i *Infrastructure) UnSubscribeChannelForDB(ctx context.Context, subscribeChannel *domain.SubscribeChannel) (_ *domain.SubscribeChannel, err error) {
+	ctx, span := infraSpan(ctx, "UnSubscribeChannelForDB")
+	defer func() { endSpan(span, err) }()
+
 	id, err := i.db.Database.GetUserSubscriptionID(ctx, sqlc.GetUserSubscriptionIDParams{
 		UserID:    subscribeChannel.UserID,
 		ChannelID: subscribeChannel.ChannelID,
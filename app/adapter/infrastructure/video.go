@@ -1,103 +1,271 @@
 package infrastructure
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"time"
 
 	"github.com/yuorei/video-server/app/domain"
 	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// 一度に取得できる動画IDの上限
+	maxVideoIDsPerRequest = 100
+	// これ以下のサイズの動画ファイルは途中で切れているとみなす
+	minVideoFileSize = 10 * 1024
+	// 正確なシークで粗く切り出す際に開始位置より前に含める秒数(HLSのセグメント長に合わせる)
+	accurateSeekMargin = 10
 )
 
 type WatchCountJsonType struct {
 	Count int `json:"count"`
 }
 
-type UploaderID struct {
-	ID string `json:"id"`
-}
+// filterで指定した対象年齢までの動画を取得する
+func (i *Infrastructure) GetVideosFromDB(ctx context.Context, filter domain.ContentFilter) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosFromDB")
+	span.SetAttributes(attribute.String("maxRating", string(filter.MaxRating)))
+	defer func() { endSpan(span, err) }()
 
-func (i *Infrastructure) CheckUploadAPIRateLimit(ctx context.Context, id string) error {
-	var uploaderID UploaderID
-	hit, err := getFromRedis(ctx, i.redis, "upload"+id, &uploaderID)
+	err = filter.MaxRating.Validate()
 	if err != nil {
-		return err
-	} else if hit {
-		return fmt.Errorf("upload api rate limit")
+		return nil, err
+	}
+	ratings := filter.AllowedRatings()
+	contentRatings := make([]string, len(ratings))
+	for n, rating := range ratings {
+		contentRatings[n] = string(rating)
 	}
 
-	return nil
-}
+	dbVideos, err := i.db.Database.GetPublicNonAdVideosByContentRatings(ctx, contentRatings)
+	if err != nil {
+		return nil, err
+	}
 
-func (i *Infrastructure) SetUploadAPIRateLimit(ctx context.Context, id string) error {
-	// 24時間のアップロード回数を制限
-	err := setToRedis(ctx, i.redis, "upload"+id, 24*time.Hour, &UploaderID{
-		ID: id,
-	})
+	tags, err := i.db.Database.GetAllVideosTags(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	return newVideosWithTags(dbVideos, tags), nil
 }
 
-func (i *Infrastructure) GetVideosFromDB(ctx context.Context) ([]*domain.Video, error) {
-	var videos []*domain.Video
-	dbVideos, err := i.db.Database.GetPublicAndNonAdultNonAdVideos(ctx)
+func (i *Infrastructure) GetVideosSortedFromDB(ctx context.Context, order domain.SortOrder) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosSortedFromDB")
+	defer func() { endSpan(span, err) }()
+
+	err = order.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	tags, err := i.db.Database.GetAllVideosTags(ctx)
+	dbVideos, err := i.db.Database.GetPublicAndNonAdultNonAdVideosSorted(ctx, sqlc.GetPublicAndNonAdultNonAdVideosSortedParams{
+		SortOrder: string(order),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, dbVideo := range dbVideos {
-		video := domain.NewVideo(dbVideo.ID, dbVideo.VideoUrl, dbVideo.ThumbnailImageUrl, dbVideo.Title, &dbVideo.Description.String, []string{}, int(dbVideo.WatchCount), dbVideo.IsPrivate, dbVideo.IsAdult, dbVideo.IsExternalCutout, dbVideo.IsAd, dbVideo.UploaderID, dbVideo.CreatedAt, dbVideo.UpdatedAt)
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, tag := range tags {
-			if tag.VideoID == dbVideo.ID {
-				video.Tags = append(video.Tags, tag.TagName)
-			}
+	return newVideosWithTags(dbVideos, tags), nil
+}
+
+func (i *Infrastructure) GetVideosFromDBPaged(ctx context.Context, page domain.Page) (_ []*domain.Video, _ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosFromDBPaged")
+	defer func() { endSpan(span, err) }()
+
+	err = page.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := i.db.Database.CountPublicAndNonAdultNonAdVideos(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dbVideos, err := i.db.Database.GetPublicAndNonAdultNonAdVideosPaged(ctx, sqlc.GetPublicAndNonAdultNonAdVideosPagedParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return newVideosWithTags(dbVideos, tags), total, nil
+}
+
+// 最後に取得した動画の (created_at, id) より後の動画を取得する
+// cursorが空文字の場合は先頭から取得し、次のページがない場合は空文字のcursorを返す
+func (i *Infrastructure) GetVideosFromDBAfterCursor(ctx context.Context, cursor string, limit int) (_ []*domain.Video, _ string, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosFromDBAfterCursor")
+	defer func() { endSpan(span, err) }()
+
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("%w: limit must be greater than 0", domain.ErrInvalidInput)
+	}
+
+	// 次のページがあるかを判定するために1件多く取得する
+	var dbVideos []sqlc.Video
+	if cursor == "" {
+		videos, err := i.db.Database.GetPublicAndNonAdultNonAdVideosFirstPage(ctx, int32(limit+1))
+		if err != nil {
+			return nil, "", err
+		}
+		dbVideos = videos
+	} else {
+		c, err := domain.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
 		}
 
-		videos = append(videos, video)
+		videos, err := i.db.Database.GetPublicAndNonAdultNonAdVideosAfterCursor(ctx, sqlc.GetPublicAndNonAdultNonAdVideosAfterCursorParams{
+			CreatedAt: c.CreatedAt,
+			ID:        c.ID,
+			Limit:     int32(limit + 1),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		dbVideos = videos
 	}
 
-	return videos, nil
+	var nextCursor string
+	if len(dbVideos) > limit {
+		dbVideos = dbVideos[:limit]
+		last := dbVideos[len(dbVideos)-1]
+		c, err := domain.EncodeCursor(last.CreatedAt, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		nextCursor = c
+	}
+
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return newVideosWithTags(dbVideos, tags), nextCursor, nil
 }
 
-func (i *Infrastructure) GetVideosByUserIDFromDB(ctx context.Context, userID string) ([]*domain.Video, error) {
-	var videos []*domain.Video
+func (i *Infrastructure) GetVideosByUserIDFromDB(ctx context.Context, userID string) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosByUserIDFromDB")
+	span.SetAttributes(attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
 	dbVideos, err := i.db.Database.GetPublicAndNonAdByUploaderID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	tags, err := i.db.Database.GetAllVideosTagsByUserID(ctx, userID)
+	userTags, err := i.db.Database.GetAllVideosTagsByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+
+	tags := make([]sqlc.GetAllVideosTagsRow, 0, len(userTags))
+	for _, tag := range userTags {
+		tags = append(tags, sqlc.GetAllVideosTagsRow(tag))
+	}
+
+	return newVideosWithTags(dbVideos, tags), nil
+}
+
+// 取得した動画に紐づくタグだけをまとめて取得する
+func (i *Infrastructure) getVideosTags(ctx context.Context, dbVideos []sqlc.Video) ([]sqlc.GetAllVideosTagsRow, error) {
+	if len(dbVideos) == 0 {
+		return []sqlc.GetAllVideosTagsRow{}, nil
+	}
+
+	videoIDs := make([]string, 0, len(dbVideos))
 	for _, dbVideo := range dbVideos {
-		video := domain.NewVideo(dbVideo.ID, dbVideo.VideoUrl, dbVideo.ThumbnailImageUrl, dbVideo.Title, &dbVideo.Description.String, []string{}, int(dbVideo.WatchCount), dbVideo.IsPrivate, dbVideo.IsAdult, dbVideo.IsExternalCutout, dbVideo.IsAd, dbVideo.UploaderID, dbVideo.CreatedAt, dbVideo.UpdatedAt)
-		for _, tag := range tags {
-			if tag.VideoID == dbVideo.ID {
-				video.Tags = append(video.Tags, tag.TagName)
-			}
-			videos = append(videos, video)
+		videoIDs = append(videoIDs, dbVideo.ID)
+	}
+
+	videoTags, err := i.db.Database.GetVideosTagsByVideoIDs(ctx, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]sqlc.GetAllVideosTagsRow, 0, len(videoTags))
+	for _, tag := range videoTags {
+		tags = append(tags, sqlc.GetAllVideosTagsRow(tag))
+	}
+	return tags, nil
+}
+
+// 動画IDをキーにしたmapでタグをまとめ、動画1件につき1要素になるようにする
+func newVideosWithTags(dbVideos []sqlc.Video, tags []sqlc.GetAllVideosTagsRow) []*domain.Video {
+	videos := make([]*domain.Video, 0, len(dbVideos))
+	videoMap := make(map[string]*domain.Video, len(dbVideos))
+	for _, dbVideo := range dbVideos {
+		video := newVideoFromDB(dbVideo)
+		videoMap[dbVideo.ID] = video
+		videos = append(videos, video)
+	}
+
+	for _, tag := range tags {
+		if video, ok := videoMap[tag.VideoID]; ok {
+			video.Tags = append(video.Tags, tag.TagName)
 		}
 	}
-	return videos, nil
+
+	return videos
 }
 
-func (i *Infrastructure) GetVideoFromDB(ctx context.Context, id string) (*domain.Video, error) {
-	dbVideo, err := i.db.Database.GetVideo(ctx, id)
+func newVideoFromDB(dbVideo sqlc.Video) *domain.Video {
+	description := dbVideo.Description.String
+	video := domain.NewVideo(dbVideo.ID, dbVideo.VideoUrl, dbVideo.ThumbnailImageUrl, dbVideo.Title, &description, []string{}, int(dbVideo.WatchCount), dbVideo.IsPrivate, domain.ContentRating(dbVideo.ContentRating), dbVideo.IsExternalCutout, dbVideo.IsAd, dbVideo.UploaderID, dbVideo.CreatedAt, dbVideo.UpdatedAt)
+	video.Duration = time.Duration(dbVideo.DurationMs) * time.Millisecond
+	video.Width = int(dbVideo.Width)
+	video.Height = int(dbVideo.Height)
+	video.Bitrate = dbVideo.Bitrate
+	video.DownloadCount = int(dbVideo.DownloadCount)
+	video.PreviewURL = dbVideo.PreviewUrl.String
+	video.ThumbnailVTTURL = dbVideo.ThumbnailVttUrl.String
+	video.IsDeleted = dbVideo.IsDeleted
+	video.IsBanned = dbVideo.IsBanned
+	video.ProcessingStatus = domain.ProcessingStatus(dbVideo.ProcessingStatus)
+	video.ProcessingError = dbVideo.ProcessingError.String
+	video.Checksum = dbVideo.Checksum.String
+	video.AllowedRegions = parseAllowedRegions(dbVideo.AllowedRegions)
+	video.Language = dbVideo.Language
+	video.ChaptersURL = dbVideo.ChaptersUrl.String
+	video.HLSMasterURL = dbVideo.HlsMasterUrl.String
+	video.DASHManifestURL = dbVideo.DashManifestUrl.String
+	video.Version = int(dbVideo.Version)
+	if dbVideo.PublishAt.Valid {
+		video.PublishAt = &dbVideo.PublishAt.Time
+	}
+	if dbVideo.ExpiresAt.Valid {
+		video.ExpiresAt = &dbVideo.ExpiresAt.Time
+	}
+	return video
+}
+
+func (i *Infrastructure) GetVideoFromDB(ctx context.Context, id string) (_ *domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetVideoFromDB")
+	span.SetAttributes(attribute.String("videoID", id))
+	defer func() { endSpan(span, err) }()
+
+	dbVideo, err := i.getUndeletedVideo(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -107,58 +275,215 @@ func (i *Infrastructure) GetVideoFromDB(ctx context.Context, id string) (*domain
 		return nil, err
 	}
 
-	video := domain.NewVideo(dbVideo.ID, dbVideo.VideoUrl, dbVideo.ThumbnailImageUrl, dbVideo.Title, &dbVideo.Description.String, []string{}, int(dbVideo.WatchCount), dbVideo.IsPrivate, dbVideo.IsAdult, dbVideo.IsExternalCutout, dbVideo.IsAd, dbVideo.UploaderID, dbVideo.CreatedAt, dbVideo.UpdatedAt)
+	video := newVideoFromDB(dbVideo)
 	for _, tag := range tags {
 		video.Tags = append(video.Tags, tag.TagName)
 	}
+	video.Descriptions, err = i.getVideoDescriptions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	video.SubtitleTracks, err = i.getSubtitleTracks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	video.AudioTracks, err = parseAudioTracks(dbVideo.AudioTracks)
+	if err != nil {
+		return nil, err
+	}
 
+	// 公開期限を過ぎた動画は見つからない場合と区別できるように動画も返す
+	if video.IsExpired(time.Now()) {
+		return video, domain.ErrVideoExpired
+	}
 	return video, nil
 }
 
-func (i *Infrastructure) InsertVideo(ctx context.Context, id string, videoURL string, thumbnailImageURL string, title string, description *string, uploaderID string, tags []string, isAdult bool, isPrivate bool, isExternalCutout bool, isAd bool) (*domain.UploadVideoResponse, error) {
-	_, err := i.db.Database.CreateVideo(ctx, sqlc.CreateVideoParams{
-		ID:                id,
-		VideoUrl:          videoURL,
-		ThumbnailImageUrl: thumbnailImageURL,
-		Title:             title,
-		Description: sql.NullString{
-			String: *description,
-			Valid:  description != nil,
-		},
-		UploaderID:       uploaderID,
-		IsPrivate:        isPrivate,
-		IsAdult:          isAdult,
-		IsExternalCutout: isExternalCutout,
-		IsAd:             isAd,
-		CreatedAt:        time.Now(),
-		UpdatedAt:        time.Now(),
-		WatchCount:       0,
+// errMsgは失敗した場合のみ保存し、それ以外の状態では消す
+// 処理はサーバーが行うため、監査ログにはAuditActorSystemを残す
+func (i *Infrastructure) UpdateVideoProcessingStatus(ctx context.Context, videoID string, status domain.ProcessingStatus, errMsg string) (err error) {
+	ctx, span := infraSpan(ctx, "UpdateVideoProcessingStatus")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	err = status.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionUpdateProcessingStatus, domain.AuditActorSystem, func(sqlc.Video) error {
+			_, err := q.UpdateVideoProcessingStatus(ctx, sqlc.UpdateVideoProcessingStatusParams{
+				ProcessingStatus: string(status),
+				ProcessingError: sql.NullString{
+					String: errMsg,
+					Valid:  status == domain.StatusFailed && errMsg != "",
+				},
+				UpdatedAt: time.Now(),
+				ID:        videoID,
+			})
+			return err
+		})
 	})
+	if err != nil {
+		return err
+	}
+
+	i.publishVideoStatus(ctx, videoID, status)
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+// 複数の動画を1回のクエリで取得する
+// 返り値はidsと同じ順番で、存在しない動画の位置はnilになる
+func (i *Infrastructure) GetVideosByIDsFromDB(ctx context.Context, ids []string) (_ []*domain.Video, err error) {
+	ctx, span := infraSpan(ctx, "GetVideosByIDsFromDB")
+	defer func() { endSpan(span, err) }()
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: ids is empty", domain.ErrInvalidInput)
+	}
+	if len(ids) > maxVideoIDsPerRequest {
+		return nil, fmt.Errorf("%w: ids must be %d or less", domain.ErrInvalidInput, maxVideoIDsPerRequest)
+	}
+
+	dbVideos, err := i.db.Database.GetVideosByIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, tag := range tags {
-		tagInsertResult, err := i.db.Database.CreateTags(ctx, tag)
-		if err != nil {
-			return nil, err
-		}
+	tags, err := i.getVideosTags(ctx, dbVideos)
+	if err != nil {
+		return nil, err
+	}
+
+	videoMap := make(map[string]*domain.Video, len(dbVideos))
+	for _, video := range newVideosWithTags(dbVideos, tags) {
+		videoMap[video.ID] = video
+	}
+
+	videos := make([]*domain.Video, len(ids))
+	for index, id := range ids {
+		videos[index] = videoMap[id]
+	}
+	return videos, nil
+}
 
-		tagID, err := tagInsertResult.LastInsertId()
+func (i *Infrastructure) InsertVideo(ctx context.Context, id string, videoURL string, thumbnailImageURL string, title string, description *string, uploaderID string, tags []string, contentRating domain.ContentRating, isPrivate bool, isExternalCutout bool, isAd bool, metadata *domain.VideoMetadata, watermarkKey string, publishAt *time.Time) (_ *domain.UploadVideoResponse, err error) {
+	ctx, span := infraSpan(ctx, "InsertVideo")
+	span.SetAttributes(attribute.String("videoID", id), attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	tags, err = i.normalizeVideoTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// ウォーターマークが指定された場合はサムネイルにも入るように先に適用する
+	if watermarkKey != "" {
+		err := i.watermarkUploadedVideo(ctx, id, uploaderID, watermarkKey)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		_, err = i.db.Database.CreateVideoTags(ctx, sqlc.CreateVideoTagsParams{
-			VideoID: id,
-			TagID:   int32(tagID),
-		})
+	// サムネイルが指定されなかった場合は動画から生成する
+	if thumbnailImageURL == "" {
+		atSecond := min(defaultThumbnailSecond, int(metadata.Duration/time.Second))
+		generatedURL, err := i.GenerateThumbnail(ctx, videoURL, atSecond)
 		if err != nil {
 			return nil, err
 		}
+		thumbnailImageURL = generatedURL
 	}
 
-	return &domain.UploadVideoResponse{
+	// サーバー上で作った動画はアップロードの確認を通らないため、登録と同じトランザクションで容量を確認して使用量を増やす
+	return i.createVideo(ctx, id, videoURL, thumbnailImageURL, title, description, uploaderID, tags, contentRating, isPrivate, isExternalCutout, isAd, metadata, publishAt, domain.StatusReady, true)
+}
+
+// 同じIDの動画が他のユーザーによって登録されていた場合はErrPermissionDeniedを返す
+// 再送されたアップロードでファイルを書き込む前に確認する
+func (i *Infrastructure) CheckVideoUploader(ctx context.Context, videoID, uploaderID string) (err error) {
+	ctx, span := infraSpan(ctx, "CheckVideoUploader")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.videoRegisteredBy(ctx, videoID, uploaderID)
+	return err
+}
+
+// 同じユーザーが同じIDの動画を登録済みの場合は再送されたアップロードとしてtrueを返す
+// 他のユーザーによって登録されていた場合はErrPermissionDeniedを返す
+func (i *Infrastructure) IsRetriedUpload(ctx context.Context, videoID, uploaderID string) (_ bool, err error) {
+	ctx, span := infraSpan(ctx, "IsRetriedUpload")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	return i.videoRegisteredBy(ctx, videoID, uploaderID)
+}
+
+func (i *Infrastructure) videoRegisteredBy(ctx context.Context, videoID, uploaderID string) (bool, error) {
+	// 削除された動画のIDも使わせない
+	existing, err := i.db.Database.GetVideoUploaderID(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if existing != uploaderID {
+		return false, fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, uploaderID, videoID)
+	}
+	return true, nil
+}
+
+// 変換前の動画を処理待ちの状態で登録する
+// ウォーターマークやサムネイルの生成は変換後にEnqueueVideoProcessingJobのワーカーで行う
+func (i *Infrastructure) InsertPendingVideo(ctx context.Context, id string, thumbnailImageURL string, title string, description *string, uploaderID string, tags []string, contentRating domain.ContentRating, isPrivate bool, isExternalCutout bool, isAd bool, metadata *domain.VideoMetadata, publishAt *time.Time) (_ *domain.UploadVideoResponse, err error) {
+	ctx, span := infraSpan(ctx, "InsertPendingVideo")
+	span.SetAttributes(attribute.String("videoID", id), attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	tags, err = i.normalizeVideoTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.createVideo(ctx, id, i.videoPlaylistURL(id), thumbnailImageURL, title, description, uploaderID, tags, contentRating, isPrivate, isExternalCutout, isAd, metadata, publishAt, domain.StatusPending, false)
+}
+
+// タグを正規化し、長さと数を確認する
+// 1つずつINSERTするため、数はDBに書き込む前に制限する
+func (i *Infrastructure) normalizeVideoTags(tags []string) ([]string, error) {
+	tags = domain.NormalizeTags(tags)
+	err := domain.ValidateTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	limit := i.config.MaxTagsPerVideo
+	if limit <= 0 {
+		limit = defaultMaxTagsPerVideo
+	}
+	if int64(len(tags)) > limit {
+		return nil, fmt.Errorf("%w: %d tags, up to %d", domain.ErrTooManyTags, len(tags), limit)
+	}
+	return tags, nil
+}
+
+func (i *Infrastructure) createVideo(ctx context.Context, id string, videoURL string, thumbnailImageURL string, title string, description *string, uploaderID string, tags []string, contentRating domain.ContentRating, isPrivate bool, isExternalCutout bool, isAd bool, metadata *domain.VideoMetadata, publishAt *time.Time, status domain.ProcessingStatus, chargeStorage bool) (*domain.UploadVideoResponse, error) {
+	// 公開予約された動画は公開時刻までStartScheduledPublisherが非公開にしておく
+	var dbPublishAt sql.NullTime
+	if publishAt != nil && publishAt.After(time.Now()) {
+		isPrivate = true
+		dbPublishAt = sql.NullTime{Time: *publishAt, Valid: true}
+	}
+
+	audioTracks, err := marshalAudioTracks(metadata.AudioTracks)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.UploadVideoResponse{
 		ID:                id,
 		VideoURL:          videoURL,
 		ThumbnailImageURL: thumbnailImageURL,
@@ -166,17 +491,132 @@ func (i *Infrastructure) InsertVideo(ctx context.Context, id string, videoURL st
 		Description:       description,
 		UploaderID:        uploaderID,
 		Tags:              tags,
-		IsAdult:           isAdult,
+		ContentRating:     contentRating,
 		IsPrivate:         isPrivate,
 		IsExternalCutout:  isExternalCutout,
 		IsAd:              isAd,
+		Duration:          metadata.Duration,
+		Width:             metadata.Width,
+		Height:            metadata.Height,
+		Bitrate:           metadata.Bitrate,
+		ProcessingStatus:  status,
+		PublishAt:         publishAt,
+		IsNew:             true,
 		// CreatedAt:         time.Now(),
-	}, nil
+	}
+
+	// タグの登録に失敗した場合に、タグが欠けた動画が残らないようにする
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		result, err := q.CreateVideo(ctx, sqlc.CreateVideoParams{
+			ID:                id,
+			VideoUrl:          videoURL,
+			ThumbnailImageUrl: thumbnailImageURL,
+			Title:             title,
+			Description: sql.NullString{
+				String: *description,
+				Valid:  description != nil,
+			},
+			UploaderID:       uploaderID,
+			IsPrivate:        isPrivate,
+			ContentRating:    string(contentRating),
+			IsExternalCutout: isExternalCutout,
+			IsAd:             isAd,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
+			WatchCount:       0,
+			DurationMs:       metadata.Duration.Milliseconds(),
+			Width:            int32(metadata.Width),
+			Height:           int32(metadata.Height),
+			Bitrate:          metadata.Bitrate,
+			ProcessingStatus: string(status),
+			PublishAt:        dbPublishAt,
+			FileSizeBytes:    metadata.Size,
+			AudioTracks:      audioTracks,
+		})
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n != 1 {
+			// 再送された登録。タグは最初の登録と同じトランザクションで付けている
+			response.IsNew = false
+			video, err := q.GetVideoForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if video.UploaderID != uploaderID {
+				return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, uploaderID, id)
+			}
+			// 最初の登録の変換が終わっていない場合は呼び出し側でジョブを入れ直す
+			response.ProcessingStatus = domain.ProcessingStatus(video.ProcessingStatus)
+			return nil
+		}
+		err = insertVideoTags(ctx, q, id, tags)
+		if err != nil {
+			return err
+		}
+		if chargeStorage {
+			err = i.chargeUserStorage(ctx, q, uploaderID, metadata.Size)
+			if err != nil {
+				return err
+			}
+		}
+		return recordVideoAuditLog(ctx, q, id, domain.AuditActionInsertVideo, uploaderID, nil, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.IsNew && chargeStorage {
+		err = i.redis.Del(ctx, storageQuotaKey(uploaderID)).Err()
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to delete storage quota cache", "userID", uploaderID, "error", err)
+		}
+	}
+
+	// 索引は起動時に作り直すため、更新に失敗しても動画の登録は失敗にしない
+	if response.IsNew {
+		err = i.addTagsToAutocompleteIndex(ctx, tags)
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to update tag autocomplete index", "videoID", id, "error", err)
+		}
+	}
+
+	return response, nil
+}
+
+// トランザクションは1つの接続を使うため、タグは順番に登録する
+// 同じ名前のタグを同時に登録してもUpsertTagで既存の行のIDが返る
+func insertVideoTags(ctx context.Context, q *sqlc.Queries, videoID string, tags []string) error {
+	for _, tag := range tags {
+		result, err := q.UpsertTag(ctx, tag)
+		if err != nil {
+			return err
+		}
+		tagID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateVideoTags(ctx, sqlc.CreateVideoTagsParams{
+			VideoID: videoID,
+			TagID:   int32(tagID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (i *Infrastructure) GetWatchCount(ctx context.Context, videoID string) (int, error) {
+func (i *Infrastructure) GetWatchCount(ctx context.Context, videoID string) (_ int, err error) {
+	ctx, span := infraSpan(ctx, "GetWatchCount")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
 	var watchCountJson WatchCountJsonType
-	hit, err := getFromRedis(ctx, i.redis, "watchcount"+domain.IDSeparator+videoID, &watchCountJson)
+	hit, err := i.getFromCache(ctx, "watch_count", "watchcount"+domain.IDSeparator+videoID, &watchCountJson)
 	if err != nil {
 		return 0, err
 	} else if hit {
@@ -198,11 +638,23 @@ func (i *Infrastructure) GetWatchCount(ctx context.Context, videoID string) (int
 	return int(watchCount), nil
 }
 
-func (i *Infrastructure) IncrementWatchCount(ctx context.Context, videoID, userID string) (int, error) {
-	_, err := i.db.Database.IncrementWatchCount(ctx, videoID)
+// 視聴のたびにDBに書き込まないようにRedisに溜めておき、StartWatchCountFlusherでまとめてDBに反映する
+func (i *Infrastructure) IncrementWatchCount(ctx context.Context, videoID, userID string) (_ int, err error) {
+	ctx, span := infraSpan(ctx, "IncrementWatchCount")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	pending, err := i.redis.IncrBy(ctx, pendingWatchCountKey(videoID), 1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// HyperLogLogが変わった場合は新しい視聴者
+	added, err := i.redis.PFAdd(ctx, uniqueViewerKey(videoID), userID).Result()
 	if err != nil {
 		return 0, err
 	}
+	i.recordMetrics().RecordWatchCountIncrement(WatchCountLabels{NewViewer: added == 1})
 
 	watchCount, err := i.db.Database.GetWatchCount(ctx, videoID)
 	if err != nil {
@@ -210,7 +662,7 @@ func (i *Infrastructure) IncrementWatchCount(ctx context.Context, videoID, userI
 	}
 
 	watchCountJsonType := WatchCountJsonType{
-		Count: int(watchCount),
+		Count: int(watchCount) + int(pending),
 	}
 
 	err = setToRedis(ctx, i.redis, videoID+domain.IDSeparator+userID, 24*time.Hour, &watchCountJsonType)
@@ -218,14 +670,24 @@ func (i *Infrastructure) IncrementWatchCount(ctx context.Context, videoID, userI
 		return 0, err
 	}
 
-	return int(watchCount), nil
+	// GetWatchCountが古い値を返さないようにキャッシュも更新する
+	err = setToRedis(ctx, i.redis, "watchcount"+domain.IDSeparator+videoID, 1*time.Hour, &watchCountJsonType)
+	if err != nil {
+		return 0, err
+	}
+
+	return watchCountJsonType.Count, nil
 }
 
-func (i *Infrastructure) ChechWatchCount(ctx context.Context, videoID, userID string) (bool, error) {
+func (i *Infrastructure) ChechWatchCount(ctx context.Context, videoID, userID string) (_ bool, err error) {
+	ctx, span := infraSpan(ctx, "ChechWatchCount")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
 	key := videoID + domain.IDSeparator + userID
 
 	var watchCountJson WatchCountJsonType
-	hit, err := getFromRedis(ctx, i.redis, key, &watchCountJson)
+	hit, err := i.getFromCache(ctx, "watched", key, &watchCountJson)
 	if err != nil {
 		return false, err
 	}
@@ -235,34 +697,169 @@ func (i *Infrastructure) ChechWatchCount(ctx context.Context, videoID, userID st
 	return true, nil
 }
 
-func (i *Infrastructure) CutVideo(ctx context.Context, videoID, userID string, start, end int) (string, error) {
-	const bucketName = "video"
-	err := os.MkdirAll("cut-video", 0755)
+// ユニーク視聴者数はHyperLogLogで数える
+// ChechWatchCountのキー(<videoID>_<userID>)は再生回数を加算するかの判定に使う正確な値だが、
+// ユーザーごとにキーを持つため人気の動画ほどメモリを使い、24時間で消えるので累計の視聴者数には使えない
+// HyperLogLogは動画ごとに最大12KB程度で済む代わりに約0.81%の誤差があるため、表示用途に限って使う
+func uniqueViewerKey(videoID string) string {
+	return "videounique:" + videoID
+}
+
+func (i *Infrastructure) GetUniqueViewerCount(ctx context.Context, videoID string) (_ int64, err error) {
+	ctx, span := infraSpan(ctx, "GetUniqueViewerCount")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	return i.redis.PFCount(ctx, uniqueViewerKey(videoID)).Result()
+}
+
+// 視聴時間も再生回数と同じくRedisに溜めておき、StartWatchCountFlusherでまとめてDBに反映する
+func (i *Infrastructure) RecordWatchDuration(ctx context.Context, videoID, userID string, durationSeconds int) (err error) {
+	ctx, span := infraSpan(ctx, "RecordWatchDuration")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	if durationSeconds < 0 {
+		return fmt.Errorf("%w: duration must not be negative", domain.ErrInvalidInput)
+	}
+
+	return i.redis.IncrBy(ctx, pendingWatchDurationKey(videoID), int64(durationSeconds)).Err()
+}
+
+// 平均視聴時間(秒)を返す
+// まだDBに反映していない分も含めて計算する
+func (i *Infrastructure) GetAverageWatchDuration(ctx context.Context, videoID string) (_ float64, err error) {
+	ctx, span := infraSpan(ctx, "GetAverageWatchDuration")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	stats, err := i.db.Database.GetWatchStats(ctx, videoID)
+	if err != nil {
+		return 0, err
+	}
+
+	pendingCount, err := i.getPendingCount(ctx, pendingWatchCountKey(videoID))
+	if err != nil {
+		return 0, err
+	}
+	pendingDuration, err := i.getPendingCount(ctx, pendingWatchDurationKey(videoID))
+	if err != nil {
+		return 0, err
+	}
+
+	watchCount := int64(stats.WatchCount) + pendingCount
+	if watchCount == 0 {
+		return 0, nil
+	}
+	return float64(stats.WatchDurationTotal+pendingDuration) / float64(watchCount), nil
+}
+
+// テストで失敗を注入できるように差し替え可能にしている
+var (
+	cutVideoFFmpeg = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+		return i.ffmpegFromObjectURL(ctx, "cut", url, args)
+	}
+	cutVideoUpload = (*Infrastructure).uploadObject
+)
+
+func (i *Infrastructure) CutVideo(ctx context.Context, videoID, userID string, start, end int, options domain.CutOptions) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "CutVideo")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	err = options.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	// 同時に実行されたリクエストと作業ファイルが衝突しないようにリクエストごとにディレクトリを作る
+	tempDir, err := os.MkdirTemp("", "cut-video-")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp dir: %w", err)
 	}
+	defer os.RemoveAll(tempDir)
+
+	key := videoID + domain.IDSeparator + domain.NewUUID() + "." + options.Format
+	outPath := filepath.Join(tempDir, key)
+	url := i.videoPlaylistURL(videoID)
+
+	// exec.CommandContextはcontextが終了するとプロセスをKillする
+	// 途中まで書き込まれた出力ファイルはtempDirごと削除される
+	ffmpegCtx := ctx
+	if i.config.FFmpegTimeout > 0 {
+		var cancel context.CancelFunc
+		ffmpegCtx, cancel = context.WithTimeout(ctx, i.config.FFmpegTimeout)
+		defer cancel()
+	}
+	var contentType string
+	if options.AccurateSeeking {
+		contentType, err = i.cutVideoAccurate(ffmpegCtx, url, tempDir, outPath, start, end, options)
+	} else {
+		contentType, err = i.cutVideoFast(ffmpegCtx, url, outPath, start, end, options)
+	}
+	if err != nil {
+		if ffmpegCtx.Err() != nil {
+			return "", fmt.Errorf("%w: %v", domain.ErrFFmpegTimeout, ffmpegCtx.Err())
+		}
+		return "", err
+	}
 
-	key := videoID + domain.IDSeparator + domain.NewUUID() + ".mp4"
-	outPath := "cut-video" + "/" + key
-	url := fmt.Sprintf("%s/%s/output_%s.m3u8", os.Getenv("AWS_S3_URL"), bucketName, videoID)
+	err = cutVideoUpload(i, ctx, outPath, cutVideoBucketName, key, contentType)
+	if err != nil {
+		return "", err
+	}
+	return i.objectURL(cutVideoBucketName, key), nil
+}
 
-	cmd := exec.Command("ffmpeg", "-ss", fmt.Sprintf("%d", start), "-i", url, "-to", fmt.Sprintf("%d", end-start), "-c", "copy", outPath)
+// -iより前に-ssを置いてコンテナ単位でシークする
+// 速いが開始位置がキーフレームからずれると先頭のフレームが乱れることがある
+func (i *Infrastructure) cutVideoFast(ctx context.Context, url, outPath string, start, end int, options domain.CutOptions) (string, error) {
+	inputArgs, codecArgs, contentType := i.cutVideoFormatArgs(options)
+	args := append([]string{"-ss", fmt.Sprintf("%d", start)}, inputArgs...)
+	args = append(args, "-i", ffmpegInput, "-to", fmt.Sprintf("%d", end-start))
+	args = append(args, codecArgs...)
+	args = append(args, outPath)
+	_, err := cutVideoFFmpeg(i, ctx, url, args)
+	if err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
 
-	log.Println(cmd.Args)
-	result, err := cmd.CombinedOutput()
-	log.Println(string(result))
+// 1回目は開始位置より少し前から-c copyで粗く切り出し、
+// 2回目でその中のキーフレームからデコードして再エンコードすることで開始位置を正確に合わせる
+func (i *Infrastructure) cutVideoAccurate(ctx context.Context, url, tempDir, outPath string, start, end int, options domain.CutOptions) (string, error) {
+	roughStart := max(start-accurateSeekMargin, 0)
+	roughPath := filepath.Join(tempDir, "rough.mp4")
+	_, err := cutVideoFFmpeg(i, ctx, url, []string{"-ss", fmt.Sprintf("%d", roughStart), "-i", ffmpegInput, "-to", fmt.Sprintf("%d", end-roughStart), "-c", "copy", roughPath})
 	if err != nil {
-		return "", fmt.Errorf("failed to execute ffmpeg command: %w", err)
+		return "", err
 	}
 
-	uploadbucketName := "cut-video"
-	err = uploadVideoForS3(outPath, uploadbucketName)
+	options.ReEncode = true
+	_, codecArgs, contentType := i.cutVideoFormatArgs(options)
+	args := []string{"-i", ffmpegInput, "-ss", fmt.Sprintf("%d", start-roughStart), "-to", fmt.Sprintf("%d", end-roughStart)}
+	args = append(args, codecArgs...)
+	args = append(args, outPath)
+	_, err = cutVideoFFmpeg(i, ctx, roughPath, args)
 	if err != nil {
 		return "", err
 	}
+	return contentType, nil
+}
 
-	cutURL := fmt.Sprintf("%s/%s/%s", os.Getenv("AWS_S3_URL"), uploadbucketName, key)
-	return cutURL, nil
+// 出力形式に応じたffmpegの入力側と出力側の引数、アップロード時のContent-Typeを返す
+func (i *Infrastructure) cutVideoFormatArgs(options domain.CutOptions) ([]string, []string, string) {
+	switch {
+	case options.Format == domain.CutFormatWebM:
+		// WebMにはH.264をそのままコピーできないため常に再エンコードする
+		return nil, []string{"-c:v", "libvpx-vp9", "-c:a", "libopus"}, "video/webm"
+	case options.ReEncode:
+		return nil, nil, "video/mp4"
+	default:
+		transcoder := i.config.Transcoder
+		return transcoder.inputArgs(), transcoder.codecArgs(), "video/mp4"
+	}
 }
 
 func (i *Infrastructure) ValidationVideo(video io.ReadSeeker) error {
@@ -270,26 +867,72 @@ func (i *Infrastructure) ValidationVideo(video io.ReadSeeker) error {
 		return fmt.Errorf("video is nil")
 	}
 
-	// MP4ファイルのシグネチャとして 'ftyp' を確認
-	const ftyp = "ftyp"
-
-	// 先頭の12バイトだけ読み込む（ftypボックスの確認に十分な範囲）
-	header := make([]byte, 12)
-	_, err := video.Read(header)
+	// ヘッダを読む前に途中で切れているようなファイルを弾く
+	size, err := video.Seek(0, io.SeekEnd)
 	if err != nil {
 		return err
 	}
+	if size <= minVideoFileSize {
+		return fmt.Errorf("%w: file size %d bytes is too small", domain.ErrInvalidVideo, size)
+	}
 
-	// ReadSeekerを先頭に戻す
-	_, err = video.Seek(0, io.SeekStart)
+	// DetectMediaFormatが先頭に戻してから読み、読んだ後も先頭に戻す
+	format, err := DetectMediaFormat(video)
 	if err != nil {
 		return err
 	}
+	if !format.IsSupportedVideo() {
+		return domain.NewErrUnsupportedFormat(string(format))
+	}
+
+	return nil
+}
+
+// maxBytesを超える動画を弾く
+// Seekできる場合は先にサイズを確認し、できない場合は読み進めた時点で超過を検知する
+func (i *Infrastructure) ValidateVideoSize(ctx context.Context, r io.Reader, maxBytes int64) (_ io.Reader, err error) {
+	ctx, span := infraSpan(ctx, "ValidateVideoSize")
+	defer func() { endSpan(span, err) }()
+
+	if r == nil {
+		return nil, fmt.Errorf("video is nil")
+	}
 
-	// ヘッダの4バイト目から'ftyp'が存在するかチェック
-	if bytes.Contains(header[4:], []byte(ftyp)) {
-		return nil
+	if seeker, ok := r.(io.Seeker); ok {
+		size, err := seeker.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		_, err = seeker.Seek(0, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+		if size > maxBytes {
+			return nil, fmt.Errorf("%w: %d bytes exceeds %d bytes", domain.ErrFileTooLarge, size, maxBytes)
+		}
+		return r, nil
 	}
 
-	return fmt.Errorf("invalid video file")
+	// 上限を1バイト超えて読めたら超過とみなす
+	return &sizeLimitedReader{
+		r:        &io.LimitedReader{R: r, N: maxBytes + 1},
+		maxBytes: maxBytes,
+	}, nil
+}
+
+type sizeLimitedReader struct {
+	r        *io.LimitedReader
+	maxBytes int64
+}
+
+func (s *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if s.r.N <= 0 {
+		// 超過分の1バイトは呼び出し側に渡さない
+		if n > 0 {
+			n--
+		}
+		return n, fmt.Errorf("%w: exceeds %d bytes", domain.ErrFileTooLarge, s.maxBytes)
+	}
+	return n, err
 }
@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const videoAnalysisCacheTTL = 1 * time.Hour
+
+func videoAnalysisKey(videoID string) string {
+	return "analysis" + domain.IDSeparator + videoID
+}
+
+type ffprobeAnalysisOutput struct {
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		RFrameRate   string `json:"r_frame_rate"`
+		SampleRate   string `json:"sample_rate"`
+	} `json:"streams"`
+}
+
+// テストでffprobeの実行を差し替えられるようにしている
+var probeVideoURL = (*Infrastructure).ffprobeFromObjectURL
+
+// 保存されている動画をffprobeで調べる
+// 結果はDBに保存し、Redisを消しても調べ直さなくて済むようにする
+func (i *Infrastructure) AnalyzeVideo(ctx context.Context, videoID string) (_ *domain.VideoAnalysis, err error) {
+	ctx, span := infraSpan(ctx, "AnalyzeVideo")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	var analysis domain.VideoAnalysis
+	hit, err := i.getFromCache(ctx, "video_analysis", videoAnalysisKey(videoID), &analysis)
+	if err != nil {
+		return nil, err
+	} else if hit {
+		return &analysis, nil
+	}
+
+	video, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.Analysis.Valid && video.Analysis.String != "" {
+		err = json.Unmarshal([]byte(video.Analysis.String), &analysis)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		output, err := probeVideoURL(i, ctx, video.VideoUrl, []string{"-v", "quiet", "-print_format", "json", "-show_streams", "-show_format"})
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseVideoAnalysis(output)
+		if err != nil {
+			return nil, err
+		}
+		analysis = *parsed
+
+		bytes, err := json.Marshal(analysis)
+		if err != nil {
+			return nil, err
+		}
+		err = i.updateVideoAsSystem(ctx, videoID, domain.AuditActionAnalyzeVideo, func(q *sqlc.Queries) error {
+			_, err := q.UpdateVideoAnalysis(ctx, sqlc.UpdateVideoAnalysisParams{
+				Analysis: sql.NullString{String: string(bytes), Valid: true},
+				ID:       videoID,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = setToRedis(ctx, i.redis, videoAnalysisKey(videoID), videoAnalysisCacheTTL, &analysis)
+	if err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// ffmpegFromObjectURLと同じく、HLSは署名せずにそのまま渡す
+func (i *Infrastructure) ffprobeFromObjectURL(ctx context.Context, url string, args []string) ([]byte, error) {
+	input, err := i.ffmpegInputURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return runFFprobe(ctx, append(args, input)...)
+}
+
+// 映像と音声はそれぞれ最初のストリームの情報を使う
+func parseVideoAnalysis(output []byte) (*domain.VideoAnalysis, error) {
+	var probe ffprobeAnalysisOutput
+	err := json.Unmarshal(output, &probe)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse ffprobe output: %v", domain.ErrInvalidVideo, err)
+	}
+
+	seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid duration %q", domain.ErrInvalidVideo, probe.Format.Duration)
+	}
+	analysis := &domain.VideoAnalysis{Duration: time.Duration(seconds * float64(time.Second))}
+	// HLSのプレイリストなどではビットレートが出力されないことがある
+	analysis.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+
+	hasVideo, hasAudio := false, false
+	for _, stream := range probe.Streams {
+		switch {
+		case stream.CodecType == "video" && !hasVideo:
+			hasVideo = true
+			analysis.VideoCodec = stream.CodecName
+			analysis.Width = stream.Width
+			analysis.Height = stream.Height
+			analysis.FrameRate = parseFrameRate(stream.AvgFrameRate)
+			if analysis.FrameRate == 0 {
+				analysis.FrameRate = parseFrameRate(stream.RFrameRate)
+			}
+		case stream.CodecType == "audio" && !hasAudio:
+			hasAudio = true
+			analysis.AudioCodec = stream.CodecName
+			analysis.AudioSampleRate, _ = strconv.Atoi(stream.SampleRate)
+		}
+	}
+	if !hasVideo {
+		return nil, fmt.Errorf("%w: video stream is missing", domain.ErrInvalidVideo)
+	}
+	return analysis, nil
+}
+
+// ffprobeのフレームレートは"30000/1001"のような分数で出力される
+func parseFrameRate(s string) float64 {
+	num, den, ok := strings.Cut(s, "/")
+	if !ok {
+		rate, _ := strconv.ParseFloat(s, 64)
+		return rate
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
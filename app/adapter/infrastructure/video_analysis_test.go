@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の解析結果の解析(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    *domain.VideoAnalysis
+		wantErr error
+	}{
+		{
+			name:   "映像と音声",
+			output: `{"streams": [{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "avg_frame_rate": "30000/1001", "r_frame_rate": "30000/1001"}, {"codec_type": "audio", "codec_name": "aac", "sample_rate": "48000"}], "format": {"duration": "12.500000", "bit_rate": "4500000"}}`,
+			want:   &domain.VideoAnalysis{Duration: 12500 * time.Millisecond, Bitrate: 4500000, Width: 1920, Height: 1080, FrameRate: 30000.0 / 1001, VideoCodec: "h264", AudioCodec: "aac", AudioSampleRate: 48000},
+		},
+		{
+			name:   "音声なし",
+			output: `{"streams": [{"codec_type": "video", "codec_name": "vp9", "width": 640, "height": 360, "avg_frame_rate": "0/0", "r_frame_rate": "25/1"}], "format": {"duration": "3.0"}}`,
+			want:   &domain.VideoAnalysis{Duration: 3 * time.Second, Width: 640, Height: 360, FrameRate: 25, VideoCodec: "vp9"},
+		},
+		{
+			name:    "映像なし",
+			output:  `{"streams": [{"codec_type": "audio", "codec_name": "aac", "sample_rate": "48000"}], "format": {"duration": "3.0"}}`,
+			wantErr: domain.ErrInvalidVideo,
+		},
+		{
+			name:    "JSONではない",
+			output:  `Invalid data found when processing input`,
+			wantErr: domain.ErrInvalidVideo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVideoAnalysis([]byte(tt.output))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseVideoAnalysis() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVideoAnalysis() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_動画の解析(t *testing.T) {
+	stored := `{"duration":3000000000,"bitrate":64000,"width":640,"height":360,"frame_rate":25,"video_codec":"vp9","audio_codec":"","audio_sample_rate":0}`
+	tests := []struct {
+		name          string
+		analysis      driver.Value
+		wantProbe     int
+		wantExecNames []string
+		want          *domain.VideoAnalysis
+	}{
+		{
+			name:          "ffprobeで調べてDBに保存する",
+			analysis:      nil,
+			wantProbe:     1,
+			wantExecNames: []string{"UpdateVideoAnalysis", "CreateAuditLog"},
+			want:          &domain.VideoAnalysis{Duration: 12500 * time.Millisecond, Bitrate: 4500000, Width: 1920, Height: 1080, FrameRate: 30, VideoCodec: "h264", AudioCodec: "aac", AudioSampleRate: 44100},
+		},
+		{
+			name:     "DBに保存された結果を使う",
+			analysis: stored,
+			want:     &domain.VideoAnalysis{Duration: 3 * time.Second, Bitrate: 64000, Width: 640, Height: 360, FrameRate: 25, VideoCodec: "vp9"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probed := 0
+			original := probeVideoURL
+			t.Cleanup(func() { probeVideoURL = original })
+			probeVideoURL = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+				probed++
+				return []byte(`{"streams": [{"codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "avg_frame_rate": "30/1"}, {"codec_type": "audio", "codec_name": "aac", "sample_rate": "44100"}], "format": {"duration": "12.5", "bit_rate": "4500000"}}`), nil
+			}
+
+			_, client := newTestRedis(t)
+			row := relatedVideoRow("video_1", 0, "")
+			row = row[:len(row)-2]
+			row[len(row)-5] = tt.analysis
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row}, "GetVideoForUpdate": {row}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, redis: client}
+
+			// 2回目はRedisから返す
+			for n := 0; n < 2; n++ {
+				got, err := i.AnalyzeVideo(context.Background(), "video_1")
+				if err != nil {
+					t.Fatalf("Infrastructure.AnalyzeVideo() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Infrastructure.AnalyzeVideo() = %+v, want %+v", got, tt.want)
+				}
+			}
+			if probed != tt.wantProbe {
+				t.Errorf("ffprobe was run %d times, want %d", probed, tt.wantProbe)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+		})
+	}
+}
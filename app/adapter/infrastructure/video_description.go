@@ -0,0 +1,103 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 言語ごとの説明を保存する。既にある場合は上書きする
+// 監査ログには変更の前後の言語ごとの説明を残す
+func (i *Infrastructure) SetVideoDescription(ctx context.Context, videoID, language, text, requestingUserID string) (err error) {
+	ctx, span := infraSpan(ctx, "SetVideoDescription")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("language", language), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	language, err = domain.NormalizeLanguage(language)
+	if err != nil {
+		return err
+	}
+	_, err = i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return err
+	}
+
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		rows, err := q.GetVideoDescriptions(ctx, videoID)
+		if err != nil {
+			return err
+		}
+		_, err = q.UpsertVideoDescription(ctx, sqlc.UpsertVideoDescriptionParams{
+			VideoID:     videoID,
+			Language:    language,
+			Description: text,
+		})
+		if err != nil {
+			return err
+		}
+
+		before := make(map[string]string, len(rows))
+		after := make(map[string]string, len(rows)+1)
+		for _, row := range rows {
+			before[row.Language] = row.Description
+			after[row.Language] = row.Description
+		}
+		after[language] = text
+		return recordVideoAuditLog(ctx, q, videoID, domain.AuditActionSetVideoDescription, requestingUserID, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
+
+// 指定された言語の説明がない場合は投稿時の説明を返す
+func (i *Infrastructure) GetVideoDescriptionForLanguage(ctx context.Context, videoID, language string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "GetVideoDescriptionForLanguage")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("language", language))
+	defer func() { endSpan(span, err) }()
+
+	language, err = domain.NormalizeLanguage(language)
+	if err != nil {
+		return "", err
+	}
+	dbVideo, err := i.getUndeletedVideo(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+
+	video := newVideoFromDB(dbVideo)
+	video.Descriptions, err = i.getVideoDescriptions(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	return video.DescriptionForLanguage(language), nil
+}
+
+func (i *Infrastructure) getVideoDescriptions(ctx context.Context, videoID string) (map[string]string, error) {
+	rows, err := i.db.Database.GetVideoDescriptions(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	descriptions := make(map[string]string, len(rows))
+	for _, row := range rows {
+		descriptions[row.Language] = row.Description
+	}
+	return descriptions, nil
+}
+
+// 動画を読む処理はこれを使い、論理削除した動画を返さないようにする
+func (i *Infrastructure) getUndeletedVideo(ctx context.Context, videoID string) (sqlc.Video, error) {
+	video, err := i.db.Database.GetUndeletedVideo(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Video{}, fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+	}
+	return video, err
+}
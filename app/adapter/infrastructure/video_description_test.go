@@ -0,0 +1,100 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_言語ごとの説明の保存(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		rows     [][]driver.Value
+		wantArgs []driver.Value
+		wantErr  error
+	}{
+		{name: "言語タグは正規の形にする", language: "en-us", wantArgs: []driver.Value{"video_1", "en-US", "description"}},
+		{name: "不正な言語", language: "not a language", wantErr: domain.ErrInvalidInput},
+		{name: "動画がない", language: "en", rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo":    rows,
+				"GetVideoDescriptions": {{"ja", "説明"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.SetVideoDescription(context.Background(), "video_1", tt.language, "description", "user_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SetVideoDescription() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("execs = %v, want none", connector.execs)
+				}
+				return
+			}
+			if want := []string{"UpsertVideoDescription", "CreateAuditLog"}; !reflect.DeepEqual(connector.execNames, want) {
+				t.Fatalf("exec names = %v, want %v", connector.execNames, want)
+			}
+			if !reflect.DeepEqual(connector.execs[0], tt.wantArgs) {
+				t.Errorf("UpsertVideoDescription args = %v, want %v", connector.execs[0], tt.wantArgs)
+			}
+			// 他の言語の説明も含めて変更の前後を残す
+			want := []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionSetVideoDescription, "user_1", `{"ja":"説明"}`, `{"en-US":"description","ja":"説明"}`}
+			if got := connector.execs[1][:6]; !reflect.DeepEqual(got, want) {
+				t.Errorf("CreateAuditLog args = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_言語ごとの説明の取得(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{name: "指定した言語", language: "en", want: "English"},
+		{name: "地域を除いた言語", language: "en-US", want: "English"},
+		{name: "ない場合は元の説明", language: "fr", want: "元の説明"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			row = row[:len(row)-2]
+			row[4] = "元の説明"
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo":    {row},
+				"GetVideoDescriptions": {{"en", "English"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			got, err := i.GetVideoDescriptionForLanguage(context.Background(), "video_1", tt.language)
+			if err != nil {
+				t.Fatalf("Infrastructure.GetVideoDescriptionForLanguage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.GetVideoDescriptionForLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
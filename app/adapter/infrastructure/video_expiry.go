@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// checkIntervalごとに公開期限を過ぎた動画を非公開にする
+func (i *Infrastructure) StartExpiryEnforcer(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := i.expireVideos(ctx, time.Now())
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to expire videos", "error", err)
+			}
+		}
+	}
+}
+
+// nowまでに公開期限を過ぎた動画を非公開にし、非公開にした動画のIDを返す
+// expires_atは残すため、再び公開するには公開期限を変更する必要がある
+func (i *Infrastructure) expireVideos(ctx context.Context, now time.Time) ([]string, error) {
+	videoIDs, err := i.db.Database.ListExpiredVideos(ctx, sql.NullTime{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make([]string, 0, len(videoIDs))
+	defer func() {
+		if len(expired) > 0 {
+			i.invalidateVideoCache(ctx, expired...)
+		}
+	}()
+	for _, videoID := range videoIDs {
+		err := i.updateVideoAsSystem(ctx, videoID, domain.AuditActionExpireVideo, func(q *sqlc.Queries) error {
+			result, err := q.ExpireVideo(ctx, sqlc.ExpireVideoParams{
+				UpdatedAt: now,
+				ID:        videoID,
+			})
+			if err != nil {
+				return err
+			}
+			return requireVideoUpdated(result)
+		})
+		// 他のサーバーが先に非公開にした場合や削除された場合は何もしない
+		if errors.Is(err, errVideoNotUpdated) || errors.Is(err, domain.ErrVideoNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		i.log().InfoContext(ctx, "video expired and made private", "videoID", videoID)
+		expired = append(expired, videoID)
+	}
+	return expired, nil
+}
+
+// expiresAtがnilの場合は公開期限をなくす
+// 投稿者本人か管理者のみ変更できる
+func (i *Infrastructure) UpdateVideoExpiry(ctx context.Context, videoID, requestingUserID string, expiresAt *time.Time) (err error) {
+	ctx, span := infraSpan(ctx, "UpdateVideoExpiry")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("requestingUserID", requestingUserID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.getVideoForDelete(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	if video.UploaderID != requestingUserID && !i.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, requestingUserID, videoID)
+	}
+
+	var dbExpiresAt sql.NullTime
+	if expiresAt != nil {
+		dbExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+	err = i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		return updateVideoWithAuditLog(ctx, q, videoID, domain.AuditActionUpdateVideoExpiry, requestingUserID, func(sqlc.Video) error {
+			_, err := q.UpdateVideoExpiresAt(ctx, sqlc.UpdateVideoExpiresAtParams{
+				ExpiresAt: dbExpiresAt,
+				UpdatedAt: time.Now(),
+				ID:        videoID,
+			})
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	i.invalidateVideoCache(ctx, videoID)
+	return nil
+}
@@ -0,0 +1,141 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// expires_atを指定した動画の行
+func expiryTestVideoRow(expiresAt driver.Value) []driver.Value {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[len(row)-16] = expiresAt
+	return row
+}
+
+func Test_公開期限を過ぎた動画の取得(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt driver.Value
+		wantErr   error
+	}{
+		{name: "期限なし", expiresAt: nil},
+		{name: "期限前", expiresAt: time.Now().Add(time.Hour)},
+		{name: "期限切れ", expiresAt: time.Now().Add(-time.Hour), wantErr: domain.ErrVideoExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"GetUndeletedVideo":          {expiryTestVideoRow(tt.expiresAt)},
+				"GetVideoTags":               {},
+				"GetVideoDescriptions":       {},
+				"GetSubtitleTracksByVideoID": {},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			got, err := i.GetVideoFromDB(context.Background(), "video_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.GetVideoFromDB() error = %v, want %v", err, tt.wantErr)
+			}
+			// 期限切れでも動画の情報は返す
+			if got == nil || got.ID != "video_1" {
+				t.Errorf("Infrastructure.GetVideoFromDB() = %+v, want video_1", got)
+			}
+		})
+	}
+}
+
+func Test_公開期限を過ぎた動画の非公開化(t *testing.T) {
+	connector := &rowConnector{
+		rowsByQuery: map[string][][]driver.Value{
+			"ListExpiredVideos": {{"video_1"}, {"video_2"}},
+			"GetVideoForUpdate": {expiryTestVideoRow(nil)},
+		},
+		// video_2は他のサーバーが先に非公開にした
+		affectedByArgs: func(name string, args []driver.Value) (int64, bool) {
+			if name == "ExpireVideo" && args[1] == "video_2" {
+				return 0, true
+			}
+			return 0, false
+		},
+	}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+	now := time.Now()
+	got, err := i.expireVideos(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Infrastructure.expireVideos() error = %v", err)
+	}
+	want := []string{"video_1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Infrastructure.expireVideos() = %v, want %v", got, want)
+	}
+	// 非公開にしなかった動画は監査ログを残さずに取り消す
+	wantNames := []string{"ExpireVideo", "CreateAuditLog", "ExpireVideo"}
+	if !reflect.DeepEqual(connector.execNames, wantNames) {
+		t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+	}
+	if connector.commits != 1 || connector.rollbacks != 1 {
+		t.Errorf("commits, rollbacks = %d, %d, want 1, 1", connector.commits, connector.rollbacks)
+	}
+	if action, actor := connector.execs[1][2], connector.execs[1][3]; action != domain.AuditActionExpireVideo || actor != domain.AuditActorSystem {
+		t.Errorf("audit log = %v by %v, want %v by %v", action, actor, domain.AuditActionExpireVideo, domain.AuditActorSystem)
+	}
+}
+
+func Test_公開期限の変更(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		name             string
+		requestingUserID string
+		expiresAt        *time.Time
+		wantExpiresAt    driver.Value
+		wantErr          error
+	}{
+		{name: "投稿者本人", requestingUserID: "user_1", expiresAt: &expiresAt, wantExpiresAt: expiresAt},
+		{name: "期限をなくす", requestingUserID: "user_1", expiresAt: nil, wantExpiresAt: nil},
+		{name: "管理者", requestingUserID: "admin_1", expiresAt: &expiresAt, wantExpiresAt: expiresAt},
+		{name: "他のユーザー", requestingUserID: "user_2", expiresAt: &expiresAt, wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{values: expiryTestVideoRow(nil)}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				config: InfrastructureConfig{AdminUserIDs: []string{"admin_1"}},
+			}
+
+			err := i.UpdateVideoExpiry(context.Background(), "video_1", tt.requestingUserID, tt.expiresAt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.UpdateVideoExpiry() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("Infrastructure.UpdateVideoExpiry() execs = %v, want none", connector.execs)
+				}
+				return
+			}
+			if len(connector.execs) != 2 || connector.execs[0][0] != tt.wantExpiresAt {
+				t.Fatalf("UpdateVideoExpiresAt args = %v, want expires_at %v", connector.execs, tt.wantExpiresAt)
+			}
+			if got := connector.execs[1][:4]; !reflect.DeepEqual(got, []driver.Value{domain.AuditEntityVideo, "video_1", domain.AuditActionUpdateVideoExpiry, tt.requestingUserID}) {
+				t.Errorf("CreateAuditLog args = %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 1回に確認する動画の数。S3から動画全体を取得するため少なくする
+const integrityCheckBatchSize = 10
+
+func sha256Hex(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// 保存した元の動画を取得してSHA-256を計算し直し、アップロード時の値と一致するかを返す
+func (i *Infrastructure) VerifyVideoChecksum(ctx context.Context, videoID string) (_ bool, err error) {
+	ctx, span := infraSpan(ctx, "VerifyVideoChecksum")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	video, err := i.db.Database.GetVideo(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+	}
+	if err != nil {
+		return false, err
+	}
+	if !video.Checksum.Valid {
+		return false, fmt.Errorf("%w: video %s has no checksum", domain.ErrInvalidInput, videoID)
+	}
+
+	body, err := i.storage.Download(ctx, videoSourceKey(videoID))
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	checksum, err := sha256Hex(body)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = i.db.Database.UpdateVideoChecksumVerifiedAt(ctx, sqlc.UpdateVideoChecksumVerifiedAtParams{
+		ChecksumVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:                 videoID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return checksum == video.Checksum.String, nil
+}
+
+// checkIntervalごとに最後に確認してから最も時間が経った動画を確認する
+func (i *Infrastructure) StartIntegrityChecker(ctx context.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := i.checkVideoIntegrity(ctx)
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to check video integrity", "error", err)
+			}
+		}
+	}
+}
+
+// 壊れていた動画はfailedにして再生できないようにし、そのIDを返す
+// 1つの動画の確認に失敗しても残りの動画は確認する
+func (i *Infrastructure) checkVideoIntegrity(ctx context.Context) ([]string, error) {
+	videoIDs, err := i.db.Database.ListVideosForIntegrityCheck(ctx, integrityCheckBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatched []string
+	for _, videoID := range videoIDs {
+		ok, err := i.VerifyVideoChecksum(ctx, videoID)
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to verify video checksum", "videoID", videoID, "error", err)
+			continue
+		}
+		if ok {
+			continue
+		}
+
+		i.log().ErrorContext(ctx, "video checksum mismatch", "videoID", videoID)
+		mismatched = append(mismatched, videoID)
+		err = i.UpdateVideoProcessingStatus(ctx, videoID, domain.StatusFailed, "checksum mismatch")
+		if err != nil {
+			return mismatched, err
+		}
+	}
+	return mismatched, nil
+}
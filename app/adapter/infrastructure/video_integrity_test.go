@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の整合性の確認(t *testing.T) {
+	checksum, err := sha256Hex(strings.NewReader("video"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[len(row)-13] = checksum
+
+	// video_2は保存した動画が壊れている
+	ctx := context.Background()
+	storage := NewMemoryBackend()
+	storage.Upload(ctx, videoSourceKey("video_1"), "video/mp4", strings.NewReader("video"))
+	storage.Upload(ctx, videoSourceKey("video_2"), "video/mp4", strings.NewReader("broken"))
+
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"ListVideosForIntegrityCheck": {{"video_1"}, {"video_2"}},
+		"GetVideo":                    {row},
+		"GetVideoForUpdate":           {row},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, storage: storage}
+
+	got, err := i.checkVideoIntegrity(ctx)
+	if err != nil {
+		t.Fatalf("Infrastructure.checkVideoIntegrity() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"video_2"}) {
+		t.Errorf("Infrastructure.checkVideoIntegrity() = %v, want [video_2]", got)
+	}
+
+	wantNames := []string{"UpdateVideoChecksumVerifiedAt", "UpdateVideoChecksumVerifiedAt", "UpdateVideoProcessingStatus", "CreateAuditLog"}
+	if !reflect.DeepEqual(connector.execNames, wantNames) {
+		t.Errorf("exec names = %v, want %v", connector.execNames, wantNames)
+	}
+	failed := connector.execs[2]
+	if failed[0] != string(domain.StatusFailed) || failed[len(failed)-1] != "video_2" {
+		t.Errorf("UpdateVideoProcessingStatus args = %v, want failed for video_2", failed)
+	}
+}
+
+func Test_チェックサムがない動画の確認(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	connector := &rowConnector{values: row}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	_, err := i.VerifyVideoChecksum(context.Background(), "video_1")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("Infrastructure.VerifyVideoChecksum() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+	if len(connector.execs) != 0 {
+		t.Errorf("exec names = %v, want no updates", connector.execNames)
+	}
+}
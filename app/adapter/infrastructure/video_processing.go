@@ -0,0 +1,429 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// 変換ジョブを入れるRedis Streamsのキーとコンシューマグループ
+// サーバーが再起動してもジョブが失われないようにRedisに置く
+const (
+	videoProcessingStream = "video-jobs"
+	videoProcessingGroup  = "video-workers"
+	// ReclaimStalledJobsで止まったジョブを入れ直すときに一時的に所有するコンシューマ
+	videoProcessingReclaimer = "reclaimer"
+	// XREADGROUPで待つ時間。ctxが終了してからワーカーが止まるまでの最大の時間になる
+	videoProcessingBlock = 5 * time.Second
+	// 止まったジョブを確認する間隔
+	videoProcessingReclaimInterval = time.Minute
+)
+
+// テストでffmpegとS3を使う処理を差し替えられるようにしている
+var transcodeVideo = (*Infrastructure).transcodeVideo
+
+// 変換前の動画をストレージに置くキー。再送されたアップロードと重ならないように毎回変える
+func videoProcessingSourceKey(videoID string) string {
+	return "processing/" + videoID + "/" + domain.NewUUID() + ".mp4"
+}
+
+// アップロードのレスポンスを変換の完了まで待たせないようにジョブをキューに入れる
+// job.SourceKeyのローカルのファイルは共有のストレージに移し、ジョブにはそのキーを入れる
+// 未処理のジョブが設定された数以上ある場合はErrProcessingQueueFullを返す
+// 数の確認と追加はアトミックではないため、同時に追加された分だけ超えることがある
+func (i *Infrastructure) EnqueueVideoProcessingJob(ctx context.Context, job domain.VideoProcessingJob) (err error) {
+	ctx, span := infraSpan(ctx, "EnqueueVideoProcessingJob")
+	defer func() { endSpan(span, err) }()
+
+	queued, err := i.redis.XLen(ctx, videoProcessingStream).Result()
+	if err != nil {
+		return err
+	}
+	if queued >= i.config.VideoProcessingQueueSize {
+		return domain.ErrProcessingQueueFull
+	}
+
+	// 他のインスタンスのワーカーや、再起動した後のワーカーでも読めるようにする
+	localPath := job.SourceKey
+	job.SourceKey = videoProcessingSourceKey(job.VideoID)
+	err = i.uploadLocalFile(ctx, localPath, job.SourceKey, "video/mp4")
+	if err != nil {
+		return err
+	}
+
+	err = i.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: videoProcessingStream,
+		Values: videoProcessingJobValues(job),
+	}).Err()
+	if err != nil {
+		deleteErr := i.storage.Delete(ctx, job.SourceKey)
+		if deleteErr != nil {
+			i.log().WarnContext(ctx, "failed to delete video processing source", "key", job.SourceKey, "error", deleteErr)
+		}
+		return err
+	}
+
+	// 以降はストレージのファイルを使う
+	err = os.Remove(localPath)
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to remove uploaded video", "path", localPath, "error", err)
+	}
+	return nil
+}
+
+func (i *Infrastructure) uploadLocalFile(ctx context.Context, localPath, key, contentType string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return i.storage.Upload(ctx, key, contentType, file)
+}
+
+// ストレージに置いた変換前の動画をffmpegで読めるようにローカルの一時ファイルに書き出す
+func (i *Infrastructure) downloadVideoProcessingSource(ctx context.Context, key string) (string, error) {
+	body, err := i.storage.Download(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	file, err := os.CreateTemp("", "video-source-*.mp4")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, body)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func videoProcessingJobValues(job domain.VideoProcessingJob) map[string]interface{} {
+	return map[string]interface{}{
+		"videoID":           job.VideoID,
+		"sourceKey":         job.SourceKey,
+		"watermarkKey":      job.Options.WatermarkKey,
+		"generateThumbnail": strconv.FormatBool(job.Options.GenerateThumbnail),
+	}
+}
+
+func videoProcessingJobFromValues(values map[string]interface{}) (domain.VideoProcessingJob, error) {
+	videoID, _ := values["videoID"].(string)
+	sourceKey, _ := values["sourceKey"].(string)
+	if videoID == "" || sourceKey == "" {
+		return domain.VideoProcessingJob{}, fmt.Errorf("%w: video processing job without video id or source", domain.ErrInvalidInput)
+	}
+	watermarkKey, _ := values["watermarkKey"].(string)
+	generateThumbnail, _ := values["generateThumbnail"].(string)
+
+	return domain.NewVideoProcessingJob(videoID, sourceKey, domain.TranscodeOptions{
+		WatermarkKey:      watermarkKey,
+		GenerateThumbnail: generateThumbnail == "true",
+	}), nil
+}
+
+// 処理が終わったジョブを確認応答し、未処理の数に含めないようにStreamからも消す
+func (i *Infrastructure) AcknowledgeJob(ctx context.Context, jobID string) (err error) {
+	ctx, span := infraSpan(ctx, "AcknowledgeJob")
+	defer func() { endSpan(span, err) }()
+
+	_, err = i.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, videoProcessingStream, videoProcessingGroup, jobID)
+		pipe.XDel(ctx, videoProcessingStream, jobID)
+		return nil
+	})
+	return err
+}
+
+// idleTimeoutより長く確認応答されていないジョブを新しいジョブとして入れ直し、その数を返す
+// ワーカーが処理中に落ちてもPELに残ったジョブを他のワーカーが処理できるようにする
+// 処理中のジョブを入れ直さないように、idleTimeoutは1つのジョブの処理にかかる時間より長くする
+func (i *Infrastructure) ReclaimStalledJobs(ctx context.Context, idleTimeout time.Duration) (_ int, err error) {
+	ctx, span := infraSpan(ctx, "ReclaimStalledJobs")
+	defer func() { endSpan(span, err) }()
+
+	reclaimed := 0
+	start := "0-0"
+	for {
+		messages, next, err := i.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   videoProcessingStream,
+			Group:    videoProcessingGroup,
+			MinIdle:  idleTimeout,
+			Start:    start,
+			Count:    100,
+			Consumer: videoProcessingReclaimer,
+		}).Result()
+		if err != nil {
+			return reclaimed, err
+		}
+
+		for _, message := range messages {
+			// XDELで消された後に確認応答されなかったジョブは値が空になる
+			if len(message.Values) > 0 {
+				err = i.redis.XAdd(ctx, &redis.XAddArgs{
+					Stream: videoProcessingStream,
+					Values: message.Values,
+				}).Err()
+				if err != nil {
+					return reclaimed, err
+				}
+				reclaimed++
+			}
+			err = i.AcknowledgeJob(ctx, message.ID)
+			if err != nil {
+				return reclaimed, err
+			}
+		}
+
+		if next == "0-0" {
+			return reclaimed, nil
+		}
+		start = next
+	}
+}
+
+// concurrency個のワーカーでStreamのジョブを処理し、止まったジョブを定期的に入れ直す
+// ctxが終了すると処理中のジョブを終えてから戻る
+func (i *Infrastructure) StartWorkerPool(ctx context.Context, concurrency int) error {
+	if concurrency <= 0 {
+		return fmt.Errorf("%w: concurrency must be positive", domain.ErrInvalidInput)
+	}
+
+	err := i.redis.XGroupCreateMkStream(ctx, videoProcessingStream, videoProcessingGroup, "0").Err()
+	// 既にグループがある場合はそのまま使う
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func(consumer string) {
+			defer wg.Done()
+			i.runVideoProcessingWorker(ctx, consumer)
+		}(fmt.Sprintf("%s-%d", hostname, n))
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(videoProcessingReclaimInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, err := i.ReclaimStalledJobs(ctx, i.config.VideoProcessingStalledTimeout)
+				if err != nil {
+					i.log().ErrorContext(ctx, "failed to reclaim stalled video processing jobs", "error", err)
+				}
+			}
+		}
+	}()
+
+	wg.Wait()
+	return nil
+}
+
+func (i *Infrastructure) runVideoProcessingWorker(ctx context.Context, consumer string) {
+	for ctx.Err() == nil {
+		streams, err := i.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    videoProcessingGroup,
+			Consumer: consumer,
+			Streams:  []string{videoProcessingStream, ">"},
+			Count:    1,
+			Block:    videoProcessingBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			i.log().ErrorContext(ctx, "failed to read video processing jobs", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(videoProcessingBlock):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				i.handleVideoProcessingMessage(context.WithoutCancel(ctx), message)
+			}
+		}
+	}
+}
+
+// 変換に失敗したジョブはfailedにして確認応答し、やり直さない
+func (i *Infrastructure) handleVideoProcessingMessage(ctx context.Context, message redis.XMessage) {
+	job, err := videoProcessingJobFromValues(message.Values)
+	if err != nil {
+		i.log().ErrorContext(ctx, "failed to parse video processing job", "messageID", message.ID, "error", err)
+	} else {
+		i.processVideoJob(ctx, job)
+	}
+
+	err = i.AcknowledgeJob(ctx, message.ID)
+	if err != nil {
+		i.log().ErrorContext(ctx, "failed to acknowledge video processing job", "messageID", message.ID, "error", err)
+	}
+}
+
+// 失敗しても再送されたアップロードで新しいジョブを入れるため、ストレージの変換前の動画は消す
+func (i *Infrastructure) processVideoJob(ctx context.Context, job domain.VideoProcessingJob) {
+	defer func() {
+		err := i.storage.Delete(ctx, job.SourceKey)
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to delete video processing source", "key", job.SourceKey, "error", err)
+		}
+	}()
+	defer os.RemoveAll(filepath.Join("output", job.VideoID))
+
+	start := time.Now()
+	err := i.UpdateVideoProcessingStatus(ctx, job.VideoID, domain.StatusProcessing, "")
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to update processing status", "videoID", job.VideoID, "error", err)
+	}
+
+	err = i.transcodeVideoFromStorage(ctx, job)
+	if err != nil {
+		i.log().ErrorContext(ctx, "failed to process video", "videoID", job.VideoID, "duration", time.Since(start), "error", err)
+		i.recordMetrics().RecordUpload(UploadLabels{Status: domain.StatusFailed})
+		err = i.UpdateVideoProcessingStatus(ctx, job.VideoID, domain.StatusFailed, err.Error())
+		if err != nil {
+			i.log().ErrorContext(ctx, "failed to update processing status", "videoID", job.VideoID, "error", err)
+		}
+		return
+	}
+
+	i.log().InfoContext(ctx, "video processed", "videoID", job.VideoID, "duration", time.Since(start))
+	i.recordMetrics().RecordUpload(UploadLabels{Status: domain.StatusReady})
+	err = i.UpdateVideoProcessingStatus(ctx, job.VideoID, domain.StatusReady, "")
+	if err != nil {
+		i.log().ErrorContext(ctx, "failed to update processing status", "videoID", job.VideoID, "error", err)
+		return
+	}
+
+	// プレビューとスプライトは無くても再生できるため失敗してもreadyのままにする
+	_, err = i.GenerateAnimatedPreview(ctx, job.VideoID)
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to generate preview", "videoID", job.VideoID, "error", err)
+	}
+	// 0を渡すとデフォルトの間隔で切り出す
+	_, _, err = i.GenerateThumbnailSprite(ctx, job.VideoID, 0)
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to generate thumbnail sprite", "videoID", job.VideoID, "error", err)
+	}
+}
+
+func (i *Infrastructure) transcodeVideoFromStorage(ctx context.Context, job domain.VideoProcessingJob) error {
+	source, err := i.downloadVideoProcessingSource(ctx, job.SourceKey)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(source)
+	return transcodeVideo(i, ctx, job, source)
+}
+
+// sourceにダウンロードした動画にウォーターマークを重ねて画質ごとのHLSに変換し、S3にアップロードする
+func (i *Infrastructure) transcodeVideo(ctx context.Context, job domain.VideoProcessingJob, source string) error {
+	video, err := i.db.Database.GetVideo(ctx, job.VideoID)
+	if err != nil {
+		return err
+	}
+
+	if job.Options.WatermarkKey != "" {
+		err = i.watermarkLocalVideo(ctx, source, video.UploaderID, job.Options.WatermarkKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	ladder := domain.QualityLadderFor(domain.DefaultQualityLadder, int(video.Height))
+	videoURL, err := i.TranscodeToHLSLadder(ctx, job.VideoID, source, ladder)
+	if err != nil {
+		return err
+	}
+	err = i.updateVideoAsSystem(ctx, job.VideoID, domain.AuditActionTranscodeVideo, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoHLSMasterURL(ctx, sqlc.UpdateVideoHLSMasterURLParams{
+			HlsMasterUrl: sql.NullString{String: videoURL, Valid: true},
+			ID:           job.VideoID,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// ダウンロード用に変換前のMP4も残す
+	var checksum string
+	err = retryWithBackoff(ctx, int(i.config.S3UploadMaxAttempts), func() error {
+		checksum, err = i.uploadVideoSource(ctx, source, job.VideoID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = i.updateVideoAsSystem(ctx, job.VideoID, domain.AuditActionTranscodeVideo, func(q *sqlc.Queries) error {
+		_, err := q.UpdateVideoChecksum(ctx, sqlc.UpdateVideoChecksumParams{
+			Checksum:  sql.NullString{String: checksum, Valid: true},
+			UpdatedAt: time.Now(),
+			ID:        job.VideoID,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// DASHは元の動画から作る。無くてもHLSで再生できるため失敗しても続ける
+	_, err = i.GenerateDASHManifest(ctx, job.VideoID, ladder)
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to generate DASH manifest", "videoID", job.VideoID, "error", err)
+	}
+
+	if job.Options.GenerateThumbnail {
+		atSecond := min(defaultThumbnailSecond, int(video.DurationMs/1000))
+		thumbnailImageURL, err := i.GenerateThumbnail(ctx, videoURL, atSecond)
+		if err != nil {
+			return err
+		}
+		err = i.updateVideoAsSystem(ctx, job.VideoID, domain.AuditActionTranscodeVideo, func(q *sqlc.Queries) error {
+			_, err := q.UpdateVideoThumbnailImageURL(ctx, sqlc.UpdateVideoThumbnailImageURLParams{
+				ThumbnailImageUrl: thumbnailImageURL,
+				UpdatedAt:         time.Now(),
+				ID:                job.VideoID,
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
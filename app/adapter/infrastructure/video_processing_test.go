@@ -0,0 +1,289 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// アップロードされた動画の代わりに中身がcontentのファイルを作る
+func writeTestSource(t *testing.T, name, content string) string {
+	t.Helper()
+	source := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(source, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source
+}
+
+func Test_変換ジョブのキューへの追加(t *testing.T) {
+	_, client := newTestRedis(t)
+	storage := NewMemoryBackend()
+	i := &Infrastructure{
+		redis:   client,
+		storage: storage,
+		config:  InfrastructureConfig{VideoProcessingQueueSize: 1},
+	}
+	ctx := context.Background()
+
+	source := writeTestSource(t, "video_1.mp4", "video")
+	job := domain.NewVideoProcessingJob("video_1", source, domain.TranscodeOptions{WatermarkKey: "logo", GenerateThumbnail: true})
+	err := i.EnqueueVideoProcessingJob(ctx, job)
+	if err != nil {
+		t.Fatalf("Infrastructure.EnqueueVideoProcessingJob() error = %v", err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Errorf("local source should be removed, stat error = %v", err)
+	}
+
+	// 未処理のジョブが上限に達している場合はエラーを返し、ストレージにも置かない
+	source2 := writeTestSource(t, "video_2.mp4", "video")
+	err = i.EnqueueVideoProcessingJob(ctx, domain.NewVideoProcessingJob("video_2", source2, domain.TranscodeOptions{}))
+	if !errors.Is(err, domain.ErrProcessingQueueFull) {
+		t.Errorf("Infrastructure.EnqueueVideoProcessingJob() error = %v, want %v", err, domain.ErrProcessingQueueFull)
+	}
+	if len(storage.objects) != 1 {
+		t.Errorf("stored objects = %d, want 1", len(storage.objects))
+	}
+
+	messages, err := client.XRange(ctx, videoProcessingStream, "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("stream length = %d, want 1", len(messages))
+	}
+	got, err := videoProcessingJobFromValues(messages[0].Values)
+	if err != nil {
+		t.Fatalf("videoProcessingJobFromValues() error = %v", err)
+	}
+	// ジョブには他のインスタンスからも読めるストレージのキーを入れる
+	if !strings.HasPrefix(got.SourceKey, "processing/video_1/") || got.VideoID != job.VideoID || got.Options != job.Options {
+		t.Errorf("videoProcessingJobFromValues() = %+v, want %+v with a storage key", got, job)
+	}
+	body, err := storage.Download(ctx, got.SourceKey)
+	if err != nil {
+		t.Fatalf("stored source error = %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "video" {
+		t.Errorf("stored source = %q, want %q", data, "video")
+	}
+}
+
+func Test_止まった変換ジョブの入れ直し(t *testing.T) {
+	_, client := newTestRedis(t)
+	i := &Infrastructure{
+		redis:   client,
+		storage: NewMemoryBackend(),
+		config:  InfrastructureConfig{VideoProcessingQueueSize: 10},
+	}
+	ctx := context.Background()
+
+	err := client.XGroupCreateMkStream(ctx, videoProcessingStream, videoProcessingGroup, "0").Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = i.EnqueueVideoProcessingJob(ctx, domain.NewVideoProcessingJob("video_1", writeTestSource(t, "video_1.mp4", "video"), domain.TranscodeOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 読み出したワーカーが確認応答せずに落ちた状態にする
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    videoProcessingGroup,
+		Consumer: "crashed",
+		Streams:  []string{videoProcessingStream, ">"},
+		Count:    1,
+	}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stalledID := streams[0].Messages[0].ID
+
+	got, err := i.ReclaimStalledJobs(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Infrastructure.ReclaimStalledJobs() error = %v", err)
+	}
+	if got != 0 {
+		t.Errorf("Infrastructure.ReclaimStalledJobs() = %d, want 0 before timeout", got)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	got, err = i.ReclaimStalledJobs(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Infrastructure.ReclaimStalledJobs() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Infrastructure.ReclaimStalledJobs() = %d, want 1", got)
+	}
+
+	// 入れ直したジョブは別のワーカーが新しいジョブとして読み出せる
+	streams, err = client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    videoProcessingGroup,
+		Consumer: "worker",
+		Streams:  []string{videoProcessingStream, ">"},
+		Count:    1,
+	}).Result()
+	if err != nil {
+		t.Fatalf("XReadGroup() error = %v", err)
+	}
+	message := streams[0].Messages[0]
+	if message.ID == stalledID || message.Values["videoID"] != "video_1" {
+		t.Errorf("reclaimed message = %+v, want new job for video_1", message)
+	}
+	pending, err := client.XPending(ctx, videoProcessingStream, videoProcessingGroup).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Count != 1 {
+		t.Errorf("pending count = %d, want 1", pending.Count)
+	}
+}
+
+func Test_変換ジョブの処理(t *testing.T) {
+	tests := []struct {
+		name         string
+		transcodeErr error
+		wantStatuses []driver.Value
+		wantErrorMsg driver.Value
+	}{
+		{
+			name:         "成功",
+			wantStatuses: []driver.Value{string(domain.StatusProcessing), string(domain.StatusReady)},
+			wantErrorMsg: nil,
+		},
+		{
+			name:         "失敗",
+			transcodeErr: errors.New("ffmpeg failed"),
+			wantStatuses: []driver.Value{string(domain.StatusProcessing), string(domain.StatusFailed)},
+			wantErrorMsg: "ffmpeg failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := transcodeVideo
+			t.Cleanup(func() { transcodeVideo = original })
+			var source string
+			transcodeVideo = func(i *Infrastructure, ctx context.Context, job domain.VideoProcessingJob, localSource string) error {
+				source = localSource
+				data, err := os.ReadFile(localSource)
+				if err != nil || string(data) != "video" {
+					t.Errorf("local source = %q, %v, want the stored video", data, err)
+				}
+				return tt.transcodeErr
+			}
+
+			storage := NewMemoryBackend()
+			err := storage.Upload(context.Background(), "processing/video_1/source.mp4", "video/mp4", strings.NewReader("video"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// プレビューとスプライトの生成は動画が見つからずに失敗させる
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideo": {}, "GetVideoForUpdate": {row[:len(row)-2]}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}, storage: storage}
+
+			i.processVideoJob(context.Background(), domain.NewVideoProcessingJob("video_1", "processing/video_1/source.mp4", domain.TranscodeOptions{}))
+
+			var statuses []driver.Value
+			var last []driver.Value
+			for n, args := range connector.execs {
+				if connector.execNames[n] == "UpdateVideoProcessingStatus" {
+					statuses = append(statuses, args[0])
+					last = args
+				}
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatuses) {
+				t.Fatalf("processing_status = %v, want %v", statuses, tt.wantStatuses)
+			}
+			if last[1] != tt.wantErrorMsg {
+				t.Errorf("processing_error = %v, want %v", last[1], tt.wantErrorMsg)
+			}
+			if _, err := os.Stat(source); !os.IsNotExist(err) {
+				t.Errorf("local source file should be removed, stat error = %v", err)
+			}
+			if len(storage.objects) != 0 {
+				t.Errorf("stored source should be removed, objects = %d", len(storage.objects))
+			}
+		})
+	}
+}
+
+func Test_変換ワーカーの停止(t *testing.T) {
+	original := transcodeVideo
+	t.Cleanup(func() { transcodeVideo = original })
+	processed := make(chan string, 2)
+	transcodeVideo = func(i *Infrastructure, ctx context.Context, job domain.VideoProcessingJob, source string) error {
+		processed <- job.VideoID
+		return errors.New("stop after transcode")
+	}
+
+	_, client := newTestRedis(t)
+	connector := &rowConnector{}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+		redis:   client,
+		storage: NewMemoryBackend(),
+		config:  InfrastructureConfig{VideoProcessingQueueSize: 10},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- i.StartWorkerPool(ctx, 2)
+	}()
+
+	for _, id := range []string{"video_1", "video_2"} {
+		err := i.EnqueueVideoProcessingJob(ctx, domain.NewVideoProcessingJob(id, writeTestSource(t, id+".mp4", "video"), domain.TranscodeOptions{}))
+		if err != nil {
+			t.Fatalf("Infrastructure.EnqueueVideoProcessingJob() error = %v", err)
+		}
+	}
+	for n := 0; n < 2; n++ {
+		select {
+		case <-processed:
+		case <-time.After(2 * videoProcessingBlock):
+			t.Fatal("job was not processed")
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Infrastructure.StartWorkerPool() error = %v", err)
+		}
+	case <-time.After(2 * videoProcessingBlock):
+		t.Fatal("Infrastructure.StartWorkerPool() did not return after cancel")
+	}
+
+	// 確認応答したジョブはStreamに残らない
+	length, err := client.XLen(context.Background(), videoProcessingStream).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != 0 {
+		t.Errorf("stream length = %d, want 0", length)
+	}
+}
@@ -0,0 +1,101 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 同じユーザーが同じ動画を通報済みの場合はErrDuplicateReportを返す
+func (i *Infrastructure) SubmitVideoReport(ctx context.Context, report domain.VideoReport) (err error) {
+	ctx, span := infraSpan(ctx, "SubmitVideoReport")
+	span.SetAttributes(attribute.String("videoID", report.VideoID), attribute.String("reporterID", report.ReporterID), attribute.String("reason", report.Reason))
+	defer func() { endSpan(span, err) }()
+
+	err = report.Validate()
+	if err != nil {
+		return err
+	}
+	_, err = i.getUndeletedVideo(ctx, report.VideoID)
+	if err != nil {
+		return err
+	}
+	if report.ID == "" {
+		report.ID = domain.NewVideoReportID()
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now()
+	}
+
+	// 一意制約に違反した行はINSERT IGNOREで追加されない
+	result, err := i.db.Database.CreateVideoReport(ctx, sqlc.CreateVideoReportParams{
+		ID:         report.ID,
+		VideoID:    report.VideoID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		CreatedAt:  report.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s by %s", domain.ErrDuplicateReport, report.VideoID, report.ReporterID)
+	}
+	return nil
+}
+
+// 新しい通報から順に返す
+func (i *Infrastructure) GetVideoReports(ctx context.Context, videoID string) (_ []*domain.VideoReport, err error) {
+	ctx, span := infraSpan(ctx, "GetVideoReports")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	dbReports, err := i.db.Database.GetVideoReportsByVideoID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return newVideoReportsFromDB(dbReports), nil
+}
+
+// モデレーターが確認していない通報を古い順に返す
+func (i *Infrastructure) GetPendingReports(ctx context.Context, page domain.Page) (_ []*domain.VideoReport, err error) {
+	ctx, span := infraSpan(ctx, "GetPendingReports")
+	span.SetAttributes(attribute.Int("offset", page.Offset), attribute.Int("limit", page.Limit))
+	defer func() { endSpan(span, err) }()
+
+	err = page.Validate()
+	if err != nil {
+		return nil, err
+	}
+	dbReports, err := i.db.Database.GetVideoReportsByStatus(ctx, sqlc.GetVideoReportsByStatusParams{
+		Status: domain.VideoReportStatusPending,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newVideoReportsFromDB(dbReports), nil
+}
+
+func newVideoReportsFromDB(dbReports []sqlc.VideoReport) []*domain.VideoReport {
+	reports := make([]*domain.VideoReport, 0, len(dbReports))
+	for _, r := range dbReports {
+		reports = append(reports, &domain.VideoReport{
+			ID:         r.ID,
+			VideoID:    r.VideoID,
+			ReporterID: r.ReporterID,
+			Reason:     r.Reason,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+	return reports
+}
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の通報(t *testing.T) {
+	tests := []struct {
+		name      string
+		report    domain.VideoReport
+		wantErr   error
+		wantExecs []string
+	}{
+		{name: "通報できる", report: domain.VideoReport{VideoID: "video_1", ReporterID: "user_2", Reason: domain.ReasonSpam}, wantExecs: []string{"CreateVideoReport"}},
+		{name: "理由が不正", report: domain.VideoReport{VideoID: "video_1", ReporterID: "user_2", Reason: "boring"}, wantErr: domain.ErrInvalidInput},
+		{name: "通報したユーザーがない", report: domain.VideoReport{VideoID: "video_1", Reason: domain.ReasonOther}, wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetUndeletedVideo": {row[:len(row)-2]}}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			err := i.SubmitVideoReport(context.Background(), tt.report)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SubmitVideoReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr == nil {
+				args := connector.execs[0]
+				if args[0] == "" || args[1] != "video_1" || args[2] != "user_2" || args[3] != domain.ReasonSpam {
+					t.Errorf("CreateVideoReport args = %v", args)
+				}
+			}
+		})
+	}
+}
+
+func Test_確認待ちの通報の取得(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetVideoReportsByStatus": {
+			{"report_1", "video_1", "user_2", domain.ReasonSpam, domain.VideoReportStatusPending, createdAt},
+			{"report_2", "video_2", "user_3", domain.ReasonCopyright, domain.VideoReportStatusPending, createdAt},
+		},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	got, err := i.GetPendingReports(context.Background(), domain.NewPage(0, 20))
+	if err != nil {
+		t.Fatalf("Infrastructure.GetPendingReports() error = %v", err)
+	}
+	want := []*domain.VideoReport{
+		{ID: "report_1", VideoID: "video_1", ReporterID: "user_2", Reason: domain.ReasonSpam, CreatedAt: createdAt},
+		{ID: "report_2", VideoID: "video_2", ReporterID: "user_3", Reason: domain.ReasonCopyright, CreatedAt: createdAt},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Infrastructure.GetPendingReports() = %v, want %v", got, want)
+	}
+
+	_, err = i.GetPendingReports(context.Background(), domain.NewPage(0, 0))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Infrastructure.GetPendingReports() error = %v, want %v", err, domain.ErrInvalidInput)
+	}
+}
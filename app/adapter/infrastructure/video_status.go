@@ -0,0 +1,139 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 受信側の処理が遅れた場合に溜めておく状態の数
+const videoStatusBufferSize = 8
+
+func videoStatusChannel(videoID string) string {
+	return "video-status:" + videoID
+}
+
+// HLSの鍵のトークンと同じ形式で署名する
+// keyIDに使えない/を含めて、鍵のトークンを処理状態の購読に使えないようにする
+func videoStatusTokenSubject(videoID string) string {
+	return "video-status/" + videoID
+}
+
+// 処理状態の変更を購読しているクライアントに通知する
+// 状態はDBに保存済みなので、通知に失敗しても更新は失敗させない
+// テストで作成したInfrastructureはRedisを持たないことがあるため通知しない
+func (i *Infrastructure) publishVideoStatus(ctx context.Context, videoID string, status domain.ProcessingStatus) {
+	if i.redis == nil {
+		return
+	}
+
+	err := i.redis.Publish(ctx, videoStatusChannel(videoID), string(status)).Err()
+	if err != nil {
+		i.log().WarnContext(ctx, "failed to publish video status", "videoID", videoID, "error", err)
+	}
+}
+
+// 処理状態の購読に使うトークンを作る。処理状態は投稿者と管理者のみが購読できる
+func (i *Infrastructure) IssueVideoStatusToken(ctx context.Context, videoID, userID string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "IssueVideoStatusToken")
+	span.SetAttributes(attribute.String("videoID", videoID), attribute.String("userID", userID))
+	defer func() { endSpan(span, err) }()
+
+	if i.config.HLSKeyTokenSecret == "" {
+		return "", errors.New("HLS_KEY_TOKEN_SECRET is not set")
+	}
+	video, err := i.db.Database.GetVideo(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+	}
+	if err != nil {
+		return "", err
+	}
+	if video.UploaderID != userID && !i.isAdmin(userID) {
+		return "", fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, userID, videoID)
+	}
+	return hlsKeyToken(i.config.HLSKeyTokenSecret, videoStatusTokenSubject(videoID), userID, time.Now().Add(i.config.HLSKeyTokenTTL)), nil
+}
+
+// 最初に今の処理状態を送り、その後は変更されるたびに送る
+// 処理が終わるかctxがキャンセルされたらチャネルを閉じる
+// Redisとの接続が切れた場合はgo-redisが再接続して購読し直す
+// トークンを発行した後に動画が譲渡されている場合があるため、購読する時にも投稿者か確認する
+func (i *Infrastructure) SubscribeToVideoStatus(ctx context.Context, videoID, userToken string) (_ <-chan domain.ProcessingStatus, err error) {
+	ctx, span := infraSpan(ctx, "SubscribeToVideoStatus")
+	span.SetAttributes(attribute.String("videoID", videoID))
+	defer func() { endSpan(span, err) }()
+
+	if i.config.HLSKeyTokenSecret == "" {
+		return nil, errors.New("HLS_KEY_TOKEN_SECRET is not set")
+	}
+	subject, userID, err := parseHLSKeyToken(i.config.HLSKeyTokenSecret, userToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if subject != videoStatusTokenSubject(videoID) {
+		return nil, fmt.Errorf("%w: token is not for video %s", domain.ErrPermissionDenied, videoID)
+	}
+
+	// 今の状態を取得する前に購読し、その間の変更を取りこぼさないようにする
+	pubsub := i.redis.Subscribe(ctx, videoStatusChannel(videoID))
+	_, err = pubsub.Receive(ctx)
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	video, err := i.db.Database.GetVideo(ctx, videoID)
+	if errors.Is(err, sql.ErrNoRows) {
+		pubsub.Close()
+		return nil, fmt.Errorf("%w: %s", domain.ErrVideoNotFound, videoID)
+	}
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	if video.UploaderID != userID && !i.isAdmin(userID) {
+		pubsub.Close()
+		return nil, fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, userID, videoID)
+	}
+
+	statuses := make(chan domain.ProcessingStatus, videoStatusBufferSize)
+	statuses <- domain.ProcessingStatus(video.ProcessingStatus)
+	if domain.ProcessingStatus(video.ProcessingStatus).IsFinished() {
+		pubsub.Close()
+		close(statuses)
+		return statuses, nil
+	}
+
+	go func() {
+		defer close(statuses)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				status := domain.ProcessingStatus(message.Payload)
+				select {
+				case statuses <- status:
+				case <-ctx.Done():
+					return
+				}
+				if status.IsFinished() {
+					return
+				}
+			}
+		}
+	}()
+	return statuses, nil
+}
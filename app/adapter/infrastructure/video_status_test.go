@@ -0,0 +1,172 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_動画の処理状態の購読(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		status  string
+		rows    [][]driver.Value
+		token   string
+		updates []domain.ProcessingStatus
+		want    []domain.ProcessingStatus
+		wantErr error
+	}{
+		{
+			name:    "処理が終わるまで送る",
+			status:  "pending",
+			updates: []domain.ProcessingStatus{domain.StatusProcessing, domain.StatusReady},
+			want:    []domain.ProcessingStatus{domain.StatusPending, domain.StatusProcessing, domain.StatusReady},
+		},
+		{
+			name:   "処理が終わっている場合は今の状態のみ送る",
+			status: "failed",
+			want:   []domain.ProcessingStatus{domain.StatusFailed},
+		},
+		{name: "動画がない", rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+		{
+			name:   "管理者",
+			status: "failed",
+			token:  hlsKeyToken("secret", videoStatusTokenSubject("video_1"), "admin_1", expiresAt),
+			want:   []domain.ProcessingStatus{domain.StatusFailed},
+		},
+		{name: "他のユーザー", status: "pending", token: hlsKeyToken("secret", videoStatusTokenSubject("video_1"), "user_2", expiresAt), wantErr: domain.ErrPermissionDenied},
+		{name: "他の動画のトークン", status: "pending", token: hlsKeyToken("secret", videoStatusTokenSubject("video_2"), "user_1", expiresAt), wantErr: domain.ErrPermissionDenied},
+		{name: "HLSの鍵のトークン", status: "pending", token: hlsKeyToken("secret", "video_1", "user_1", expiresAt), wantErr: domain.ErrPermissionDenied},
+		{name: "期限切れのトークン", status: "pending", token: hlsKeyToken("secret", videoStatusTokenSubject("video_1"), "user_1", time.Now().Add(-time.Minute)), wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := tt.token
+			if token == "" {
+				token = hlsKeyToken("secret", videoStatusTokenSubject("video_1"), "user_1", expiresAt)
+			}
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				row = row[:len(row)-2]
+				row[21] = tt.status
+				rows = [][]driver.Value{row}
+			}
+			_, client := newTestRedis(t)
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideo": rows, "GetVideoForUpdate": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:  client,
+				config: InfrastructureConfig{HLSKeyTokenSecret: "secret", AdminUserIDs: []string{"admin_1"}},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			statuses, err := i.SubscribeToVideoStatus(ctx, "video_1", token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.SubscribeToVideoStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			for _, status := range tt.updates {
+				err = i.UpdateVideoProcessingStatus(ctx, "video_1", status, "")
+				if err != nil {
+					t.Fatalf("Infrastructure.UpdateVideoProcessingStatus() error = %v", err)
+				}
+			}
+
+			got := []domain.ProcessingStatus{}
+			for status := range statuses {
+				got = append(got, status)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statuses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_動画の処理状態の購読の終了(t *testing.T) {
+	row := relatedVideoRow("video_1", 0, "")
+	row = row[:len(row)-2]
+	row[21] = "processing"
+	_, client := newTestRedis(t)
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideo": {row}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}, redis: client, config: InfrastructureConfig{HLSKeyTokenSecret: "secret"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	token := hlsKeyToken("secret", videoStatusTokenSubject("video_1"), "user_1", time.Now().Add(time.Hour))
+	statuses, err := i.SubscribeToVideoStatus(ctx, "video_1", token)
+	if err != nil {
+		t.Fatalf("Infrastructure.SubscribeToVideoStatus() error = %v", err)
+	}
+	<-statuses
+	cancel()
+
+	// キャンセルしたらチャネルを閉じる
+	select {
+	case _, ok := <-statuses:
+		if ok {
+			t.Error("statuses should be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("statuses was not closed after cancel")
+	}
+}
+
+func Test_動画の処理状態の購読のトークンの発行(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		rows    [][]driver.Value
+		wantErr error
+	}{
+		{name: "投稿者", userID: "user_1"},
+		{name: "管理者", userID: "admin_1"},
+		{name: "他のユーザー", userID: "user_2", wantErr: domain.ErrPermissionDenied},
+		{name: "動画がない", userID: "user_1", rows: [][]driver.Value{}, wantErr: domain.ErrVideoNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.rows
+			if rows == nil {
+				row := relatedVideoRow("video_1", 0, "")
+				rows = [][]driver.Value{row[:len(row)-2]}
+			}
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideo": rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:     &db.DB{Database: sqlc.New(sqlDB)},
+				config: InfrastructureConfig{HLSKeyTokenSecret: "secret", HLSKeyTokenTTL: time.Hour, AdminUserIDs: []string{"admin_1"}},
+			}
+
+			token, err := i.IssueVideoStatusToken(context.Background(), "video_1", tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.IssueVideoStatusToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			subject, userID, err := parseHLSKeyToken("secret", token, time.Now())
+			if err != nil || subject != videoStatusTokenSubject("video_1") || userID != tt.userID {
+				t.Errorf("parseHLSKeyToken() = %v, %v, %v, want %v and %v", subject, userID, err, videoStatusTokenSubject("video_1"), tt.userID)
+			}
+		})
+	}
+}
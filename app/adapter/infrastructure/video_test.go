@@ -1,9 +1,25 @@
 package infrastructure
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
 )
 
 func Test_動画のバリデーションチェック(t *testing.T) {
@@ -88,3 +104,979 @@ func Test_動画のバリデーションチェック(t *testing.T) {
 		})
 	}
 }
+
+func Test_動画フォーマットの判定(t *testing.T) {
+	// マジックバイトの後ろを埋めて最小サイズを超えるファイルを作る
+	newVideo := func(header string) io.ReadSeeker {
+		return bytes.NewReader(append([]byte(header), make([]byte, minVideoFileSize)...))
+	}
+
+	tests := []struct {
+		name       string
+		video      io.ReadSeeker
+		wantFormat string
+		wantErr    bool
+	}{
+		{
+			name:    "mp4",
+			video:   newVideo("\x00\x00\x00\x20ftypisom"),
+			wantErr: false,
+		},
+		{
+			name:    "mov",
+			video:   newVideo("\x00\x00\x00\x14ftypqt  "),
+			wantErr: false,
+		},
+		{
+			name:       "webm",
+			video:      newVideo("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81"),
+			wantFormat: "WebM",
+			wantErr:    true,
+		},
+		{
+			name:       "avi",
+			video:      newVideo("RIFF\x00\x10\x00\x00AVI "),
+			wantFormat: "AVI",
+			wantErr:    true,
+		},
+		{
+			name:       "mkv",
+			video:      newVideo("\x1a\x45\xdf\xa3\xa3\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x88matroska"),
+			wantFormat: "MKV",
+			wantErr:    true,
+		},
+		{
+			name:       "heic",
+			video:      newVideo("\x00\x00\x00\x18ftypheic"),
+			wantFormat: "HEIF",
+			wantErr:    true,
+		},
+		{
+			name:    "truncated",
+			video:   bytes.NewReader([]byte("\x00\x00\x00\x20ftypisom")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Infrastructure{}
+			err := i.ValidationVideo(tt.video)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infrastructure.ValidationVideo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantFormat == "" {
+				return
+			}
+			var formatErr *domain.ErrUnsupportedFormat
+			if !errors.As(err, &formatErr) {
+				t.Fatalf("Infrastructure.ValidationVideo() error = %v, want ErrUnsupportedFormat", err)
+			}
+			if formatErr.Format != tt.wantFormat {
+				t.Errorf("ErrUnsupportedFormat.Format = %v, want %v", formatErr.Format, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func Test_動画とタグの紐付け(t *testing.T) {
+	type args struct {
+		dbVideos []sqlc.Video
+		tags     []sqlc.GetAllVideosTagsRow
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want map[string][]string
+	}{
+		{
+			name: "tag is empty",
+			args: args{
+				dbVideos: []sqlc.Video{{ID: "video_1"}, {ID: "video_2"}},
+				tags:     []sqlc.GetAllVideosTagsRow{},
+			},
+			want: map[string][]string{
+				"video_1": {},
+				"video_2": {},
+			},
+		},
+		{
+			name: "one tag",
+			args: args{
+				dbVideos: []sqlc.Video{{ID: "video_1"}, {ID: "video_2"}},
+				tags: []sqlc.GetAllVideosTagsRow{
+					{VideoID: "video_1", TagID: 1, TagName: "go"},
+				},
+			},
+			want: map[string][]string{
+				"video_1": {"go"},
+				"video_2": {},
+			},
+		},
+		{
+			name: "multiple tags",
+			args: args{
+				dbVideos: []sqlc.Video{{ID: "video_1"}, {ID: "video_2"}},
+				tags: []sqlc.GetAllVideosTagsRow{
+					{VideoID: "video_1", TagID: 1, TagName: "go"},
+					{VideoID: "video_2", TagID: 1, TagName: "go"},
+					{VideoID: "video_1", TagID: 2, TagName: "grpc"},
+					{VideoID: "video_2", TagID: 3, TagName: "mysql"},
+					{VideoID: "video_1", TagID: 3, TagName: "mysql"},
+					{VideoID: "video_2", TagID: 4, TagName: "redis"},
+				},
+			},
+			want: map[string][]string{
+				"video_1": {"go", "grpc", "mysql"},
+				"video_2": {"go", "mysql", "redis"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newVideosWithTags(tt.args.dbVideos, tt.args.tags)
+			if len(got) != len(tt.args.dbVideos) {
+				t.Fatalf("newVideosWithTags() len = %v, want %v", len(got), len(tt.args.dbVideos))
+			}
+			for i, video := range got {
+				if video.ID != tt.args.dbVideos[i].ID {
+					t.Errorf("newVideosWithTags()[%d].ID = %v, want %v", i, video.ID, tt.args.dbVideos[i].ID)
+				}
+				if !reflect.DeepEqual(video.Tags, tt.want[video.ID]) {
+					t.Errorf("newVideosWithTags()[%d].Tags = %v, want %v", i, video.Tags, tt.want[video.ID])
+				}
+			}
+		})
+	}
+}
+
+func Test_動画サイズの上限チェック(t *testing.T) {
+	tests := []struct {
+		name     string
+		video    io.Reader
+		maxBytes int64
+		wantErr  bool
+	}{
+		{
+			name:     "seeker within limit",
+			video:    bytes.NewReader(make([]byte, 100)),
+			maxBytes: 100,
+			wantErr:  false,
+		},
+		{
+			name:     "seeker exceeds limit",
+			video:    bytes.NewReader(make([]byte, 101)),
+			maxBytes: 100,
+			wantErr:  true,
+		},
+		{
+			name:     "stream within limit",
+			video:    io.LimitReader(bytes.NewReader(make([]byte, 100)), 100),
+			maxBytes: 100,
+			wantErr:  false,
+		},
+		{
+			name:     "stream exceeds limit",
+			video:    io.LimitReader(bytes.NewReader(make([]byte, 101)), 101),
+			maxBytes: 100,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Infrastructure{}
+			r, err := i.ValidateVideoSize(context.Background(), tt.video, tt.maxBytes)
+			if err == nil {
+				// Seekできない場合は読み切った時点でエラーになる
+				_, err = io.ReadAll(r)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infrastructure.ValidateVideoSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, domain.ErrFileTooLarge) {
+				t.Errorf("Infrastructure.ValidateVideoSize() error = %v, want ErrFileTooLarge", err)
+			}
+		})
+	}
+}
+
+func Test_切り抜き動画の一時ファイル削除(t *testing.T) {
+	originalFFmpeg, originalUpload := cutVideoFFmpeg, cutVideoUpload
+	t.Cleanup(func() {
+		cutVideoFFmpeg, cutVideoUpload = originalFFmpeg, originalUpload
+	})
+
+	tests := []struct {
+		name      string
+		uploadErr error
+		wantErr   bool
+	}{
+		{
+			name:      "upload failed",
+			uploadErr: errors.New("upload failed"),
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			uploadErr: nil,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outPath string
+			// ffmpegの代わりに出力先にファイルを作る
+			cutVideoFFmpeg = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+				outPath = args[len(args)-1]
+				return nil, os.WriteFile(outPath, []byte("video"), 0644)
+			}
+			cutVideoUpload = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("file to upload does not exist: %v", err)
+				}
+				return tt.uploadErr
+			}
+
+			i := &Infrastructure{storage: NewMemoryBackend()}
+			_, err := i.CutVideo(context.Background(), "video_1", "user_1", 0, 10, domain.NewCutOptions("", false, false))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Infrastructure.CutVideo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if outPath == "" {
+				t.Fatal("ffmpeg was not called")
+			}
+			if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+				t.Errorf("temp file %s was not removed", outPath)
+			}
+		})
+	}
+}
+
+func Test_切り抜き動画のタイムアウト(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i := &Infrastructure{
+		storage: NewMemoryBackend(),
+		config: InfrastructureConfig{
+			FFmpegTimeout: time.Minute,
+		},
+	}
+	_, err := i.CutVideo(ctx, "video_1", "user_1", 0, 10, domain.NewCutOptions("", false, false))
+	if !errors.Is(err, domain.ErrFFmpegTimeout) {
+		t.Errorf("Infrastructure.CutVideo() error = %v, want ErrFFmpegTimeout", err)
+	}
+}
+
+func Test_切り抜き動画の出力形式(t *testing.T) {
+	originalFFmpeg, originalUpload := cutVideoFFmpeg, cutVideoUpload
+	t.Cleanup(func() {
+		cutVideoFFmpeg, cutVideoUpload = originalFFmpeg, originalUpload
+	})
+
+	tests := []struct {
+		name            string
+		options         domain.CutOptions
+		wantCodecArgs   []string
+		wantContentType string
+		wantErr         error
+	}{
+		{
+			name:            "mp4",
+			options:         domain.NewCutOptions("", false, false),
+			wantCodecArgs:   []string{"-codec:", "copy"},
+			wantContentType: "video/mp4",
+		},
+		{
+			name:            "mp4 re-encode",
+			options:         domain.NewCutOptions(domain.CutFormatMP4, true, false),
+			wantCodecArgs:   []string{},
+			wantContentType: "video/mp4",
+		},
+		{
+			name:            "webm",
+			options:         domain.NewCutOptions(domain.CutFormatWebM, false, false),
+			wantCodecArgs:   []string{"-c:v", "libvpx-vp9", "-c:a", "libopus"},
+			wantContentType: "video/webm",
+		},
+		{
+			name:    "unsupported",
+			options: domain.NewCutOptions("avi", false, false),
+			wantErr: domain.ErrUnsupportedCutFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var codecArgs []string
+			cutVideoFFmpeg = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+				// -toの値の後ろから出力ファイルの手前までが出力側の引数
+				codecArgs = append([]string{}, args[6:len(args)-1]...)
+				return nil, nil
+			}
+			var contentType string
+			cutVideoUpload = func(i *Infrastructure, ctx context.Context, path, bucketName, key, ct string) error {
+				contentType = ct
+				return nil
+			}
+
+			i := &Infrastructure{storage: NewMemoryBackend()}
+			_, err := i.CutVideo(context.Background(), "video_1", "user_1", 0, 10, tt.options)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.CutVideo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(codecArgs, tt.wantCodecArgs) {
+				t.Errorf("codec args = %v, want %v", codecArgs, tt.wantCodecArgs)
+			}
+			if contentType != tt.wantContentType {
+				t.Errorf("content type = %v, want %v", contentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func Test_切り抜き動画の正確なシーク(t *testing.T) {
+	originalFFmpeg, originalUpload := cutVideoFFmpeg, cutVideoUpload
+	t.Cleanup(func() {
+		cutVideoFFmpeg, cutVideoUpload = originalFFmpeg, originalUpload
+	})
+
+	var calls [][]string
+	cutVideoFFmpeg = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+		calls = append(calls, args)
+		return nil, nil
+	}
+	cutVideoUpload = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+		return nil
+	}
+
+	i := &Infrastructure{storage: NewMemoryBackend()}
+	_, err := i.CutVideo(context.Background(), "video_1", "user_1", 25, 40, domain.NewCutOptions("", false, true))
+	if err != nil {
+		t.Fatalf("Infrastructure.CutVideo() error = %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("ffmpeg called %d times, want 2", len(calls))
+	}
+
+	roughPath := calls[0][len(calls[0])-1]
+	wantRough := []string{"-ss", "15", "-i", ffmpegInput, "-to", "25", "-c", "copy", roughPath}
+	if !reflect.DeepEqual(calls[0], wantRough) {
+		t.Errorf("first pass args = %v, want %v", calls[0], wantRough)
+	}
+	wantAccurate := []string{"-i", ffmpegInput, "-ss", "10", "-to", "25", calls[1][len(calls[1])-1]}
+	if !reflect.DeepEqual(calls[1], wantAccurate) {
+		t.Errorf("second pass args = %v, want %v", calls[1], wantAccurate)
+	}
+}
+
+// 60秒のテスト用動画で-ssを-iの前に置く方法と2回に分けて再エンコードする方法を比較する
+func Benchmark_切り抜き動画のシーク(b *testing.B) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		b.Skip("ffmpeg not found")
+	}
+
+	clip := filepath.Join(b.TempDir(), "clip.mp4")
+	_, err := runFFmpeg(context.Background(), "cut", []string{"-f", "lavfi", "-i", "testsrc=duration=60:size=640x360:rate=30", "-c:v", "libx264", "-g", "300", clip, "-y"})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	originalFFmpeg, originalUpload := cutVideoFFmpeg, cutVideoUpload
+	b.Cleanup(func() {
+		cutVideoFFmpeg, cutVideoUpload = originalFFmpeg, originalUpload
+	})
+	// S3のURLの代わりにローカルのテスト用動画を入力にする
+	cutVideoFFmpeg = func(i *Infrastructure, ctx context.Context, url string, args []string) ([]byte, error) {
+		if strings.HasSuffix(url, ".m3u8") {
+			url = clip
+		}
+		return runFFmpeg(ctx, "cut", replaceFFmpegInput(append([]string{"-loglevel", "error"}, args...), url))
+	}
+	cutVideoUpload = func(i *Infrastructure, ctx context.Context, path, bucketName, key, contentType string) error {
+		return nil
+	}
+
+	benchmarks := []struct {
+		name    string
+		options domain.CutOptions
+	}{
+		{name: "fast", options: domain.NewCutOptions("", false, false)},
+		{name: "accurate", options: domain.NewCutOptions("", false, true)},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			i := &Infrastructure{storage: NewMemoryBackend()}
+			for n := 0; n < b.N; n++ {
+				_, err := i.CutVideo(context.Background(), "video_1", "user_1", 25, 40, bm.options)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// rowsByQueryにsqlcのクエリ名があればその行を、なければvaluesの1行を返し、
+// 問い合わせ回数と更新系のクエリの引数、トランザクションの結果を記録するドライバ
+type rowConnector struct {
+	mu          sync.Mutex
+	values      []driver.Value
+	rowsByQuery map[string][][]driver.Value
+	queries     int
+	execs       [][]driver.Value
+	execNames   []string
+	commits     int
+	rollbacks   int
+	// execErrorsにあるクエリは記録した後にそのエラーを返す。キーはrowsByQueryと同じ
+	execErrors map[string]error
+	// 更新系のクエリの応答にかかる時間
+	execDelay time.Duration
+	// rowsAffectedにないクエリは1行を変更したとみなす
+	rowsAffected map[string]int64
+	// 引数によって変更した行数が変わる場合に使う。okがfalseの場合はrowsAffectedを使う
+	affectedByArgs func(name string, args []driver.Value) (affected int64, ok bool)
+}
+
+func (c *rowConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &rowConn{connector: c}, nil
+}
+
+func (c *rowConnector) Driver() driver.Driver {
+	return nil
+}
+
+type rowConn struct {
+	connector *rowConnector
+}
+
+func (c *rowConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *rowConn) Close() error {
+	return nil
+}
+
+func (c *rowConn) Begin() (driver.Tx, error) {
+	return &rowTx{connector: c.connector}, nil
+}
+
+type rowTx struct {
+	connector *rowConnector
+}
+
+func (t *rowTx) Commit() error {
+	t.connector.mu.Lock()
+	defer t.connector.mu.Unlock()
+	t.connector.commits++
+	return nil
+}
+
+func (t *rowTx) Rollback() error {
+	t.connector.mu.Lock()
+	defer t.connector.mu.Unlock()
+	t.connector.rollbacks++
+	return nil
+}
+
+// LastInsertIdは何番目の更新系のクエリかを返す
+type rowResult struct {
+	id       int64
+	affected int64
+}
+
+func (r rowResult) LastInsertId() (int64, error) {
+	return r.id, nil
+}
+
+func (r rowResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+func (c *rowConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(c.connector.execDelay)
+	c.connector.mu.Lock()
+	defer c.connector.mu.Unlock()
+	values := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	c.connector.execs = append(c.connector.execs, values)
+	c.connector.execNames = append(c.connector.execNames, queryName(query))
+	if len(args) > 0 {
+		if err := c.connector.execErrors[fmt.Sprintf("%s %v", queryName(query), args[0].Value)]; err != nil {
+			return nil, err
+		}
+	}
+	if err := c.connector.execErrors[queryName(query)]; err != nil {
+		return nil, err
+	}
+	affected, ok := int64(0), false
+	if c.connector.affectedByArgs != nil {
+		affected, ok = c.connector.affectedByArgs(queryName(query), values)
+	}
+	if !ok {
+		affected, ok = c.connector.rowsAffected[queryName(query)]
+	}
+	if !ok {
+		affected = 1
+	}
+	return rowResult{id: int64(len(c.connector.execs)), affected: affected}, nil
+}
+
+func (c *rowConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.connector.mu.Lock()
+	defer c.connector.mu.Unlock()
+	c.connector.queries++
+
+	// 引数で返す行を変える場合は "<クエリ名> <最初の引数>" で登録する
+	if len(args) > 0 {
+		if rows, ok := c.connector.rowsByQuery[fmt.Sprintf("%s %v", queryName(query), args[0].Value)]; ok {
+			return &rowsWithValues{rows: rows}, nil
+		}
+	}
+	if rows, ok := c.connector.rowsByQuery[queryName(query)]; ok {
+		return &rowsWithValues{rows: rows}, nil
+	}
+	return &rowsWithValues{rows: [][]driver.Value{c.connector.values}}, nil
+}
+
+// sqlcが生成するクエリは "-- name: <クエリ名> :<種類>" から始まる
+func queryName(query string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(query, "-- name: "), " ")
+	return name
+}
+
+type rowsWithValues struct {
+	rows [][]driver.Value
+	next int
+}
+
+func (r *rowsWithValues) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *rowsWithValues) Close() error {
+	return nil
+}
+
+func (r *rowsWithValues) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+func Test_再生回数の加算後のキャッシュ(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(10)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(sqlDB)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		userID string
+		want   int
+	}{
+		{name: "1回目の視聴", userID: "user_1", want: 11},
+		{name: "別のユーザーの視聴", userID: "user_2", want: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.IncrementWatchCount(ctx, "video_1", tt.userID)
+			if err != nil {
+				t.Fatalf("Infrastructure.IncrementWatchCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.IncrementWatchCount() = %v, want %v", got, tt.want)
+			}
+
+			queries := connector.queries
+			got, err = i.GetWatchCount(ctx, "video_1")
+			if err != nil {
+				t.Fatalf("Infrastructure.GetWatchCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.GetWatchCount() = %v, want %v", got, tt.want)
+			}
+			// キャッシュから返るのでDBには問い合わせない
+			if connector.queries != queries {
+				t.Errorf("GetWatchCount queried the database %d times", connector.queries-queries)
+			}
+		})
+	}
+}
+
+func Test_ユニーク視聴者数(t *testing.T) {
+	_, client := newTestRedis(t)
+	connector := &rowConnector{values: []driver.Value{int64(0)}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(sqlDB)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	// 同じユーザーが何度視聴しても1人として数える
+	for _, userID := range []string{"user_1", "user_2", "user_1", "user_3", "user_2"} {
+		_, err := i.IncrementWatchCount(ctx, "video_1", userID)
+		if err != nil {
+			t.Fatalf("Infrastructure.IncrementWatchCount() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		videoID string
+		want    int64
+	}{
+		{name: "視聴された動画", videoID: "video_1", want: 3},
+		{name: "視聴されていない動画", videoID: "video_2", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.GetUniqueViewerCount(ctx, tt.videoID)
+			if err != nil {
+				t.Fatalf("Infrastructure.GetUniqueViewerCount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.GetUniqueViewerCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_平均視聴時間(t *testing.T) {
+	tests := []struct {
+		name          string
+		dbCount       int64
+		dbDuration    int64
+		pendingCount  int64
+		durations     []int
+		want          float64
+		wantRecordErr error
+	}{
+		{name: "DBの値のみ", dbCount: 4, dbDuration: 100, want: 25},
+		{name: "未反映の値を含む", dbCount: 4, dbDuration: 100, pendingCount: 1, durations: []int{20}, want: 24},
+		{name: "未反映の値のみ", pendingCount: 2, durations: []int{10, 5}, want: 7.5},
+		{name: "視聴されていない", want: 0},
+		{name: "負の視聴時間", dbCount: 1, dbDuration: 10, durations: []int{-1}, want: 10, wantRecordErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			connector := &rowConnector{values: []driver.Value{tt.dbCount, tt.dbDuration}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:    &db.DB{Database: sqlc.New(sqlDB)},
+				redis: client,
+			}
+
+			ctx := context.Background()
+			if tt.pendingCount > 0 {
+				client.IncrBy(ctx, pendingWatchCountKey("video_1"), tt.pendingCount)
+			}
+			for _, d := range tt.durations {
+				err := i.RecordWatchDuration(ctx, "video_1", "user_1", d)
+				if !errors.Is(err, tt.wantRecordErr) {
+					t.Fatalf("Infrastructure.RecordWatchDuration() error = %v, want %v", err, tt.wantRecordErr)
+				}
+			}
+
+			got, err := i.GetAverageWatchDuration(ctx, "video_1")
+			if err != nil {
+				t.Fatalf("Infrastructure.GetAverageWatchDuration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Infrastructure.GetAverageWatchDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_動画の処理状態の更新(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           domain.ProcessingStatus
+		errMsg           string
+		wantErrorMessage any
+		wantErr          error
+	}{
+		{name: "処理中", status: domain.StatusProcessing, wantErrorMessage: nil},
+		{name: "失敗", status: domain.StatusFailed, errMsg: "ffmpeg failed", wantErrorMessage: "ffmpeg failed"},
+		{name: "成功ならエラーは保存しない", status: domain.StatusReady, errMsg: "ffmpeg failed", wantErrorMessage: nil},
+		{name: "不明な状態", status: "unknown", wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := relatedVideoRow("video_1", 0, "")
+			connector := &rowConnector{values: row[:len(row)-2]}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.UpdateVideoProcessingStatus(context.Background(), "video_1", tt.status, tt.errMsg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.UpdateVideoProcessingStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(connector.execs) != 0 {
+					t.Errorf("Infrastructure.UpdateVideoProcessingStatus() execs = %v, want none", connector.execs)
+				}
+				return
+			}
+
+			if want := []string{"UpdateVideoProcessingStatus", "CreateAuditLog"}; !reflect.DeepEqual(connector.execNames, want) {
+				t.Fatalf("Infrastructure.UpdateVideoProcessingStatus() execs = %v, want %v", connector.execNames, want)
+			}
+			if audit := connector.execs[1]; audit[2] != domain.AuditActionUpdateProcessingStatus || audit[3] != domain.AuditActorSystem {
+				t.Errorf("CreateAuditLog args = %v", audit)
+			}
+			args := connector.execs[0]
+			if args[0] != string(tt.status) {
+				t.Errorf("processing_status = %v, want %v", args[0], tt.status)
+			}
+			if args[1] != tt.wantErrorMessage {
+				t.Errorf("processing_error = %v, want %v", args[1], tt.wantErrorMessage)
+			}
+			if args[3] != "video_1" {
+				t.Errorf("id = %v, want video_1", args[3])
+			}
+		})
+	}
+}
+
+func Test_動画の登録時のタグの確認(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		wantTags []string
+		wantErr  error
+	}{
+		{name: "大文字と空白と重複", tags: []string{" JavaScript", "javascript", "", "Go"}, wantTags: []string{"javascript", "go"}},
+		{name: "長すぎるタグ", tags: []string{strings.Repeat("a", domain.MaxTagLength+1)}, wantErr: domain.ErrTagTooLong},
+		{name: "上限の数のタグ", tags: []string{"a", "b", "c"}, wantTags: []string{"a", "b", "c"}},
+		{name: "重複を除くと上限の数", tags: []string{"a", "b", "c", "A"}, wantTags: []string{"a", "b", "c"}},
+		{name: "タグが多すぎる", tags: []string{"a", "b", "c", "d"}, wantErr: domain.ErrTooManyTags},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr, client := newTestRedis(t)
+			connector := &rowConnector{}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:   client,
+				storage: NewMemoryBackend(),
+				config:  InfrastructureConfig{MaxTagsPerVideo: 3},
+			}
+
+			description := ""
+			got, err := i.InsertPendingVideo(context.Background(), "video_1", "", "title", &description, "user_1", tt.tags, domain.RatingGeneral, false, false, false, &domain.VideoMetadata{}, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.InsertPendingVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				// DBに書き込む前に失敗する
+				if len(connector.execs) != 0 {
+					t.Errorf("execs = %v, want none", connector.execNames)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got.Tags, tt.wantTags) {
+				t.Errorf("Infrastructure.InsertPendingVideo() Tags = %v, want %v", got.Tags, tt.wantTags)
+			}
+			var created []string
+			for n, name := range connector.execNames {
+				if name == "UpsertTag" {
+					created = append(created, connector.execs[n][0].(string))
+				}
+			}
+			if !reflect.DeepEqual(created, tt.wantTags) {
+				t.Errorf("UpsertTag = %v, want %v", created, tt.wantTags)
+			}
+			for _, tag := range tt.wantTags {
+				if score, err := mr.ZScore(tagAutocompleteKey, tag); err != nil || score != 1 {
+					t.Errorf("autocomplete score of %s = %v, %v, want 1", tag, score, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_タグの登録に失敗した動画のロールバック(t *testing.T) {
+	_, client := newTestRedis(t)
+	// 3つ目のタグの登録に失敗する
+	connector := &rowConnector{execErrors: map[string]error{"UpsertTag c": errors.New("deadlock")}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{
+		db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+		redis:   client,
+		storage: NewMemoryBackend(),
+		config:  InfrastructureConfig{MaxTagsPerVideo: 3},
+	}
+
+	description := ""
+	_, err := i.InsertPendingVideo(context.Background(), "video_1", "", "title", &description, "user_1", []string{"a", "b", "c"}, domain.RatingGeneral, false, false, false, &domain.VideoMetadata{}, nil)
+	if err == nil {
+		t.Fatal("Infrastructure.InsertPendingVideo() error = nil, want error")
+	}
+	if connector.commits != 0 || connector.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want 0 and 1", connector.commits, connector.rollbacks)
+	}
+	if connector.execNames[0] != "CreateVideo" {
+		t.Errorf("execs = %v, want CreateVideo first", connector.execNames)
+	}
+}
+
+func Test_同じIDの動画の再登録(t *testing.T) {
+	tests := []struct {
+		name         string
+		uploaderID   string
+		rowsAffected int64
+		status       domain.ProcessingStatus
+		wantNew      bool
+		wantStatus   domain.ProcessingStatus
+		wantErr      error
+		wantExecs    []string
+	}{
+		{name: "新しい動画", uploaderID: "user_1", rowsAffected: 1, wantNew: true, wantStatus: domain.StatusPending, wantExecs: []string{"CreateVideo", "UpsertTag", "CreateVideoTags", "CreateAuditLog"}},
+		{name: "再送された登録", uploaderID: "user_1", rowsAffected: 2, status: domain.StatusReady, wantNew: false, wantStatus: domain.StatusReady, wantExecs: []string{"CreateVideo"}},
+		{name: "同じ秒に再送された登録", uploaderID: "user_1", rowsAffected: 0, status: domain.StatusReady, wantNew: false, wantStatus: domain.StatusReady, wantExecs: []string{"CreateVideo"}},
+		{name: "変換に失敗した動画の再送", uploaderID: "user_1", rowsAffected: 2, status: domain.StatusFailed, wantNew: false, wantStatus: domain.StatusFailed, wantExecs: []string{"CreateVideo"}},
+		{name: "他のユーザーの動画", uploaderID: "user_2", rowsAffected: 2, status: domain.StatusReady, wantErr: domain.ErrPermissionDenied, wantExecs: []string{"CreateVideo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newTestRedis(t)
+			row := relatedVideoRow("video_1", 0, "")
+			row[21] = string(tt.status)
+			connector := &rowConnector{
+				rowsByQuery:  map[string][][]driver.Value{"GetVideoForUpdate": {row[:len(row)-2]}},
+				rowsAffected: map[string]int64{"CreateVideo": tt.rowsAffected},
+			}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{
+				db:      &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB},
+				redis:   client,
+				storage: NewMemoryBackend(),
+			}
+
+			description := ""
+			got, err := i.InsertPendingVideo(context.Background(), "video_1", "", "title", &description, tt.uploaderID, []string{"go"}, domain.RatingGeneral, false, false, false, &domain.VideoMetadata{}, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Infrastructure.InsertPendingVideo() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecs) {
+				t.Errorf("execs = %v, want %v", connector.execNames, tt.wantExecs)
+			}
+			if tt.wantErr != nil {
+				if connector.commits != 0 {
+					t.Errorf("commits = %d, want 0", connector.commits)
+				}
+				return
+			}
+			if got.IsNew != tt.wantNew || got.ProcessingStatus != tt.wantStatus {
+				t.Errorf("Infrastructure.InsertPendingVideo() IsNew, ProcessingStatus = %v, %v, want %v, %v", got.IsNew, got.ProcessingStatus, tt.wantNew, tt.wantStatus)
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+		})
+	}
+}
+
+func Test_アップロード前の投稿者の確認(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        [][]driver.Value
+		uploaderID  string
+		wantRetried bool
+		wantErr     error
+	}{
+		{name: "新しいID", rows: [][]driver.Value{}, uploaderID: "user_1"},
+		{name: "自分の動画の再送", rows: [][]driver.Value{{"user_1"}}, uploaderID: "user_1", wantRetried: true},
+		{name: "他のユーザーの動画", rows: [][]driver.Value{{"user_1"}}, uploaderID: "user_2", wantErr: domain.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"GetVideoUploaderID": tt.rows}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			err := i.CheckVideoUploader(context.Background(), "video_1", tt.uploaderID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Infrastructure.CheckVideoUploader() error = %v, want %v", err, tt.wantErr)
+			}
+			retried, err := i.IsRetriedUpload(context.Background(), "video_1", tt.uploaderID)
+			if !errors.Is(err, tt.wantErr) || retried != tt.wantRetried {
+				t.Errorf("Infrastructure.IsRetriedUpload() = %v, %v, want %v, %v", retried, err, tt.wantRetried, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_論理削除した動画の取得(t *testing.T) {
+	// 論理削除した動画はGetUndeletedVideoで見つからない
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetVideo":          {deleteTestVideoRow(true)},
+		"GetUndeletedVideo": {},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	_, err := i.GetVideoFromDB(context.Background(), "video_1")
+	if !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("Infrastructure.GetVideoFromDB() error = %v, want %v", err, domain.ErrVideoNotFound)
+	}
+}
+
+func Test_論理削除した動画の加工(t *testing.T) {
+	// permanent_banにした動画も論理削除されているためGetUndeletedVideoで見つからない
+	row := deleteTestVideoRow(true)
+	row[len(row)-1] = true
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+		"GetVideo":          {row},
+		"GetUndeletedVideo": {},
+	}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+	ctx := context.Background()
+	_, err := i.SplitVideo(ctx, "video_1", 1, "user_1")
+	if !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("Infrastructure.SplitVideo() error = %v, want %v", err, domain.ErrVideoNotFound)
+	}
+	_, _, err = i.GenerateThumbnailSprite(ctx, "video_1", 0)
+	if !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("Infrastructure.GenerateThumbnailSprite() error = %v, want %v", err, domain.ErrVideoNotFound)
+	}
+	_, err = i.GenerateAnimatedPreview(ctx, "video_1")
+	if !errors.Is(err, domain.ErrVideoNotFound) {
+		t.Errorf("Infrastructure.GenerateAnimatedPreview() error = %v, want %v", err, domain.ErrVideoNotFound)
+	}
+	if len(connector.execs) != 0 {
+		t.Errorf("execs = %v, want none", connector.execNames)
+	}
+}
@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// まだDBに反映していない再生回数と視聴時間のキー
+// GetWatchCountのキャッシュ(watchcount_<videoID>)とは別のキーにする
+const (
+	pendingWatchCountPrefix    = "watchcount:"
+	pendingWatchDurationPrefix = "watchduration:"
+)
+
+// 急上昇の集計に使う期間より長く残しておく
+const watchCountSnapshotRetention = 7 * 24 * time.Hour
+
+func pendingWatchCountKey(videoID string) string {
+	return pendingWatchCountPrefix + videoID
+}
+
+func pendingWatchDurationKey(videoID string) string {
+	return pendingWatchDurationPrefix + videoID
+}
+
+// intervalごとにRedisに溜まった再生回数と視聴時間をDBに反映する
+// ctxが終了すると残っている分を反映してから戻る
+func (i *Infrastructure) StartWatchCountFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := i.flushWatchCounts(context.Background())
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to flush watch counts", "error", err)
+			}
+			return
+		case <-ticker.C:
+			err := i.flushWatchCounts(ctx)
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to flush watch counts", "error", err)
+			}
+		}
+	}
+}
+
+func (i *Infrastructure) flushWatchCounts(ctx context.Context) error {
+	now := time.Now()
+	err := i.flushPendingCounts(ctx, pendingWatchCountPrefix, func(ctx context.Context, videoID string, count int64) error {
+		_, err := i.db.Database.AddWatchCount(ctx, sqlc.AddWatchCountParams{
+			WatchCount: int32(count),
+			ID:         videoID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// DBには反映済みなので以降は失敗しても戻さない
+		// DBの値が変わったのでキャッシュを消す
+		err = i.redis.Del(ctx, "watchcount"+domain.IDSeparator+videoID).Err()
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to delete watch count cache", "videoID", videoID, "error", err)
+		}
+
+		// 急上昇の動画を求めるために反映後の再生回数を記録する
+		_, err = i.db.Database.CreateWatchCountSnapshot(ctx, sqlc.CreateWatchCountSnapshotParams{
+			SnapshotTime: now,
+			VideoID:      videoID,
+		})
+		if err != nil {
+			i.log().WarnContext(ctx, "failed to create watch count snapshot", "videoID", videoID, "error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = i.db.Database.DeleteWatchCountSnapshotsBefore(ctx, now.Add(-watchCountSnapshotRetention))
+	if err != nil {
+		return err
+	}
+
+	return i.flushPendingCounts(ctx, pendingWatchDurationPrefix, func(ctx context.Context, videoID string, count int64) error {
+		_, err := i.db.Database.AddWatchDuration(ctx, sqlc.AddWatchDurationParams{
+			WatchDurationTotal: count,
+			ID:                 videoID,
+		})
+		return err
+	})
+}
+
+// prefixから始まるキーの値をapplyでDBに反映してから消す
+func (i *Infrastructure) flushPendingCounts(ctx context.Context, prefix string, apply func(context.Context, string, int64) error) error {
+	iter := i.redis.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		// 取得と削除を同時に行い、反映中に増えた分は次回に回す
+		count, err := i.redis.GetDel(ctx, key).Int64()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return err
+		}
+		if count == 0 {
+			continue
+		}
+
+		err = apply(ctx, strings.TrimPrefix(key, prefix), count)
+		if err != nil {
+			// 反映できなかった分は戻して次回に持ち越す
+			restoreErr := i.redis.IncrBy(ctx, key, count).Err()
+			return errors.Join(err, restoreErr)
+		}
+	}
+	return iter.Err()
+}
+
+// DBに反映していない分を返す
+func (i *Infrastructure) getPendingCount(ctx context.Context, key string) (int64, error) {
+	count, err := i.redis.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
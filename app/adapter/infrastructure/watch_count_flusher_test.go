@@ -0,0 +1,184 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+// ExecContextの呼び出しだけを記録するDB
+type fakeDBTX struct {
+	mu    sync.Mutex
+	execs [][]interface{}
+	names []string
+	err   error
+}
+
+func (f *fakeDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.execs = append(f.execs, args)
+	f.names = append(f.names, queryName(query))
+	return driver.RowsAffected(1), nil
+}
+
+// sqlcのクエリ名がnameの呼び出しの引数を返す
+func (f *fakeDBTX) execsOf(name string) [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var execs [][]interface{}
+	for n, args := range f.execs {
+		if f.names[n] == name {
+			execs = append(execs, args)
+		}
+	}
+	return execs
+}
+
+func (f *fakeDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func Test_再生回数のDBへの反映(t *testing.T) {
+	mr, client := newTestRedis(t)
+	fake := &fakeDBTX{}
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(fake)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	client.IncrBy(ctx, pendingWatchCountKey("video_1"), 3)
+	client.IncrBy(ctx, pendingWatchCountKey("video_2"), 2)
+	mr.Set("watchcount_video_1", `{"count":10}`)
+
+	err := i.flushWatchCounts(ctx)
+	if err != nil {
+		t.Fatalf("Infrastructure.flushWatchCounts() error = %v", err)
+	}
+
+	execs := fake.execsOf("AddWatchCount")
+	sort.Slice(execs, func(a, b int) bool {
+		return execs[a][1].(string) < execs[b][1].(string)
+	})
+	want := [][]interface{}{{int32(3), "video_1"}, {int32(2), "video_2"}}
+	if len(execs) != len(want) {
+		t.Fatalf("exec calls = %v, want %v", execs, want)
+	}
+	for n := range want {
+		if execs[n][0] != want[n][0] || execs[n][1] != want[n][1] {
+			t.Errorf("exec args = %v, want %v", execs[n], want[n])
+		}
+	}
+	// 反映した動画ごとにスナップショットを記録する
+	if snapshots := fake.execsOf("CreateWatchCountSnapshot"); len(snapshots) != len(want) {
+		t.Errorf("snapshot calls = %v, want %d calls", snapshots, len(want))
+	}
+
+	for _, key := range []string{pendingWatchCountKey("video_1"), pendingWatchCountKey("video_2"), "watchcount_video_1"} {
+		if mr.Exists(key) {
+			t.Errorf("key %s was not removed", key)
+		}
+	}
+}
+
+func Test_視聴時間のDBへの反映(t *testing.T) {
+	mr, client := newTestRedis(t)
+	fake := &fakeDBTX{}
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(fake)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	for _, d := range []int{30, 45} {
+		err := i.RecordWatchDuration(ctx, "video_1", "user_1", d)
+		if err != nil {
+			t.Fatalf("Infrastructure.RecordWatchDuration() error = %v", err)
+		}
+	}
+
+	err := i.flushWatchCounts(ctx)
+	if err != nil {
+		t.Fatalf("Infrastructure.flushWatchCounts() error = %v", err)
+	}
+
+	execs := fake.execsOf("AddWatchDuration")
+	if len(execs) != 1 || execs[0][0] != int64(75) || execs[0][1] != "video_1" {
+		t.Errorf("exec calls = %v, want [[75 video_1]]", execs)
+	}
+	if mr.Exists(pendingWatchDurationKey("video_1")) {
+		t.Errorf("key %s was not removed", pendingWatchDurationKey("video_1"))
+	}
+}
+
+func Test_再生回数のDBへの反映に失敗した場合(t *testing.T) {
+	mr, client := newTestRedis(t)
+	fake := &fakeDBTX{err: errors.New("db error")}
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(fake)},
+		redis: client,
+	}
+
+	ctx := context.Background()
+	client.IncrBy(ctx, pendingWatchCountKey("video_1"), 3)
+
+	err := i.flushWatchCounts(ctx)
+	if err == nil {
+		t.Fatal("Infrastructure.flushWatchCounts() error = nil, want error")
+	}
+
+	// 反映できなかった分は次回に持ち越される
+	got, err := mr.Get(pendingWatchCountKey("video_1"))
+	if err != nil || got != "3" {
+		t.Errorf("pending count = %v, %v, want 3", got, err)
+	}
+}
+
+func Test_再生回数の反映の停止(t *testing.T) {
+	_, client := newTestRedis(t)
+	fake := &fakeDBTX{}
+	i := &Infrastructure{
+		db:    &db.DB{Database: sqlc.New(fake)},
+		redis: client,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.IncrBy(ctx, pendingWatchCountKey("video_1"), 1)
+
+	done := make(chan struct{})
+	go func() {
+		i.StartWatchCountFlusher(ctx, time.Hour)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("StartWatchCountFlusher did not stop after cancel")
+	}
+	// 停止時に残っていた分が反映される
+	if execs := fake.execsOf("AddWatchCount"); len(execs) != 1 {
+		t.Errorf("exec calls = %v, want 1 call", execs)
+	}
+}
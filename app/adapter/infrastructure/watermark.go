@@ -0,0 +1,118 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	WatermarkTopLeft     = "top-left"
+	WatermarkTopRight    = "top-right"
+	WatermarkBottomLeft  = "bottom-left"
+	WatermarkBottomRight = "bottom-right"
+	WatermarkCenter      = "center"
+)
+
+// ウォーターマーク画像はアップロードしたユーザーごとに<uploaderID>/<key>に保存する
+const watermarkBucketName = "watermark"
+
+// ウォーターマーク画像を保存し、InsertVideoに渡すキーを返す
+func (i *Infrastructure) UploadWatermark(ctx context.Context, uploaderID, imagePath string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "UploadWatermark")
+	span.SetAttributes(attribute.String("uploaderID", uploaderID))
+	defer func() { endSpan(span, err) }()
+
+	key := domain.NewUUID() + filepath.Ext(imagePath)
+	err = i.uploadObject(ctx, imagePath, watermarkBucketName, watermarkObjectKey(uploaderID, key), mime.TypeByExtension(filepath.Ext(imagePath)))
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func watermarkObjectKey(uploaderID, key string) string {
+	return uploaderID + "/" + key
+}
+
+// videoPathの動画にwatermarkPathの画像を重ね、出力したファイルのパスを返す
+// watermarkPathにはURLも指定できる
+func (i *Infrastructure) ApplyWatermark(ctx context.Context, videoPath, watermarkPath string, position string) (_ string, err error) {
+	ctx, span := infraSpan(ctx, "ApplyWatermark")
+	defer func() { endSpan(span, err) }()
+
+	overlay, err := watermarkOverlay(position, i.config.WatermarkMargin)
+	if err != nil {
+		return "", err
+	}
+
+	outputPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_watermarked" + filepath.Ext(videoPath)
+	_, err = runFFmpeg(ctx, "watermark", []string{"-i", videoPath, "-i", watermarkPath, "-filter_complex", "[0:v][1:v]overlay=" + overlay, "-c:a", "copy", outputPath, "-y"})
+	if err != nil {
+		os.Remove(outputPath)
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// 位置の文字列をffmpegのoverlayフィルタのx:yに変換する
+func watermarkOverlay(position string, margin int64) (string, error) {
+	switch position {
+	case WatermarkTopLeft:
+		return fmt.Sprintf("%d:%d", margin, margin), nil
+	case WatermarkTopRight:
+		return fmt.Sprintf("main_w-overlay_w-%d:%d", margin, margin), nil
+	case WatermarkBottomLeft:
+		return fmt.Sprintf("%d:main_h-overlay_h-%d", margin, margin), nil
+	case WatermarkBottomRight:
+		return fmt.Sprintf("main_w-overlay_w-%d:main_h-overlay_h-%d", margin, margin), nil
+	case WatermarkCenter:
+		return "(main_w-overlay_w)/2:(main_h-overlay_h)/2", nil
+	default:
+		return "", fmt.Errorf("%w: %s", domain.ErrInvalidWatermarkPosition, position)
+	}
+}
+
+// アップロード済みの動画にウォーターマークを入れ、HLSを作り直して同じパスに上書きする
+func (i *Infrastructure) watermarkUploadedVideo(ctx context.Context, id, uploaderID, watermarkKey string) error {
+	tempMp4, err := localVideoPath(id)
+	if err != nil {
+		return err
+	}
+	err = i.watermarkLocalVideo(ctx, tempMp4, uploaderID, watermarkKey)
+	if err != nil {
+		return err
+	}
+
+	err = i.ConvertVideoHLS(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = i.UploadVideoForStorage(ctx, domain.NewVideoFile(id, nil))
+	return err
+}
+
+// pathの動画をウォーターマークを重ねた動画で置き換える
+func (i *Infrastructure) watermarkLocalVideo(ctx context.Context, path, uploaderID, watermarkKey string) error {
+	watermarkURL, err := i.bucket(watermarkBucketName).PresignGetURL(ctx, watermarkObjectKey(uploaderID, watermarkKey), presignedURLExpires)
+	if err != nil {
+		return err
+	}
+
+	watermarked, err := i.ApplyWatermark(ctx, path, watermarkURL, i.config.WatermarkPosition)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(watermarked, path)
+	if err != nil {
+		os.Remove(watermarked)
+		return err
+	}
+	return nil
+}
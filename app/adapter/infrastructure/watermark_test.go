@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+func Test_ウォーターマークの位置(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "top-left",
+			position: WatermarkTopLeft,
+			want:     "16:16",
+		},
+		{
+			name:     "top-right",
+			position: WatermarkTopRight,
+			want:     "main_w-overlay_w-16:16",
+		},
+		{
+			name:     "bottom-left",
+			position: WatermarkBottomLeft,
+			want:     "16:main_h-overlay_h-16",
+		},
+		{
+			name:     "bottom-right",
+			position: WatermarkBottomRight,
+			want:     "main_w-overlay_w-16:main_h-overlay_h-16",
+		},
+		{
+			name:     "center",
+			position: WatermarkCenter,
+			want:     "(main_w-overlay_w)/2:(main_h-overlay_h)/2",
+		},
+		{
+			name:     "unknown",
+			position: "middle-left",
+			wantErr:  domain.ErrInvalidWatermarkPosition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := watermarkOverlay(tt.position, 16)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("watermarkOverlay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("watermarkOverlay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
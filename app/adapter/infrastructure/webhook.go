@@ -0,0 +1,232 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/db/sqlc"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookEventHeader     = "X-Webhook-Event"
+	// 最初の送信に失敗した後に再送する回数
+	webhookMaxRetries = 5
+	// 1回に再送する数
+	webhookRetryBatchSize = 100
+	// 再送する行を取得したインスタンスが送り終えるまで、他のインスタンスが同じ行を取得しないように再送の時刻を遅らせる
+	// 1回に再送する全ての送信がタイムアウトしても終わる時間にし、途中で止まった場合はこの時間の後に他のインスタンスが再送する
+	webhookClaimTimeout = 20 * time.Minute
+	// 送信待ちのイベントの数。溢れた場合は捨てる
+	webhookEventBufferSize = 1000
+)
+
+// テストで待たずに再送できるように変数にしている
+var (
+	webhookClient           = &http.Client{Timeout: 10 * time.Second}
+	webhookRetryBaseBackoff = 30 * time.Second
+)
+
+// attempts回再送した後に次に再送するまでの時間。1回ごとに2倍にする
+func webhookRetryBackoff(attempts int32) time.Duration {
+	return webhookRetryBaseBackoff << attempts
+}
+
+// 受信側が本文の改ざんを確認できるように、Secretを鍵にしたHMAC-SHA256を付ける
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IDが空の場合は新しく作る
+func (i *Infrastructure) RegisterWebhookTarget(ctx context.Context, target domain.WebhookTarget) (err error) {
+	ctx, span := infraSpan(ctx, "RegisterWebhookTarget")
+	span.SetAttributes(attribute.String("webhookTargetID", target.ID))
+	defer func() { endSpan(span, err) }()
+
+	err = target.Validate()
+	if err != nil {
+		return err
+	}
+	if target.ID == "" {
+		target.ID = domain.NewUUID()
+	}
+
+	_, err = i.db.Database.CreateWebhookTarget(ctx, sqlc.CreateWebhookTargetParams{
+		ID:        target.ID,
+		Url:       target.URL,
+		Secret:    target.Secret,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// 登録されたすべての通知先に送信する
+// 失敗した通知先はwebhook_deliveriesに保存し、StartWebhookDispatcherで再送する
+func (i *Infrastructure) DeliverWebhookEvent(ctx context.Context, event domain.WebhookEvent) (err error) {
+	ctx, span := infraSpan(ctx, "DeliverWebhookEvent")
+	span.SetAttributes(attribute.String("videoID", event.VideoID), attribute.String("eventType", event.Type))
+	defer func() { endSpan(span, err) }()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	targets, err := i.db.Database.ListWebhookTargets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		sendErr := sendWebhook(ctx, target.Url, target.Secret, event.Type, body)
+		if sendErr == nil {
+			continue
+		}
+
+		i.log().WarnContext(ctx, "failed to deliver webhook", "videoID", event.VideoID, "webhookTargetID", target.ID, "error", sendErr)
+		now := time.Now()
+		_, err = i.db.Database.CreateWebhookDelivery(ctx, sqlc.CreateWebhookDeliveryParams{
+			TargetID:      target.ID,
+			EventType:     event.Type,
+			VideoID:       event.VideoID,
+			Body:          string(body),
+			Attempts:      0,
+			NextAttemptAt: now.Add(webhookRetryBackoff(0)),
+			LastError:     sql.NullString{String: sendErr.Error(), Valid: true},
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendWebhook(ctx context.Context, url, secret, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, eventType)
+	req.Header.Set(webhookSignatureHeader, webhookSignature(secret, body))
+
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// 動画の操作を止めないように、イベントはバックグラウンドで送信する
+// テストで作成したInfrastructureはチャネルを持たないため送信しない
+func (i *Infrastructure) emitWebhookEvent(ctx context.Context, eventType, videoID string, payload interface{}) {
+	if i.webhookEvents == nil {
+		return
+	}
+
+	select {
+	case i.webhookEvents <- domain.NewWebhookEvent(eventType, videoID, payload):
+	default:
+		i.log().WarnContext(ctx, "webhook event buffer is full, dropping event", "videoID", videoID, "eventType", eventType)
+	}
+}
+
+// emitWebhookEventで受け取ったイベントを送信し、retryIntervalごとに失敗した送信を再送する
+func (i *Infrastructure) StartWebhookDispatcher(ctx context.Context, retryInterval time.Duration) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-i.webhookEvents:
+			err := i.DeliverWebhookEvent(ctx, event)
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to deliver webhook event", "videoID", event.VideoID, "eventType", event.Type, "error", err)
+			}
+		case <-ticker.C:
+			err := i.retryWebhookDeliveries(ctx, time.Now())
+			if err != nil {
+				i.log().ErrorContext(ctx, "failed to retry webhook deliveries", "error", err)
+			}
+		}
+	}
+}
+
+// 再送の時刻を過ぎた送信を再送する
+// 成功したら行を消し、失敗したら次の再送の時刻を遅らせる
+// webhookMaxRetries回失敗した行は調査できるように残す
+// 複数のインスタンスで同じ行を再送しないように、送る前に行を取得したことを記録してからコミットする
+func (i *Infrastructure) retryWebhookDeliveries(ctx context.Context, now time.Time) error {
+	var deliveries []sqlc.ListDueWebhookDeliveriesRow
+	err := i.db.WithTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		deliveries, err = q.ListDueWebhookDeliveries(ctx, sqlc.ListDueWebhookDeliveriesParams{
+			Attempts:      webhookMaxRetries,
+			NextAttemptAt: now,
+			Limit:         webhookRetryBatchSize,
+		})
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]int64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		_, err = q.ClaimWebhookDeliveries(ctx, sqlc.ClaimWebhookDeliveriesParams{
+			NextAttemptAt: now.Add(webhookClaimTimeout),
+			Ids:           ids,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		sendErr := sendWebhook(ctx, delivery.Url, delivery.Secret, delivery.EventType, []byte(delivery.Body))
+		if sendErr == nil {
+			_, err = i.db.Database.DeleteWebhookDelivery(ctx, delivery.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempts := delivery.Attempts + 1
+		if attempts >= webhookMaxRetries {
+			i.log().ErrorContext(ctx, "gave up delivering webhook", "videoID", delivery.VideoID, "webhookTargetID", delivery.TargetID, "error", sendErr)
+		}
+		_, err = i.db.Database.UpdateWebhookDeliveryAttempt(ctx, sqlc.UpdateWebhookDeliveryAttemptParams{
+			Attempts:      attempts,
+			NextAttemptAt: now.Add(webhookRetryBackoff(attempts)),
+			LastError:     sql.NullString{String: sendErr.Error(), Valid: true},
+			ID:            delivery.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"github.com/yuorei/video-server/app/driver/db"
+	"github.com/yuorei/video-server/db/sqlc"
+)
+
+func Test_Webhookの送信(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantExecNames []string
+	}{
+		{name: "成功", status: http.StatusOK},
+		{name: "失敗した場合は再送に保存する", status: http.StatusInternalServerError, wantExecNames: []string{"CreateWebhookDelivery"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody []byte
+			var gotSignature, gotEvent string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = io.ReadAll(r.Body)
+				gotSignature = r.Header.Get(webhookSignatureHeader)
+				gotEvent = r.Header.Get(webhookEventHeader)
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(srv.Close)
+
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"ListWebhookTargets": {{"target_1", srv.URL, "secret"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB)}}
+
+			event := domain.NewWebhookEvent(domain.WebhookEventVideoUpdated, "video_1", map[string]string{"title": "new title"})
+			err := i.DeliverWebhookEvent(context.Background(), event)
+			if err != nil {
+				t.Fatalf("Infrastructure.DeliverWebhookEvent() error = %v", err)
+			}
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write(gotBody)
+			if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSignature != want {
+				t.Errorf("signature = %s, want %s", gotSignature, want)
+			}
+			if gotEvent != domain.WebhookEventVideoUpdated {
+				t.Errorf("event header = %s, want %s", gotEvent, domain.WebhookEventVideoUpdated)
+			}
+			var got domain.WebhookEvent
+			if err := json.Unmarshal(gotBody, &got); err != nil || got.VideoID != "video_1" {
+				t.Errorf("body = %s, want event of video_1", gotBody)
+			}
+
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Errorf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+			if tt.wantExecNames != nil {
+				args := connector.execs[0]
+				want := []driver.Value{"target_1", domain.WebhookEventVideoUpdated, "video_1", string(gotBody), int64(0)}
+				if !reflect.DeepEqual(args[:5], want) {
+					t.Errorf("CreateWebhookDelivery args = %v, want %v", args[:5], want)
+				}
+			}
+		})
+	}
+}
+
+func Test_Webhookの再送(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		status        int
+		attempts      int64
+		wantExecNames []string
+		wantAttempts  int64
+		wantNext      time.Time
+	}{
+		{name: "成功したら削除する", status: http.StatusOK, attempts: 0, wantExecNames: []string{"ClaimWebhookDeliveries", "DeleteWebhookDelivery"}},
+		{name: "失敗したら次の再送を遅らせる", status: http.StatusBadGateway, attempts: 0, wantExecNames: []string{"ClaimWebhookDeliveries", "UpdateWebhookDeliveryAttempt"}, wantAttempts: 1, wantNext: now.Add(2 * webhookRetryBaseBackoff)},
+		{name: "再送するたびに間隔を2倍にする", status: http.StatusBadGateway, attempts: 3, wantExecNames: []string{"ClaimWebhookDeliveries", "UpdateWebhookDeliveryAttempt"}, wantAttempts: 4, wantNext: now.Add(16 * webhookRetryBaseBackoff)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(srv.Close)
+
+			connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{
+				"ListDueWebhookDeliveries": {{int64(1), domain.WebhookEventVideoDeleted, "video_1", `{"type":"video.deleted"}`, tt.attempts, "target_1", srv.URL, "secret"}},
+			}}
+			sqlDB := sql.OpenDB(connector)
+			t.Cleanup(func() { sqlDB.Close() })
+			i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+			err := i.retryWebhookDeliveries(context.Background(), now)
+			if err != nil {
+				t.Fatalf("Infrastructure.retryWebhookDeliveries() error = %v", err)
+			}
+			if !reflect.DeepEqual(connector.execNames, tt.wantExecNames) {
+				t.Fatalf("exec names = %v, want %v", connector.execNames, tt.wantExecNames)
+			}
+			// 送る前に他のインスタンスが取得しないように再送の時刻を遅らせてコミットする
+			claim := connector.execs[0]
+			if claim[0] != now.Add(webhookClaimTimeout) || claim[1] != int64(1) {
+				t.Errorf("ClaimWebhookDeliveries args = %v, want [%v 1]", claim, now.Add(webhookClaimTimeout))
+			}
+			if connector.commits != 1 {
+				t.Errorf("commits = %d, want 1", connector.commits)
+			}
+			if tt.wantAttempts == 0 {
+				return
+			}
+			args := connector.execs[1]
+			if args[0] != tt.wantAttempts || args[1] != tt.wantNext {
+				t.Errorf("UpdateWebhookDeliveryAttempt args = %v, want attempts %d next %v", args, tt.wantAttempts, tt.wantNext)
+			}
+		})
+	}
+}
+
+func Test_再送するWebhookがない場合(t *testing.T) {
+	connector := &rowConnector{rowsByQuery: map[string][][]driver.Value{"ListDueWebhookDeliveries": {}}}
+	sqlDB := sql.OpenDB(connector)
+	t.Cleanup(func() { sqlDB.Close() })
+	i := &Infrastructure{db: &db.DB{Database: sqlc.New(sqlDB), SQL: sqlDB}}
+
+	err := i.retryWebhookDeliveries(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Infrastructure.retryWebhookDeliveries() error = %v", err)
+	}
+	if len(connector.execs) != 0 {
+		t.Errorf("exec names = %v, want none", connector.execNames)
+	}
+}
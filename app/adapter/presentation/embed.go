@@ -0,0 +1,207 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	embedPathPrefix     = "/embed/"
+	embedCodePathPrefix = "/embed-code/"
+)
+
+// 他のサイトのiframeの中で表示するので、ナビゲーションなどは置かずに動画のみ表示する
+// Safari以外はHLSを再生できないのでhls.jsを使う
+var embedPlayerTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Title}}">
+{{range $property, $content := .OpenGraph}}<meta property="{{$property}}" content="{{$content}}">
+{{end}}{{with .JSONLD}}<script type="application/ld+json">{{.}}</script>
+{{end}}<style>html,body{margin:0;height:100%;background:#000}video{width:100%;height:100%}</style>
+</head>
+<body>
+<video id="player" controls playsinline poster="{{.ThumbnailURL}}" data-src="{{.VideoURL}}" data-start="{{.StartSeconds}}"></video>
+<script src="https://cdn.jsdelivr.net/npm/hls.js@1"></script>
+<script>
+const video = document.getElementById("player");
+const src = video.dataset.src;
+if (video.canPlayType("application/vnd.apple.mpegurl")) {
+  video.src = src;
+} else if (window.Hls && Hls.isSupported()) {
+  const hls = new Hls();
+  hls.loadSource(src);
+  hls.attachMedia(video);
+}
+video.addEventListener("loadedmetadata", () => {
+  video.currentTime = Number(video.dataset.start);
+}, { once: true });
+</script>
+</body>
+</html>
+`))
+
+type embedPlayerData struct {
+	Title        string
+	VideoURL     string
+	ThumbnailURL string
+	StartSeconds int
+	OEmbedURL    string
+	OpenGraph    map[string]string
+	// json.Marshalが<と>をエスケープするため、<script>の中にそのまま書ける
+	JSONLD template.JS
+}
+
+type EmbedVideoGetter interface {
+	GetVideo(ctx context.Context, videoID string) (*domain.Video, error)
+}
+
+type embedCodeResponse struct {
+	HTML   string `json:"html"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// siteURLはiframeのsrcに使う、埋め込み用のページがあるURL
+type EmbedHandler struct {
+	getter  EmbedVideoGetter
+	siteURL string
+}
+
+func NewEmbedHandler(getter EmbedVideoGetter, siteURL string) *EmbedHandler {
+	return &EmbedHandler{getter: getter, siteURL: siteURL}
+}
+
+// GET /embed/{id}?t={seconds}
+// 埋め込めない動画は404、視聴できない国からのリクエストは451を返す
+func (h *EmbedHandler) HandleEmbed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	videoID := strings.TrimPrefix(r.URL.Path, embedPathPrefix)
+	if videoID == "" || strings.Contains(videoID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	video, ok := h.getEmbeddableVideo(w, r, videoID)
+	if !ok {
+		return
+	}
+
+	data := embedPlayerData{
+		Title:        video.Title,
+		VideoURL:     video.VideoURL,
+		ThumbnailURL: video.ThumbnailImageURL,
+		OEmbedURL:    oEmbedDiscoveryURL(h.siteURL, video.ID),
+		OpenGraph:    GenerateOpenGraphMeta(video, h.siteURL),
+	}
+	jsonLD, err := GenerateJSONLD(video, h.siteURL)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to generate JSON-LD", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data.JSONLD = template.JS(jsonLD)
+	// 不正な開始位置は最初から再生する
+	if t := r.URL.Query().Get(domain.ShareStartTimeParam); t != "" {
+		seconds, err := strconv.Atoi(t)
+		if err == nil && video.ValidateStartTime(seconds) == nil {
+			data.StartSeconds = seconds
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors *")
+	err = embedPlayerTemplate.Execute(w, data)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to render embed player", "videoID", videoID, "error", err)
+	}
+}
+
+// GET /embed-code/{id}?width={px}&height={px}
+// 幅と高さを省略した場合は動画の大きさを使う
+func (h *EmbedHandler) HandleEmbedCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	videoID := strings.TrimPrefix(r.URL.Path, embedCodePathPrefix)
+	if videoID == "" || strings.Contains(videoID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	video, ok := h.getEmbeddableVideo(w, r, videoID)
+	if !ok {
+		return
+	}
+
+	width, height := oEmbedSize(video.Width, video.Height, 0, 0)
+	query := r.URL.Query()
+	if s := query.Get("width"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "width must be an integer", http.StatusBadRequest)
+			return
+		}
+		width = n
+	}
+	if s := query.Get("height"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "height must be an integer", http.StatusBadRequest)
+			return
+		}
+		height = n
+	}
+
+	code, err := domain.GenerateEmbedCode(video, h.siteURL, width, height)
+	if errors.Is(err, domain.ErrInvalidEmbedDimensions) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to generate embed code", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(embedCodeResponse{HTML: code, Width: width, Height: height})
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to write embed code", "videoID", videoID, "error", err)
+	}
+}
+
+// 埋め込めない動画の場合はレスポンスを書いてfalseを返す
+func (h *EmbedHandler) getEmbeddableVideo(w http.ResponseWriter, r *http.Request, videoID string) (*domain.Video, bool) {
+	video, err := h.getter.GetVideo(r.Context(), videoID)
+	if errors.Is(err, domain.ErrVideoNotFound) || errors.Is(err, domain.ErrVideoExpired) {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get video for embedding", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, false
+	}
+	if !video.IsEmbeddable() {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if !allowVideoInClientRegion(w, r, video) {
+		return nil, false
+	}
+	return video, true
+}
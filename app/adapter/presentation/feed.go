@@ -0,0 +1,162 @@
+package presentation
+
+import (
+	"context"
+	"encoding/xml"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	feedPathPrefix = "/feed/"
+	// HLSのプレイリストのMIMEタイプ
+	hlsContentType = "application/vnd.apple.mpegurl"
+)
+
+type FeedVideoGetter interface {
+	GetVideosByUserID(ctx context.Context, userID string) ([]*domain.Video, error)
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	MediaNS string     `xml:"xmlns:media,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	GUID        rssGUID        `xml:"guid"`
+	PubDate     string         `xml:"pubDate"`
+	Description string         `xml:"description,omitempty"`
+	Enclosure   rssEnclosure   `xml:"enclosure"`
+	Thumbnail   *rssMediaImage `xml:"media:thumbnail,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL string `xml:"url,attr"`
+	// HLSのプレイリストなので動画全体の大きさはわからない
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssMediaImage struct {
+	URL string `xml:"url,attr"`
+}
+
+// siteURLは動画のページがあるフロントエンドのURL
+type FeedHandler struct {
+	getter  FeedVideoGetter
+	siteURL string
+}
+
+func NewFeedHandler(getter FeedVideoGetter, siteURL string) *FeedHandler {
+	return &FeedHandler{getter: getter, siteURL: strings.TrimSuffix(siteURL, "/")}
+}
+
+// 投稿者の公開されている動画をRSS 2.0で返す
+// 成人向けの動画と広告、変換が終わっていない動画は含めない
+// 動画がない場合もitemのない正しいフィードを返す
+func (h *FeedHandler) GenerateRSSFeed(ctx context.Context, uploaderID string, baseURL string) ([]byte, error) {
+	videos, err := h.getter.GetVideosByUserID(ctx, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 投稿者の名前は取得できなくてもフィードには困らないのでIDを使う
+	name := uploaderID
+	user, err := h.getter.GetUser(ctx, uploaderID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get uploader for RSS feed", "userID", uploaderID, "error", err)
+	} else if user.Name != "" {
+		name = user.Name
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	now := time.Now()
+	items := make([]rssItem, 0, len(videos))
+	for _, video := range videos {
+		if video.ContentRating.IsAdult() || video.IsAd || video.IsPrivate || video.IsExpired(now) || video.ProcessingStatus != domain.StatusReady {
+			continue
+		}
+
+		pageURL := videoPageURL(baseURL, video.ID)
+		item := rssItem{
+			Title:     video.Title,
+			Link:      pageURL,
+			GUID:      rssGUID{IsPermaLink: true, Value: pageURL},
+			PubDate:   video.CreatedAt.Format(time.RFC1123Z),
+			Enclosure: rssEnclosure{URL: video.VideoURL, Type: hlsContentType},
+		}
+		if video.Description != nil {
+			item.Description = *video.Description
+		}
+		if video.ThumbnailImageURL != "" {
+			item.Thumbnail = &rssMediaImage{URL: video.ThumbnailImageURL}
+		}
+		items = append(items, item)
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		MediaNS: "http://search.yahoo.com/mrss/",
+		Channel: rssChannel{
+			Title:         name + " - " + oEmbedProvider,
+			Link:          baseURL,
+			Description:   name + "が" + oEmbedProvider + "に投稿した動画",
+			LastBuildDate: now.Format(time.RFC1123Z),
+			Items:         items,
+		},
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// GET /feed/{uploaderID}
+func (h *FeedHandler) HandleRSSFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	uploaderID := strings.TrimPrefix(r.URL.Path, feedPathPrefix)
+	if uploaderID == "" || strings.Contains(uploaderID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := h.GenerateRSSFeed(r.Context(), uploaderID, h.siteURL)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to generate RSS feed", "userID", uploaderID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	_, err = w.Write(body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to write RSS feed", "userID", uploaderID, "error", err)
+	}
+}
@@ -0,0 +1,33 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) domain.HealthStatus
+}
+
+// Kubernetesのprobeから呼ばれる
+// いずれかのサービスに接続できない場合は503を返す
+func NewHealthHandler(checker HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := checker.HealthCheck(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if status.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		err := json.NewEncoder(w).Encode(status)
+		if err != nil {
+			slog.WarnContext(r.Context(), "failed to write health status", "error", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const hlsKeyPathPrefix = "/hls-key/"
+
+type HLSKeyGetter interface {
+	GetHLSEncryptionKey(ctx context.Context, keyID, userToken string) ([]byte, error)
+}
+
+// GET /hls-key/{keyID}
+// プレイヤーが#EXT-X-KEYのURIから鍵を取得する。トークンはAuthorizationヘッダーか?token=で渡す
+func NewHLSKeyHandler(getter HLSKeyGetter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		keyID := strings.TrimPrefix(r.URL.Path, hlsKeyPathPrefix)
+		if keyID == "" || strings.Contains(keyID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = r.URL.Query().Get("token")
+		}
+		if token == "" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		key, err := getter.GetHLSEncryptionKey(r.Context(), keyID, token)
+		switch {
+		case errors.Is(err, domain.ErrPermissionDenied):
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		case errors.Is(err, domain.ErrHLSKeyNotFound), errors.Is(err, domain.ErrVideoNotFound), errors.Is(err, domain.ErrVideoExpired):
+			http.NotFound(w, r)
+			return
+		case err != nil:
+			slog.ErrorContext(r.Context(), "failed to get hls encryption key", "keyID", keyID, "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		// 鍵はユーザーごとに確認して返すため、共有のキャッシュに残さない
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Cache-Control", "private, no-store")
+		_, err = w.Write(key)
+		if err != nil {
+			slog.WarnContext(r.Context(), "failed to write hls encryption key", "keyID", keyID, "error", err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	oEmbedPath     = "/oembed"
+	oEmbedProvider = "YuoVision"
+	// 動画の大きさがわからない場合の埋め込みの大きさ
+	oEmbedDefaultWidth  = 640
+	oEmbedDefaultHeight = 360
+)
+
+type OEmbedVideoGetter interface {
+	GetVideo(ctx context.Context, videoID string) (*domain.Video, error)
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+}
+
+type oEmbedResponse struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name,omitempty"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// siteURLは動画のページがあるフロントエンドのURL
+// 動画のページは{siteURL}/video/{id}、埋め込み用のページは{siteURL}/embed/{id}にある
+type OEmbedHandler struct {
+	getter  OEmbedVideoGetter
+	siteURL *url.URL
+}
+
+func NewOEmbedHandler(getter OEmbedVideoGetter, siteURL string) (*OEmbedHandler, error) {
+	u, err := url.Parse(strings.TrimSuffix(siteURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid site url: %q", siteURL)
+	}
+	return &OEmbedHandler{getter: getter, siteURL: u}, nil
+}
+
+// ページの<head>の<link rel="alternate" type="application/json+oembed">に使い、oEmbedに対応したサイトがエンドポイントを見つけられるようにする
+// 埋め込み用のページと同じく、エンドポイントも{siteURL}/oembedにある
+func oEmbedDiscoveryURL(siteURL, videoID string) string {
+	return strings.TrimSuffix(siteURL, "/") + oEmbedPath + "?" + url.Values{"url": {videoPageURL(siteURL, videoID)}, "format": {"json"}}.Encode()
+}
+
+// GET /oembed?url={siteURL}/video/{id}
+// 非公開、削除済み、公開期限を過ぎた動画と埋め込めない動画は404を返す
+func (h *OEmbedHandler) HandleOEmbed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	// JSONのみ対応する
+	if format := query.Get("format"); format != "" && format != "json" {
+		http.Error(w, "only json format is supported", http.StatusNotImplemented)
+		return
+	}
+	videoID, ok := h.videoIDFromURL(query.Get("url"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	maxWidth, err := parseOEmbedMaxSize(query.Get("maxwidth"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	maxHeight, err := parseOEmbedMaxSize(query.Get("maxheight"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	video, err := h.getter.GetVideo(ctx, videoID)
+	if errors.Is(err, domain.ErrVideoNotFound) || errors.Is(err, domain.ErrVideoExpired) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get video for oEmbed", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	width, height := oEmbedSize(video.Width, video.Height, maxWidth, maxHeight)
+	// 埋め込めない動画や、埋め込める大きさに収まらない場合は返せるものがない
+	embedCode, err := domain.GenerateEmbedCode(video, h.siteURL.String(), width, height)
+	if errors.Is(err, domain.ErrEmbedNotAllowed) || errors.Is(err, domain.ErrInvalidEmbedDimensions) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate embed code for oEmbed", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// 投稿者の名前は取得できなくても埋め込みには困らないので省略する
+	authorName := ""
+	user, err := h.getter.GetUser(ctx, video.UploaderID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get uploader for oEmbed", "videoID", videoID, "userID", video.UploaderID, "error", err)
+	} else {
+		authorName = user.Name
+	}
+
+	res := oEmbedResponse{
+		Version:      "1.0",
+		Type:         "video",
+		ProviderName: oEmbedProvider,
+		ProviderURL:  h.siteURL.String(),
+		Title:        video.Title,
+		AuthorName:   authorName,
+		HTML:         embedCode,
+		Width:        width,
+		Height:       height,
+		ThumbnailURL: video.ThumbnailImageURL,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to write oEmbed response", "videoID", videoID, "error", err)
+	}
+}
+
+// {siteURL}/video/{id}の形式のURLから動画のIDを取り出す
+func (h *OEmbedHandler) videoIDFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host != h.siteURL.Host {
+		return "", false
+	}
+	videoID, ok := strings.CutPrefix(u.Path, h.siteURL.Path+videoPagePrefix)
+	if !ok || videoID == "" || strings.Contains(videoID, "/") {
+		return "", false
+	}
+	return videoID, true
+}
+
+func parseOEmbedMaxSize(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid max size: %q", s)
+	}
+	return n, nil
+}
+
+// 縦横比を保ったままmaxWidthとmaxHeightに収まる大きさを返す。0の場合は制限しない
+func oEmbedSize(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= 0 || height <= 0 {
+		width, height = oEmbedDefaultWidth, oEmbedDefaultHeight
+	}
+	if maxWidth > 0 && width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width = width * maxHeight / height
+		height = maxHeight
+	}
+	return width, height
+}
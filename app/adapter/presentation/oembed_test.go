@@ -0,0 +1,131 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const testSiteURL = "https://example.com"
+
+// GetVideoで動画がない場合はErrVideoNotFoundを返す
+type fakeVideoGetter struct {
+	videos map[string]*domain.Video
+}
+
+func (g *fakeVideoGetter) GetVideo(ctx context.Context, videoID string) (*domain.Video, error) {
+	video, ok := g.videos[videoID]
+	if !ok {
+		return nil, domain.ErrVideoNotFound
+	}
+	return video, nil
+}
+
+func (g *fakeVideoGetter) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	return &domain.User{ID: id, Name: "uploader"}, nil
+}
+
+func (g *fakeVideoGetter) GetOrGenerateQRCode(ctx context.Context, videoID string) (string, error) {
+	return "https://example.com/qrcode/" + videoID + ".png", nil
+}
+
+func newFakeVideoGetter() *fakeVideoGetter {
+	return &fakeVideoGetter{videos: map[string]*domain.Video{
+		"video_1": {ID: "video_1", Title: "title", UploaderID: "user_1", IsExternalCutout: true, Width: 1280, Height: 720},
+		"private": {ID: "private", Title: "private", UploaderID: "user_1", IsPrivate: true, IsExternalCutout: true},
+	}}
+}
+
+var oEmbedLinkPattern = regexp.MustCompile(`<link rel="alternate" type="application/json\+oembed" href="([^"]+)"`)
+
+func Test_埋め込み用のページのoEmbedのリンク(t *testing.T) {
+	getter := newFakeVideoGetter()
+	embed := NewEmbedHandler(getter, testSiteURL)
+	oEmbed, err := NewOEmbedHandler(getter, testSiteURL)
+	if err != nil {
+		t.Fatalf("NewOEmbedHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		videoID    string
+		wantStatus int
+	}{
+		{name: "公開されている動画", videoID: "video_1", wantStatus: http.StatusOK},
+		{name: "非公開の動画", videoID: "private", wantStatus: http.StatusNotFound},
+		{name: "動画がない", videoID: "video_2", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			embed.HandleEmbed(rec, httptest.NewRequest(http.MethodGet, embedPathPrefix+tt.videoID, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			match := oEmbedLinkPattern.FindStringSubmatch(rec.Body.String())
+			if match == nil {
+				t.Fatalf("embed page has no oEmbed link: %s", rec.Body.String())
+			}
+			// リンクのエンドポイントから動画の埋め込みを取得できる
+			link, err := url.Parse(html.UnescapeString(match[1]))
+			if err != nil || link.Scheme+"://"+link.Host != testSiteURL || link.Path != oEmbedPath {
+				t.Fatalf("oEmbed link = %s, want %s%s", match[1], testSiteURL, oEmbedPath)
+			}
+			rec = httptest.NewRecorder()
+			oEmbed.HandleOEmbed(rec, httptest.NewRequest(http.MethodGet, link.RequestURI(), nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("oEmbed status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			var res oEmbedResponse
+			err = json.Unmarshal(rec.Body.Bytes(), &res)
+			if err != nil || res.Title != "title" {
+				t.Errorf("oEmbed response = %+v, %v, want title", res, err)
+			}
+		})
+	}
+}
+
+func Test_共有リンクのoEmbedのURL(t *testing.T) {
+	share := NewShareLinkHandler(newFakeVideoGetter(), testSiteURL)
+	tests := []struct {
+		name       string
+		videoID    string
+		wantStatus int
+	}{
+		{name: "公開されている動画", videoID: "video_1", wantStatus: http.StatusOK},
+		{name: "非公開の動画", videoID: "private", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			share.HandleShareLink(rec, httptest.NewRequest(http.MethodGet, sharePathPrefix+tt.videoID, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var res shareLinkResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &res)
+			if err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			want := testSiteURL + "/oembed?format=json&url=" + url.QueryEscape(testSiteURL+"/video/video_1")
+			if res.OEmbedURL != want {
+				t.Errorf("oembed_url = %s, want %s", res.OEmbedURL, want)
+			}
+		})
+	}
+}
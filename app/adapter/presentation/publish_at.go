@@ -0,0 +1,31 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"google.golang.org/grpc/metadata"
+)
+
+// VideoMetaに公開時刻の項目がないため、アップロードのリクエストのメタデータで受け取る
+const publishAtMetadataKey = "publish-at"
+
+// メタデータのpublish-atをRFC 3339の時刻として読む
+// 指定されていない場合はnilを返し、すぐに公開する
+func publishAtFromContext(ctx context.Context) (*time.Time, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get(publishAtMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+	publishAt, err := time.Parse(time.RFC3339, values[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be RFC 3339: %v", domain.ErrInvalidInput, publishAtMetadataKey, err)
+	}
+	return &publishAt, nil
+}
@@ -0,0 +1,41 @@
+package presentation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"google.golang.org/grpc/metadata"
+)
+
+func Test_公開時刻のメタデータ(t *testing.T) {
+	want := time.Date(2026, 10, 16, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	tests := []struct {
+		name    string
+		md      metadata.MD
+		want    *time.Time
+		wantErr error
+	}{
+		{name: "メタデータがない", md: nil},
+		{name: "指定されていない", md: metadata.Pairs("authorization", "token")},
+		{name: "RFC 3339の時刻", md: metadata.Pairs("publish-at", "2026-10-16T09:00:00+09:00"), want: &want},
+		{name: "形式が違う", md: metadata.Pairs("publish-at", "2026/10/16 09:00"), wantErr: domain.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			got, err := publishAtFromContext(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("publishAtFromContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("publishAtFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package presentation
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+	"google.golang.org/grpc/metadata"
+)
+
+// IETFのRateLimitヘッダーのドラフトに合わせてレスポンスヘッダーを作る
+// RateLimit-Resetはリセットまでの秒数
+func rateLimitHeader(remaining int, resetAt time.Time) metadata.MD {
+	return metadata.Pairs(
+		"ratelimit-remaining", strconv.Itoa(remaining),
+		"ratelimit-reset", strconv.Itoa(secondsUntil(resetAt)),
+	)
+}
+
+// 制限を超えた場合はRetry-Afterも付ける
+func rateLimitExceededHeader(err error) (metadata.MD, bool) {
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		return nil, false
+	}
+
+	md := rateLimitHeader(0, rateLimitErr.ResetAt)
+	md.Set("retry-after", strconv.Itoa(secondsUntil(rateLimitErr.ResetAt)))
+	return md, true
+}
+
+func secondsUntil(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return max(int(math.Ceil(time.Until(t).Seconds())), 0)
+}
@@ -0,0 +1,31 @@
+package presentation
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// CDNやリバースプロキシが付けるクライアントの国のヘッダー。先にあるものを優先する
+var countryHeaders = []string{"CF-IPCountry", "X-Country"}
+
+func clientCountry(r *http.Request) string {
+	for _, header := range countryHeaders {
+		if country := r.Header.Get(header); country != "" {
+			return country
+		}
+	}
+	return ""
+}
+
+// 視聴できない国からのリクエストの場合はレスポンスを書いてfalseを返す
+func allowVideoInClientRegion(w http.ResponseWriter, r *http.Request, video *domain.Video) bool {
+	country := clientCountry(r)
+	if domain.IsVideoAllowedInRegion(video, country) {
+		return true
+	}
+	slog.InfoContext(r.Context(), "video is blocked in client region", "videoID", video.ID, "country", country)
+	http.Error(w, http.StatusText(http.StatusUnavailableForLegalReasons), http.StatusUnavailableForLegalReasons)
+	return false
+}
@@ -0,0 +1,149 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const (
+	sharePathPrefix  = "/share/"
+	qrCodePathPrefix = "/qrcode/"
+)
+
+type ShareVideoGetter interface {
+	GetVideo(ctx context.Context, videoID string) (*domain.Video, error)
+	GetOrGenerateQRCode(ctx context.Context, videoID string) (string, error)
+}
+
+// フロントエンドは動画のページの<head>にoembed_urlのリンク、open_graphの<meta>とjson_ldの<script>を入れる
+type shareLinkResponse struct {
+	URL          string            `json:"url"`
+	StartSeconds *int              `json:"start_seconds,omitempty"`
+	OEmbedURL    string            `json:"oembed_url"`
+	OpenGraph    map[string]string `json:"open_graph"`
+	JSONLD       json.RawMessage   `json:"json_ld"`
+}
+
+// siteURLは動画のページがあるフロントエンドのURL
+// フロントエンドは動画のページの?t=を読んで、その秒数から再生する
+type ShareLinkHandler struct {
+	getter  ShareVideoGetter
+	siteURL string
+}
+
+func NewShareLinkHandler(getter ShareVideoGetter, siteURL string) *ShareLinkHandler {
+	return &ShareLinkHandler{getter: getter, siteURL: siteURL}
+}
+
+// GET /share/{id}?t={seconds}
+// 動画の長さを超える秒数の場合は400を返す
+func (h *ShareLinkHandler) HandleShareLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	videoID := strings.TrimPrefix(r.URL.Path, sharePathPrefix)
+	if videoID == "" || strings.Contains(videoID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	var startSeconds *int
+	if t := r.URL.Query().Get(domain.ShareStartTimeParam); t != "" {
+		seconds, err := strconv.Atoi(t)
+		if err != nil {
+			http.Error(w, "t must be an integer", http.StatusBadRequest)
+			return
+		}
+		startSeconds = &seconds
+	}
+
+	ctx := r.Context()
+	video, ok := h.getShareableVideo(w, r, videoID)
+	if !ok {
+		return
+	}
+
+	if startSeconds != nil {
+		err := video.ValidateStartTime(*startSeconds)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	link, err := domain.GenerateShareLink(h.siteURL, video.ID, startSeconds)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate share link", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	jsonLD, err := GenerateJSONLD(video, h.siteURL)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate JSON-LD", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(shareLinkResponse{
+		URL:          link,
+		StartSeconds: startSeconds,
+		OEmbedURL:    oEmbedDiscoveryURL(h.siteURL, video.ID),
+		OpenGraph:    GenerateOpenGraphMeta(video, h.siteURL),
+		JSONLD:       jsonLD,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to write share link", "videoID", videoID, "error", err)
+	}
+}
+
+// GET /qrcode/{id}
+// 動画のページのリンクのQRコードの画像にリダイレクトする
+func (h *ShareLinkHandler) HandleQRCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	videoID := strings.TrimPrefix(r.URL.Path, qrCodePathPrefix)
+	if videoID == "" || strings.Contains(videoID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	_, ok := h.getShareableVideo(w, r, videoID)
+	if !ok {
+		return
+	}
+
+	url, err := h.getter.GetOrGenerateQRCode(r.Context(), videoID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get QR code", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// 共有できない動画の場合はレスポンスを書いてfalseを返す
+func (h *ShareLinkHandler) getShareableVideo(w http.ResponseWriter, r *http.Request, videoID string) (*domain.Video, bool) {
+	video, err := h.getter.GetVideo(r.Context(), videoID)
+	if errors.Is(err, domain.ErrVideoNotFound) || errors.Is(err, domain.ErrVideoExpired) {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get video for sharing", "videoID", videoID, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, false
+	}
+	if video.IsPrivate {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	return video, true
+}
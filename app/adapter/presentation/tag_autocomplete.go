@@ -0,0 +1,56 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultTagAutocompleteLimit = 10
+	maxTagAutocompleteLimit     = 50
+)
+
+type TagAutocompleter interface {
+	AutocompleteTags(ctx context.Context, prefix string, limit int) ([]string, error)
+}
+
+type tagAutocompleteResponse struct {
+	Tags []string `json:"tags"`
+}
+
+// GET /tags/autocomplete?prefix={prefix}&limit={limit}
+// タグの入力欄で1文字ごとに呼ばれるため、DBではなくRedisの索引から返す
+func NewTagAutocompleteHandler(completer TagAutocompleter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		query := r.URL.Query()
+		limit := defaultTagAutocompleteLimit
+		if s := query.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = min(n, maxTagAutocompleteLimit)
+		}
+
+		tags, err := completer.AutocompleteTags(r.Context(), query.Get("prefix"), limit)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to autocomplete tags", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(tagAutocompleteResponse{Tags: tags})
+		if err != nil {
+			slog.WarnContext(r.Context(), "failed to write tag autocomplete", "error", err)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/yuorei/video-server/app/application"
 	"github.com/yuorei/video-server/app/domain"
 	"github.com/yuorei/video-server/yuovision-proto/go/video/video_grpc"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -44,7 +46,7 @@ func (s *VideoService) Video(ctx context.Context, id *video_grpc.VideoID) (*vide
 		Tags:              video.Tags,
 		WatchCount:        int32(video.WatchCount),
 		Private:           video.IsPrivate,
-		Adult:             video.IsAdult,
+		Adult:             video.ContentRating.IsAdult(),
 		ExternalCutout:    video.IsExternalCutout,
 		IsAd:              video.IsAd,
 		CreatedAt:         timestamppb.New(video.CreatedAt),
@@ -54,7 +56,7 @@ func (s *VideoService) Video(ctx context.Context, id *video_grpc.VideoID) (*vide
 }
 
 func (s *VideoService) Videos(ctx context.Context, _ *empty.Empty) (*video_grpc.VideosResponse, error) {
-	videos, err := s.usecase.GetVideos(ctx)
+	videos, err := s.usecase.GetVideos(ctx, domain.DefaultContentFilter)
 	if err != nil {
 		sentry.CaptureException(err)
 		return nil, err
@@ -81,7 +83,7 @@ func (s *VideoService) Videos(ctx context.Context, _ *empty.Empty) (*video_grpc.
 			Tags:              video.Tags,
 			WatchCount:        int32(video.WatchCount),
 			Private:           video.IsPrivate,
-			Adult:             video.IsAdult,
+			Adult:             video.ContentRating.IsAdult(),
 			ExternalCutout:    video.IsExternalCutout,
 			IsAd:              video.IsAd,
 		})
@@ -113,7 +115,7 @@ func (s *VideoService) VideosByUserID(ctx context.Context, id *video_grpc.VideoU
 			Tags:              video.Tags,
 			WatchCount:        int32(video.WatchCount),
 			Private:           video.IsPrivate,
-			Adult:             video.IsAdult,
+			Adult:             video.ContentRating.IsAdult(),
 			ExternalCutout:    video.IsExternalCutout,
 			IsAd:              video.IsAd,
 		})
@@ -196,6 +198,19 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 	var videoFile *os.File
 	var id string
 	var meta *video_grpc.VideoMeta
+	var tempMp4 string
+	// 変換用のジョブに渡した後はEnqueueVideoProcessingJobが一時ファイルをストレージに移して削除する
+	enqueued := false
+	// ディスクを使い切らないように、上限を超えた時点で受け取るのをやめる
+	maxSize := s.usecase.MaxVideoSize()
+	var received int64
+
+	// ファイルを受け取る前に公開時刻の形式を確かめる
+	publishAt, err := publishAtFromContext(stream.Context())
+	if err != nil {
+		sentry.CaptureException(err)
+		return err
+	}
 
 	for {
 		input, err := stream.Recv()
@@ -210,6 +225,12 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 		if input.GetValue() != nil {
 			switch x := input.GetValue().(type) {
 			case *video_grpc.UploadVideoInput_Video:
+				received += int64(len(x.Video))
+				if received > maxSize {
+					err := fmt.Errorf("%w: exceeds %d bytes", domain.ErrFileTooLarge, maxSize)
+					sentry.CaptureException(err)
+					return err
+				}
 				_, err := videoFile.Write(x.Video)
 				if err != nil {
 					sentry.CaptureException(err)
@@ -222,13 +243,20 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 					sentry.CaptureException(fmt.Errorf("id is required"))
 					return fmt.Errorf("id is required")
 				}
+				// 他のユーザーの動画のIDで送られた場合はファイルを作る前に断る
+				err := s.usecase.CheckVideoUploader(ctx, id, meta.UserId)
+				if err != nil {
+					sentry.CaptureException(err)
+					return err
+				}
 				tempDir := "temp"
-				err := os.MkdirAll(tempDir, 0755)
+				err = os.MkdirAll(tempDir, 0755)
 				if err != nil {
 					sentry.CaptureException(err)
 					return err
 				}
-				tempMp4 := filepath.Join(tempDir, id+".mp4")
+				// 再送されたアップロードが最初のアップロードのファイルを上書きしないようにパスを毎回変える
+				tempMp4 = filepath.Join(tempDir, id+domain.IDSeparator+domain.NewUUID()+".mp4")
 				videoFile, err = os.Create(tempMp4)
 				if err != nil {
 					sentry.CaptureException(err)
@@ -236,6 +264,9 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 				}
 				defer videoFile.Close()
 				defer func() {
+					if enqueued {
+						return
+					}
 					if _, err := os.Stat(tempMp4); err == nil {
 						if err := os.Remove(tempMp4); err != nil {
 							return
@@ -246,12 +277,34 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 		}
 	}
 
-	video := domain.NewUploadVideo(id, videoFile, meta.Title, &meta.Description, meta.Tags, meta.Adult, meta.Private, meta.ExternalCutout, meta.IsAd)
+	video := domain.NewUploadVideo(id, videoFile, meta.Title, &meta.Description, meta.Tags, domain.ContentRatingFromAdult(meta.Adult), meta.Private, meta.ExternalCutout, meta.IsAd)
+	video.SourceKey = tempMp4
+	video.PublishAt = publishAt
+	// IPアドレス単位のレート制限に使う
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err == nil {
+			video.ClientIP = host
+		}
+	}
 	uploadVideo, err := s.usecase.UploadVideo(ctx, video, meta.UserId, meta.ThumbnailImageUrl)
 	if err != nil {
+		if md, ok := rateLimitExceededHeader(err); ok {
+			stream.SetHeader(md)
+		}
 		sentry.CaptureException(err)
 		return err
 	}
+	enqueued = uploadVideo.Enqueued
+
+	// 再送されたアップロードは数えていないため、制限のヘッダーを付けない
+	if !uploadVideo.RateLimitResetAt.IsZero() {
+		err = stream.SetHeader(rateLimitHeader(uploadVideo.RateLimitRemaining, uploadVideo.RateLimitResetAt))
+		if err != nil {
+			sentry.CaptureException(err)
+			return err
+		}
+	}
 
 	err = stream.SendAndClose(
 		&video_grpc.VideoPayload{
@@ -265,7 +318,7 @@ func (s *VideoService) UploadVideo(stream video_grpc.VideoService_UploadVideoSer
 			UserId:            uploadVideo.UploaderID,
 			Tags:              uploadVideo.Tags,
 			Private:           uploadVideo.IsPrivate,
-			Adult:             uploadVideo.IsAdult,
+			Adult:             uploadVideo.ContentRating.IsAdult(),
 			ExternalCutout:    uploadVideo.IsExternalCutout,
 			IsAd:              uploadVideo.IsAd,
 		},
@@ -303,7 +356,7 @@ func (s *VideoService) IncrementWatchCount(ctx context.Context, input *video_grp
 }
 
 func (s *VideoService) CutVideo(ctx context.Context, input *video_grpc.CutVideoInput) (*video_grpc.CutVideoPayload, error) {
-	url, err := s.usecase.CutVideo(ctx, input.VideoId, input.UserId, int(input.Start), int(input.End))
+	url, err := s.usecase.CutVideo(ctx, input.VideoId, input.UserId, int(input.Start), int(input.End), domain.NewCutOptions("", false, false))
 	if err != nil {
 		sentry.CaptureException(err)
 		return nil, err
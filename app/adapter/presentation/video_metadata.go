@@ -0,0 +1,132 @@
+package presentation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// フロントエンドの動画のページのパス
+const videoPagePrefix = "/video/"
+
+func videoPageURL(baseURL, videoID string) string {
+	return strings.TrimSuffix(baseURL, "/") + videoPagePrefix + url.PathEscape(videoID)
+}
+
+// 非公開の動画はプレビューに出さない。削除済みの動画は取得した時点で見つからない
+func isVideoShareable(v *domain.Video) bool {
+	return v != nil && !v.IsPrivate
+}
+
+// SlackやDiscordなどでプレビューを表示するための<meta property="og:*">の値を返す
+// 共有できない動画の場合は空のmapを返す
+func GenerateOpenGraphMeta(v *domain.Video, baseURL string) map[string]string {
+	meta := map[string]string{}
+	if !isVideoShareable(v) {
+		return meta
+	}
+
+	meta["og:type"] = "video.other"
+	meta["og:site_name"] = oEmbedProvider
+	meta["og:url"] = videoPageURL(baseURL, v.ID)
+	meta["og:title"] = v.Title
+	meta["og:image"] = v.ThumbnailImageURL
+	if v.Description != nil && *v.Description != "" {
+		meta["og:description"] = *v.Description
+	}
+	// 埋め込めない動画はプレビューの中で再生させない
+	if v.IsEmbeddable() {
+		embedURL := domain.EmbedURL(baseURL, v.ID)
+		meta["og:video:url"] = embedURL
+		meta["og:video:secure_url"] = embedURL
+		meta["og:video:type"] = "text/html"
+		if v.Width > 0 && v.Height > 0 {
+			meta["og:video:width"] = strconv.Itoa(v.Width)
+			meta["og:video:height"] = strconv.Itoa(v.Height)
+		}
+	}
+	if v.Duration > 0 {
+		meta["video:duration"] = strconv.Itoa(int(v.Duration.Seconds()))
+	}
+	return meta
+}
+
+type jsonLDInteractionCounter struct {
+	Type                 string `json:"@type"`
+	InteractionType      string `json:"interactionType"`
+	UserInteractionCount int    `json:"userInteractionCount"`
+}
+
+type jsonLDVideoObject struct {
+	Context              string                   `json:"@context"`
+	Type                 string                   `json:"@type"`
+	Name                 string                   `json:"name"`
+	Description          string                   `json:"description"`
+	ThumbnailURL         string                   `json:"thumbnailUrl"`
+	UploadDate           string                   `json:"uploadDate"`
+	Duration             string                   `json:"duration,omitempty"`
+	ContentURL           string                   `json:"contentUrl"`
+	EmbedURL             string                   `json:"embedUrl,omitempty"`
+	URL                  string                   `json:"url"`
+	IsFamilyFriendly     bool                     `json:"isFamilyFriendly"`
+	InteractionStatistic jsonLDInteractionCounter `json:"interactionStatistic"`
+}
+
+// 検索エンジン向けにschema.orgのVideoObjectを返す
+// 共有できない動画の場合はnilを返す
+func GenerateJSONLD(v *domain.Video, baseURL string) ([]byte, error) {
+	if !isVideoShareable(v) {
+		return nil, nil
+	}
+
+	// descriptionは必須なので、ない場合はタイトルを使う
+	description := v.Title
+	if v.Description != nil && *v.Description != "" {
+		description = *v.Description
+	}
+	embedURL := ""
+	if v.IsEmbeddable() {
+		embedURL = domain.EmbedURL(baseURL, v.ID)
+	}
+	return json.Marshal(jsonLDVideoObject{
+		Context:          "https://schema.org",
+		Type:             "VideoObject",
+		Name:             v.Title,
+		Description:      description,
+		ThumbnailURL:     v.ThumbnailImageURL,
+		UploadDate:       v.CreatedAt.Format(time.RFC3339),
+		Duration:         isoDuration(v.Duration),
+		ContentURL:       v.VideoURL,
+		EmbedURL:         embedURL,
+		URL:              videoPageURL(baseURL, v.ID),
+		IsFamilyFriendly: v.ContentRating == domain.RatingGeneral,
+		InteractionStatistic: jsonLDInteractionCounter{
+			Type:                 "InteractionCounter",
+			InteractionType:      "https://schema.org/WatchAction",
+			UserInteractionCount: v.WatchCount,
+		},
+	})
+}
+
+// ISO 8601の形式(PT1H2M3S)にする。長さがわからない場合は空文字を返す
+func isoDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	seconds := int(d.Round(time.Second).Seconds())
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := seconds / 3600; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := seconds % 3600 / 60; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	fmt.Fprintf(&b, "%dS", seconds%60)
+	return b.String()
+}
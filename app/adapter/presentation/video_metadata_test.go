@@ -0,0 +1,116 @@
+package presentation
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+var (
+	openGraphMetaPattern = regexp.MustCompile(`<meta property="([^"]+)" content="([^"]*)">`)
+	jsonLDPattern        = regexp.MustCompile(`<script type="application/ld\+json">(.*?)</script>`)
+)
+
+func newMetadataTestVideo() *domain.Video {
+	description := "description"
+	return &domain.Video{
+		ID:                "video_1",
+		Title:             `</script><script>alert("title")</script>`,
+		Description:       &description,
+		ThumbnailImageURL: "https://example.com/video_1.webp",
+		UploaderID:        "user_1",
+		IsExternalCutout:  true,
+		Width:             1280,
+		Height:            720,
+		Duration:          90 * time.Second,
+		ContentRating:     domain.RatingGeneral,
+	}
+}
+
+func Test_埋め込み用のページのOpenGraphとJSONLD(t *testing.T) {
+	video := newMetadataTestVideo()
+	getter := &fakeVideoGetter{videos: map[string]*domain.Video{"video_1": video}}
+	rec := httptest.NewRecorder()
+	NewEmbedHandler(getter, testSiteURL).HandleEmbed(rec, httptest.NewRequest(http.MethodGet, embedPathPrefix+"video_1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+
+	meta := map[string]string{}
+	for _, match := range openGraphMetaPattern.FindAllStringSubmatch(body, -1) {
+		meta[match[1]] = html.UnescapeString(match[2])
+	}
+	for property, want := range GenerateOpenGraphMeta(video, testSiteURL) {
+		if meta[property] != want {
+			t.Errorf("%s = %q, want %q", property, meta[property], want)
+		}
+	}
+	if meta["og:video:url"] != domain.EmbedURL(testSiteURL, "video_1") {
+		t.Errorf("og:video:url = %q, want the embed page", meta["og:video:url"])
+	}
+
+	// タイトルに</script>が含まれていても<script>の外に出ない
+	scripts := jsonLDPattern.FindAllStringSubmatch(body, -1)
+	if len(scripts) != 1 {
+		t.Fatalf("JSON-LD scripts = %d, want 1: %s", len(scripts), body)
+	}
+	var object jsonLDVideoObject
+	err := json.Unmarshal([]byte(scripts[0][1]), &object)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if object.Type != "VideoObject" || object.Name != video.Title || object.Duration != "PT1M30S" {
+		t.Errorf("JSON-LD = %+v", object)
+	}
+}
+
+func Test_共有リンクのOpenGraphとJSONLD(t *testing.T) {
+	video := newMetadataTestVideo()
+	getter := &fakeVideoGetter{videos: map[string]*domain.Video{"video_1": video}}
+	rec := httptest.NewRecorder()
+	NewShareLinkHandler(getter, testSiteURL).HandleShareLink(rec, httptest.NewRequest(http.MethodGet, sharePathPrefix+"video_1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var res shareLinkResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if res.OpenGraph["og:url"] != testSiteURL+"/video/video_1" || res.OpenGraph["og:title"] != video.Title {
+		t.Errorf("open_graph = %v", res.OpenGraph)
+	}
+	var object jsonLDVideoObject
+	err = json.Unmarshal(res.JSONLD, &object)
+	if err != nil || object.URL != testSiteURL+"/video/video_1" || object.Description != "description" {
+		t.Errorf("json_ld = %s, %v", res.JSONLD, err)
+	}
+}
+
+func Test_非公開の動画のOpenGraphとJSONLD(t *testing.T) {
+	video := newMetadataTestVideo()
+	video.IsPrivate = true
+	if meta := GenerateOpenGraphMeta(video, testSiteURL); len(meta) != 0 {
+		t.Errorf("GenerateOpenGraphMeta() = %v, want empty", meta)
+	}
+	jsonLD, err := GenerateJSONLD(video, testSiteURL)
+	if err != nil || jsonLD != nil {
+		t.Errorf("GenerateJSONLD() = %s, %v, want nil", jsonLD, err)
+	}
+	// 埋め込み用のページ自体も返さない
+	getter := &fakeVideoGetter{videos: map[string]*domain.Video{"video_1": video}}
+	rec := httptest.NewRecorder()
+	NewEmbedHandler(getter, testSiteURL).HandleEmbed(rec, httptest.NewRequest(http.MethodGet, embedPathPrefix+"video_1", nil))
+	if rec.Code != http.StatusNotFound || strings.Contains(rec.Body.String(), "og:title") {
+		t.Errorf("status = %d, want %d without metadata", rec.Code, http.StatusNotFound)
+	}
+}
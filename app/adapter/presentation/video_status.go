@@ -0,0 +1,89 @@
+package presentation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+const videoStatusPathPrefix = "/video/"
+
+type VideoStatusSubscriber interface {
+	SubscribeToVideoStatus(ctx context.Context, videoID, userToken string) (<-chan domain.ProcessingStatus, error)
+}
+
+type videoStatusEvent struct {
+	VideoID string                  `json:"video_id"`
+	Status  domain.ProcessingStatus `json:"status"`
+}
+
+// GET /video/{id}/events
+// ポーリングしなくても済むように、処理状態が変わるたびにServer-Sent Eventsで送る
+// 処理が終わるかクライアントが切断したら終了する
+// EventSourceはヘッダーを付けられないため、トークンはAuthorizationヘッダーか?token=で渡す
+func NewVideoStatusHandler(subscriber VideoStatusSubscriber) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		videoID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, videoStatusPathPrefix), "/events")
+		if !ok || videoID == "" || strings.Contains(videoID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			token = r.URL.Query().Get("token")
+		}
+		if token == "" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		statuses, err := subscriber.SubscribeToVideoStatus(r.Context(), videoID, token)
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, domain.ErrVideoNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "failed to subscribe to video status", "videoID", videoID, "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for status := range statuses {
+			data, err := json.Marshal(videoStatusEvent{VideoID: videoID, Status: status})
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to encode video status", "videoID", videoID, "error", err)
+				return
+			}
+			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	})
+}
@@ -5,10 +5,11 @@ import (
 )
 
 type Application struct {
-	Video   *VideoUseCase
-	Image   *ImageUseCase
-	User    *UserUseCase
-	Comment *CommentUseCase
+	Video    *VideoUseCase
+	Image    *ImageUseCase
+	User     *UserUseCase
+	Comment  *CommentUseCase
+	Playlist *PlaylistUseCase
 }
 
 func NewApplication(infra *infrastructure.Infrastructure) *Application {
@@ -16,11 +17,13 @@ func NewApplication(infra *infrastructure.Infrastructure) *Application {
 	imageUseCase := NewImageUseCase(infra)
 	userUseCase := NewUserUseCase(infra)
 	CommentUseCase := NewCommentUseCase(infra)
+	playlistUseCase := NewPlaylistUseCase(infra)
 
 	return &Application{
-		Video:   videoUseCase,
-		Image:   imageUseCase,
-		User:    userUseCase,
-		Comment: CommentUseCase,
+		Video:    videoUseCase,
+		Image:    imageUseCase,
+		User:     userUseCase,
+		Comment:  CommentUseCase,
+		Playlist: playlistUseCase,
 	}
 }
@@ -24,3 +24,11 @@ func (a *Application) GetCommentsByVideoID(ctx context.Context, videoID string)
 func (a *Application) PostComment(ctx context.Context, postComment *domain.Comment) (*domain.Comment, error) {
 	return a.Comment.commentRepository.InsertComment(ctx, postComment)
 }
+
+func (a *Application) GetCommentsByVideoIDPaged(ctx context.Context, videoID string, page domain.Page) ([]*domain.Comment, int64, error) {
+	return a.Comment.commentRepository.GetCommentsByVideoIDFromDBPaged(ctx, videoID, page)
+}
+
+func (a *Application) DeleteComment(ctx context.Context, commentID, requestingUserID string) error {
+	return a.Comment.commentRepository.DeleteComment(ctx, commentID, requestingUserID)
+}
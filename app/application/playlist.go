@@ -0,0 +1,42 @@
+package application
+
+import (
+	"context"
+
+	"github.com/yuorei/video-server/app/application/port"
+	"github.com/yuorei/video-server/app/domain"
+)
+
+type PlaylistUseCase struct {
+	playlistRepository port.PlaylistRepository
+}
+
+func NewPlaylistUseCase(playlistRepository port.PlaylistRepository) *PlaylistUseCase {
+	return &PlaylistUseCase{
+		playlistRepository: playlistRepository,
+	}
+}
+
+func (a *Application) CreatePlaylist(ctx context.Context, playlist *domain.Playlist) (*domain.Playlist, error) {
+	return a.Playlist.playlistRepository.CreatePlaylist(ctx, playlist)
+}
+
+func (a *Application) GetPlaylistByID(ctx context.Context, playlistID string) (*domain.Playlist, error) {
+	return a.Playlist.playlistRepository.GetPlaylistByID(ctx, playlistID)
+}
+
+func (a *Application) AddVideoToPlaylist(ctx context.Context, playlistID, videoID, requestingUserID string) error {
+	return a.Playlist.playlistRepository.AddVideoToPlaylist(ctx, playlistID, videoID, requestingUserID)
+}
+
+func (a *Application) RemoveVideoFromPlaylist(ctx context.Context, playlistID, videoID, requestingUserID string) error {
+	return a.Playlist.playlistRepository.RemoveVideoFromPlaylist(ctx, playlistID, videoID, requestingUserID)
+}
+
+func (a *Application) ReorderPlaylistVideos(ctx context.Context, playlistID string, videoIDs []string, requestingUserID string) error {
+	return a.Playlist.playlistRepository.ReorderPlaylistVideos(ctx, playlistID, videoIDs, requestingUserID)
+}
+
+func (a *Application) DeletePlaylist(ctx context.Context, playlistID, requestingUserID string) error {
+	return a.Playlist.playlistRepository.DeletePlaylist(ctx, playlistID, requestingUserID)
+}
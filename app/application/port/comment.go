@@ -10,10 +10,14 @@ import (
 type CommentInputPort interface {
 	GetCommentsByVideoID(context.Context, string) ([]*domain.Comment, error)
 	PostComment(context.Context, *domain.Comment) (*domain.Comment, error)
+	GetCommentsByVideoIDPaged(context.Context, string, domain.Page) ([]*domain.Comment, int64, error)
+	DeleteComment(context.Context, string, string) error
 }
 
 // ユースケースからインフラを呼び出されるメソッドのインターフェースを定義
 type CommentRepository interface {
 	GetCommentsByVideoIDFromDB(context.Context, string) ([]*domain.Comment, error)
 	InsertComment(context.Context, *domain.Comment) (*domain.Comment, error)
+	GetCommentsByVideoIDFromDBPaged(context.Context, string, domain.Page) ([]*domain.Comment, int64, error)
+	DeleteComment(context.Context, string, string) error
 }
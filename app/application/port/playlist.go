@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+
+	"github.com/yuorei/video-server/app/domain"
+)
+
+// adaputerがusecase層を呼び出されるメソッドのインターフェースを定義
+type PlaylistInputPort interface {
+	CreatePlaylist(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	GetPlaylistByID(context.Context, string) (*domain.Playlist, error)
+	AddVideoToPlaylist(context.Context, string, string, string) error
+	RemoveVideoFromPlaylist(context.Context, string, string, string) error
+	ReorderPlaylistVideos(context.Context, string, []string, string) error
+	DeletePlaylist(context.Context, string, string) error
+}
+
+// ユースケースからインフラを呼び出されるメソッドのインターフェースを定義
+type PlaylistRepository interface {
+	CreatePlaylist(context.Context, *domain.Playlist) (*domain.Playlist, error)
+	GetPlaylistByID(context.Context, string) (*domain.Playlist, error)
+	AddVideoToPlaylist(context.Context, string, string, string) error
+	RemoveVideoFromPlaylist(context.Context, string, string, string) error
+	ReorderPlaylistVideos(context.Context, string, []string, string) error
+	DeletePlaylist(context.Context, string, string) error
+}
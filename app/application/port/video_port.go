@@ -3,34 +3,182 @@ package port
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/yuorei/video-server/app/domain"
 )
 
 // adaputerがusecase層を呼び出されるメソッドのインターフェースを定義
 type VideoInputPort interface {
-	GetVideos(context.Context) ([]*domain.Video, error)
+	GetVideos(context.Context, domain.ContentFilter) ([]*domain.Video, error)
+	GetVideosSorted(context.Context, domain.SortOrder) ([]*domain.Video, error)
+	GetVideosPaged(context.Context, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosAfterCursor(context.Context, string, int) ([]*domain.Video, string, error)
+	SearchVideos(context.Context, string, domain.SearchOptions, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosByTags(context.Context, []string, bool, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosInRange(context.Context, time.Time, time.Time, domain.Page) ([]*domain.Video, int64, error)
 	GetVideosByUserID(context.Context, string) ([]*domain.Video, error)
 	GetVideo(context.Context, string) (*domain.Video, error)
+	GetVideosByIDs(context.Context, []string) ([]*domain.Video, error)
 	UploadVideo(context.Context, *domain.UploadVideo, string, string) (*domain.UploadVideoResponse, error)
+	MaxVideoSize() int64
+	CheckVideoUploader(context.Context, string, string) error
 	GetWatchCount(context.Context, string) (int, error)
 	IncrementWatchCount(context.Context, string, string) (int, error)
-	CutVideo(context.Context, string, string, int, int) (string, error)
+	GetDownloadCount(context.Context, string) (int, error)
+	IncrementDownloadCount(context.Context, string, string) (int, error)
+	GenerateDownloadURL(context.Context, string, string, string) (string, error)
+	ExtractAudio(context.Context, string, string, string) (string, error)
+	AnalyzeVideo(context.Context, string, string) (*domain.VideoAnalysis, error)
+	EncryptHLSSegments(context.Context, string, string, string) error
+	IssueHLSKeyToken(context.Context, string, string) (string, error)
+	GetHLSEncryptionKey(context.Context, string, string) ([]byte, error)
+	CreateThumbnailVariant(context.Context, string, string, float64, string) (*domain.ThumbnailVariant, error)
+	SelectThumbnailForUser(context.Context, string, string) (*domain.ThumbnailVariant, error)
+	RecordThumbnailImpression(context.Context, string) error
+	RecordThumbnailClick(context.Context, string) error
+	GetThumbnailVariantStats(context.Context, string, string) ([]domain.ThumbnailStats, error)
+	SubmitVideoReport(context.Context, domain.VideoReport) error
+	GetVideoReports(context.Context, string, string) ([]*domain.VideoReport, error)
+	GetPendingReports(context.Context, domain.Page, string) ([]*domain.VideoReport, error)
+	ModerateVideo(context.Context, string, string, domain.ModerationAction, string) error
+	GetModerationHistory(context.Context, string, string) ([]*domain.ModerationRecord, error)
+	RenameTag(context.Context, string, string, string) (int64, error)
+	MergeTags(context.Context, string, string, string) (int64, error)
+	SuggestTagsForTitle(context.Context, string, int) ([]string, error)
+	AutocompleteTags(context.Context, string, int) ([]string, error)
+	GetUniqueViewerCount(context.Context, string) (int64, error)
+	RecordWatchDuration(context.Context, string, string, int) error
+	GetAverageWatchDuration(context.Context, string) (float64, error)
+	UpsertReaction(context.Context, string, string, domain.ReactionType) error
+	GetReactionCounts(context.Context, string) (int64, int64, error)
+	BookmarkVideo(context.Context, string, string) error
+	UnbookmarkVideo(context.Context, string, string) error
+	IsVideoBookmarked(context.Context, string, string) (bool, error)
+	GetBookmarkedVideosByUser(context.Context, string, domain.Page) ([]*domain.Video, int64, error)
+	GetRelatedVideos(context.Context, string, int) ([]*domain.Video, error)
+	GetTrendingVideos(context.Context, time.Duration, int) ([]*domain.Video, error)
+	SoftDeleteVideo(context.Context, string, string) error
+	UpdateVideoExpiry(context.Context, string, string, *time.Time) error
+	UpdateVideo(context.Context, string, string, int, domain.VideoUpdate) (*domain.Video, error)
+	TransferVideoOwnership(context.Context, string, string, string) error
+	SetAllVideosByUserPrivacy(context.Context, string, bool, string) (int64, error)
+	RestoreVideo(context.Context, string, string) error
+	HardDeleteVideo(context.Context, string, string) error
+	GetAuditLogsForVideo(context.Context, string, domain.Page, string) ([]*domain.AuditEntry, error)
+	UpdateVideoProcessingStatus(context.Context, string, domain.ProcessingStatus, string) error
+	CutVideo(context.Context, string, string, int, int, domain.CutOptions) (string, error)
+	ConcatenateVideos(context.Context, []string, string, string) (*domain.UploadVideoResponse, error)
+	SplitVideo(context.Context, string, int, string) ([2]*domain.UploadVideoResponse, error)
+	UploadWatermark(context.Context, string, string) (string, error)
+	RegisterWebhookTarget(context.Context, domain.WebhookTarget, string) error
+	SetVideoRegionRestrictions(context.Context, string, []string, string) error
+	SetVideoDescription(context.Context, string, string, string, string) error
+	GetVideoDescriptionForLanguage(context.Context, string, string) (string, error)
+	UploadSubtitleTrack(context.Context, string, string, string, io.Reader, string) (*domain.SubtitleTrack, error)
+	GetSubtitleTracksByVideoID(context.Context, string) ([]*domain.SubtitleTrack, error)
+	DeleteSubtitleTrack(context.Context, string, string) error
+	SetVideoChapters(context.Context, string, []domain.Chapter, string) (string, error)
+	GetVideoChapters(context.Context, string) ([]domain.Chapter, error)
+	IssueVideoStatusToken(context.Context, string, string) (string, error)
+	SubscribeToVideoStatus(context.Context, string, string) (<-chan domain.ProcessingStatus, error)
+	GetOrGenerateQRCode(context.Context, string) (string, error)
 }
 
 // ユースケースからインフラを呼び出されるメソッドのインターフェースを定義
 type VideoRepository interface {
-	CheckUploadAPIRateLimit(context.Context, string) error
-	SetUploadAPIRateLimit(context.Context, string) error
-	GetVideosFromDB(context.Context) ([]*domain.Video, error)
+	TakeUploadAPIRateLimit(context.Context, string) (int, time.Time, string, error)
+	TakeUploadIPRateLimit(context.Context, string) (string, error)
+	ReleaseUploadRateLimit(context.Context, string, string, string, string) error
+	IsRetriedUpload(context.Context, string, string) (bool, error)
+	GetVideosFromDB(context.Context, domain.ContentFilter) ([]*domain.Video, error)
+	GetVideosSortedFromDB(context.Context, domain.SortOrder) ([]*domain.Video, error)
+	GetVideosFromDBPaged(context.Context, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosFromDBAfterCursor(context.Context, string, int) ([]*domain.Video, string, error)
+	SearchVideosFromDB(context.Context, string, domain.SearchOptions, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosByTagsFromDB(context.Context, []string, bool, domain.Page) ([]*domain.Video, int64, error)
+	GetVideosFromDBInRange(context.Context, time.Time, time.Time, domain.Page) ([]*domain.Video, int64, error)
 	GetVideosByUserIDFromDB(context.Context, string) ([]*domain.Video, error)
 	ConvertVideoHLS(context.Context, string) error
 	ValidationVideo(io.ReadSeeker) error
+	ValidateVideoSize(context.Context, io.Reader, int64) (io.Reader, error)
+	MaxVideoSize() int64
+	VideoDurationRange() (time.Duration, time.Duration)
 	UploadVideoForStorage(context.Context, *domain.VideoFile) (string, error)
 	GetVideoFromDB(context.Context, string) (*domain.Video, error)
-	InsertVideo(context.Context, string, string, string, string, *string, string, []string, bool, bool, bool, bool) (*domain.UploadVideoResponse, error)
+	GetVideosByIDsFromDB(context.Context, []string) ([]*domain.Video, error)
+	ProbeVideoMetadata(context.Context, string) (*domain.VideoMetadata, error)
+	GenerateAnimatedPreview(context.Context, string) (string, error)
+	GenerateThumbnailSprite(context.Context, string, int) (string, string, error)
+	InsertVideo(context.Context, string, string, string, string, *string, string, []string, domain.ContentRating, bool, bool, bool, *domain.VideoMetadata, string, *time.Time) (*domain.UploadVideoResponse, error)
+	CheckVideoUploader(context.Context, string, string) error
+	InsertPendingVideo(context.Context, string, string, string, *string, string, []string, domain.ContentRating, bool, bool, bool, *domain.VideoMetadata, *time.Time) (*domain.UploadVideoResponse, error)
+	EnqueueVideoProcessingJob(context.Context, domain.VideoProcessingJob) error
+	CheckUserStorageQuota(context.Context, string, int64) error
+	AddToUserStorageUsage(context.Context, string, int64) error
 	GetWatchCount(context.Context, string) (int, error)
 	ChechWatchCount(context.Context, string, string) (bool, error)
 	IncrementWatchCount(context.Context, string, string) (int, error)
-	CutVideo(context.Context, string, string, int, int) (string, error)
+	GetDownloadCount(context.Context, string) (int, error)
+	CheckDownloadCount(context.Context, string, string) (bool, error)
+	IncrementDownloadCount(context.Context, string, string) (int, error)
+	PresignVideoDownloadURL(context.Context, string) (string, error)
+	TakeDownloadRateLimit(context.Context, string, string) error
+	ExtractAudio(context.Context, string, string, string) (string, error)
+	AnalyzeVideo(context.Context, string) (*domain.VideoAnalysis, error)
+	EncryptHLSSegments(context.Context, string, string) error
+	IssueHLSKeyToken(context.Context, string, string) (string, error)
+	GetHLSEncryptionKey(context.Context, string, string) ([]byte, error)
+	CreateThumbnailVariant(context.Context, string, string, float64, string) (*domain.ThumbnailVariant, error)
+	SelectThumbnailForUser(context.Context, string, string) (*domain.ThumbnailVariant, error)
+	RecordThumbnailImpression(context.Context, string) error
+	RecordThumbnailClick(context.Context, string) error
+	GetThumbnailVariantStats(context.Context, string) ([]domain.ThumbnailStats, error)
+	SubmitVideoReport(context.Context, domain.VideoReport) error
+	GetVideoReports(context.Context, string) ([]*domain.VideoReport, error)
+	GetPendingReports(context.Context, domain.Page) ([]*domain.VideoReport, error)
+	ModerateVideo(context.Context, string, string, domain.ModerationAction, string) error
+	GetModerationHistory(context.Context, string) ([]*domain.ModerationRecord, error)
+	RenameTag(context.Context, string, string, string) (int64, error)
+	MergeTags(context.Context, string, string, string) (int64, error)
+	SuggestTagsForTitle(context.Context, string, int) ([]string, error)
+	AutocompleteTags(context.Context, string, int) ([]string, error)
+	GetUniqueViewerCount(context.Context, string) (int64, error)
+	RecordWatchDuration(context.Context, string, string, int) error
+	GetAverageWatchDuration(context.Context, string) (float64, error)
+	UpsertReaction(context.Context, string, string, domain.ReactionType) error
+	GetReactionCounts(context.Context, string) (int64, int64, error)
+	BookmarkVideo(context.Context, string, string) error
+	UnbookmarkVideo(context.Context, string, string) error
+	IsVideoBookmarked(context.Context, string, string) (bool, error)
+	GetBookmarkedVideosByUser(context.Context, string, domain.Page) ([]*domain.Video, int64, error)
+	GetRelatedVideos(context.Context, string, int) ([]*domain.Video, error)
+	GetTrendingVideos(context.Context, time.Duration, int) ([]*domain.Video, error)
+	SoftDeleteVideo(context.Context, string, string) error
+	UpdateVideoExpiry(context.Context, string, string, *time.Time) error
+	UpdateVideo(context.Context, string, string, int, domain.VideoUpdate) (*domain.Video, error)
+	TransferVideoOwnership(context.Context, string, string, string) error
+	SetAllVideosByUserPrivacy(context.Context, string, bool, string) (int64, error)
+	RestoreVideo(context.Context, string, string) error
+	HardDeleteVideo(context.Context, string, string) error
+	GetAuditLogsForVideo(context.Context, string, domain.Page) ([]*domain.AuditEntry, error)
+	UpdateVideoProcessingStatus(context.Context, string, domain.ProcessingStatus, string) error
+	IsAdmin(string) bool
+	CutVideo(context.Context, string, string, int, int, domain.CutOptions) (string, error)
+	ConcatenateVideos(context.Context, []string, string, string) (*domain.UploadVideoResponse, error)
+	SplitVideo(context.Context, string, int, string) ([2]*domain.UploadVideoResponse, error)
+	UploadWatermark(context.Context, string, string) (string, error)
+	RegisterWebhookTarget(context.Context, domain.WebhookTarget) error
+	SetVideoRegionRestrictions(context.Context, string, []string, string) error
+	SetVideoDescription(context.Context, string, string, string, string) error
+	GetVideoDescriptionForLanguage(context.Context, string, string) (string, error)
+	UploadSubtitleTrack(context.Context, string, string, string, io.Reader, string) (*domain.SubtitleTrack, error)
+	GetSubtitleTrack(context.Context, string) (*domain.SubtitleTrack, error)
+	GetSubtitleTracksByVideoID(context.Context, string) ([]*domain.SubtitleTrack, error)
+	DeleteSubtitleTrack(context.Context, string, string) error
+	SetVideoChapters(context.Context, string, []domain.Chapter, string) (string, error)
+	GetVideoChapters(context.Context, string) ([]domain.Chapter, error)
+	IssueVideoStatusToken(context.Context, string, string) (string, error)
+	SubscribeToVideoStatus(context.Context, string, string) (<-chan domain.ProcessingStatus, error)
+	GetOrGenerateQRCode(context.Context, string) (string, error)
 }
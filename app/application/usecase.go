@@ -9,13 +9,15 @@ type UseCase struct {
 	port.UserInputPort
 	port.CommentInputPort
 	port.ImageInputPort
+	port.PlaylistInputPort
 }
 
 func NewUseCase(application *Application) *UseCase {
 	return &UseCase{
-		VideoInputPort:   application,
-		UserInputPort:    application,
-		CommentInputPort: application,
-		ImageInputPort:   application,
+		VideoInputPort:    application,
+		UserInputPort:     application,
+		CommentInputPort:  application,
+		ImageInputPort:    application,
+		PlaylistInputPort: application,
 	}
 }
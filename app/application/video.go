@@ -2,7 +2,12 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"sort"
+	"time"
 
 	"github.com/yuorei/video-server/app/application/port"
 	"github.com/yuorei/video-server/app/domain"
@@ -18,8 +23,8 @@ func NewVideoUseCase(videoRepository port.VideoRepository) *VideoUseCase {
 	}
 }
 
-func (a *Application) GetVideos(ctx context.Context) ([]*domain.Video, error) {
-	videos, err := a.Video.videoRepository.GetVideosFromDB(ctx)
+func (a *Application) GetVideos(ctx context.Context, filter domain.ContentFilter) ([]*domain.Video, error) {
+	videos, err := a.Video.videoRepository.GetVideosFromDB(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +36,30 @@ func (a *Application) GetVideos(ctx context.Context) ([]*domain.Video, error) {
 	return videos, nil
 }
 
+func (a *Application) GetVideosSorted(ctx context.Context, order domain.SortOrder) ([]*domain.Video, error) {
+	return a.Video.videoRepository.GetVideosSortedFromDB(ctx, order)
+}
+
+func (a *Application) GetVideosPaged(ctx context.Context, page domain.Page) ([]*domain.Video, int64, error) {
+	return a.Video.videoRepository.GetVideosFromDBPaged(ctx, page)
+}
+
+func (a *Application) GetVideosAfterCursor(ctx context.Context, cursor string, limit int) ([]*domain.Video, string, error) {
+	return a.Video.videoRepository.GetVideosFromDBAfterCursor(ctx, cursor, limit)
+}
+
+func (a *Application) SearchVideos(ctx context.Context, query string, options domain.SearchOptions, page domain.Page) ([]*domain.Video, int64, error) {
+	return a.Video.videoRepository.SearchVideosFromDB(ctx, query, options, page)
+}
+
+func (a *Application) GetVideosByTags(ctx context.Context, tags []string, matchAll bool, page domain.Page) ([]*domain.Video, int64, error) {
+	return a.Video.videoRepository.GetVideosByTagsFromDB(ctx, tags, matchAll, page)
+}
+
+func (a *Application) GetVideosInRange(ctx context.Context, from, to time.Time, page domain.Page) ([]*domain.Video, int64, error) {
+	return a.Video.videoRepository.GetVideosFromDBInRange(ctx, from, to, page)
+}
+
 func (a *Application) GetVideosByUserID(ctx context.Context, userID string) ([]*domain.Video, error) {
 	videos, err := a.Video.videoRepository.GetVideosByUserIDFromDB(ctx, userID)
 	if err != nil {
@@ -48,43 +77,143 @@ func (a *Application) GetVideo(ctx context.Context, videoID string) (*domain.Vid
 	return a.Video.videoRepository.GetVideoFromDB(ctx, videoID)
 }
 
-func (a *Application) UploadVideo(ctx context.Context, video *domain.UploadVideo, userID string, imageURL string) (*domain.UploadVideoResponse, error) {
-	err := a.Video.videoRepository.CheckUploadAPIRateLimit(ctx, userID)
+func (a *Application) GetVideosByIDs(ctx context.Context, ids []string) ([]*domain.Video, error) {
+	return a.Video.videoRepository.GetVideosByIDsFromDB(ctx, ids)
+}
+
+func (a *Application) UploadVideo(ctx context.Context, video *domain.UploadVideo, userID string, imageURL string) (_ *domain.UploadVideoResponse, err error) {
+	videofile := domain.NewVideoFile(video.ID, video.Video)
+	_, err = a.Video.videoRepository.ValidateVideoSize(ctx, videofile.Video, a.Video.videoRepository.MaxVideoSize())
 	if err != nil {
 		return nil, err
 	}
 
-	videofile := domain.NewVideoFile(video.ID, video.Video)
 	// TODO: 実際に動かしたらhaedが0バイトになりEOFになるため、コメントアウト
 	// err := a.Video.videoRepository.ValidationVideo(videofile.Video)
 	// if err != nil {
 	// 	return nil, err
 	// }
 
-	err = a.Video.videoRepository.ConvertVideoHLS(ctx, videofile.ID)
+	metadata, err := a.Video.videoRepository.ProbeVideoMetadata(ctx, video.SourceKey)
 	if err != nil {
 		return nil, err
 	}
 
-	videoURL, err := a.Video.videoRepository.UploadVideoForStorage(ctx, videofile)
+	minDuration, maxDuration := a.Video.videoRepository.VideoDurationRange()
+	err = domain.ValidateDuration(metadata.Duration, minDuration, maxDuration)
 	if err != nil {
 		return nil, err
 	}
 
-	videoResponse, err := a.Video.videoRepository.InsertVideo(ctx, video.ID, videoURL, imageURL, video.Title, video.Description, userID, video.Tags, video.IsAdult, video.IsPrivate, video.IsExternalCutout, video.IsAd)
+	err = a.Video.videoRepository.CheckUserStorageQuota(ctx, userID, metadata.Size)
 	if err != nil {
 		return nil, err
 	}
 
-	go func() {
-		err = a.Video.videoRepository.SetUploadAPIRateLimit(ctx, userID)
+	// 再送されたアップロードは新しい動画を作らないため数えない
+	// 上限に達した後でも、最初の登録で変換できなかった動画をジョブに入れ直せる
+	retried, err := a.Video.videoRepository.IsRetriedUpload(ctx, video.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var remaining int
+	var resetAt time.Time
+	if !retried {
+		// 同時に送られたアップロードで上限を超えないように、確認と同時に1回分数える
+		// 検証で弾かれたアップロードは数えず、数えた後に失敗した場合は1回分を返す
+		var userTaken, ipTaken string
+		remaining, resetAt, userTaken, err = a.Video.videoRepository.TakeUploadAPIRateLimit(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err == nil {
+				return
+			}
+			releaseErr := a.Video.videoRepository.ReleaseUploadRateLimit(context.WithoutCancel(ctx), userID, userTaken, video.ClientIP, ipTaken)
+			if releaseErr != nil {
+				slog.WarnContext(ctx, "failed to release upload rate limit", "userID", userID, "videoID", video.ID, "error", releaseErr)
+			}
+		}()
+		if video.ClientIP != "" {
+			ipTaken, err = a.Video.videoRepository.TakeUploadIPRateLimit(ctx, video.ClientIP)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 変換はワーカーで行い、レスポンスは処理待ちの状態で返す
+	videoResponse, err := a.Video.videoRepository.InsertPendingVideo(ctx, video.ID, imageURL, video.Title, video.Description, userID, video.Tags, video.ContentRating, video.IsPrivate, video.IsExternalCutout, video.IsAd, metadata, video.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	// 再送されたアップロードは最初の登録で使用量の加算を済ませている
+	// 最初の登録でジョブを入れられなかった場合や変換に失敗した場合は、再送されたファイルで変換し直す
+	videoResponse.RateLimitRemaining = remaining
+	videoResponse.RateLimitResetAt = resetAt
+	if !videoResponse.IsNew {
+		switch videoResponse.ProcessingStatus {
+		case domain.StatusPending:
+		case domain.StatusFailed:
+			err = a.Video.videoRepository.UpdateVideoProcessingStatus(ctx, video.ID, domain.StatusPending, "")
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return videoResponse, nil
+		}
+		err = a.enqueueUploadedVideo(ctx, video, imageURL)
 		if err != nil {
+			return nil, err
 		}
-	}()
+		videoResponse.ProcessingStatus = domain.StatusPending
+		videoResponse.Enqueued = true
+		return videoResponse, nil
+	}
+
+	err = a.enqueueUploadedVideo(ctx, video, imageURL)
+	if err != nil {
+		return nil, err
+	}
+	videoResponse.Enqueued = true
+
+	// 動画は受け付けているため、使用量を増やせなくてもアップロードは失敗にしない
+	err = a.Video.videoRepository.AddToUserStorageUsage(ctx, userID, metadata.Size)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to add storage usage", "userID", userID, "videoID", video.ID, "error", err)
+	}
 
 	return videoResponse, nil
 }
 
+// アップロードを受け取りながら大きさを確認するために使う
+func (a *Application) MaxVideoSize() int64 {
+	return a.Video.videoRepository.MaxVideoSize()
+}
+
+// 同じIDの動画を他のユーザーが登録していないかを、アップロードされたファイルを書き込む前に確認する
+func (a *Application) CheckVideoUploader(ctx context.Context, videoID, userID string) error {
+	return a.Video.videoRepository.CheckVideoUploader(ctx, videoID, userID)
+}
+
+// 入れられなかった場合は再送で変換し直せるように動画をfailedにする
+func (a *Application) enqueueUploadedVideo(ctx context.Context, video *domain.UploadVideo, imageURL string) error {
+	job := domain.NewVideoProcessingJob(video.ID, video.SourceKey, domain.TranscodeOptions{
+		WatermarkKey:      video.WatermarkKey,
+		GenerateThumbnail: imageURL == "",
+	})
+	err := a.Video.videoRepository.EnqueueVideoProcessingJob(ctx, job)
+	if err != nil {
+		updateErr := a.Video.videoRepository.UpdateVideoProcessingStatus(ctx, video.ID, domain.StatusFailed, err.Error())
+		if updateErr != nil {
+			slog.ErrorContext(ctx, "failed to update processing status", "videoID", video.ID, "error", updateErr)
+		}
+		return err
+	}
+	return nil
+}
+
 func (a *Application) GetWatchCount(ctx context.Context, videoID string) (int, error) {
 	return a.Video.videoRepository.GetWatchCount(ctx, videoID)
 }
@@ -100,6 +229,381 @@ func (a *Application) IncrementWatchCount(ctx context.Context, videoID, userID s
 	return a.Video.videoRepository.IncrementWatchCount(ctx, videoID, userID)
 }
 
-func (a *Application) CutVideo(ctx context.Context, videoID, userID string, start, end int) (string, error) {
-	return a.Video.videoRepository.CutVideo(ctx, videoID, userID, start, end)
+func (a *Application) GetDownloadCount(ctx context.Context, videoID string) (int, error) {
+	return a.Video.videoRepository.GetDownloadCount(ctx, videoID)
+}
+
+// 再生回数と同じく、同じユーザーのダウンロードは24時間に1回だけ数える
+func (a *Application) IncrementDownloadCount(ctx context.Context, videoID, userID string) (int, error) {
+	ok, err := a.Video.videoRepository.CheckDownloadCount(ctx, videoID, userID)
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, nil
+	}
+
+	return a.Video.videoRepository.IncrementDownloadCount(ctx, videoID, userID)
+}
+
+// 切り抜きを許可していない動画はダウンロードさせない
+// 非公開の動画は投稿者のみダウンロードできる
+// ログインしていない場合はuserIDを空にし、clientIPで回数を制限する
+func (a *Application) GenerateDownloadURL(ctx context.Context, videoID, userID, clientIP string) (string, error) {
+	err := a.Video.videoRepository.TakeDownloadRateLimit(ctx, userID, clientIP)
+	if err != nil {
+		return "", err
+	}
+
+	video, err := a.Video.videoRepository.GetVideoFromDB(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	if !video.AllowsExternalUse() {
+		return "", fmt.Errorf("%w: %s does not allow external cutout", domain.ErrDownloadNotAllowed, videoID)
+	}
+	if video.IsPrivate && video.UploaderID != userID {
+		return "", fmt.Errorf("%w: %s is private", domain.ErrPermissionDenied, videoID)
+	}
+
+	url, err := a.Video.videoRepository.PresignVideoDownloadURL(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+
+	downloaderID := userID
+	if downloaderID == "" {
+		downloaderID = clientIP
+	}
+	_, err = a.IncrementDownloadCount(ctx, videoID, downloaderID)
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// 音声だけを聞きたい場合に使う。非公開の動画は投稿者のみ取得できる
+func (a *Application) ExtractAudio(ctx context.Context, videoID, userID, format string) (string, error) {
+	video, err := a.Video.videoRepository.GetVideoFromDB(ctx, videoID)
+	if err != nil {
+		return "", err
+	}
+	if video.IsPrivate && video.UploaderID != userID {
+		return "", fmt.Errorf("%w: %s is private", domain.ErrPermissionDenied, videoID)
+	}
+	return a.Video.videoRepository.ExtractAudio(ctx, videoID, userID, format)
+}
+
+// 配信に向いた動画かを確認できるように、投稿者か管理者にのみ返す
+func (a *Application) AnalyzeVideo(ctx context.Context, videoID, requestingUserID string) (*domain.VideoAnalysis, error) {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Video.videoRepository.AnalyzeVideo(ctx, videoID)
+}
+
+// 有料や広告付きの動画を暗号化して配信する。投稿者か管理者のみ設定できる
+func (a *Application) EncryptHLSSegments(ctx context.Context, videoID, keyID, requestingUserID string) error {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return err
+	}
+	return a.Video.videoRepository.EncryptHLSSegments(ctx, videoID, keyID)
+}
+
+// 動画を見られるかは鍵を取得するときに確認する
+func (a *Application) IssueHLSKeyToken(ctx context.Context, keyID, userID string) (string, error) {
+	return a.Video.videoRepository.IssueHLSKeyToken(ctx, keyID, userID)
+}
+
+func (a *Application) GetHLSEncryptionKey(ctx context.Context, keyID, userToken string) ([]byte, error) {
+	return a.Video.videoRepository.GetHLSEncryptionKey(ctx, keyID, userToken)
+}
+
+// サムネイルのA/Bテストの候補の追加と結果の確認は投稿者か管理者のみできる
+func (a *Application) CreateThumbnailVariant(ctx context.Context, videoID, thumbnailURL string, weight float64, requestingUserID string) (*domain.ThumbnailVariant, error) {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Video.videoRepository.CreateThumbnailVariant(ctx, videoID, thumbnailURL, weight, requestingUserID)
+}
+
+func (a *Application) SelectThumbnailForUser(ctx context.Context, videoID, userID string) (*domain.ThumbnailVariant, error) {
+	return a.Video.videoRepository.SelectThumbnailForUser(ctx, videoID, userID)
+}
+
+func (a *Application) RecordThumbnailImpression(ctx context.Context, variantID string) error {
+	return a.Video.videoRepository.RecordThumbnailImpression(ctx, variantID)
+}
+
+func (a *Application) RecordThumbnailClick(ctx context.Context, variantID string) error {
+	return a.Video.videoRepository.RecordThumbnailClick(ctx, variantID)
+}
+
+func (a *Application) GetThumbnailVariantStats(ctx context.Context, videoID, requestingUserID string) ([]domain.ThumbnailStats, error) {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Video.videoRepository.GetThumbnailVariantStats(ctx, videoID)
+}
+
+// 非公開の動画は見られるユーザーしかいないため、投稿者以外は通報できない
+func (a *Application) SubmitVideoReport(ctx context.Context, report domain.VideoReport) error {
+	video, err := a.Video.videoRepository.GetVideoFromDB(ctx, report.VideoID)
+	if err != nil {
+		return err
+	}
+	if video.IsPrivate && video.UploaderID != report.ReporterID {
+		return fmt.Errorf("%w: %s is private", domain.ErrPermissionDenied, report.VideoID)
+	}
+	return a.Video.videoRepository.SubmitVideoReport(ctx, report)
+}
+
+// 通報した人が分かるため、通報の一覧は管理者のみ見られる
+func (a *Application) GetVideoReports(ctx context.Context, videoID, requestingUserID string) ([]*domain.VideoReport, error) {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return nil, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.GetVideoReports(ctx, videoID)
+}
+
+func (a *Application) GetPendingReports(ctx context.Context, page domain.Page, requestingUserID string) ([]*domain.VideoReport, error) {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return nil, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.GetPendingReports(ctx, page)
+}
+
+// 通報を確認した管理者のみ操作できる
+func (a *Application) ModerateVideo(ctx context.Context, videoID, moderatorID string, action domain.ModerationAction, reason string) error {
+	if !a.Video.videoRepository.IsAdmin(moderatorID) {
+		return fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, moderatorID)
+	}
+	return a.Video.videoRepository.ModerateVideo(ctx, videoID, moderatorID, action, reason)
+}
+
+func (a *Application) GetModerationHistory(ctx context.Context, videoID, requestingUserID string) ([]*domain.ModerationRecord, error) {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return nil, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.GetModerationHistory(ctx, videoID)
+}
+
+// 管理者の確認はRenameTagの中で行う
+func (a *Application) RenameTag(ctx context.Context, oldName, newName, requestingUserID string) (int64, error) {
+	return a.Video.videoRepository.RenameTag(ctx, oldName, newName, requestingUserID)
+}
+
+// 管理者の確認はMergeTagsの中で行う
+func (a *Application) MergeTags(ctx context.Context, sourceTag, targetTag, requestingUserID string) (int64, error) {
+	return a.Video.videoRepository.MergeTags(ctx, sourceTag, targetTag, requestingUserID)
+}
+
+func (a *Application) SuggestTagsForTitle(ctx context.Context, title string, maxSuggestions int) ([]string, error) {
+	return a.Video.videoRepository.SuggestTagsForTitle(ctx, title, maxSuggestions)
+}
+
+func (a *Application) AutocompleteTags(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return a.Video.videoRepository.AutocompleteTags(ctx, prefix, limit)
+}
+
+func (a *Application) GetUniqueViewerCount(ctx context.Context, videoID string) (int64, error) {
+	return a.Video.videoRepository.GetUniqueViewerCount(ctx, videoID)
+}
+
+func (a *Application) RecordWatchDuration(ctx context.Context, videoID, userID string, durationSeconds int) error {
+	return a.Video.videoRepository.RecordWatchDuration(ctx, videoID, userID, durationSeconds)
+}
+
+func (a *Application) GetAverageWatchDuration(ctx context.Context, videoID string) (float64, error) {
+	return a.Video.videoRepository.GetAverageWatchDuration(ctx, videoID)
+}
+
+func (a *Application) UpsertReaction(ctx context.Context, videoID, userID string, reaction domain.ReactionType) error {
+	return a.Video.videoRepository.UpsertReaction(ctx, videoID, userID, reaction)
+}
+
+func (a *Application) GetReactionCounts(ctx context.Context, videoID string) (int64, int64, error) {
+	return a.Video.videoRepository.GetReactionCounts(ctx, videoID)
+}
+
+func (a *Application) BookmarkVideo(ctx context.Context, videoID, userID string) error {
+	return a.Video.videoRepository.BookmarkVideo(ctx, videoID, userID)
+}
+
+func (a *Application) UnbookmarkVideo(ctx context.Context, videoID, userID string) error {
+	return a.Video.videoRepository.UnbookmarkVideo(ctx, videoID, userID)
+}
+
+func (a *Application) IsVideoBookmarked(ctx context.Context, videoID, userID string) (bool, error) {
+	return a.Video.videoRepository.IsVideoBookmarked(ctx, videoID, userID)
+}
+
+func (a *Application) GetBookmarkedVideosByUser(ctx context.Context, userID string, page domain.Page) ([]*domain.Video, int64, error) {
+	return a.Video.videoRepository.GetBookmarkedVideosByUser(ctx, userID, page)
+}
+
+func (a *Application) GetRelatedVideos(ctx context.Context, videoID string, limit int) ([]*domain.Video, error) {
+	return a.Video.videoRepository.GetRelatedVideos(ctx, videoID, limit)
+}
+
+func (a *Application) GetTrendingVideos(ctx context.Context, window time.Duration, limit int) ([]*domain.Video, error) {
+	return a.Video.videoRepository.GetTrendingVideos(ctx, window, limit)
+}
+
+func (a *Application) SoftDeleteVideo(ctx context.Context, videoID, requestingUserID string) error {
+	return a.Video.videoRepository.SoftDeleteVideo(ctx, videoID, requestingUserID)
+}
+
+func (a *Application) UpdateVideo(ctx context.Context, id, requestingUserID string, version int, update domain.VideoUpdate) (*domain.Video, error) {
+	return a.Video.videoRepository.UpdateVideo(ctx, id, requestingUserID, version, update)
+}
+
+func (a *Application) TransferVideoOwnership(ctx context.Context, videoID, newOwnerID, requestingUserID string) error {
+	return a.Video.videoRepository.TransferVideoOwnership(ctx, videoID, newOwnerID, requestingUserID)
+}
+
+func (a *Application) SetAllVideosByUserPrivacy(ctx context.Context, userID string, isPrivate bool, requestingUserID string) (int64, error) {
+	return a.Video.videoRepository.SetAllVideosByUserPrivacy(ctx, userID, isPrivate, requestingUserID)
+}
+
+func (a *Application) UpdateVideoExpiry(ctx context.Context, videoID, requestingUserID string, expiresAt *time.Time) error {
+	return a.Video.videoRepository.UpdateVideoExpiry(ctx, videoID, requestingUserID, expiresAt)
+}
+
+// 復元と完全な削除は管理者のみ行える
+func (a *Application) RestoreVideo(ctx context.Context, videoID, requestingUserID string) error {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.RestoreVideo(ctx, videoID, requestingUserID)
+}
+
+func (a *Application) HardDeleteVideo(ctx context.Context, videoID, requestingUserID string) error {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.HardDeleteVideo(ctx, videoID, requestingUserID)
+}
+
+func (a *Application) GetAuditLogsForVideo(ctx context.Context, videoID string, page domain.Page, requestingUserID string) ([]*domain.AuditEntry, error) {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return nil, fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.GetAuditLogsForVideo(ctx, videoID, page)
+}
+
+func (a *Application) UpdateVideoProcessingStatus(ctx context.Context, videoID string, status domain.ProcessingStatus, errMsg string) error {
+	return a.Video.videoRepository.UpdateVideoProcessingStatus(ctx, videoID, status, errMsg)
+}
+
+func (a *Application) CutVideo(ctx context.Context, videoID, userID string, start, end int, options domain.CutOptions) (string, error) {
+	return a.Video.videoRepository.CutVideo(ctx, videoID, userID, start, end, options)
+}
+
+func (a *Application) ConcatenateVideos(ctx context.Context, videoIDs []string, outputTitle string, uploaderID string) (*domain.UploadVideoResponse, error) {
+	return a.Video.videoRepository.ConcatenateVideos(ctx, videoIDs, outputTitle, uploaderID)
+}
+
+func (a *Application) SplitVideo(ctx context.Context, videoID string, splitAt int, uploaderID string) ([2]*domain.UploadVideoResponse, error) {
+	return a.Video.videoRepository.SplitVideo(ctx, videoID, splitAt, uploaderID)
+}
+
+func (a *Application) UploadWatermark(ctx context.Context, uploaderID, imagePath string) (string, error) {
+	return a.Video.videoRepository.UploadWatermark(ctx, uploaderID, imagePath)
+}
+
+// Webhookの通知先は管理者のみ登録できる
+func (a *Application) RegisterWebhookTarget(ctx context.Context, target domain.WebhookTarget, requestingUserID string) error {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.RegisterWebhookTarget(ctx, target)
+}
+
+// 配信できる国は契約で決まるので管理者のみ設定できる
+func (a *Application) SetVideoRegionRestrictions(ctx context.Context, videoID string, regions []string, requestingUserID string) error {
+	if !a.Video.videoRepository.IsAdmin(requestingUserID) {
+		return fmt.Errorf("%w: %s is not an admin", domain.ErrPermissionDenied, requestingUserID)
+	}
+	return a.Video.videoRepository.SetVideoRegionRestrictions(ctx, videoID, regions, requestingUserID)
+}
+
+// 投稿者か管理者のみ設定できる
+func (a *Application) SetVideoDescription(ctx context.Context, videoID, language, text, requestingUserID string) error {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return err
+	}
+	return a.Video.videoRepository.SetVideoDescription(ctx, videoID, language, text, requestingUserID)
+}
+
+func (a *Application) GetVideoDescriptionForLanguage(ctx context.Context, videoID, language string) (string, error) {
+	return a.Video.videoRepository.GetVideoDescriptionForLanguage(ctx, videoID, language)
+}
+
+// 投稿者か管理者のみアップロードできる
+func (a *Application) UploadSubtitleTrack(ctx context.Context, videoID, language, format string, data io.Reader, requestingUserID string) (*domain.SubtitleTrack, error) {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Video.videoRepository.UploadSubtitleTrack(ctx, videoID, language, format, data, requestingUserID)
+}
+
+func (a *Application) GetSubtitleTracksByVideoID(ctx context.Context, videoID string) ([]*domain.SubtitleTrack, error) {
+	return a.Video.videoRepository.GetSubtitleTracksByVideoID(ctx, videoID)
+}
+
+// 投稿者か管理者のみ削除できる
+func (a *Application) DeleteSubtitleTrack(ctx context.Context, trackID, requestingUserID string) error {
+	track, err := a.Video.videoRepository.GetSubtitleTrack(ctx, trackID)
+	if err != nil {
+		return err
+	}
+	err = a.checkVideoEditor(ctx, track.VideoID, requestingUserID)
+	if err != nil {
+		return err
+	}
+	return a.Video.videoRepository.DeleteSubtitleTrack(ctx, trackID, requestingUserID)
+}
+
+// 投稿者か管理者のみ設定できる
+func (a *Application) SetVideoChapters(ctx context.Context, videoID string, chapters []domain.Chapter, requestingUserID string) (string, error) {
+	err := a.checkVideoEditor(ctx, videoID, requestingUserID)
+	if err != nil {
+		return "", err
+	}
+	return a.Video.videoRepository.SetVideoChapters(ctx, videoID, chapters, requestingUserID)
+}
+
+func (a *Application) GetVideoChapters(ctx context.Context, videoID string) ([]domain.Chapter, error) {
+	return a.Video.videoRepository.GetVideoChapters(ctx, videoID)
+}
+
+// 動画の投稿者か管理者でない場合はErrPermissionDeniedを返す
+// 公開期限を過ぎた動画も編集できる
+func (a *Application) checkVideoEditor(ctx context.Context, videoID, userID string) error {
+	video, err := a.Video.videoRepository.GetVideoFromDB(ctx, videoID)
+	if err != nil && !errors.Is(err, domain.ErrVideoExpired) {
+		return err
+	}
+	if video.UploaderID != userID && !a.Video.videoRepository.IsAdmin(userID) {
+		return fmt.Errorf("%w: %s is not the uploader of %s", domain.ErrPermissionDenied, userID, videoID)
+	}
+	return nil
+}
+
+func (a *Application) IssueVideoStatusToken(ctx context.Context, videoID, userID string) (string, error) {
+	return a.Video.videoRepository.IssueVideoStatusToken(ctx, videoID, userID)
+}
+
+func (a *Application) SubscribeToVideoStatus(ctx context.Context, videoID, userToken string) (<-chan domain.ProcessingStatus, error) {
+	return a.Video.videoRepository.SubscribeToVideoStatus(ctx, videoID, userToken)
+}
+
+func (a *Application) GetOrGenerateQRCode(ctx context.Context, videoID string) (string, error) {
+	return a.Video.videoRepository.GetOrGenerateQRCode(ctx, videoID)
 }
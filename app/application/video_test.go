@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/yuorei/video-server/app/domain"
+	mock_port "github.com/yuorei/video-server/mock"
+)
+
+// 検証と容量の確認を通るアップロードを用意する
+func newUploadTestRepository(t *testing.T) *mock_port.MockVideoRepository {
+	t.Helper()
+	repo := mock_port.NewMockVideoRepository(gomock.NewController(t))
+	repo.EXPECT().MaxVideoSize().Return(int64(1 << 20)).AnyTimes()
+	repo.EXPECT().ValidateVideoSize(gomock.Any(), gomock.Any(), gomock.Any()).Return(strings.NewReader(""), nil).AnyTimes()
+	repo.EXPECT().ProbeVideoMetadata(gomock.Any(), "source_1").Return(&domain.VideoMetadata{Duration: time.Minute, Size: 100}, nil).AnyTimes()
+	repo.EXPECT().VideoDurationRange().Return(time.Second, time.Hour).AnyTimes()
+	repo.EXPECT().CheckUserStorageQuota(gomock.Any(), "user_1", int64(100)).Return(nil).AnyTimes()
+	return repo
+}
+
+func newUploadTestVideo() *domain.UploadVideo {
+	description := "description"
+	return &domain.UploadVideo{
+		ID:          "video_1",
+		Video:       strings.NewReader(""),
+		Title:       "title",
+		Description: &description,
+		SourceKey:   "source_1",
+	}
+}
+
+func Test_上限に達した後の再送されたアップロード(t *testing.T) {
+	tests := []struct {
+		name   string
+		status domain.ProcessingStatus
+	}{
+		{name: "処理待ちの動画", status: domain.StatusPending},
+		{name: "変換に失敗した動画", status: domain.StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newUploadTestRepository(t)
+			// 数えようとすると上限を超える
+			resetAt := time.Now().Add(time.Hour)
+			repo.EXPECT().TakeUploadAPIRateLimit(gomock.Any(), "user_1").Return(0, resetAt, "", domain.NewRateLimitError(domain.ErrUploadRateLimitExceeded, resetAt)).AnyTimes()
+			repo.EXPECT().IsRetriedUpload(gomock.Any(), "video_1", "user_1").Return(true, nil)
+			repo.EXPECT().InsertPendingVideo(gomock.Any(), "video_1", gomock.Any(), gomock.Any(), gomock.Any(), "user_1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&domain.UploadVideoResponse{ID: "video_1", ProcessingStatus: tt.status, IsNew: false}, nil)
+			if tt.status == domain.StatusFailed {
+				repo.EXPECT().UpdateVideoProcessingStatus(gomock.Any(), "video_1", domain.StatusPending, "").Return(nil)
+			}
+			repo.EXPECT().EnqueueVideoProcessingJob(gomock.Any(), gomock.Any()).Return(nil)
+
+			app := &Application{Video: NewVideoUseCase(repo)}
+			got, err := app.UploadVideo(context.Background(), newUploadTestVideo(), "user_1", "")
+			if err != nil {
+				t.Fatalf("Application.UploadVideo() error = %v", err)
+			}
+			if !got.Enqueued || got.ProcessingStatus != domain.StatusPending {
+				t.Errorf("Application.UploadVideo() Enqueued, ProcessingStatus = %v, %v, want true, %v", got.Enqueued, got.ProcessingStatus, domain.StatusPending)
+			}
+		})
+	}
+}
+
+func Test_失敗したアップロードのレート制限(t *testing.T) {
+	insertErr := errors.New("insert failed")
+	tests := []struct {
+		name        string
+		probeErr    error
+		insertErr   error
+		wantTake    bool
+		wantRelease bool
+		wantErr     error
+	}{
+		{name: "検証で弾かれたアップロード", probeErr: domain.ErrVideoTooShort, wantErr: domain.ErrVideoTooShort},
+		{name: "登録に失敗したアップロード", insertErr: insertErr, wantTake: true, wantRelease: true, wantErr: insertErr},
+		{name: "成功したアップロード", wantTake: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mock_port.NewMockVideoRepository(gomock.NewController(t))
+			repo.EXPECT().MaxVideoSize().Return(int64(1 << 20)).AnyTimes()
+			repo.EXPECT().ValidateVideoSize(gomock.Any(), gomock.Any(), gomock.Any()).Return(strings.NewReader(""), nil)
+			// 短すぎる動画は再生時間の確認で弾かれる
+			duration := time.Minute
+			if tt.probeErr != nil {
+				duration = time.Millisecond
+			}
+			repo.EXPECT().ProbeVideoMetadata(gomock.Any(), "source_1").Return(&domain.VideoMetadata{Duration: duration, Size: 100}, nil)
+			repo.EXPECT().VideoDurationRange().Return(time.Second, time.Hour)
+			if tt.wantTake {
+				repo.EXPECT().CheckUserStorageQuota(gomock.Any(), "user_1", int64(100)).Return(nil)
+				repo.EXPECT().IsRetriedUpload(gomock.Any(), "video_1", "user_1").Return(false, nil)
+				repo.EXPECT().TakeUploadAPIRateLimit(gomock.Any(), "user_1").Return(2, time.Now().Add(time.Hour), "taken_1", nil)
+				repo.EXPECT().TakeUploadIPRateLimit(gomock.Any(), "192.0.2.1").Return("taken_2", nil)
+				repo.EXPECT().InsertPendingVideo(gomock.Any(), "video_1", gomock.Any(), gomock.Any(), gomock.Any(), "user_1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&domain.UploadVideoResponse{ID: "video_1", ProcessingStatus: domain.StatusPending, IsNew: true}, tt.insertErr)
+			}
+			if tt.wantRelease {
+				repo.EXPECT().ReleaseUploadRateLimit(gomock.Any(), "user_1", "taken_1", "192.0.2.1", "taken_2").Return(nil)
+			}
+			if tt.wantTake && !tt.wantRelease {
+				repo.EXPECT().EnqueueVideoProcessingJob(gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().AddToUserStorageUsage(gomock.Any(), "user_1", int64(100)).Return(nil)
+			}
+
+			video := newUploadTestVideo()
+			video.ClientIP = "192.0.2.1"
+			app := &Application{Video: NewVideoUseCase(repo)}
+			_, err := app.UploadVideo(context.Background(), video, "user_1", "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Application.UploadVideo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "fmt"
+
+const (
+	AudioFormatMP3 = "mp3"
+	AudioFormatAAC = "aac"
+)
+
+func ValidateAudioFormat(format string) error {
+	switch format {
+	case AudioFormatMP3, AudioFormatAAC:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAudioFormat, format)
+	}
+}
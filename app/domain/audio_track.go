@@ -0,0 +1,8 @@
+package domain
+
+// 動画の音声トラック。解説や吹き替えなど複数の音声がある動画でプレイヤーが切り替えられるようにする
+type AudioTrack struct {
+	Index    int    `json:"index"`    // 音声トラックの中での順番(ffmpegの0:a:N)
+	Language string `json:"language"` // BCP 47。わからない場合は空
+	Label    string `json:"label"`
+}
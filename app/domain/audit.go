@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 監査ログの対象
+const (
+	AuditEntityVideo = "video"
+	AuditEntityTag   = "tag"
+)
+
+// 利用者ではなくサーバーが行った操作のActorID
+const AuditActorSystem = "system"
+
+// 監査ログに記録する操作
+const (
+	AuditActionInsertVideo            = "insert_video"
+	AuditActionUpdateVideo            = "update_video"
+	AuditActionSoftDeleteVideo        = "soft_delete_video"
+	AuditActionRestoreVideo           = "restore_video"
+	AuditActionHardDeleteVideo        = "hard_delete_video"
+	AuditActionTransferVideoOwnership = "transfer_video_ownership"
+	AuditActionSetVideoPrivacy        = "set_video_privacy"
+	AuditActionModerateVideo          = "moderate_video"
+	AuditActionUpdateVideoExpiry      = "update_video_expiry"
+	AuditActionSetVideoRegions        = "set_video_regions"
+	AuditActionSetVideoChapters       = "set_video_chapters"
+	AuditActionSetVideoDescription    = "set_video_description"
+	AuditActionUpdateProcessingStatus = "update_processing_status"
+	AuditActionUploadSubtitleTrack    = "upload_subtitle_track"
+	AuditActionDeleteSubtitleTrack    = "delete_subtitle_track"
+	AuditActionCreateThumbnailVariant = "create_thumbnail_variant"
+	AuditActionExpireVideo            = "expire_video"
+	AuditActionPublishScheduledVideo  = "publish_scheduled_video"
+	AuditActionTranscodeVideo         = "transcode_video"
+	AuditActionGeneratePreview        = "generate_preview"
+	AuditActionGenerateSprite         = "generate_sprite"
+	AuditActionGenerateQRCode         = "generate_qrcode"
+	AuditActionGenerateDASHManifest   = "generate_dash_manifest"
+	AuditActionAnalyzeVideo           = "analyze_video"
+	AuditActionEncryptHLS             = "encrypt_hls"
+	AuditActionRenameTag              = "rename_tag"
+	AuditActionMergeTags              = "merge_tags"
+)
+
+// 誰がいつ何に対してどの操作をしたか
+// BeforeとAfterは操作の前後の値をJSONにしたもので、追加ではBeforeが、削除ではAfterが空になる
+type AuditEntry struct {
+	ID         int64
+	EntityType string
+	EntityID   string
+	Action     string
+	ActorID    string
+	Before     json.RawMessage
+	After      json.RawMessage
+	OccurredAt time.Time
+}
+
+// before、afterがnilの場合は空にする
+func NewAuditEntry(entityType, entityID, action, actorID string, before, after any) (AuditEntry, error) {
+	beforeJSON, err := marshalAuditValue(before)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	afterJSON, err := marshalAuditValue(after)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	return AuditEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorID:    actorID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		OccurredAt: time.Now(),
+	}, nil
+}
+
+func marshalAuditValue(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// nilのポインタもnullではなく空にする
+	if string(bytes) == "null" {
+		return nil, nil
+	}
+	return bytes, nil
+}
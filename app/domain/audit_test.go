@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	var nilVideo *Video
+	tests := []struct {
+		name       string
+		before     any
+		after      any
+		wantBefore string
+		wantAfter  string
+	}{
+		{name: "insert", before: nil, after: &Video{ID: "video_1"}, wantBefore: "", wantAfter: `"ID":"video_1"`},
+		{name: "delete with nil pointer", before: &Video{ID: "video_1"}, after: nilVideo, wantBefore: `"ID":"video_1"`, wantAfter: ""},
+		{name: "map", before: map[string]string{"from": "user_1"}, after: map[string]string{"to": "user_2"}, wantBefore: `{"from":"user_1"}`, wantAfter: `{"to":"user_2"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAuditEntry(AuditEntityVideo, "video_1", AuditActionUpdateVideo, "user_1", tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("NewAuditEntry() error = %v", err)
+			}
+			if !containsJSON(got.Before, tt.wantBefore) || !containsJSON(got.After, tt.wantAfter) {
+				t.Errorf("NewAuditEntry() before = %s, after = %s, want %s and %s", got.Before, got.After, tt.wantBefore, tt.wantAfter)
+			}
+			if got.OccurredAt.IsZero() {
+				t.Error("NewAuditEntry() OccurredAt is zero")
+			}
+		})
+	}
+
+	_, err := NewAuditEntry(AuditEntityVideo, "video_1", AuditActionUpdateVideo, "user_1", func() {}, nil)
+	if err == nil {
+		t.Error("NewAuditEntry() error = nil, want error for a value that cannot be marshalled")
+	}
+}
+
+// wantが空の場合は値も空であることを確認する
+func containsJSON(got []byte, want string) bool {
+	if want == "" {
+		return len(got) == 0
+	}
+	return strings.Contains(string(got), want)
+}
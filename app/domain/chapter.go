@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 動画のチャプター。次のチャプターの開始位置までがこのチャプターになる
+type Chapter struct {
+	Title        string
+	StartSeconds int
+}
+
+// チャプターが開始位置の昇順に並び、動画の中に収まっているか確認する
+// 長さがわからない動画は負の値でなければよい
+func ValidateChapters(chapters []Chapter, duration time.Duration) error {
+	for n, chapter := range chapters {
+		if strings.TrimSpace(chapter.Title) == "" {
+			return fmt.Errorf("%w: title of chapter %d must not be empty", ErrInvalidChapters, n+1)
+		}
+		if chapter.StartSeconds < 0 {
+			return fmt.Errorf("%w: start of chapter %d must not be negative", ErrInvalidChapters, n+1)
+		}
+		if duration > 0 && time.Duration(chapter.StartSeconds)*time.Second >= duration {
+			return fmt.Errorf("%w: chapter %d starts after the end of the video", ErrInvalidChapters, n+1)
+		}
+		if n > 0 && chapter.StartSeconds <= chapters[n-1].StartSeconds {
+			return fmt.Errorf("%w: chapters must be in ascending order of start time", ErrInvalidChapters)
+		}
+	}
+	return nil
+}
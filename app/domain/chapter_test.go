@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateChapters(t *testing.T) {
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		duration time.Duration
+		wantErr  error
+	}{
+		{name: "empty", chapters: nil, duration: time.Minute},
+		{name: "ascending", chapters: []Chapter{{Title: "intro", StartSeconds: 0}, {Title: "main", StartSeconds: 30}}, duration: time.Minute},
+		{name: "unknown duration", chapters: []Chapter{{Title: "intro", StartSeconds: 3600}}},
+		{name: "not ascending", chapters: []Chapter{{Title: "a", StartSeconds: 30}, {Title: "b", StartSeconds: 10}}, duration: time.Minute, wantErr: ErrInvalidChapters},
+		{name: "same start", chapters: []Chapter{{Title: "a", StartSeconds: 10}, {Title: "b", StartSeconds: 10}}, duration: time.Minute, wantErr: ErrInvalidChapters},
+		{name: "negative", chapters: []Chapter{{Title: "a", StartSeconds: -1}}, duration: time.Minute, wantErr: ErrInvalidChapters},
+		{name: "after the end", chapters: []Chapter{{Title: "a", StartSeconds: 60}}, duration: time.Minute, wantErr: ErrInvalidChapters},
+		{name: "empty title", chapters: []Chapter{{Title: " ", StartSeconds: 0}}, duration: time.Minute, wantErr: ErrInvalidChapters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateChapters(tt.chapters, tt.duration); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateChapters() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ type (
 		CreatedAt time.Time
 		UpdatedAt time.Time
 		User      *User
+		// 返信の場合は返信先のコメントID
+		ParentCommentID *string
 	}
 )
 
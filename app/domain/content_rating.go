@@ -0,0 +1,75 @@
+package domain
+
+import "fmt"
+
+// 動画の対象年齢。下にあるものほど制限が厳しい
+type ContentRating string
+
+const (
+	RatingGeneral ContentRating = "general"
+	RatingTeen    ContentRating = "teen"
+	RatingMature  ContentRating = "mature"
+	RatingAdult   ContentRating = "adult"
+)
+
+// 制限が緩い順
+var contentRatings = []ContentRating{RatingGeneral, RatingTeen, RatingMature, RatingAdult}
+
+func (r ContentRating) Validate() error {
+	if r.level() < 0 {
+		return fmt.Errorf("%w: unknown content rating: %s", ErrInvalidInput, r)
+	}
+	return nil
+}
+
+func (r ContentRating) level() int {
+	for i, rating := range contentRatings {
+		if r == rating {
+			return i
+		}
+	}
+	return -1
+}
+
+// 制限が厳しい方を返す
+func StricterContentRating(a, b ContentRating) ContentRating {
+	if b.level() > a.level() {
+		return b
+	}
+	return a
+}
+
+// gRPCのAPIはまだ成人向けかどうかのboolしか持っていないので変換する
+func ContentRatingFromAdult(isAdult bool) ContentRating {
+	if isAdult {
+		return RatingAdult
+	}
+	return RatingGeneral
+}
+
+func (r ContentRating) IsAdult() bool {
+	return r == RatingAdult
+}
+
+// 一覧に表示する動画の対象年齢の上限
+type ContentFilter struct {
+	MaxRating ContentRating
+}
+
+// 成人向け以外を表示する
+var DefaultContentFilter = ContentFilter{MaxRating: RatingMature}
+
+func (f ContentFilter) Allows(r ContentRating) bool {
+	return r.level() >= 0 && r.level() <= f.MaxRating.level()
+}
+
+// 表示してよい対象年齢を制限が緩い順に返す
+func (f ContentFilter) AllowedRatings() []ContentRating {
+	ratings := []ContentRating{}
+	for _, r := range contentRatings {
+		if f.Allows(r) {
+			ratings = append(ratings, r)
+		}
+	}
+	return ratings
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestContentRatingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rating  ContentRating
+		wantErr error
+	}{
+		{name: "general", rating: RatingGeneral},
+		{name: "adult", rating: RatingAdult},
+		{name: "empty", rating: "", wantErr: ErrInvalidInput},
+		{name: "unknown", rating: "pg-13", wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rating.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ContentRating.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContentFilterAllowedRatings(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ContentFilter
+		want   []ContentRating
+	}{
+		{name: "general", filter: ContentFilter{MaxRating: RatingGeneral}, want: []ContentRating{RatingGeneral}},
+		{name: "default", filter: DefaultContentFilter, want: []ContentRating{RatingGeneral, RatingTeen, RatingMature}},
+		{name: "adult", filter: ContentFilter{MaxRating: RatingAdult}, want: []ContentRating{RatingGeneral, RatingTeen, RatingMature, RatingAdult}},
+		{name: "unknown", filter: ContentFilter{MaxRating: "unknown"}, want: []ContentRating{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.AllowedRatings()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContentFilter.AllowedRatings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStricterContentRating(t *testing.T) {
+	if got := StricterContentRating(RatingTeen, RatingGeneral); got != RatingTeen {
+		t.Errorf("StricterContentRating() = %v, want %v", got, RatingTeen)
+	}
+	if got := StricterContentRating(RatingTeen, RatingAdult); got != RatingAdult {
+		t.Errorf("StricterContentRating() = %v, want %v", got, RatingAdult)
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// 最後に取得した動画の (created_at, id) を表す
+	Cursor struct {
+		CreatedAt time.Time `json:"created_at"`
+		ID        string    `json:"id"`
+	}
+)
+
+func EncodeCursor(createdAt time.Time, id string) (string, error) {
+	bytes, err := json.Marshal(&Cursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(bytes), nil
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	bytes, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var c Cursor
+	err = json.Unmarshal(bytes, &c)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.ID == "" {
+		return nil, fmt.Errorf("invalid cursor: id is empty")
+	}
+	return &c, nil
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	cursor, err := EncodeCursor(createdAt, "video_1")
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+
+	got, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(createdAt) || got.ID != "video_1" {
+		t.Errorf("DecodeCursor() = %v, want (%v, %v)", got, createdAt, "video_1")
+	}
+
+	invalids := []string{"", "not base64!", "e30="}
+	for _, invalid := range invalids {
+		if _, err := DecodeCursor(invalid); err == nil {
+			t.Errorf("DecodeCursor(%q) error = nil, want error", invalid)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "fmt"
+
+const (
+	CutFormatMP4  = "mp4"
+	CutFormatWebM = "webm"
+)
+
+// 切り抜き動画の出力形式
+type CutOptions struct {
+	Format string
+	// trueの場合は-c copyせずにffmpegにコンテナに合ったコーデックを選ばせる
+	ReEncode bool
+	// trueの場合は再エンコードしてフレーム単位で正確に切り出す
+	AccurateSeeking bool
+}
+
+// formatが空の場合はmp4にする
+func NewCutOptions(format string, reEncode, accurateSeeking bool) CutOptions {
+	if format == "" {
+		format = CutFormatMP4
+	}
+	return CutOptions{
+		Format:          format,
+		ReEncode:        reEncode,
+		AccurateSeeking: accurateSeeking,
+	}
+}
+
+func (o CutOptions) Validate() error {
+	switch o.Format {
+	case CutFormatMP4, CutFormatWebM:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedCutFormat, o.Format)
+	}
+}
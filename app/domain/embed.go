@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// 埋め込みで指定できる幅と高さの範囲(px)
+const (
+	MinEmbedDimension = 160
+	MaxEmbedDimension = 3840
+)
+
+// 投稿者が外部での利用を許可していない動画は他のサイトに埋め込ませない
+func (v *Video) IsEmbeddable() bool {
+	return !v.IsPrivate && v.AllowsExternalUse()
+}
+
+func EmbedURL(baseURL, videoID string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/embed/" + url.PathEscape(videoID)
+}
+
+// 他のサイトに貼り付けるための<iframe>を返す
+func GenerateEmbedCode(v *Video, baseURL string, width, height int) (string, error) {
+	if width < MinEmbedDimension || width > MaxEmbedDimension || height < MinEmbedDimension || height > MaxEmbedDimension {
+		return "", fmt.Errorf("%w: %dx%d must be between %d and %d", ErrInvalidEmbedDimensions, width, height, MinEmbedDimension, MaxEmbedDimension)
+	}
+	if !v.IsEmbeddable() {
+		return "", fmt.Errorf("%w: %s", ErrEmbedNotAllowed, v.ID)
+	}
+
+	return fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" frameborder="0" allow="autoplay; fullscreen; picture-in-picture" allowfullscreen></iframe>`,
+		html.EscapeString(EmbedURL(baseURL, v.ID)), width, height, html.EscapeString(v.Title)), nil
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateEmbedCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		video   Video
+		width   int
+		height  int
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "success",
+			video:  Video{ID: "video_1", Title: `"title" & more`, IsExternalCutout: true},
+			width:  640,
+			height: 360,
+			want:   `<iframe src="https://yuovision.yuorei.com/embed/video_1" width="640" height="360" title="&#34;title&#34; &amp; more" frameborder="0" allow="autoplay; fullscreen; picture-in-picture" allowfullscreen></iframe>`,
+		},
+		{name: "min dimensions", video: Video{ID: "video_1", IsExternalCutout: true}, width: 160, height: 160, want: `<iframe src="https://yuovision.yuorei.com/embed/video_1" width="160" height="160" title="" frameborder="0" allow="autoplay; fullscreen; picture-in-picture" allowfullscreen></iframe>`},
+		{name: "too small", video: Video{ID: "video_1"}, width: 159, height: 360, wantErr: ErrInvalidEmbedDimensions},
+		{name: "too large", video: Video{ID: "video_1"}, width: 640, height: 3841, wantErr: ErrInvalidEmbedDimensions},
+		{name: "external use not allowed", video: Video{ID: "video_1"}, width: 640, height: 360, wantErr: ErrEmbedNotAllowed},
+		{name: "private", video: Video{ID: "video_1", IsPrivate: true, IsExternalCutout: true}, width: 640, height: 360, wantErr: ErrEmbedNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateEmbedCode(&tt.video, "https://yuovision.yuorei.com/", tt.width, tt.height)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GenerateEmbedCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateEmbedCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ダウンロードと埋め込みでIsExternalCutoutの意味を揃える
+func TestVideoAllowsExternalUse(t *testing.T) {
+	tests := []struct {
+		name           string
+		video          Video
+		wantExternal   bool
+		wantEmbeddable bool
+	}{
+		{name: "allowed", video: Video{IsExternalCutout: true}, wantExternal: true, wantEmbeddable: true},
+		{name: "not allowed", video: Video{IsExternalCutout: false}, wantExternal: false, wantEmbeddable: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.video.AllowsExternalUse(); got != tt.wantExternal {
+				t.Errorf("Video.AllowsExternalUse() = %v, want %v", got, tt.wantExternal)
+			}
+			if got := tt.video.IsEmbeddable(); got != tt.wantEmbeddable {
+				t.Errorf("Video.IsEmbeddable() = %v, want %v", got, tt.wantEmbeddable)
+			}
+		})
+	}
+}
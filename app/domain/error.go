@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidInput              = errors.New("invalid input")
+	ErrInvalidDateRange          = errors.New("invalid date range")
+	ErrInvalidVideo              = errors.New("invalid video")
+	ErrFileTooLarge              = errors.New("file too large")
+	ErrVideoTooShort             = errors.New("video is too short")
+	ErrVideoTooLong              = errors.New("video is too long")
+	ErrFFmpegTimeout             = errors.New("ffmpeg timed out")
+	ErrFFmpegCircuitOpen         = errors.New("ffmpeg is temporarily unavailable")
+	ErrUnsupportedCutFormat      = errors.New("unsupported cut format")
+	ErrInvalidSplitPoint         = errors.New("invalid split point")
+	ErrPermissionDenied          = errors.New("permission denied")
+	ErrInvalidWatermarkPosition  = errors.New("invalid watermark position")
+	ErrUploadRateLimitExceeded   = errors.New("upload api rate limit")
+	ErrPlaylistFull              = errors.New("playlist is full")
+	ErrTooManyBookmarks          = errors.New("too many bookmarks")
+	ErrProcessingQueueFull       = errors.New("video processing queue is full")
+	ErrVideoExpired              = errors.New("video has expired")
+	ErrVideoNotFound             = errors.New("video not found")
+	ErrStorageQuotaExceeded      = errors.New("storage quota exceeded")
+	ErrDownloadNotAllowed        = errors.New("download is not allowed")
+	ErrDownloadRateLimitExceeded = errors.New("download rate limit")
+	ErrInvalidStartTime          = errors.New("invalid start time")
+	ErrInvalidEmbedDimensions    = errors.New("invalid embed dimensions")
+	ErrEmbedNotAllowed           = errors.New("embedding is not allowed")
+	ErrUnsupportedSubtitleFormat = errors.New("unsupported subtitle format")
+	ErrInvalidSubtitleFormat     = errors.New("invalid subtitle format")
+	ErrSubtitleTrackNotFound     = errors.New("subtitle track not found")
+	ErrInvalidChapters           = errors.New("invalid chapters")
+	ErrUnsupportedAudioFormat    = errors.New("unsupported audio format")
+	ErrHLSKeyNotFound            = errors.New("hls encryption key not found")
+	ErrDuplicateReport           = errors.New("video has already been reported")
+	ErrTagTooLong                = errors.New("tag is too long")
+	ErrTooManyTags               = errors.New("too many tags")
+	ErrTagNotFound               = errors.New("tag not found")
+	ErrTagAlreadyExists          = errors.New("tag already exists")
+	ErrVersionConflict           = errors.New("video has been updated by someone else")
+)
+
+// 対応していない動画形式の場合に返すエラー
+// 検出した形式名をユーザーに伝えるためにerrors.Asで取り出して使う
+type ErrUnsupportedFormat struct {
+	Format string
+}
+
+func NewErrUnsupportedFormat(format string) *ErrUnsupportedFormat {
+	return &ErrUnsupportedFormat{
+		Format: format,
+	}
+}
+
+func (e *ErrUnsupportedFormat) Error() string {
+	return fmt.Sprintf("unsupported video format: %s (convert it to %s or %s)", e.Format, MediaFormatMP4, MediaFormatMOV)
+}
+
+// アップロードやダウンロードの回数の制限を超えた場合に返すエラー
+// errors.Is(err, ErrUploadRateLimitExceeded)のようにReasonで判定でき、ResetAtからRetry-Afterを計算できる
+type RateLimitError struct {
+	Reason  error
+	ResetAt time.Time
+}
+
+func NewRateLimitError(reason error, resetAt time.Time) *RateLimitError {
+	return &RateLimitError{
+		Reason:  reason,
+		ResetAt: resetAt,
+	}
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: reset at %s", e.Reason, e.ResetAt.Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Reason
+}
@@ -0,0 +1,22 @@
+package domain
+
+// サーバーが依存しているサービスの状態
+type HealthState string
+
+const (
+	StatusOK       HealthState = "ok"
+	StatusDegraded HealthState = "degraded"
+)
+
+type ComponentStatus struct {
+	Status HealthState `json:"status"`
+	// StatusDegradedの場合のみ原因を入れる
+	Error string `json:"error,omitempty"`
+}
+
+type HealthStatus struct {
+	DB      ComponentStatus `json:"db"`
+	Redis   ComponentStatus `json:"redis"`
+	Storage ComponentStatus `json:"storage"`
+	Healthy bool            `json:"healthy"`
+}
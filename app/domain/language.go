@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// BCP 47の言語タグを確認し、"en-us"を"en-US"のような正規の形にする
+func NormalizeLanguage(code string) (string, error) {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid language %q", ErrInvalidInput, code)
+	}
+	return tag.String(), nil
+}
+
+// 指定された言語の説明を返す
+// その言語の説明がない場合は"en-US"に対して"en"のように地域を除いた言語を探し、それもなければ元の説明を返す
+func (v *Video) DescriptionForLanguage(lang string) string {
+	if description, ok := v.Descriptions[lang]; ok {
+		return description
+	}
+	if tag, err := language.Parse(lang); err == nil {
+		base, _ := tag.Base()
+		if description, ok := v.Descriptions[base.String()]; ok {
+			return description
+		}
+	}
+	if v.Description == nil {
+		return ""
+	}
+	return *v.Description
+}
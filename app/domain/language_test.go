@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr error
+	}{
+		{code: "ja", want: "ja"},
+		{code: "en-us", want: "en-US"},
+		{code: "zh-hant-tw", want: "zh-Hant-TW"},
+		{code: "", wantErr: ErrInvalidInput},
+		{code: "not a language", wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := NormalizeLanguage(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NormalizeLanguage() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideo_DescriptionForLanguage(t *testing.T) {
+	description := "日本語の説明"
+	video := &Video{
+		Description:  &description,
+		Descriptions: map[string]string{"en": "English", "pt-BR": "Português"},
+	}
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: "en", want: "English"},
+		{lang: "en-US", want: "English"},
+		{lang: "pt-BR", want: "Português"},
+		{lang: "fr", want: description},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := video.DescriptionForLanguage(tt.lang); got != tt.want {
+				t.Errorf("Video.DescriptionForLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
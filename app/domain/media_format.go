@@ -0,0 +1,24 @@
+package domain
+
+// マジックバイトから判定したファイルの形式
+// 対応していない形式の場合も、ユーザーに変換元を伝えるために形式名を使う
+type MediaFormat string
+
+const (
+	MediaFormatUnknown MediaFormat = "unknown"
+	MediaFormatMP4     MediaFormat = "MP4"
+	MediaFormatMOV     MediaFormat = "MOV"
+	MediaFormatWebM    MediaFormat = "WebM"
+	MediaFormatMKV     MediaFormat = "MKV"
+	MediaFormatAVI     MediaFormat = "AVI"
+	MediaFormatHEIF    MediaFormat = "HEIF"
+	MediaFormatAVIF    MediaFormat = "AVIF"
+	MediaFormatPNG     MediaFormat = "PNG"
+	MediaFormatJPEG    MediaFormat = "JPEG"
+	MediaFormatGIF     MediaFormat = "GIF"
+)
+
+// アップロードを受け付ける形式か
+func (f MediaFormat) IsSupportedVideo() bool {
+	return f == MediaFormatMP4 || f == MediaFormatMOV
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// 通報を確認したモデレーターが動画に行う操作
+type ModerationAction string
+
+const (
+	// 問題がないとして通報を閉じる
+	ActionApprove ModerationAction = "approve"
+	// 成人向けの動画として一覧に表示しないようにする
+	ActionRemoveAsAdult ModerationAction = "remove_as_adult"
+	ActionSoftDelete    ModerationAction = "soft_delete"
+	// 論理削除し、管理者でも復元できないようにする
+	ActionPermanentBan ModerationAction = "permanent_ban"
+)
+
+func (a ModerationAction) Validate() error {
+	switch a {
+	case ActionApprove, ActionRemoveAsAdult, ActionSoftDelete, ActionPermanentBan:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown moderation action: %s", ErrInvalidInput, a)
+	}
+}
+
+type ModerationRecord struct {
+	ID          int64
+	VideoID     string
+	ModeratorID string
+	Action      ModerationAction
+	Reason      string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "fmt"
+
+type (
+	Page struct {
+		Offset int
+		Limit  int
+	}
+)
+
+func NewPage(offset, limit int) Page {
+	return Page{
+		Offset: offset,
+		Limit:  limit,
+	}
+}
+
+func (p Page) Validate() error {
+	if p.Limit <= 0 {
+		return fmt.Errorf("%w: limit must be greater than 0", ErrInvalidInput)
+	}
+	if p.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestPage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    Page
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			page:    NewPage(0, 20),
+			wantErr: false,
+		},
+		{
+			name:    "offset is larger than total",
+			page:    NewPage(10000, 20),
+			wantErr: false,
+		},
+		{
+			name:    "limit is 0",
+			page:    NewPage(0, 0),
+			wantErr: true,
+		},
+		{
+			name:    "limit is negative",
+			page:    NewPage(0, -1),
+			wantErr: true,
+		},
+		{
+			name:    "offset is negative",
+			page:    NewPage(-1, 20),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.page.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Page.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// 1つのプレイリストに追加できる動画の最大数
+const MaxPlaylistVideos = 500
+
+func NewPlaylistID() string {
+	return fmt.Sprintf("%s%s%s", "playlist", IDSeparator, NewUUID())
+}
+
+type (
+	Playlist struct {
+		ID          string
+		OwnerID     string
+		Title       string
+		Description string
+		IsPublic    bool
+		VideoIDs    []string // 再生順に並んだ動画ID
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+)
+
+func NewPlaylist(id, ownerID, title, description string, isPublic bool, videoIDs []string, createdAt, updatedAt time.Time) *Playlist {
+	return &Playlist{
+		ID:          id,
+		OwnerID:     ownerID,
+		Title:       title,
+		Description: description,
+		IsPublic:    isPublic,
+		VideoIDs:    videoIDs,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func (p *Playlist) Validate() error {
+	if p.Title == "" {
+		return fmt.Errorf("%w: playlist title must not be empty", ErrInvalidInput)
+	}
+	if len(p.VideoIDs) > MaxPlaylistVideos {
+		return fmt.Errorf("%w: %d videos", ErrPlaylistFull, len(p.VideoIDs))
+	}
+	return ValidatePlaylistVideoIDs(p.VideoIDs)
+}
+
+// 同じ動画を複数回含めることはできない
+func ValidatePlaylistVideoIDs(videoIDs []string) error {
+	seen := make(map[string]struct{}, len(videoIDs))
+	for _, id := range videoIDs {
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("%w: duplicate video %s", ErrInvalidInput, id)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
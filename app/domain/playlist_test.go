@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPlaylist_Validate(t *testing.T) {
+	tooManyVideos := make([]string, MaxPlaylistVideos+1)
+	for i := range tooManyVideos {
+		tooManyVideos[i] = fmt.Sprintf("video_%d", i)
+	}
+
+	tests := []struct {
+		name     string
+		playlist *Playlist
+		wantErr  bool
+	}{
+		{
+			name:     "success",
+			playlist: NewPlaylist("playlist_1", "user_1", "後で見る", "", false, []string{"video_1", "video_2"}, time.Now(), time.Now()),
+			wantErr:  false,
+		},
+		{
+			name:     "title is empty",
+			playlist: NewPlaylist("playlist_1", "user_1", "", "", false, nil, time.Now(), time.Now()),
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate video",
+			playlist: NewPlaylist("playlist_1", "user_1", "後で見る", "", false, []string{"video_1", "video_1"}, time.Now(), time.Now()),
+			wantErr:  true,
+		},
+		{
+			name:     "too many videos",
+			playlist: NewPlaylist("playlist_1", "user_1", "後で見る", "", false, tooManyVideos, time.Now(), time.Now()),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.playlist.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Playlist.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "fmt"
+
+// アップロードされた動画の処理状況
+type ProcessingStatus string
+
+const (
+	StatusPending    ProcessingStatus = "pending"
+	StatusProcessing ProcessingStatus = "processing"
+	StatusReady      ProcessingStatus = "ready"
+	StatusFailed     ProcessingStatus = "failed"
+)
+
+func (s ProcessingStatus) Validate() error {
+	switch s {
+	case StatusPending, StatusProcessing, StatusReady, StatusFailed:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown processing status: %s", ErrInvalidInput, s)
+	}
+}
+
+// これ以上状態が変わらない場合はtrueを返す
+func (s ProcessingStatus) IsFinished() bool {
+	return s == StatusReady || s == StatusFailed
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// HLSで配信する画質の1つ。NameはS3の<videoID>/<Name>/に使う
+type QualityLevel struct {
+	Name       string
+	Width      int
+	Height     int
+	BitrateBPS int
+}
+
+// 低い画質から順に並べる
+var DefaultQualityLadder = []QualityLevel{
+	{Name: "360p", Width: 640, Height: 360, BitrateBPS: 800_000},
+	{Name: "720p", Width: 1280, Height: 720, BitrateBPS: 2_800_000},
+	{Name: "1080p", Width: 1920, Height: 1080, BitrateBPS: 5_000_000},
+}
+
+var qualityLevelNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+func ValidateQualityLadder(ladder []QualityLevel) error {
+	if len(ladder) == 0 {
+		return fmt.Errorf("%w: quality ladder is empty", ErrInvalidInput)
+	}
+	seen := make(map[string]struct{}, len(ladder))
+	for _, level := range ladder {
+		if !qualityLevelNamePattern.MatchString(level.Name) {
+			return fmt.Errorf("%w: invalid quality level name %q", ErrInvalidInput, level.Name)
+		}
+		if _, ok := seen[level.Name]; ok {
+			return fmt.Errorf("%w: duplicate quality level %q", ErrInvalidInput, level.Name)
+		}
+		seen[level.Name] = struct{}{}
+		if level.Width <= 0 || level.Height <= 0 || level.BitrateBPS <= 0 {
+			return fmt.Errorf("%w: quality level %q must have positive size and bitrate", ErrInvalidInput, level.Name)
+		}
+	}
+	return nil
+}
+
+// 元の動画より高さが大きい画質は拡大になるため除く
+// 元の動画がどの画質よりも小さい場合は一番低い画質だけにする
+func QualityLadderFor(ladder []QualityLevel, sourceHeight int) []QualityLevel {
+	levels := make([]QualityLevel, 0, len(ladder))
+	lowest := 0
+	for n, level := range ladder {
+		if level.Height <= sourceHeight {
+			levels = append(levels, level)
+		}
+		if level.Height < ladder[lowest].Height {
+			lowest = n
+		}
+	}
+	if len(levels) == 0 && len(ladder) > 0 {
+		levels = append(levels, ladder[lowest])
+	}
+	return levels
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateQualityLadder(t *testing.T) {
+	tests := []struct {
+		name    string
+		ladder  []QualityLevel
+		wantErr error
+	}{
+		{name: "default", ladder: DefaultQualityLadder},
+		{name: "empty", ladder: nil, wantErr: ErrInvalidInput},
+		{name: "invalid name", ladder: []QualityLevel{{Name: "../360p", Width: 640, Height: 360, BitrateBPS: 800_000}}, wantErr: ErrInvalidInput},
+		{name: "duplicate name", ladder: []QualityLevel{{Name: "360p", Width: 640, Height: 360, BitrateBPS: 800_000}, {Name: "360p", Width: 640, Height: 360, BitrateBPS: 600_000}}, wantErr: ErrInvalidInput},
+		{name: "zero bitrate", ladder: []QualityLevel{{Name: "360p", Width: 640, Height: 360}}, wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateQualityLadder(tt.ladder); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateQualityLadder() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQualityLadderFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceHeight int
+		want         []string
+	}{
+		{name: "1080p", sourceHeight: 1080, want: []string{"360p", "720p", "1080p"}},
+		{name: "4k", sourceHeight: 2160, want: []string{"360p", "720p", "1080p"}},
+		{name: "720p", sourceHeight: 720, want: []string{"360p", "720p"}},
+		{name: "smaller than every level", sourceHeight: 240, want: []string{"360p"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, level := range QualityLadderFor(DefaultQualityLadder, tt.sourceHeight) {
+				got = append(got, level.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QualityLadderFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "fmt"
+
+type ReactionType string
+
+const (
+	ReactionLike    ReactionType = "like"
+	ReactionDislike ReactionType = "dislike"
+)
+
+func (r ReactionType) Validate() error {
+	switch r {
+	case ReactionLike, ReactionDislike:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown reaction: %s", ErrInvalidInput, r)
+	}
+}
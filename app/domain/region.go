@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 国コード(ISO 3166-1 alpha-2)を大文字にして重複を除き、順番を揃える
+func NormalizeRegions(regions []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(regions))
+	normalized := make([]string, 0, len(regions))
+	for _, region := range regions {
+		code := strings.ToUpper(strings.TrimSpace(region))
+		if !isRegionCode(code) {
+			return nil, fmt.Errorf("%w: invalid region code %q", ErrInvalidInput, region)
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		normalized = append(normalized, code)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func isRegionCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedRegionsが空の動画はどの国からでも見られる
+// 制限されている動画は、国がわからない場合もライセンスを守るために見られないようにする
+func IsVideoAllowedInRegion(video *Video, countryCode string) bool {
+	if len(video.AllowedRegions) == 0 {
+		return true
+	}
+	code := strings.ToUpper(strings.TrimSpace(countryCode))
+	for _, region := range video.AllowedRegions {
+		if region == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "testing"
+
+func TestIsVideoAllowedInRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []string
+		country string
+		want    bool
+	}{
+		{name: "no restriction", regions: nil, country: "US", want: true},
+		{name: "no restriction and unknown country", regions: nil, country: "", want: true},
+		{name: "allowed", regions: []string{"JP", "US"}, country: "US", want: true},
+		{name: "lower case country", regions: []string{"JP"}, country: "jp", want: true},
+		{name: "blocked", regions: []string{"JP"}, country: "US", want: false},
+		{name: "unknown country", regions: []string{"JP"}, country: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &Video{ID: "video_1", AllowedRegions: tt.regions}
+			if got := IsVideoAllowedInRegion(video, tt.country); got != tt.want {
+				t.Errorf("IsVideoAllowedInRegion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
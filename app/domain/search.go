@@ -0,0 +1,14 @@
+package domain
+
+type (
+	SearchOptions struct {
+		// trueの場合は大文字と小文字を区別せずに検索する
+		CaseInsensitive bool
+	}
+)
+
+func NewSearchOptions(caseInsensitive bool) SearchOptions {
+	return SearchOptions{
+		CaseInsensitive: caseInsensitive,
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 共有するリンクで再生を始める位置を指定するクエリパラメータ
+const ShareStartTimeParam = "t"
+
+// 動画のページのリンクを返す。startSecondsがnilでない場合はその秒数から再生するように?t=を付ける
+// 動画の長さを超えていないかはValidateStartTimeで確認する
+func GenerateShareLink(baseURL, videoID string, startSeconds *int) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("%w: invalid base url %q", ErrInvalidInput, baseURL)
+	}
+	if videoID == "" {
+		return "", fmt.Errorf("%w: video id must not be empty", ErrInvalidInput)
+	}
+
+	u = u.JoinPath("video", videoID)
+	if startSeconds != nil {
+		if *startSeconds < 0 {
+			return "", fmt.Errorf("%w: %d must not be negative", ErrInvalidStartTime, *startSeconds)
+		}
+		u.RawQuery = url.Values{ShareStartTimeParam: {strconv.Itoa(*startSeconds)}}.Encode()
+	}
+	return u.String(), nil
+}
+
+// 再生を始める位置が動画の中にあるか確認する
+// 長さがわからない動画は負の値でなければよい
+func (v *Video) ValidateStartTime(startSeconds int) error {
+	if startSeconds < 0 {
+		return fmt.Errorf("%w: %d must not be negative", ErrInvalidStartTime, startSeconds)
+	}
+	if v.Duration > 0 && time.Duration(startSeconds)*time.Second > v.Duration {
+		return fmt.Errorf("%w: %d exceeds the duration of %s (%s)", ErrInvalidStartTime, startSeconds, v.ID, v.Duration)
+	}
+	return nil
+}
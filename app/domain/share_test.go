@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateShareLink(t *testing.T) {
+	start := 90
+	zero := 0
+	negative := -1
+	tests := []struct {
+		name         string
+		baseURL      string
+		videoID      string
+		startSeconds *int
+		want         string
+		wantErr      error
+	}{
+		{name: "without start time", baseURL: "https://yuovision.yuorei.com", videoID: "video_1", want: "https://yuovision.yuorei.com/video/video_1"},
+		{name: "with start time", baseURL: "https://yuovision.yuorei.com/", videoID: "video_1", startSeconds: &start, want: "https://yuovision.yuorei.com/video/video_1?t=90"},
+		{name: "start from beginning", baseURL: "https://yuovision.yuorei.com", videoID: "video_1", startSeconds: &zero, want: "https://yuovision.yuorei.com/video/video_1?t=0"},
+		{name: "negative start time", baseURL: "https://yuovision.yuorei.com", videoID: "video_1", startSeconds: &negative, wantErr: ErrInvalidStartTime},
+		{name: "invalid base url", baseURL: "yuovision", videoID: "video_1", wantErr: ErrInvalidInput},
+		{name: "empty video id", baseURL: "https://yuovision.yuorei.com", wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateShareLink(tt.baseURL, tt.videoID, tt.startSeconds)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GenerateShareLink() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateShareLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStartTime(t *testing.T) {
+	tests := []struct {
+		name         string
+		duration     time.Duration
+		startSeconds int
+		wantErr      error
+	}{
+		{name: "within duration", duration: time.Minute, startSeconds: 30},
+		{name: "equal to duration", duration: time.Minute, startSeconds: 60},
+		{name: "exceeds duration", duration: time.Minute, startSeconds: 61, wantErr: ErrInvalidStartTime},
+		{name: "negative", duration: time.Minute, startSeconds: -1, wantErr: ErrInvalidStartTime},
+		{name: "unknown duration", duration: 0, startSeconds: 3600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Video{ID: "video_1", Duration: tt.duration}
+			err := v.ValidateStartTime(tt.startSeconds)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Video.ValidateStartTime() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "fmt"
+
+type SortOrder string
+
+const (
+	SortByCreatedAtDesc  SortOrder = "created_at_desc"
+	SortByCreatedAtAsc   SortOrder = "created_at_asc"
+	SortByWatchCountDesc SortOrder = "watch_count_desc"
+	SortByTitleAsc       SortOrder = "title_asc"
+)
+
+func (s SortOrder) Validate() error {
+	switch s {
+	case SortByCreatedAtDesc, SortByCreatedAtAsc, SortByWatchCountDesc, SortByTitleAsc:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown sort order: %s", ErrInvalidInput, s)
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "fmt"
+
+const (
+	SubtitleFormatVTT = "vtt"
+	SubtitleFormatSRT = "srt"
+)
+
+// 字幕。SRTでアップロードされた場合もブラウザで表示できるようにURLはWebVTTに変換したファイルを指す
+type SubtitleTrack struct {
+	ID       string
+	VideoID  string
+	Language string // BCP 47
+	Format   string // アップロードされたときの形式
+	URL      string
+}
+
+func NewSubtitleTrackID() string {
+	return fmt.Sprintf("%s%s%s", "subtitle", IDSeparator, NewUUID())
+}
+
+func ValidateSubtitleFormat(format string) error {
+	switch format {
+	case SubtitleFormatVTT, SubtitleFormatSRT:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedSubtitleFormat, format)
+	}
+}
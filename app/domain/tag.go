@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 正規化した後のタグの最大の文字数
+const MaxTagLength = 50
+
+// " JavaScript"と"javascript"を同じタグとして扱えるように、前後の空白を除いて小文字にする
+// 空のタグと重複したタグを除き、最初に出てきた順に返す
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return fmt.Errorf("%w: %q is longer than %d characters", ErrTagTooLong, tag, MaxTagLength)
+		}
+	}
+	return nil
+}
+
+// タグの候補にしない語
+var titleStopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "for": {}, "from": {},
+	"how": {}, "in": {}, "is": {}, "it": {}, "of": {}, "on": {}, "or": {}, "the": {}, "this": {}, "to": {},
+	"what": {}, "with": {}, "you": {}, "your": {},
+}
+
+// タイトルを空白と記号で区切って小文字にし、ストップワードと重複を除いて最初に出てきた順に返す
+func TitleKeywords(title string) []string {
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	keywords := make([]string, 0, len(words))
+	for _, word := range NormalizeTags(words) {
+		if _, ok := titleStopWords[word]; ok {
+			continue
+		}
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "empty", tags: []string{}, want: []string{}},
+		{name: "already normalized", tags: []string{"go", "grpc"}, want: []string{"go", "grpc"}},
+		{name: "case and whitespace variants", tags: []string{" JavaScript", "javascript", "JavaScript"}, want: []string{"javascript"}},
+		{name: "tabs and newlines", tags: []string{"\tgo\n", "GO "}, want: []string{"go"}},
+		{name: "blank tags", tags: []string{"", "   ", "\t", "go"}, want: []string{"go"}},
+		{name: "only blank tags", tags: []string{"", " "}, want: []string{}},
+		{name: "keeps first occurrence order", tags: []string{"Rust", "go", "rust", "Go", "zig"}, want: []string{"rust", "go", "zig"}},
+		{name: "inner whitespace is kept", tags: []string{"Machine Learning", "machine  learning"}, want: []string{"machine learning", "machine  learning"}},
+		{name: "non ascii", tags: []string{"プログラミング", " プログラミング ", "ÉTÉ", "été"}, want: []string{"プログラミング", "été"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr error
+	}{
+		{name: "no tags", tags: nil},
+		{name: "max length", tags: []string{strings.Repeat("a", MaxTagLength)}},
+		{name: "max length in multibyte characters", tags: []string{strings.Repeat("あ", MaxTagLength)}},
+		{name: "too long", tags: []string{"go", strings.Repeat("a", MaxTagLength+1)}, wantErr: ErrTagTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTags(tt.tags); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTags() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTitleKeywords(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  []string
+	}{
+		{name: "empty", title: "", want: []string{}},
+		{name: "spaces and punctuation", title: "How to write Go: gRPC, Docker & MySQL!", want: []string{"write", "go", "grpc", "docker", "mysql"}},
+		{name: "only stop words", title: "The and of", want: []string{}},
+		{name: "duplicates in different case", title: "Go go GO", want: []string{"go"}},
+		{name: "hyphen splits words", title: "go-lang", want: []string{"go", "lang"}},
+		{name: "non ascii", title: "Goで作る　動画サーバー", want: []string{"goで作る", "動画サーバー"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TitleKeywords(tt.title); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TitleKeywords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
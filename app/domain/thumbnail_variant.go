@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/url"
+)
+
+// 1つの動画に登録できるサムネイルの候補の数
+const MaxThumbnailVariantsPerVideo = 10
+
+// クリック率を比べるためのサムネイルの候補
+// AssignmentWeightの比で表示するユーザーを振り分ける
+type ThumbnailVariant struct {
+	ID               string
+	VideoID          string
+	ThumbnailURL     string
+	AssignmentWeight float64
+}
+
+type ThumbnailStats struct {
+	VariantID    string
+	ThumbnailURL string
+	Impressions  int64
+	Clicks       int64
+}
+
+func NewThumbnailVariantID() string {
+	return fmt.Sprintf("%s%s%s", "thumbnail", IDSeparator, NewUUID())
+}
+
+func (v ThumbnailVariant) Validate() error {
+	u, err := url.Parse(v.ThumbnailURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid thumbnail url %q", ErrInvalidInput, v.ThumbnailURL)
+	}
+	if math.IsNaN(v.AssignmentWeight) || math.IsInf(v.AssignmentWeight, 0) || v.AssignmentWeight <= 0 {
+		return fmt.Errorf("%w: assignment weight must be positive", ErrInvalidInput)
+	}
+	return nil
+}
+
+// 表示されていない場合は0
+func (s ThumbnailStats) ClickThroughRate() float64 {
+	if s.Impressions == 0 {
+		return 0
+	}
+	return float64(s.Clicks) / float64(s.Impressions)
+}
+
+// keyのハッシュを重みの合計の中の位置にして候補を選ぶ
+// 同じkeyには候補が変わらない限り同じ候補を返す
+func SelectThumbnailVariant(variants []ThumbnailVariant, key string) (ThumbnailVariant, bool) {
+	total := 0.0
+	for _, v := range variants {
+		total += v.AssignmentWeight
+	}
+	if total <= 0 {
+		return ThumbnailVariant{}, false
+	}
+
+	// 似たkeyでも偏らないように暗号学的ハッシュを使う
+	sum := sha256.Sum256([]byte(key))
+	point := float64(binary.BigEndian.Uint64(sum[:8])) / float64(math.MaxUint64) * total
+	for _, v := range variants {
+		point -= v.AssignmentWeight
+		if point < 0 {
+			return v, true
+		}
+	}
+	// 丸め誤差で最後まで残った場合
+	return variants[len(variants)-1], true
+}
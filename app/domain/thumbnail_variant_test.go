@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestThumbnailVariantValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant ThumbnailVariant
+		wantErr error
+	}{
+		{name: "valid", variant: ThumbnailVariant{ThumbnailURL: "https://example.com/a.webp", AssignmentWeight: 0.5}},
+		{name: "empty url", variant: ThumbnailVariant{AssignmentWeight: 1}, wantErr: ErrInvalidInput},
+		{name: "not http", variant: ThumbnailVariant{ThumbnailURL: "javascript:alert(1)", AssignmentWeight: 1}, wantErr: ErrInvalidInput},
+		{name: "zero weight", variant: ThumbnailVariant{ThumbnailURL: "https://example.com/a.webp"}, wantErr: ErrInvalidInput},
+		{name: "negative weight", variant: ThumbnailVariant{ThumbnailURL: "https://example.com/a.webp", AssignmentWeight: -1}, wantErr: ErrInvalidInput},
+		{name: "NaN weight", variant: ThumbnailVariant{ThumbnailURL: "https://example.com/a.webp", AssignmentWeight: math.NaN()}, wantErr: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.variant.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ThumbnailVariant.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectThumbnailVariant(t *testing.T) {
+	variants := []ThumbnailVariant{
+		{ID: "a", AssignmentWeight: 3},
+		{ID: "b", AssignmentWeight: 1},
+	}
+
+	if _, ok := SelectThumbnailVariant(nil, "user_1"); ok {
+		t.Errorf("SelectThumbnailVariant(nil) ok = true, want false")
+	}
+
+	first, ok := SelectThumbnailVariant(variants, "video_1_user_1")
+	if !ok {
+		t.Fatalf("SelectThumbnailVariant() ok = false, want true")
+	}
+	for n := 0; n < 10; n++ {
+		if got, _ := SelectThumbnailVariant(variants, "video_1_user_1"); got.ID != first.ID {
+			t.Fatalf("SelectThumbnailVariant() = %v, want the same variant %v", got.ID, first.ID)
+		}
+	}
+
+	// 重みの比でおおよそ振り分けられる
+	counts := map[string]int{}
+	for n := 0; n < 4000; n++ {
+		v, _ := SelectThumbnailVariant(variants, fmt.Sprintf("video_1_user_%d", n))
+		counts[v.ID]++
+	}
+	if counts["a"] < 2700 || counts["a"] > 3300 {
+		t.Errorf("counts = %v, want about 3000 for a", counts)
+	}
+}
+
+func TestThumbnailStatsClickThroughRate(t *testing.T) {
+	if got := (ThumbnailStats{}).ClickThroughRate(); got != 0 {
+		t.Errorf("ClickThroughRate() = %v, want 0", got)
+	}
+	if got := (ThumbnailStats{Impressions: 200, Clicks: 10}).ClickThroughRate(); got != 0.05 {
+		t.Errorf("ClickThroughRate() = %v, want 0.05", got)
+	}
+}
@@ -15,13 +15,36 @@ type (
 		Description       *string
 		UploaderID        string
 		Tags              []string
-		IsAdult           bool
+		ContentRating     ContentRating
 		IsPrivate         bool
-		IsExternalCutout  bool
+		IsExternalCutout  bool // AllowsExternalUseを参照
 		IsAd              bool
 		CreatedAt         time.Time
 		UpdatedAt         time.Time
 		WatchCount        int
+		DownloadCount     int
+		Duration          time.Duration
+		Width             int
+		Height            int
+		Bitrate           int64 // bps
+		PreviewURL        string
+		ThumbnailVTTURL   string
+		IsDeleted         bool
+		IsBanned          bool // permanent_banにした動画。復元できない
+		ProcessingStatus  ProcessingStatus
+		ProcessingError   string            // ProcessingStatusがStatusFailedの場合のみ
+		PublishAt         *time.Time        // 公開予約されている場合のみ。公開されるとnilになる
+		ExpiresAt         *time.Time        // この時刻を過ぎると非公開になる
+		Checksum          string            // S3に保存した元の動画のSHA-256。変換が終わるまでは空
+		AllowedRegions    []string          // 視聴できる国(ISO 3166-1 alpha-2)。空の場合は制限しない
+		Language          string            // 動画の言語(BCP 47)。わからない場合は空
+		Descriptions      map[string]string // 言語ごとの説明。Descriptionは投稿時の説明
+		SubtitleTracks    []*SubtitleTrack
+		ChaptersURL       string // チャプターのWebVTT。設定されていない場合は空
+		AudioTracks       []AudioTrack
+		HLSMasterURL      string // 画質ごとのHLSをまとめたマスタープレイリスト。変換が終わるまでは空
+		DASHManifestURL   string // MPEG-DASHのMPD。作られていない場合は空
+		Version           int    // メタデータを更新するたびに増える。UpdateVideoで同時に更新されていないか確認する
 	}
 
 	UploadVideo struct {
@@ -30,10 +53,14 @@ type (
 		Title            string
 		Description      *string
 		Tags             []string
-		IsAdult          bool
+		ContentRating    ContentRating
 		IsPrivate        bool
 		IsExternalCutout bool
 		IsAd             bool
+		WatermarkKey     string // 空でない場合はアップロード済みのウォーターマーク画像を重ねる
+		ClientIP         string
+		SourceKey        string     // アップロードされた動画を一時的に置いたパス
+		PublishAt        *time.Time // 未来の時刻の場合はその時刻まで非公開にする
 	}
 
 	UploadVideoResponse struct {
@@ -44,11 +71,35 @@ type (
 		Description       *string
 		UploaderID        string
 		Tags              []string
-		IsAdult           bool
+		ContentRating     ContentRating
 		IsPrivate         bool
 		IsExternalCutout  bool
 		IsAd              bool
 		CreatedAt         time.Time
+		Duration          time.Duration
+		Width             int
+		Height            int
+		Bitrate           int64
+		ProcessingStatus  ProcessingStatus
+		PublishAt         *time.Time
+		// アップロード後の残りのアップロード回数と回数がリセットされる時刻
+		RateLimitRemaining int
+		RateLimitResetAt   time.Time
+		// 同じIDの動画がすでに登録されていた場合はfalse
+		IsNew bool
+		// 変換のジョブに渡した場合はtrue。falseの場合は呼び出し側で一時ファイルを消す
+		Enqueued bool
+	}
+
+	// ffprobeで取得した動画ファイルの情報
+	VideoMetadata struct {
+		Duration time.Duration
+		Width    int
+		Height   int
+		Bitrate  int64 // bps
+		Size     int64 // bytes
+		// 音声のない動画の場合は空
+		AudioTracks []AudioTrack
 	}
 
 	VideoFile struct {
@@ -66,7 +117,7 @@ func NewVideoID() string {
 	return fmt.Sprintf("%s%s%s", "video", IDSeparator, NewUUID())
 }
 
-func NewVideo(id string, videoURL string, thumbnailImageURL string, title string, description *string, tags []string, watchCount int, private bool, adult bool, externalCutout bool, isAd bool, uploaderID string, createdAt time.Time, updatedAt time.Time) *Video {
+func NewVideo(id string, videoURL string, thumbnailImageURL string, title string, description *string, tags []string, watchCount int, private bool, rating ContentRating, externalCutout bool, isAd bool, uploaderID string, createdAt time.Time, updatedAt time.Time) *Video {
 	return &Video{
 		ID:                id,
 		VideoURL:          videoURL,
@@ -75,7 +126,7 @@ func NewVideo(id string, videoURL string, thumbnailImageURL string, title string
 		Description:       description,
 		Tags:              tags,
 		IsPrivate:         private,
-		IsAdult:           adult,
+		ContentRating:     rating,
 		IsExternalCutout:  externalCutout,
 		IsAd:              isAd,
 		UploaderID:        uploaderID,
@@ -85,20 +136,69 @@ func NewVideo(id string, videoURL string, thumbnailImageURL string, title string
 	}
 }
 
-func NewUploadVideo(id string, video io.ReadSeeker, title string, description *string, tags []string, isAdult, isPrivate, isExternalCutout, isAd bool) *UploadVideo {
+// 公開期限を過ぎているか
+func (v *Video) IsExpired(now time.Time) bool {
+	return v.ExpiresAt != nil && !v.ExpiresAt.After(now)
+}
+
+// IsExternalCutoutがtrueの動画は、切り抜きやダウンロード、他のサイトへの埋め込みを投稿者が許可している
+func (v *Video) AllowsExternalUse() bool {
+	return v.IsExternalCutout
+}
+
+func NewUploadVideo(id string, video io.ReadSeeker, title string, description *string, tags []string, rating ContentRating, isPrivate, isExternalCutout, isAd bool) *UploadVideo {
 	return &UploadVideo{
 		ID:               id,
 		Video:            video,
 		Title:            title,
 		Description:      description,
 		Tags:             tags,
-		IsAdult:          isAdult,
+		ContentRating:    rating,
 		IsPrivate:        isPrivate,
 		IsExternalCutout: isExternalCutout,
 		IsAd:             isAd,
 	}
 }
 
+func NewVideoMetadata(duration time.Duration, width, height int, bitrate int64) *VideoMetadata {
+	return &VideoMetadata{
+		Duration: duration,
+		Width:    width,
+		Height:   height,
+		Bitrate:  bitrate,
+	}
+}
+
+const (
+	// 144p未満の解像度や64kbps未満のビットレートは壊れたファイルとみなす
+	MinVideoResolution = 144
+	MinVideoBitrate    = 64 * 1000
+)
+
+func (m *VideoMetadata) Validate() error {
+	if m.Duration <= 0 {
+		return fmt.Errorf("%w: duration is zero", ErrInvalidVideo)
+	}
+	if min(m.Width, m.Height) < MinVideoResolution {
+		return fmt.Errorf("%w: resolution %dx%d is too low", ErrInvalidVideo, m.Width, m.Height)
+	}
+	if m.Bitrate < MinVideoBitrate {
+		return fmt.Errorf("%w: bitrate %dbps is too low", ErrInvalidVideo, m.Bitrate)
+	}
+	return nil
+}
+
+// アップロードされた動画の長さがminDuration以上maxDuration以下かを確認する
+func ValidateDuration(d time.Duration, minDuration, maxDuration time.Duration) error {
+	if d < minDuration {
+		return fmt.Errorf("%w: %s is shorter than %s", ErrVideoTooShort, d, minDuration)
+	}
+	if d > maxDuration {
+		return fmt.Errorf("%w: %s is longer than %s", ErrVideoTooLong, d, maxDuration)
+	}
+	return nil
+}
+
 func NewVideoFile(id string, video io.ReadSeeker) *VideoFile {
 	return &VideoFile{
 		ID:    id,
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// ffprobeで調べた、配信に影響する動画ファイルの情報
+// 音声のない動画の場合は音声の項目が空になる
+type VideoAnalysis struct {
+	Duration        time.Duration `json:"duration"`
+	Bitrate         int64         `json:"bitrate"` // bps
+	Width           int           `json:"width"`
+	Height          int           `json:"height"`
+	FrameRate       float64       `json:"frame_rate"`
+	VideoCodec      string        `json:"video_codec"`
+	AudioCodec      string        `json:"audio_codec"`
+	AudioSampleRate int           `json:"audio_sample_rate"` // Hz
+}
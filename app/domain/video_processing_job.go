@@ -0,0 +1,25 @@
+package domain
+
+// アップロード後にバックグラウンドで行う変換の設定
+type TranscodeOptions struct {
+	// 空でない場合はHLSに変換する前にウォーターマークを重ねる
+	WatermarkKey string
+	// サムネイルが指定されなかった場合は変換後に動画から生成する
+	GenerateThumbnail bool
+}
+
+type VideoProcessingJob struct {
+	VideoID string
+	// 変換元の動画。キューに入れる前はローカルのパスで、キューに入れると共有のストレージのキーになる
+	// 処理が終わったら削除する
+	SourceKey string
+	Options   TranscodeOptions
+}
+
+func NewVideoProcessingJob(videoID, sourceKey string, options TranscodeOptions) VideoProcessingJob {
+	return VideoProcessingJob{
+		VideoID:   videoID,
+		SourceKey: sourceKey,
+		Options:   options,
+	}
+}
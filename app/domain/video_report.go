@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ReasonSpam           = "spam"
+	ReasonHateSpeech     = "hate_speech"
+	ReasonCopyright      = "copyright"
+	ReasonMisinformation = "misinformation"
+	ReasonOther          = "other"
+)
+
+// 通報はモデレーターが確認するまでpendingのまま
+const (
+	VideoReportStatusPending  = "pending"
+	VideoReportStatusResolved = "resolved"
+)
+
+// 視聴者からの不適切な動画の通報。同じユーザーは同じ動画を1回だけ通報できる
+type VideoReport struct {
+	ID         string
+	VideoID    string
+	ReporterID string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+func NewVideoReportID() string {
+	return fmt.Sprintf("%s%s%s", "report", IDSeparator, NewUUID())
+}
+
+func (r VideoReport) Validate() error {
+	if r.VideoID == "" || r.ReporterID == "" {
+		return fmt.Errorf("%w: video id and reporter id are required", ErrInvalidInput)
+	}
+	switch r.Reason {
+	case ReasonSpam, ReasonHateSpeech, ReasonCopyright, ReasonMisinformation, ReasonOther:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown report reason: %s", ErrInvalidInput, r.Reason)
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			duration: 10 * time.Minute,
+			wantErr:  nil,
+		},
+		{
+			name:     "equal to min",
+			duration: time.Second,
+			wantErr:  nil,
+		},
+		{
+			name:     "equal to max",
+			duration: time.Hour,
+			wantErr:  nil,
+		},
+		{
+			name:     "too short",
+			duration: 500 * time.Millisecond,
+			wantErr:  ErrVideoTooShort,
+		},
+		{
+			name:     "too long",
+			duration: time.Hour + time.Second,
+			wantErr:  ErrVideoTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDuration(tt.duration, time.Second, time.Hour)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVideo_IsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{
+			name:      "no expiry",
+			expiresAt: nil,
+			want:      false,
+		},
+		{
+			name:      "expired",
+			expiresAt: &past,
+			want:      true,
+		},
+		{
+			name:      "equal to now",
+			expiresAt: &now,
+			want:      true,
+		},
+		{
+			name:      "not yet",
+			expiresAt: &future,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Video{ExpiresAt: tt.expiresAt}
+			if got := v.IsExpired(now); got != tt.want {
+				t.Errorf("Video.IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
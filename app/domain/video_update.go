@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 動画の情報の更新内容。nilのフィールドは変更しない
+type VideoUpdate struct {
+	Title       *string
+	Description *string
+	Tags        *[]string
+	IsPrivate   *bool
+	Language    *string // 空文字の場合は言語をわからない状態に戻す
+}
+
+func (u VideoUpdate) Validate() error {
+	if u.Title != nil && strings.TrimSpace(*u.Title) == "" {
+		return fmt.Errorf("%w: title must not be empty", ErrInvalidInput)
+	}
+	if u.Language != nil && *u.Language != "" {
+		_, err := NormalizeLanguage(*u.Language)
+		if err != nil {
+			return err
+		}
+	}
+	return ValidateTags(u.NormalizedTags())
+}
+
+// NormalizeTagsで正規化したタグを返す
+func (u VideoUpdate) NormalizedTags() []string {
+	if u.Tags == nil {
+		return nil
+	}
+	return NormalizeTags(*u.Tags)
+}
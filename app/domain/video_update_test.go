@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVideoUpdate_Validate(t *testing.T) {
+	title := "title"
+	empty := "  "
+	lang := "en-us"
+	noLang := ""
+	badLang := "not a language"
+	longTags := []string{strings.Repeat("a", MaxTagLength+1)}
+	tests := []struct {
+		name    string
+		update  VideoUpdate
+		wantErr error
+	}{
+		{
+			name:    "no change",
+			update:  VideoUpdate{},
+			wantErr: nil,
+		},
+		{
+			name:    "title",
+			update:  VideoUpdate{Title: &title},
+			wantErr: nil,
+		},
+		{
+			name:    "empty title",
+			update:  VideoUpdate{Title: &empty},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "language",
+			update:  VideoUpdate{Language: &lang},
+			wantErr: nil,
+		},
+		{
+			name:    "clear language",
+			update:  VideoUpdate{Language: &noLang},
+			wantErr: nil,
+		},
+		{
+			name:    "invalid language",
+			update:  VideoUpdate{Language: &badLang},
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "too long tag",
+			update:  VideoUpdate{Tags: &longTags},
+			wantErr: ErrTagTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.update.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VideoUpdate.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVideoUpdate_NormalizedTags(t *testing.T) {
+	tags := []string{"go", " grpc ", "", "go"}
+	mixedCase := []string{"Go", "go", " GRPC"}
+	empty := []string{}
+	tests := []struct {
+		name string
+		tags *[]string
+		want []string
+	}{
+		{
+			name: "no change",
+			tags: nil,
+			want: nil,
+		},
+		{
+			name: "remove all tags",
+			tags: &empty,
+			want: []string{},
+		},
+		{
+			name: "trim and deduplicate",
+			tags: &tags,
+			want: []string{"go", "grpc"},
+		},
+		{
+			name: "lowercase",
+			tags: &mixedCase,
+			want: []string{"go", "grpc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := VideoUpdate{Tags: tt.tags}.NormalizedTags()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VideoUpdate.NormalizedTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
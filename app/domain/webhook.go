@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Webhookで通知するイベント
+const (
+	WebhookEventVideoPublished = "video.published"
+	WebhookEventVideoUpdated   = "video.updated"
+	WebhookEventVideoDeleted   = "video.deleted"
+	// 動画ではなくタグのイベントのため、VideoIDは空にする
+	WebhookEventTagMerged = "tag.merged"
+)
+
+type WebhookEvent struct {
+	Type       string      `json:"type"`
+	VideoID    string      `json:"video_id"`
+	Payload    interface{} `json:"payload,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+func NewWebhookEvent(eventType, videoID string, payload interface{}) WebhookEvent {
+	return WebhookEvent{
+		Type:       eventType,
+		VideoID:    videoID,
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	}
+}
+
+// 通知先。Secretは本文の署名に使う
+type WebhookTarget struct {
+	ID     string
+	URL    string
+	Secret string
+}
+
+func (t WebhookTarget) Validate() error {
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid webhook url %q", ErrInvalidInput, t.URL)
+	}
+	if t.Secret == "" {
+		return fmt.Errorf("%w: webhook secret must not be empty", ErrInvalidInput)
+	}
+	return nil
+}
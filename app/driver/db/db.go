@@ -1,9 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -12,6 +13,8 @@ import (
 
 type DB struct {
 	Database *sqlc.Queries
+	// トランザクションを始めるために使う
+	SQL *sql.DB
 }
 
 func NewMySQLDB() *DB {
@@ -23,16 +26,36 @@ func NewMySQLDB() *DB {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", MYSQL_USER, MYSQL_PASSWORD, MYSQL_HOST, MYSQL_PORT, MYSQL_DATABASE)
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		log.Fatal("Error: ", err)
+		slog.Error("failed to open mysql", "error", err)
+		os.Exit(1)
 	}
 
 	if err := db.Ping(); err != nil {
-		log.Fatal("Error: ", err)
+		slog.Error("mysql ping failed", "error", err)
+		os.Exit(1)
 	}
 
 	queries := sqlc.New(db)
 
 	return &DB{
 		Database: queries,
+		SQL:      db,
 	}
 }
+
+// fnに渡したQueriesのクエリを1つのトランザクションで実行する
+// fnがエラーを返した場合はロールバックする
+func (d *DB) WithTx(ctx context.Context, fn func(*sqlc.Queries) error) error {
+	tx, err := d.SQL.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// コミットした後のロールバックは何もしない
+	defer tx.Rollback()
+
+	err = fn(d.Database.WithTx(tx))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,11 +1,29 @@
 package log
 
 import (
+	"io"
 	"log/slog"
 	"os"
 )
 
-func NewLog() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+// LOG_FORMAT=textの場合はローカルの開発で読みやすいテキストで出力する
+func NewJSONLogger(w io.Writer, level slog.Level) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if os.Getenv("LOG_FORMAT") == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// LOG_LEVELはdebug、info、warn、errorのいずれかで、それ以外はinfoにする
+func NewLog() *slog.Logger {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL")))
+	if err != nil {
+		level = slog.LevelInfo
+	}
+
+	logger := NewJSONLogger(os.Stdout, level)
 	slog.SetDefault(logger)
+	return logger
 }
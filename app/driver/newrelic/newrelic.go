@@ -1,7 +1,7 @@
 package newrelic
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -15,7 +15,8 @@ func NewRelic() *newrelic.Application {
 		newrelic.ConfigAppLogForwardingEnabled(true),
 	)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("failed to initialize newrelic", "error", err)
+		os.Exit(1)
 	}
 
 	return app
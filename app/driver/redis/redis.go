@@ -2,7 +2,7 @@ package redis
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/redis/go-redis/v9"
@@ -15,10 +15,12 @@ func ConnectRedis() *redis.Client {
 		DB:       0,  // use default DB
 	})
 	if redisDB == nil {
-		log.Fatalln("redis connection failed")
+		slog.Error("redis connection failed")
+		os.Exit(1)
 	}
 	if err := redisDB.Ping(context.Background()).Err(); err != nil {
-		log.Fatalln("redis ping failed: ", err)
+		slog.Error("redis ping failed", "error", err)
+		os.Exit(1)
 	}
 
 	return redisDB
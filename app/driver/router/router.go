@@ -1,14 +1,19 @@
 package router
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/newrelic/go-agent/v3/integrations/nrgrpc"
 	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
@@ -18,20 +23,44 @@ import (
 	flog "github.com/yuorei/video-server/app/driver/log"
 	"github.com/yuorei/video-server/app/driver/newrelic"
 	"github.com/yuorei/video-server/app/driver/sentry"
+	"github.com/yuorei/video-server/app/driver/tracing"
 	"github.com/yuorei/video-server/yuovision-proto/go/video/video_grpc"
 )
 
 const (
 	defaultPort = "50051"
 	httpAddr    = ":8081"
+	// 公開するHTTPサーバーとは別のポートで、クラスタの内部からのみ取得できるようにする
+	metricsAddr = ":9091"
+	// Redisに溜めた再生回数をDBに反映する間隔
+	watchCountFlushInterval = 1 * time.Minute
+	// 公開予約された動画を確認する間隔
+	scheduledPublishInterval = 1 * time.Minute
+	// 公開期限を過ぎた動画を確認する間隔
+	expiryCheckInterval = 1 * time.Minute
+	// S3の動画が壊れていないか確認する間隔
+	integrityCheckInterval = 1 * time.Hour
+	// 送信に失敗したWebhookを確認する間隔
+	webhookRetryInterval = 30 * time.Second
 )
 
 func NewRouter() {
-	flog.NewLog()
+	logger := flog.NewLog()
 	slog.Info("start server")
 
 	sentry.SentryInit()
 
+	shutdownTracer, err := tracing.NewTracerProvider(context.Background())
+	if err != nil {
+		slog.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracer(context.Background()); err != nil {
+			slog.Error("failed to shutdown tracing", "error", err)
+		}
+	}()
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = defaultPort
@@ -39,7 +68,8 @@ func NewRouter() {
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("failed to listen", "port", port, "error", err)
+		os.Exit(1)
 	}
 
 	// Setup metrics
@@ -52,6 +82,8 @@ func NewRouter() {
 	// reg.MustRegister(srvMetrics)
 	newrelicApp := newrelic.NewRelic()
 	s := grpc.NewServer(
+		// リクエストごとのspanを作り、Infrastructureのspanをその子にする
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainUnaryInterceptor(
 			nrgrpc.UnaryServerInterceptor(newrelicApp),
 		),
@@ -60,9 +92,19 @@ func NewRouter() {
 		),
 	)
 
-	infra := infrastructure.NewInfrastructure()
+	infra := infrastructure.NewInfrastructure(
+		infrastructure.NewStorageBackend("video"),
+		infrastructure.NewPrometheusMetrics(prometheus.DefaultRegisterer),
+		logger,
+	)
 	app := application.NewApplication(infra)
 
+	// Redisが空でもタグを補完できるように、起動時にDBから作り直す
+	err = infra.RebuildTagAutocompleteIndex(context.Background())
+	if err != nil {
+		slog.Error("failed to rebuild tag autocomplete index", "error", err)
+	}
+
 	video_grpc.RegisterUserServiceServer(s, presentation.NewUserService(app))
 	video_grpc.RegisterCommentServiceServer(s, presentation.NewCommentService(app))
 	video_grpc.RegisterVideoServiceServer(s, presentation.NewVideoService(app))
@@ -73,32 +115,122 @@ func NewRouter() {
 	// Run gRPC server and HTTP server for Prometheus metrics
 	var g run.Group
 	g.Add(func() error {
-		log.Printf("start gRPC server port: %v", port)
+		slog.Info("start gRPC server", "port", port)
 		return s.Serve(listener)
 	}, func(err error) {
 		s.GracefulStop()
 		s.Stop()
 	})
 
-	// httpSrv := &http.Server{Addr: httpAddr}
-	// g.Add(func() error {
-	// 	m := http.NewServeMux()
-	// 	m.Handle("/metrics", promhttp.HandlerFor(
-	// 		reg,
-	// 		promhttp.HandlerOpts{
-	// 			EnableOpenMetrics: true,
-	// 		},
-	// 	))
-	// 	httpSrv.Handler = m
-	// 	log.Printf("start HTTP server for Prometheus metrics: %v", httpAddr)
-	// 	return httpSrv.ListenAndServe()
-	// }, func(error) {
-	// 	if err := httpSrv.Close(); err != nil {
-	// 		log.Printf("failed to stop HTTP server: %v", err)
-	// 	}
-	// })
+	flusherCtx, cancelFlusher := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartWatchCountFlusher(flusherCtx, watchCountFlushInterval)
+		return nil
+	}, func(err error) {
+		cancelFlusher()
+	})
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	g.Add(func() error {
+		return infra.StartWorkerPool(workerCtx, infra.VideoProcessingWorkers())
+	}, func(err error) {
+		cancelWorkers()
+	})
+
+	publisherCtx, cancelPublisher := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartScheduledPublisher(publisherCtx, scheduledPublishInterval)
+		return nil
+	}, func(err error) {
+		cancelPublisher()
+	})
+
+	expiryCtx, cancelExpiry := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartExpiryEnforcer(expiryCtx, expiryCheckInterval)
+		return nil
+	}, func(err error) {
+		cancelExpiry()
+	})
+
+	integrityCtx, cancelIntegrity := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartIntegrityChecker(integrityCtx, integrityCheckInterval)
+		return nil
+	}, func(err error) {
+		cancelIntegrity()
+	})
+
+	webhookCtx, cancelWebhook := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartWebhookDispatcher(webhookCtx, webhookRetryInterval)
+		return nil
+	}, func(err error) {
+		cancelWebhook()
+	})
+
+	maintenanceCtx, cancelMaintenance := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartMaintenanceJobs(maintenanceCtx)
+		return nil
+	}, func(err error) {
+		cancelMaintenance()
+	})
+
+	cacheInvalidationCtx, cancelCacheInvalidation := context.WithCancel(context.Background())
+	g.Add(func() error {
+		infra.StartCacheInvalidationSubscriber(cacheInvalidationCtx)
+		return nil
+	}, func(err error) {
+		cancelCacheInvalidation()
+	})
+
+	siteURL := infra.SiteURL()
+	oEmbed, err := presentation.NewOEmbedHandler(app, siteURL)
+	if err != nil {
+		slog.Error("failed to create oEmbed handler", "error", err)
+		os.Exit(1)
+	}
+
+	healthSrv := &http.Server{Addr: httpAddr}
+	g.Add(func() error {
+		m := http.NewServeMux()
+		m.Handle("/health", presentation.NewHealthHandler(infra))
+		m.Handle("/video/", presentation.NewVideoStatusHandler(app))
+		m.Handle("/hls-key/", presentation.NewHLSKeyHandler(app))
+		m.Handle("/tags/autocomplete", presentation.NewTagAutocompleteHandler(app))
+		m.HandleFunc("/oembed", oEmbed.HandleOEmbed)
+		m.HandleFunc("/feed/", presentation.NewFeedHandler(app, siteURL).HandleRSSFeed)
+		share := presentation.NewShareLinkHandler(app, siteURL)
+		m.HandleFunc("/share/", share.HandleShareLink)
+		m.HandleFunc("/qrcode/", share.HandleQRCode)
+		embed := presentation.NewEmbedHandler(app, siteURL)
+		m.HandleFunc("/embed/", embed.HandleEmbed)
+		m.HandleFunc("/embed-code/", embed.HandleEmbedCode)
+		healthSrv.Handler = m
+		slog.Info("start HTTP server", "addr", httpAddr)
+		return healthSrv.ListenAndServe()
+	}, func(error) {
+		if err := healthSrv.Close(); err != nil {
+			slog.Error("failed to stop HTTP server", "error", err)
+		}
+	})
+
+	metricsSrv := &http.Server{Addr: metricsAddr}
+	g.Add(func() error {
+		m := http.NewServeMux()
+		m.Handle("/metrics", promhttp.Handler())
+		metricsSrv.Handler = m
+		slog.Info("start metrics server", "addr", metricsAddr)
+		return metricsSrv.ListenAndServe()
+	}, func(error) {
+		if err := metricsSrv.Close(); err != nil {
+			slog.Error("failed to stop metrics server", "error", err)
+		}
+	})
 
 	if err := g.Run(); err != nil {
-		log.Fatal(err)
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
 }
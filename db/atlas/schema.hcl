@@ -1,3 +1,86 @@
+table "audit_logs" {
+  schema = schema.yuovision
+  column "id" {
+    null           = false
+    type           = bigint
+    auto_increment = true
+  }
+  column "entity_type" {
+    null = false
+    type = varchar(32)
+  }
+  column "entity_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "action" {
+    null = false
+    type = varchar(64)
+  }
+  column "actor_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "before_json" {
+    null = true
+    type = text
+  }
+  column "after_json" {
+    null = true
+    type = text
+  }
+  column "occurred_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "entity_type_entity_id_occurred_at" {
+    columns = [column.entity_type, column.entity_id, column.occurred_at]
+  }
+}
+table "bookmarks" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "user_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "bookmarks_ibfk_1" {
+    columns     = [column.user_id]
+    ref_columns = [table.user.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  foreign_key "bookmarks_ibfk_2" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "user_id_video_id" {
+    unique  = true
+    columns = [column.user_id, column.video_id]
+  }
+  index "video_id" {
+    columns = [column.video_id]
+  }
+}
 table "category" {
   schema = schema.yuovision
   column "id" {
@@ -38,6 +121,10 @@ table "comment" {
     null = true
     type = varchar(255)
   }
+  column "parent_comment_id" {
+    null = true
+    type = varchar(255)
+  }
   primary_key {
     columns = [column.id]
   }
@@ -53,6 +140,9 @@ table "comment" {
     on_update   = NO_ACTION
     on_delete   = NO_ACTION
   }
+  index "parent_comment_id" {
+    columns = [column.parent_comment_id]
+  }
   index "user_id" {
     columns = [column.user_id]
   }
@@ -100,6 +190,41 @@ table "history" {
     columns = [column.video_id]
   }
 }
+table "hls_encryption_keys" {
+  schema = schema.yuovision
+  column "key_id" {
+    null = false
+    type = varchar(64)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "key_bytes" {
+    null = false
+    type = varbinary(16)
+  }
+  column "iv" {
+    null = false
+    type = varbinary(16)
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.key_id]
+  }
+  foreign_key "hls_encryption_keys_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "video_id" {
+    columns = [column.video_id]
+  }
+}
 table "like_dislike" {
   schema = schema.yuovision
   column "id" {
@@ -157,6 +282,40 @@ table "like_dislike" {
     columns = [column.video_id]
   }
 }
+table "moderation_records" {
+  schema = schema.yuovision
+  column "id" {
+    null           = false
+    type           = bigint
+    auto_increment = true
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "moderator_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "action" {
+    null = false
+    type = varchar(32)
+  }
+  column "reason" {
+    null = false
+    type = text
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "video_id_created_at" {
+    columns = [column.video_id, column.created_at]
+  }
+}
 table "playlist" {
   schema = schema.yuovision
   column "id" {
@@ -179,6 +338,16 @@ table "playlist" {
     null = false
     type = timestamp
   }
+  column "is_public" {
+    null    = false
+    type    = bool
+    default = false
+  }
+  column "updated_at" {
+    null    = false
+    type    = timestamp
+    default = sql("CURRENT_TIMESTAMP")
+  }
   primary_key {
     columns = [column.id]
   }
@@ -202,6 +371,11 @@ table "playlist_videos" {
     null = false
     type = varchar(255)
   }
+  column "position" {
+    null    = false
+    type    = int
+    default = 0
+  }
   primary_key {
     columns = [column.playlist_id, column.video_id]
   }
@@ -217,10 +391,62 @@ table "playlist_videos" {
     on_update   = NO_ACTION
     on_delete   = NO_ACTION
   }
+  index "playlist_id_position" {
+    columns = [column.playlist_id, column.position]
+  }
   index "video_id" {
     columns = [column.video_id]
   }
 }
+table "reactions" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "user_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "reaction" {
+    null = false
+    type = varchar(16)
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  column "updated_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "reactions_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  foreign_key "reactions_ibfk_2" {
+    columns     = [column.user_id]
+    ref_columns = [table.user.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "video_id_user_id" {
+    unique  = true
+    columns = [column.video_id, column.user_id]
+  }
+  index "user_id" {
+    columns = [column.user_id]
+  }
+}
 table "report" {
   schema = schema.yuovision
   column "id" {
@@ -278,6 +504,46 @@ table "report" {
     columns = [column.video_id]
   }
 }
+table "subtitle_tracks" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "language" {
+    null = false
+    type = varchar(35)
+  }
+  column "format" {
+    null = false
+    type = varchar(8)
+  }
+  column "url" {
+    null = false
+    type = varchar(255)
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "subtitle_tracks_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "video_id_language" {
+    unique  = true
+    columns = [column.video_id, column.language]
+  }
+}
 table "subscription" {
   schema = schema.yuovision
   column "user_id" {
@@ -326,6 +592,51 @@ table "tag" {
     columns = [column.tag_name]
   }
 }
+table "thumbnail_variants" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "thumbnail_url" {
+    null = false
+    type = varchar(255)
+  }
+  column "assignment_weight" {
+    null = false
+    type = double
+  }
+  column "impressions" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "clicks" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "thumbnail_variants_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "video_id" {
+    columns = [column.video_id]
+  }
+}
 table "user" {
   schema = schema.yuovision
   column "id" {
@@ -344,6 +655,25 @@ table "user" {
     columns = [column.id]
   }
 }
+table "user_storage_quotas" {
+  schema = schema.yuovision
+  column "user_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "used_bytes" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "quota_bytes" {
+    null = false
+    type = bigint
+  }
+  primary_key {
+    columns = [column.user_id]
+  }
+}
 table "video" {
   schema = schema.yuovision
   column "id" {
@@ -379,8 +709,10 @@ table "video" {
     type = bool
   }
   column "is_adult" {
-    null = false
-    type = bool
+    null    = false
+    type    = bool
+    default = false
+    comment = "content_ratingに移行済み。値の移行が終わったら削除する"
   }
   column "is_ad" {
     null = false
@@ -398,9 +730,141 @@ table "video" {
     null = false
     type = bool
   }
+  column "duration_ms" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "width" {
+    null    = false
+    type    = int
+    default = 0
+  }
+  column "height" {
+    null    = false
+    type    = int
+    default = 0
+  }
+  column "bitrate" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "preview_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "thumbnail_vtt_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "watch_duration_total" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "is_deleted" {
+    null    = false
+    type    = bool
+    default = false
+  }
+  column "processing_status" {
+    null    = false
+    type    = varchar(16)
+    default = "ready"
+  }
+  column "processing_error" {
+    null = true
+    type = text
+  }
+  column "publish_at" {
+    null = true
+    type = datetime
+  }
+  column "expires_at" {
+    null = true
+    type = datetime
+  }
+  column "file_size_bytes" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "download_count" {
+    null    = false
+    type    = bigint
+    default = 0
+  }
+  column "checksum" {
+    null = true
+    type = char(64)
+  }
+  column "checksum_verified_at" {
+    null = true
+    type = datetime
+  }
+  column "qrcode_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "content_rating" {
+    null    = false
+    type    = varchar(16)
+    default = "general"
+  }
+  column "allowed_regions" {
+    null = true
+    type = varchar(1024)
+  }
+  column "language" {
+    null    = false
+    type    = varchar(35)
+    default = ""
+  }
+  column "chapters_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "audio_tracks" {
+    null = true
+    type = text
+  }
+  column "analysis" {
+    null = true
+    type = text
+  }
+  column "hls_master_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "dash_manifest_url" {
+    null = true
+    type = varchar(255)
+  }
+  column "version" {
+    null    = false
+    type    = int
+    default = 0
+    comment = "メタデータを更新するたびに増やす楽観ロック用の番号"
+  }
+  column "is_banned" {
+    null    = false
+    type    = bool
+    default = false
+    comment = "permanent_banにした動画。trueの間は復元できない"
+  }
   primary_key {
     columns = [column.id]
   }
+  index "created_at_id" {
+    columns = [column.created_at, column.id]
+  }
+  index "publish_at" {
+    columns = [column.publish_at]
+  }
+  index "expires_at" {
+    columns = [column.expires_at]
+  }
 }
 table "video_category" {
   schema = schema.yuovision
@@ -431,6 +895,83 @@ table "video_category" {
     columns = [column.category_id]
   }
 }
+table "video_descriptions" {
+  schema = schema.yuovision
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "language" {
+    null = false
+    type = varchar(35)
+  }
+  column "description" {
+    null = false
+    type = text
+  }
+  primary_key {
+    columns = [column.video_id, column.language]
+  }
+  foreign_key "video_descriptions_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+}
+table "video_reports" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "reporter_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "reason" {
+    null = false
+    type = varchar(32)
+  }
+  column "status" {
+    null    = false
+    type    = varchar(16)
+    default = "pending"
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "video_reports_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  foreign_key "video_reports_ibfk_2" {
+    columns     = [column.reporter_id]
+    ref_columns = [table.user.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "video_id_reporter_id" {
+    unique  = true
+    columns = [column.video_id, column.reporter_id]
+  }
+  index "reporter_id" {
+    columns = [column.reporter_id]
+  }
+  index "status_created_at" {
+    columns = [column.status, column.created_at]
+  }
+}
 table "video_tags" {
   schema = schema.yuovision
   column "video_id" {
@@ -460,6 +1001,108 @@ table "video_tags" {
     columns = [column.tag_id]
   }
 }
+table "watch_count_snapshots" {
+  schema = schema.yuovision
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "watch_count" {
+    null = false
+    type = int
+  }
+  column "snapshot_time" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.video_id, column.snapshot_time]
+  }
+  foreign_key "watch_count_snapshots_ibfk_1" {
+    columns     = [column.video_id]
+    ref_columns = [table.video.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "snapshot_time" {
+    columns = [column.snapshot_time]
+  }
+}
+table "webhook_deliveries" {
+  schema = schema.yuovision
+  column "id" {
+    null           = false
+    type           = bigint
+    auto_increment = true
+  }
+  column "target_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "event_type" {
+    null = false
+    type = varchar(64)
+  }
+  column "video_id" {
+    null = false
+    type = varchar(255)
+  }
+  column "body" {
+    null = false
+    type = text
+  }
+  column "attempts" {
+    null    = false
+    type    = int
+    default = 0
+  }
+  column "next_attempt_at" {
+    null = false
+    type = timestamp
+  }
+  column "last_error" {
+    null = true
+    type = text
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  foreign_key "webhook_deliveries_ibfk_1" {
+    columns     = [column.target_id]
+    ref_columns = [table.webhook_targets.column.id]
+    on_update   = NO_ACTION
+    on_delete   = NO_ACTION
+  }
+  index "attempts_next_attempt_at" {
+    columns = [column.attempts, column.next_attempt_at]
+  }
+}
+table "webhook_targets" {
+  schema = schema.yuovision
+  column "id" {
+    null = false
+    type = varchar(255)
+  }
+  column "url" {
+    null = false
+    type = varchar(2048)
+  }
+  column "secret" {
+    null = false
+    type = varchar(255)
+  }
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
 schema "yuovision" {
   charset = "utf8mb4"
   collate = "utf8mb4_0900_ai_ci"
@@ -8,12 +8,42 @@ CREATE TABLE `video` (
  `created_at` timestamp NOT NULL,
  `updated_at` timestamp NOT NULL,
  `is_private` bool NOT NULL,
- `is_adult` bool NOT NULL,
+ `is_adult` bool NOT NULL DEFAULT false COMMENT 'content_ratingに移行済み。値の移行が終わったら削除する',
  `is_ad` bool NOT NULL,
  `uploader_id` varchar(255) NOT NULL,
  `watch_count` int NOT NULL,
  `is_external_cutout` bool NOT NULL,
- PRIMARY KEY (`id`)
+ `duration_ms` bigint NOT NULL DEFAULT 0,
+ `width` int NOT NULL DEFAULT 0,
+ `height` int NOT NULL DEFAULT 0,
+ `bitrate` bigint NOT NULL DEFAULT 0,
+ `preview_url` varchar(255) NULL,
+ `thumbnail_vtt_url` varchar(255) NULL,
+ `watch_duration_total` bigint NOT NULL DEFAULT 0,
+ `is_deleted` bool NOT NULL DEFAULT false,
+ `processing_status` varchar(16) NOT NULL DEFAULT 'ready',
+ `processing_error` text NULL,
+ `publish_at` datetime NULL,
+ `expires_at` datetime NULL,
+ `file_size_bytes` bigint NOT NULL DEFAULT 0,
+ `download_count` bigint NOT NULL DEFAULT 0,
+ `checksum` char(64) NULL,
+ `checksum_verified_at` datetime NULL,
+ `qrcode_url` varchar(255) NULL,
+ `content_rating` varchar(16) NOT NULL DEFAULT 'general',
+ `allowed_regions` varchar(1024) NULL,
+ `language` varchar(35) NOT NULL DEFAULT '',
+ `chapters_url` varchar(255) NULL,
+ `audio_tracks` text NULL,
+ `analysis` text NULL,
+ `hls_master_url` varchar(255) NULL,
+ `dash_manifest_url` varchar(255) NULL,
+ `version` int NOT NULL DEFAULT 0 COMMENT 'メタデータを更新するたびに増やす楽観ロック用の番号',
+ `is_banned` bool NOT NULL DEFAULT false COMMENT 'permanent_banにした動画。trueの間は復元できない',
+ PRIMARY KEY (`id`),
+ INDEX `created_at_id` (`created_at`, `id`),
+ INDEX `publish_at` (`publish_at`),
+ INDEX `expires_at` (`expires_at`)
 ) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
 -- Create "user" table
 CREATE TABLE `user` (
@@ -30,7 +60,9 @@ CREATE TABLE `comment` (
  `created_at` timestamp NOT NULL,
  `updated_at` timestamp NOT NULL,
  `user_id` varchar(255) NULL,
+ `parent_comment_id` varchar(255) NULL,
  PRIMARY KEY (`id`),
+ INDEX `parent_comment_id` (`parent_comment_id`),
  INDEX `user_id` (`user_id`),
  INDEX `video_id` (`video_id`),
  CONSTRAINT `comment_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
@@ -71,6 +103,8 @@ CREATE TABLE `playlist` (
  `description` text NULL,
  `user_id` varchar(255) NOT NULL,
  `created_at` timestamp NOT NULL,
+ `is_public` bool NOT NULL DEFAULT false,
+ `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
  PRIMARY KEY (`id`),
  INDEX `user_id` (`user_id`),
  CONSTRAINT `playlist_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
@@ -79,7 +113,9 @@ CREATE TABLE `playlist` (
 CREATE TABLE `playlist_videos` (
  `playlist_id` varchar(255) NOT NULL,
  `video_id` varchar(255) NOT NULL,
+ `position` int NOT NULL DEFAULT 0,
  PRIMARY KEY (`playlist_id`, `video_id`),
+ INDEX `playlist_id_position` (`playlist_id`, `position`),
  INDEX `video_id` (`video_id`),
  CONSTRAINT `playlist_videos_ibfk_1` FOREIGN KEY (`playlist_id`) REFERENCES `playlist` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
  CONSTRAINT `playlist_videos_ibfk_2` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
@@ -140,3 +176,151 @@ CREATE TABLE `video_tags` (
  CONSTRAINT `video_tags_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
  CONSTRAINT `video_tags_ibfk_2` FOREIGN KEY (`tag_id`) REFERENCES `tag` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
 ) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "reactions" table
+CREATE TABLE `reactions` (
+ `id` varchar(255) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `user_id` varchar(255) NOT NULL,
+ `reaction` varchar(16) NOT NULL,
+ `created_at` timestamp NOT NULL,
+ `updated_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ UNIQUE INDEX `video_id_user_id` (`video_id`, `user_id`),
+ INDEX `user_id` (`user_id`),
+ CONSTRAINT `reactions_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
+ CONSTRAINT `reactions_ibfk_2` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "bookmarks" table
+CREATE TABLE `bookmarks` (
+ `id` varchar(255) NOT NULL,
+ `user_id` varchar(255) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ UNIQUE INDEX `user_id_video_id` (`user_id`, `video_id`),
+ INDEX `video_id` (`video_id`),
+ CONSTRAINT `bookmarks_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
+ CONSTRAINT `bookmarks_ibfk_2` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "watch_count_snapshots" table
+CREATE TABLE `watch_count_snapshots` (
+ `video_id` varchar(255) NOT NULL,
+ `watch_count` int NOT NULL,
+ `snapshot_time` timestamp NOT NULL,
+ PRIMARY KEY (`video_id`, `snapshot_time`),
+ INDEX `snapshot_time` (`snapshot_time`),
+ CONSTRAINT `watch_count_snapshots_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "audit_logs" table
+CREATE TABLE `audit_logs` (
+ `id` bigint NOT NULL AUTO_INCREMENT,
+ `entity_type` varchar(32) NOT NULL,
+ `entity_id` varchar(255) NOT NULL,
+ `action` varchar(64) NOT NULL,
+ `actor_id` varchar(255) NOT NULL,
+ `before_json` text NULL,
+ `after_json` text NULL,
+ `occurred_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ INDEX `entity_type_entity_id_occurred_at` (`entity_type`, `entity_id`, `occurred_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "user_storage_quotas" table
+CREATE TABLE `user_storage_quotas` (
+ `user_id` varchar(255) NOT NULL,
+ `used_bytes` bigint NOT NULL DEFAULT 0,
+ `quota_bytes` bigint NOT NULL,
+ PRIMARY KEY (`user_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "webhook_targets" table
+CREATE TABLE `webhook_targets` (
+ `id` varchar(255) NOT NULL,
+ `url` varchar(2048) NOT NULL,
+ `secret` varchar(255) NOT NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "webhook_deliveries" table
+CREATE TABLE `webhook_deliveries` (
+ `id` bigint NOT NULL AUTO_INCREMENT,
+ `target_id` varchar(255) NOT NULL,
+ `event_type` varchar(64) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `body` text NOT NULL,
+ `attempts` int NOT NULL DEFAULT 0,
+ `next_attempt_at` timestamp NOT NULL,
+ `last_error` text NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ INDEX `attempts_next_attempt_at` (`attempts`, `next_attempt_at`),
+ CONSTRAINT `webhook_deliveries_ibfk_1` FOREIGN KEY (`target_id`) REFERENCES `webhook_targets` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "video_descriptions" table
+CREATE TABLE `video_descriptions` (
+ `video_id` varchar(255) NOT NULL,
+ `language` varchar(35) NOT NULL,
+ `description` text NOT NULL,
+ PRIMARY KEY (`video_id`, `language`),
+ CONSTRAINT `video_descriptions_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "subtitle_tracks" table
+CREATE TABLE `subtitle_tracks` (
+ `id` varchar(255) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `language` varchar(35) NOT NULL,
+ `format` varchar(8) NOT NULL,
+ `url` varchar(255) NOT NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ UNIQUE INDEX `video_id_language` (`video_id`, `language`),
+ CONSTRAINT `subtitle_tracks_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "hls_encryption_keys" table
+CREATE TABLE `hls_encryption_keys` (
+ `key_id` varchar(64) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `key_bytes` varbinary(16) NOT NULL,
+ `iv` varbinary(16) NOT NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`key_id`),
+ INDEX `video_id` (`video_id`),
+ CONSTRAINT `hls_encryption_keys_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "thumbnail_variants" table
+CREATE TABLE `thumbnail_variants` (
+ `id` varchar(255) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `thumbnail_url` varchar(255) NOT NULL,
+ `assignment_weight` double NOT NULL,
+ `impressions` bigint NOT NULL DEFAULT 0,
+ `clicks` bigint NOT NULL DEFAULT 0,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ INDEX `video_id` (`video_id`),
+ CONSTRAINT `thumbnail_variants_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "video_reports" table
+CREATE TABLE `video_reports` (
+ `id` varchar(255) NOT NULL,
+ `video_id` varchar(255) NOT NULL,
+ `reporter_id` varchar(255) NOT NULL,
+ `reason` varchar(32) NOT NULL,
+ `status` varchar(16) NOT NULL DEFAULT 'pending',
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ UNIQUE INDEX `video_id_reporter_id` (`video_id`, `reporter_id`),
+ INDEX `reporter_id` (`reporter_id`),
+ INDEX `status_created_at` (`status`, `created_at`),
+ CONSTRAINT `video_reports_ibfk_1` FOREIGN KEY (`video_id`) REFERENCES `video` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION,
+ CONSTRAINT `video_reports_ibfk_2` FOREIGN KEY (`reporter_id`) REFERENCES `user` (`id`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Create "moderation_records" table
+CREATE TABLE `moderation_records` (
+ `id` bigint NOT NULL AUTO_INCREMENT,
+ `video_id` varchar(255) NOT NULL,
+ `moderator_id` varchar(255) NOT NULL,
+ `action` varchar(32) NOT NULL,
+ `reason` text NOT NULL,
+ `created_at` timestamp NOT NULL,
+ PRIMARY KEY (`id`),
+ INDEX `video_id_created_at` (`video_id`, `created_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
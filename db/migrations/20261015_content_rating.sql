@@ -0,0 +1,3 @@
+-- is_adultからcontent_ratingへの移行
+-- make migrationでcontent_ratingを追加した後に一度だけ実行する
+UPDATE video SET content_rating = 'adult' WHERE is_adult = true AND content_rating = 'general';
@@ -9,18 +9,37 @@ import (
 	"time"
 )
 
+type AuditLog struct {
+	ID         int64
+	EntityType string
+	EntityID   string
+	Action     string
+	ActorID    string
+	BeforeJson sql.NullString
+	AfterJson  sql.NullString
+	OccurredAt time.Time
+}
+
+type Bookmark struct {
+	ID        string
+	UserID    string
+	VideoID   string
+	CreatedAt time.Time
+}
+
 type Category struct {
 	ID   string
 	Name string
 }
 
 type Comment struct {
-	ID        string
-	VideoID   string
-	Text      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    sql.NullString
+	ID              string
+	VideoID         string
+	Text            string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          sql.NullString
+	ParentCommentID sql.NullString
 }
 
 type History struct {
@@ -30,6 +49,14 @@ type History struct {
 	WatchedAt time.Time
 }
 
+type HlsEncryptionKey struct {
+	KeyID     string
+	VideoID   string
+	KeyBytes  []byte
+	Iv        []byte
+	CreatedAt time.Time
+}
+
 type LikeDislike struct {
 	ID        string
 	UserID    string
@@ -39,17 +66,38 @@ type LikeDislike struct {
 	CreatedAt time.Time
 }
 
+type ModerationRecord struct {
+	ID          int64
+	VideoID     string
+	ModeratorID string
+	Action      string
+	Reason      string
+	CreatedAt   time.Time
+}
+
 type Playlist struct {
 	ID          string
 	Name        string
 	Description sql.NullString
 	UserID      string
 	CreatedAt   time.Time
+	IsPublic    bool
+	UpdatedAt   time.Time
 }
 
 type PlaylistVideo struct {
 	PlaylistID string
 	VideoID    string
+	Position   int32
+}
+
+type Reaction struct {
+	ID        string
+	VideoID   string
+	UserID    string
+	Reaction  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type Report struct {
@@ -66,17 +114,42 @@ type Subscription struct {
 	ChannelID string
 }
 
+type SubtitleTrack struct {
+	ID        string
+	VideoID   string
+	Language  string
+	Format    string
+	Url       string
+	CreatedAt time.Time
+}
+
 type Tag struct {
 	ID      int32
 	TagName string
 }
 
+type ThumbnailVariant struct {
+	ID               string
+	VideoID          string
+	ThumbnailUrl     string
+	AssignmentWeight float64
+	Impressions      int64
+	Clicks           int64
+	CreatedAt        time.Time
+}
+
 type User struct {
 	ID              string
 	Name            string
 	ProfileImageUrl string
 }
 
+type UserStorageQuota struct {
+	UserID     string
+	UsedBytes  int64
+	QuotaBytes int64
+}
+
 type Video struct {
 	ID                string
 	VideoUrl          string
@@ -86,11 +159,41 @@ type Video struct {
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	IsPrivate         bool
-	IsAdult           bool
-	IsAd              bool
-	UploaderID        string
-	WatchCount        int32
-	IsExternalCutout  bool
+	// content_ratingに移行済み。値の移行が終わったら削除する
+	IsAdult            bool
+	IsAd               bool
+	UploaderID         string
+	WatchCount         int32
+	IsExternalCutout   bool
+	DurationMs         int64
+	Width              int32
+	Height             int32
+	Bitrate            int64
+	PreviewUrl         sql.NullString
+	ThumbnailVttUrl    sql.NullString
+	WatchDurationTotal int64
+	IsDeleted          bool
+	ProcessingStatus   string
+	ProcessingError    sql.NullString
+	PublishAt          sql.NullTime
+	ExpiresAt          sql.NullTime
+	FileSizeBytes      int64
+	DownloadCount      int64
+	Checksum           sql.NullString
+	ChecksumVerifiedAt sql.NullTime
+	QrcodeUrl          sql.NullString
+	ContentRating      string
+	AllowedRegions     sql.NullString
+	Language           string
+	ChaptersUrl        sql.NullString
+	AudioTracks        sql.NullString
+	Analysis           sql.NullString
+	HlsMasterUrl       sql.NullString
+	DashManifestUrl    sql.NullString
+	// メタデータを更新するたびに増やす楽観ロック用の番号
+	Version int32
+	// permanent_banにした動画。trueの間は復元できない
+	IsBanned bool
 }
 
 type VideoCategory struct {
@@ -98,7 +201,47 @@ type VideoCategory struct {
 	CategoryID string
 }
 
+type VideoDescription struct {
+	VideoID     string
+	Language    string
+	Description string
+}
+
+type VideoReport struct {
+	ID         string
+	VideoID    string
+	ReporterID string
+	Reason     string
+	Status     string
+	CreatedAt  time.Time
+}
+
 type VideoTag struct {
 	VideoID string
 	TagID   int32
 }
+
+type WatchCountSnapshot struct {
+	VideoID      string
+	WatchCount   int32
+	SnapshotTime time.Time
+}
+
+type WebhookDelivery struct {
+	ID            int64
+	TargetID      string
+	EventType     string
+	VideoID       string
+	Body          string
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     sql.NullString
+	CreatedAt     time.Time
+}
+
+type WebhookTarget struct {
+	ID        string
+	Url       string
+	Secret    string
+	CreatedAt time.Time
+}
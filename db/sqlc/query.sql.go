@@ -8,20 +8,369 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
-const createComment = `-- name: CreateComment :execresult
-INSERT INTO comment (id, video_id, text, user_id, created_at,updated_at) VALUES (?, ?, ?, ?, ?, ?)
+const addPlaylistVideo = `-- name: AddPlaylistVideo :execresult
+INSERT INTO playlist_videos (playlist_id, video_id, position)
+SELECT ?, ?, COALESCE(MAX(pv.position) + 1, 0) FROM playlist_videos pv WHERE pv.playlist_id = ?
 `
 
-type CreateCommentParams struct {
+type AddPlaylistVideoParams struct {
+	PlaylistID string
+	VideoID    string
+}
+
+func (q *Queries) AddPlaylistVideo(ctx context.Context, arg AddPlaylistVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, addPlaylistVideo, arg.PlaylistID, arg.VideoID, arg.PlaylistID)
+}
+
+const addUserStorageUsage = `-- name: AddUserStorageUsage :execresult
+INSERT INTO user_storage_quotas (user_id, used_bytes, quota_bytes) VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE used_bytes = used_bytes + VALUES(used_bytes)
+`
+
+type AddUserStorageUsageParams struct {
+	UserID     string
+	UsedBytes  int64
+	QuotaBytes int64
+}
+
+func (q *Queries) AddUserStorageUsage(ctx context.Context, arg AddUserStorageUsageParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, addUserStorageUsage, arg.UserID, arg.UsedBytes, arg.QuotaBytes)
+}
+
+const addWatchCount = `-- name: AddWatchCount :execresult
+UPDATE video SET watch_count = watch_count + ? WHERE id = ?
+`
+
+type AddWatchCountParams struct {
+	WatchCount int32
+	ID         string
+}
+
+func (q *Queries) AddWatchCount(ctx context.Context, arg AddWatchCountParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, addWatchCount, arg.WatchCount, arg.ID)
+}
+
+const addWatchDuration = `-- name: AddWatchDuration :execresult
+UPDATE video SET watch_duration_total = watch_duration_total + ? WHERE id = ?
+`
+
+type AddWatchDurationParams struct {
+	WatchDurationTotal int64
+	ID                 string
+}
+
+func (q *Queries) AddWatchDuration(ctx context.Context, arg AddWatchDurationParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, addWatchDuration, arg.WatchDurationTotal, arg.ID)
+}
+
+const banVideo = `-- name: BanVideo :execresult
+UPDATE video SET is_deleted = true, is_banned = true, updated_at = ? WHERE id = ?
+`
+
+type BanVideoParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) BanVideo(ctx context.Context, arg BanVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, banVideo, arg.UpdatedAt, arg.ID)
+}
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :execresult
+UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (/*SLICE:ids*/?)
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	NextAttemptAt time.Time
+	Ids           []int64
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) (sql.Result, error) {
+	query := claimWebhookDeliveries
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.NextAttemptAt)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	return q.db.ExecContext(ctx, query, queryParams...)
+}
+
+const countBookmark = `-- name: CountBookmark :one
+SELECT COUNT(*) FROM bookmarks WHERE user_id = ? AND video_id = ?
+`
+
+type CountBookmarkParams struct {
+	UserID  string
+	VideoID string
+}
+
+func (q *Queries) CountBookmark(ctx context.Context, arg CountBookmarkParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmark, arg.UserID, arg.VideoID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookmarkedVideosByUser = `-- name: CountBookmarkedVideosByUser :one
+SELECT COUNT(*) FROM video v INNER JOIN bookmarks b ON v.id = b.video_id
+WHERE b.user_id = ? AND v.is_deleted = false AND (v.is_private = false OR v.uploader_id = b.user_id)
+`
+
+func (q *Queries) CountBookmarkedVideosByUser(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmarkedVideosByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookmarksByUser = `-- name: CountBookmarksByUser :one
+SELECT COUNT(*) FROM bookmarks WHERE user_id = ?
+`
+
+func (q *Queries) CountBookmarksByUser(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmarksByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countHLSEncryptionKeysByVideoID = `-- name: CountHLSEncryptionKeysByVideoID :one
+SELECT COUNT(*) FROM hls_encryption_keys WHERE video_id = ?
+`
+
+func (q *Queries) CountHLSEncryptionKeysByVideoID(ctx context.Context, videoID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countHLSEncryptionKeysByVideoID, videoID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPlaylistVideos = `-- name: CountPlaylistVideos :one
+SELECT COUNT(*) FROM playlist_videos WHERE playlist_id = ?
+`
+
+func (q *Queries) CountPlaylistVideos(ctx context.Context, playlistID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPlaylistVideos, playlistID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublicAndNonAdultNonAdVideos = `-- name: CountPublicAndNonAdultNonAdVideos :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready'
+`
+
+func (q *Queries) CountPublicAndNonAdultNonAdVideos(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPublicAndNonAdultNonAdVideos)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublicAndNonAdultNonAdVideosByAllTags = `-- name: CountPublicAndNonAdultNonAdVideosByAllTags :one
+SELECT COUNT(*) FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (/*SLICE:tag_names*/?)
+        GROUP BY vt.video_id HAVING COUNT(DISTINCT t.id) = ?
+    )
+`
+
+type CountPublicAndNonAdultNonAdVideosByAllTagsParams struct {
+	TagNames []string
+	TagCount int32
+}
+
+func (q *Queries) CountPublicAndNonAdultNonAdVideosByAllTags(ctx context.Context, arg CountPublicAndNonAdultNonAdVideosByAllTagsParams) (int64, error) {
+	query := countPublicAndNonAdultNonAdVideosByAllTags
+	var queryParams []interface{}
+	if len(arg.TagNames) > 0 {
+		for _, v := range arg.TagNames {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", strings.Repeat(",?", len(arg.TagNames))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.TagCount)
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublicAndNonAdultNonAdVideosByAnyTags = `-- name: CountPublicAndNonAdultNonAdVideosByAnyTags :one
+SELECT COUNT(*) FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (/*SLICE:tag_names*/?)
+    )
+`
+
+func (q *Queries) CountPublicAndNonAdultNonAdVideosByAnyTags(ctx context.Context, tagNames []string) (int64, error) {
+	query := countPublicAndNonAdultNonAdVideosByAnyTags
+	var queryParams []interface{}
+	if len(tagNames) > 0 {
+		for _, v := range tagNames {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", strings.Repeat(",?", len(tagNames))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", "NULL", 1)
+	}
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPublicAndNonAdultNonAdVideosInRange = `-- name: CountPublicAndNonAdultNonAdVideosInRange :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND created_at BETWEEN ? AND ?
+`
+
+type CountPublicAndNonAdultNonAdVideosInRangeParams struct {
+	FromTime time.Time
+	ToTime   time.Time
+}
+
+func (q *Queries) CountPublicAndNonAdultNonAdVideosInRange(ctx context.Context, arg CountPublicAndNonAdultNonAdVideosInRangeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPublicAndNonAdultNonAdVideosInRange, arg.FromTime, arg.ToTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPublicAndNonAdultNonAdVideos = `-- name: CountSearchPublicAndNonAdultNonAdVideos :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE ? OR description LIKE ?)
+`
+
+type CountSearchPublicAndNonAdultNonAdVideosParams struct {
+	TitleKeyword       string
+	DescriptionKeyword sql.NullString
+}
+
+func (q *Queries) CountSearchPublicAndNonAdultNonAdVideos(ctx context.Context, arg CountSearchPublicAndNonAdultNonAdVideosParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchPublicAndNonAdultNonAdVideos, arg.TitleKeyword, arg.DescriptionKeyword)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPublicAndNonAdultNonAdVideosCaseSensitive = `-- name: CountSearchPublicAndNonAdultNonAdVideosCaseSensitive :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE BINARY ? OR description LIKE BINARY ?)
+`
+
+type CountSearchPublicAndNonAdultNonAdVideosCaseSensitiveParams struct {
+	TitleKeyword       string
+	DescriptionKeyword string
+}
+
+func (q *Queries) CountSearchPublicAndNonAdultNonAdVideosCaseSensitive(ctx context.Context, arg CountSearchPublicAndNonAdultNonAdVideosCaseSensitiveParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchPublicAndNonAdultNonAdVideosCaseSensitive, arg.TitleKeyword, arg.DescriptionKeyword)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVideoComments = `-- name: CountVideoComments :one
+SELECT COUNT(*) FROM comment WHERE video_id = ?
+`
+
+func (q *Queries) CountVideoComments(ctx context.Context, videoID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countVideoComments, videoID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVideosByTagID = `-- name: CountVideosByTagID :one
+SELECT COUNT(*) FROM video_tags WHERE tag_id = ?
+`
+
+func (q *Queries) CountVideosByTagID(ctx context.Context, tagID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countVideosByTagID, tagID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAuditLog = `-- name: CreateAuditLog :execresult
+INSERT INTO audit_logs (entity_type, entity_id, action, actor_id, before_json, after_json, occurred_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateAuditLogParams struct {
+	EntityType string
+	EntityID   string
+	Action     string
+	ActorID    string
+	BeforeJson sql.NullString
+	AfterJson  sql.NullString
+	OccurredAt time.Time
+}
+
+func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createAuditLog,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Action,
+		arg.ActorID,
+		arg.BeforeJson,
+		arg.AfterJson,
+		arg.OccurredAt,
+	)
+}
+
+const createBookmark = `-- name: CreateBookmark :execresult
+INSERT IGNORE INTO bookmarks (id, user_id, video_id, created_at) VALUES (?, ?, ?, ?)
+`
+
+type CreateBookmarkParams struct {
 	ID        string
+	UserID    string
 	VideoID   string
-	Text      string
-	UserID    sql.NullString
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+func (q *Queries) CreateBookmark(ctx context.Context, arg CreateBookmarkParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createBookmark,
+		arg.ID,
+		arg.UserID,
+		arg.VideoID,
+		arg.CreatedAt,
+	)
+}
+
+const createComment = `-- name: CreateComment :execresult
+INSERT INTO comment (id, video_id, text, user_id, created_at,updated_at,parent_comment_id) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateCommentParams struct {
+	ID              string
+	VideoID         string
+	Text            string
+	UserID          sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullString
 }
 
 func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (sql.Result, error) {
@@ -32,6 +381,78 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (s
 		arg.UserID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.ParentCommentID,
+	)
+}
+
+const createHLSEncryptionKey = `-- name: CreateHLSEncryptionKey :execresult
+INSERT INTO hls_encryption_keys (key_id, video_id, key_bytes, iv, created_at) VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateHLSEncryptionKeyParams struct {
+	KeyID     string
+	VideoID   string
+	KeyBytes  []byte
+	Iv        []byte
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateHLSEncryptionKey(ctx context.Context, arg CreateHLSEncryptionKeyParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createHLSEncryptionKey,
+		arg.KeyID,
+		arg.VideoID,
+		arg.KeyBytes,
+		arg.Iv,
+		arg.CreatedAt,
+	)
+}
+
+const createModerationRecord = `-- name: CreateModerationRecord :execresult
+INSERT INTO moderation_records (video_id, moderator_id, action, reason, created_at) VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateModerationRecordParams struct {
+	VideoID     string
+	ModeratorID string
+	Action      string
+	Reason      string
+	CreatedAt   time.Time
+}
+
+// 監査のため、moderation_recordsの行は更新も削除もしない
+func (q *Queries) CreateModerationRecord(ctx context.Context, arg CreateModerationRecordParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createModerationRecord,
+		arg.VideoID,
+		arg.ModeratorID,
+		arg.Action,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+}
+
+const createPlaylist = `-- name: CreatePlaylist :execresult
+INSERT INTO playlist (id, name, description, user_id, is_public, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreatePlaylistParams struct {
+	ID          string
+	Name        string
+	Description sql.NullString
+	UserID      string
+	IsPublic    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) CreatePlaylist(ctx context.Context, arg CreatePlaylistParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createPlaylist,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.UserID,
+		arg.IsPublic,
+		arg.CreatedAt,
+		arg.UpdatedAt,
 	)
 }
 
@@ -43,8 +464,30 @@ func (q *Queries) CreateTags(ctx context.Context, tagName string) (sql.Result, e
 	return q.db.ExecContext(ctx, createTags, tagName)
 }
 
+const createThumbnailVariant = `-- name: CreateThumbnailVariant :execresult
+INSERT INTO thumbnail_variants (id, video_id, thumbnail_url, assignment_weight, created_at) VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateThumbnailVariantParams struct {
+	ID               string
+	VideoID          string
+	ThumbnailUrl     string
+	AssignmentWeight float64
+	CreatedAt        time.Time
+}
+
+func (q *Queries) CreateThumbnailVariant(ctx context.Context, arg CreateThumbnailVariantParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createThumbnailVariant,
+		arg.ID,
+		arg.VideoID,
+		arg.ThumbnailUrl,
+		arg.AssignmentWeight,
+		arg.CreatedAt,
+	)
+}
+
 const createVideo = `-- name: CreateVideo :execresult
-INSERT INTO video (id, title, description, video_url, thumbnail_image_url, is_private,is_external_cutout , is_adult, is_ad, uploader_id, created_at,updated_at,watch_count) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO video (id, title, description, video_url, thumbnail_image_url, is_private,is_external_cutout , content_rating, is_ad, uploader_id, created_at,updated_at,watch_count,duration_ms,width,height,bitrate,processing_status,publish_at,file_size_bytes,audio_tracks) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE updated_at = NOW()
 `
 
 type CreateVideoParams struct {
@@ -55,14 +498,24 @@ type CreateVideoParams struct {
 	ThumbnailImageUrl string
 	IsPrivate         bool
 	IsExternalCutout  bool
-	IsAdult           bool
+	ContentRating     string
 	IsAd              bool
 	UploaderID        string
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	WatchCount        int32
+	DurationMs        int64
+	Width             int32
+	Height            int32
+	Bitrate           int64
+	ProcessingStatus  string
+	PublishAt         sql.NullTime
+	FileSizeBytes     int64
+	AudioTracks       sql.NullString
 }
 
+// アップロードを再送しても同じIDの動画が重複しないように、既存の行はupdated_atだけ更新する
+// 影響を受けた行の数は追加した場合は1、既存の行を更新した場合は2になる
 func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, createVideo,
 		arg.ID,
@@ -72,12 +525,42 @@ func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (sql.R
 		arg.ThumbnailImageUrl,
 		arg.IsPrivate,
 		arg.IsExternalCutout,
-		arg.IsAdult,
+		arg.ContentRating,
 		arg.IsAd,
 		arg.UploaderID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.WatchCount,
+		arg.DurationMs,
+		arg.Width,
+		arg.Height,
+		arg.Bitrate,
+		arg.ProcessingStatus,
+		arg.PublishAt,
+		arg.FileSizeBytes,
+		arg.AudioTracks,
+	)
+}
+
+const createVideoReport = `-- name: CreateVideoReport :execresult
+INSERT IGNORE INTO video_reports (id, video_id, reporter_id, reason, created_at) VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateVideoReportParams struct {
+	ID         string
+	VideoID    string
+	ReporterID string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+func (q *Queries) CreateVideoReport(ctx context.Context, arg CreateVideoReportParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createVideoReport,
+		arg.ID,
+		arg.VideoID,
+		arg.ReporterID,
+		arg.Reason,
+		arg.CreatedAt,
 	)
 }
 
@@ -94,6 +577,68 @@ func (q *Queries) CreateVideoTags(ctx context.Context, arg CreateVideoTagsParams
 	return q.db.ExecContext(ctx, createVideoTags, arg.VideoID, arg.TagID)
 }
 
+const createWatchCountSnapshot = `-- name: CreateWatchCountSnapshot :execresult
+INSERT INTO watch_count_snapshots (video_id, watch_count, snapshot_time)
+SELECT v.id, v.watch_count, ? FROM video v WHERE v.id = ?
+`
+
+type CreateWatchCountSnapshotParams struct {
+	SnapshotTime time.Time
+	VideoID      string
+}
+
+func (q *Queries) CreateWatchCountSnapshot(ctx context.Context, arg CreateWatchCountSnapshotParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createWatchCountSnapshot, arg.SnapshotTime, arg.VideoID)
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :execresult
+INSERT INTO webhook_deliveries (target_id, event_type, video_id, body, attempts, next_attempt_at, last_error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateWebhookDeliveryParams struct {
+	TargetID      string
+	EventType     string
+	VideoID       string
+	Body          string
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     sql.NullString
+	CreatedAt     time.Time
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createWebhookDelivery,
+		arg.TargetID,
+		arg.EventType,
+		arg.VideoID,
+		arg.Body,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.LastError,
+		arg.CreatedAt,
+	)
+}
+
+const createWebhookTarget = `-- name: CreateWebhookTarget :execresult
+INSERT INTO webhook_targets (id, url, secret, created_at) VALUES (?, ?, ?, ?)
+`
+
+type CreateWebhookTargetParams struct {
+	ID        string
+	Url       string
+	Secret    string
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateWebhookTarget(ctx context.Context, arg CreateWebhookTargetParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createWebhookTarget,
+		arg.ID,
+		arg.Url,
+		arg.Secret,
+		arg.CreatedAt,
+	)
+}
+
 const createtUser = `-- name: CreatetUser :execresult
 INSERT INTO user (id, name, profile_image_url) VALUES (?, ?, ?)
 `
@@ -108,63 +653,332 @@ func (q *Queries) CreatetUser(ctx context.Context, arg CreatetUserParams) (sql.R
 	return q.db.ExecContext(ctx, createtUser, arg.ID, arg.Name, arg.ProfileImageUrl)
 }
 
-const getAllVideosTags = `-- name: GetAllVideosTags :many
-SELECT
-    v.id AS video_id,
-    t.id AS tag_id,
-    t.tag_name
-FROM
-    video v
-    INNER JOIN video_tags vt ON v.id = vt.video_id
-    INNER JOIN tag t ON vt.tag_id = t.id
-WHERE
-    v.is_adult = false
-    AND v.is_ad = false
-    AND v.is_private = false
+const deleteBookmark = `-- name: DeleteBookmark :execresult
+DELETE FROM bookmarks WHERE user_id = ? AND video_id = ?
 `
 
-type GetAllVideosTagsRow struct {
+type DeleteBookmarkParams struct {
+	UserID  string
 	VideoID string
-	TagID   int32
-	TagName string
 }
 
-func (q *Queries) GetAllVideosTags(ctx context.Context) ([]GetAllVideosTagsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllVideosTags)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllVideosTagsRow
-	for rows.Next() {
-		var i GetAllVideosTagsRow
-		if err := rows.Scan(&i.VideoID, &i.TagID, &i.TagName); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteBookmark, arg.UserID, arg.VideoID)
 }
 
-const getAllVideosTagsByUserID = `-- name: GetAllVideosTagsByUserID :many
-SELECT
-    v.id AS video_id,
-    t.id AS tag_id,
-    t.tag_name
+const deleteBookmarksByVideoID = `-- name: DeleteBookmarksByVideoID :execresult
+DELETE FROM bookmarks WHERE video_id = ?
+`
+
+func (q *Queries) DeleteBookmarksByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteBookmarksByVideoID, videoID)
+}
+
+const deleteComments = `-- name: DeleteComments :execresult
+DELETE FROM comment WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) DeleteComments(ctx context.Context, ids []string) (sql.Result, error) {
+	query := deleteComments
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	return q.db.ExecContext(ctx, query, queryParams...)
+}
+
+const deleteCommentsByVideoID = `-- name: DeleteCommentsByVideoID :execresult
+DELETE FROM comment WHERE video_id = ?
+`
+
+func (q *Queries) DeleteCommentsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteCommentsByVideoID, videoID)
+}
+
+const deleteDuplicateVideoTags = `-- name: DeleteDuplicateVideoTags :execresult
+DELETE s FROM video_tags s INNER JOIN video_tags t ON s.video_id = t.video_id WHERE s.tag_id = ? AND t.tag_id = ?
+`
+
+type DeleteDuplicateVideoTagsParams struct {
+	SourceTagID int32
+	TargetTagID int32
+}
+
+// 統合先のタグがすでに付いた動画では、主キーが重複するため統合元の行を消す
+func (q *Queries) DeleteDuplicateVideoTags(ctx context.Context, arg DeleteDuplicateVideoTagsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteDuplicateVideoTags, arg.SourceTagID, arg.TargetTagID)
+}
+
+const deleteHLSEncryptionKeysByVideoID = `-- name: DeleteHLSEncryptionKeysByVideoID :execresult
+DELETE FROM hls_encryption_keys WHERE video_id = ?
+`
+
+func (q *Queries) DeleteHLSEncryptionKeysByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteHLSEncryptionKeysByVideoID, videoID)
+}
+
+const deleteHistoryByVideoID = `-- name: DeleteHistoryByVideoID :execresult
+DELETE FROM history WHERE video_id = ?
+`
+
+func (q *Queries) DeleteHistoryByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteHistoryByVideoID, videoID)
+}
+
+const deleteLikeDislikesByVideoID = `-- name: DeleteLikeDislikesByVideoID :execresult
+DELETE FROM like_dislike WHERE like_dislike.video_id = ? OR like_dislike.comment_id IN (SELECT c.id FROM comment c WHERE c.video_id = ?)
+`
+
+type DeleteLikeDislikesByVideoIDParams struct {
+	VideoID        sql.NullString
+	CommentVideoID string
+}
+
+func (q *Queries) DeleteLikeDislikesByVideoID(ctx context.Context, arg DeleteLikeDislikesByVideoIDParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteLikeDislikesByVideoID, arg.VideoID, arg.CommentVideoID)
+}
+
+const deleteOrphanedTags = `-- name: DeleteOrphanedTags :execresult
+DELETE FROM tag WHERE id NOT IN (SELECT DISTINCT tag_id FROM video_tags)
+`
+
+func (q *Queries) DeleteOrphanedTags(ctx context.Context) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteOrphanedTags)
+}
+
+const deletePlaylist = `-- name: DeletePlaylist :execresult
+DELETE FROM playlist WHERE id = ?
+`
+
+func (q *Queries) DeletePlaylist(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deletePlaylist, id)
+}
+
+const deletePlaylistVideos = `-- name: DeletePlaylistVideos :execresult
+DELETE FROM playlist_videos WHERE playlist_id = ?
+`
+
+func (q *Queries) DeletePlaylistVideos(ctx context.Context, playlistID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deletePlaylistVideos, playlistID)
+}
+
+const deletePlaylistVideosByVideoID = `-- name: DeletePlaylistVideosByVideoID :execresult
+DELETE FROM playlist_videos WHERE video_id = ?
+`
+
+func (q *Queries) DeletePlaylistVideosByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deletePlaylistVideosByVideoID, videoID)
+}
+
+const deleteReactionsByVideoID = `-- name: DeleteReactionsByVideoID :execresult
+DELETE FROM reactions WHERE video_id = ?
+`
+
+func (q *Queries) DeleteReactionsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteReactionsByVideoID, videoID)
+}
+
+const deleteReportsByVideoID = `-- name: DeleteReportsByVideoID :execresult
+DELETE FROM report WHERE report.video_id = ? OR report.comment_id IN (SELECT c.id FROM comment c WHERE c.video_id = ?)
+`
+
+type DeleteReportsByVideoIDParams struct {
+	VideoID        sql.NullString
+	CommentVideoID string
+}
+
+func (q *Queries) DeleteReportsByVideoID(ctx context.Context, arg DeleteReportsByVideoIDParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteReportsByVideoID, arg.VideoID, arg.CommentVideoID)
+}
+
+const deleteSubtitleTrack = `-- name: DeleteSubtitleTrack :execresult
+DELETE FROM subtitle_tracks WHERE id = ?
+`
+
+func (q *Queries) DeleteSubtitleTrack(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteSubtitleTrack, id)
+}
+
+const deleteSubtitleTracksByVideoID = `-- name: DeleteSubtitleTracksByVideoID :execresult
+DELETE FROM subtitle_tracks WHERE video_id = ?
+`
+
+func (q *Queries) DeleteSubtitleTracksByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteSubtitleTracksByVideoID, videoID)
+}
+
+const deleteTag = `-- name: DeleteTag :execresult
+DELETE FROM tag WHERE id = ?
+`
+
+func (q *Queries) DeleteTag(ctx context.Context, id int32) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteTag, id)
+}
+
+const deleteThumbnailVariantsByVideoID = `-- name: DeleteThumbnailVariantsByVideoID :execresult
+DELETE FROM thumbnail_variants WHERE video_id = ?
+`
+
+func (q *Queries) DeleteThumbnailVariantsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteThumbnailVariantsByVideoID, videoID)
+}
+
+const deleteVideo = `-- name: DeleteVideo :execresult
+DELETE FROM video WHERE id = ?
+`
+
+func (q *Queries) DeleteVideo(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideo, id)
+}
+
+const deleteVideoCategoriesByVideoID = `-- name: DeleteVideoCategoriesByVideoID :execresult
+DELETE FROM video_category WHERE video_id = ?
+`
+
+func (q *Queries) DeleteVideoCategoriesByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideoCategoriesByVideoID, videoID)
+}
+
+const deleteVideoDescriptionsByVideoID = `-- name: DeleteVideoDescriptionsByVideoID :execresult
+DELETE FROM video_descriptions WHERE video_id = ?
+`
+
+func (q *Queries) DeleteVideoDescriptionsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideoDescriptionsByVideoID, videoID)
+}
+
+const deleteVideoReportsByVideoID = `-- name: DeleteVideoReportsByVideoID :execresult
+DELETE FROM video_reports WHERE video_id = ?
+`
+
+func (q *Queries) DeleteVideoReportsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideoReportsByVideoID, videoID)
+}
+
+const deleteVideoTag = `-- name: DeleteVideoTag :execresult
+DELETE FROM video_tags WHERE video_id = ? AND tag_id = ?
+`
+
+type DeleteVideoTagParams struct {
+	VideoID string
+	TagID   int32
+}
+
+func (q *Queries) DeleteVideoTag(ctx context.Context, arg DeleteVideoTagParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideoTag, arg.VideoID, arg.TagID)
+}
+
+const deleteVideoTagsByVideoID = `-- name: DeleteVideoTagsByVideoID :execresult
+DELETE FROM video_tags WHERE video_id = ?
+`
+
+func (q *Queries) DeleteVideoTagsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteVideoTagsByVideoID, videoID)
+}
+
+const deleteWatchCountSnapshotsBefore = `-- name: DeleteWatchCountSnapshotsBefore :execresult
+DELETE FROM watch_count_snapshots WHERE snapshot_time < ?
+`
+
+func (q *Queries) DeleteWatchCountSnapshotsBefore(ctx context.Context, snapshotTime time.Time) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteWatchCountSnapshotsBefore, snapshotTime)
+}
+
+const deleteWatchCountSnapshotsByVideoID = `-- name: DeleteWatchCountSnapshotsByVideoID :execresult
+DELETE FROM watch_count_snapshots WHERE video_id = ?
+`
+
+func (q *Queries) DeleteWatchCountSnapshotsByVideoID(ctx context.Context, videoID string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteWatchCountSnapshotsByVideoID, videoID)
+}
+
+const deleteWebhookDelivery = `-- name: DeleteWebhookDelivery :execresult
+DELETE FROM webhook_deliveries WHERE id = ?
+`
+
+func (q *Queries) DeleteWebhookDelivery(ctx context.Context, id int64) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteWebhookDelivery, id)
+}
+
+const expireVideo = `-- name: ExpireVideo :execresult
+UPDATE video SET is_private = true, updated_at = ? WHERE id = ? AND is_private = false
+`
+
+type ExpireVideoParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) ExpireVideo(ctx context.Context, arg ExpireVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, expireVideo, arg.UpdatedAt, arg.ID)
+}
+
+const getAllVideosTags = `-- name: GetAllVideosTags :many
+SELECT
+    v.id AS video_id,
+    t.id AS tag_id,
+    t.tag_name
+FROM
+    video v
+    INNER JOIN video_tags vt ON v.id = vt.video_id
+    INNER JOIN tag t ON vt.tag_id = t.id
+WHERE
+    v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.is_private = false
+`
+
+type GetAllVideosTagsRow struct {
+	VideoID string
+	TagID   int32
+	TagName string
+}
+
+func (q *Queries) GetAllVideosTags(ctx context.Context) ([]GetAllVideosTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllVideosTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllVideosTagsRow
+	for rows.Next() {
+		var i GetAllVideosTagsRow
+		if err := rows.Scan(&i.VideoID, &i.TagID, &i.TagName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllVideosTagsByUserID = `-- name: GetAllVideosTagsByUserID :many
+SELECT
+    v.id AS video_id,
+    t.id AS tag_id,
+    t.tag_name
 FROM
     video v
     INNER JOIN video_tags vt ON v.id = vt.video_id
     INNER JOIN tag t ON vt.tag_id = t.id
 WHERE
     v.uploader_id = ?
-    AND v.is_adult = false
+    AND v.content_rating <> 'adult'
     AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
     AND v.is_private = false
 `
 
@@ -197,33 +1011,40 @@ func (q *Queries) GetAllVideosTagsByUserID(ctx context.Context, uploaderID strin
 	return items, nil
 }
 
-const getPublicAndNonAdByUploaderID = `-- name: GetPublicAndNonAdByUploaderID :many
-SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout FROM video WHERE is_private   = false AND is_ad = false AND uploader_id = ?
+const getAuditLogsByEntity = `-- name: GetAuditLogsByEntity :many
+SELECT id, entity_type, entity_id, action, actor_id, before_json, after_json, occurred_at FROM audit_logs WHERE entity_type = ? AND entity_id = ? ORDER BY occurred_at DESC, id DESC LIMIT ? OFFSET ?
 `
 
-func (q *Queries) GetPublicAndNonAdByUploaderID(ctx context.Context, uploaderID string) ([]Video, error) {
-	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdByUploaderID, uploaderID)
+type GetAuditLogsByEntityParams struct {
+	EntityType string
+	EntityID   string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) GetAuditLogsByEntity(ctx context.Context, arg GetAuditLogsByEntityParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsByEntity,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Video
+	var items []AuditLog
 	for rows.Next() {
-		var i Video
+		var i AuditLog
 		if err := rows.Scan(
 			&i.ID,
-			&i.VideoUrl,
-			&i.ThumbnailImageUrl,
-			&i.Title,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.IsPrivate,
-			&i.IsAdult,
-			&i.IsAd,
-			&i.UploaderID,
-			&i.WatchCount,
-			&i.IsExternalCutout,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Action,
+			&i.ActorID,
+			&i.BeforeJson,
+			&i.AfterJson,
+			&i.OccurredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -238,12 +1059,20 @@ func (q *Queries) GetPublicAndNonAdByUploaderID(ctx context.Context, uploaderID
 	return items, nil
 }
 
-const getPublicAndNonAdultNonAdVideos = `-- name: GetPublicAndNonAdultNonAdVideos :many
-SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout FROM video WHERE is_private   = false AND is_adult = false AND is_ad = false
+const getBookmarkedVideosByUser = `-- name: GetBookmarkedVideosByUser :many
+SELECT v.id, v.video_url, v.thumbnail_image_url, v.title, v.description, v.created_at, v.updated_at, v.is_private, v.is_adult, v.is_ad, v.uploader_id, v.watch_count, v.is_external_cutout, v.duration_ms, v.width, v.height, v.bitrate, v.preview_url, v.thumbnail_vtt_url, v.watch_duration_total, v.is_deleted, v.processing_status, v.processing_error, v.publish_at, v.expires_at, v.file_size_bytes, v.download_count, v.checksum, v.checksum_verified_at, v.qrcode_url, v.content_rating, v.allowed_regions, v.language, v.chapters_url, v.audio_tracks, v.analysis, v.hls_master_url, v.dash_manifest_url, v.version, v.is_banned FROM video v INNER JOIN bookmarks b ON v.id = b.video_id
+WHERE b.user_id = ? AND v.is_deleted = false AND (v.is_private = false OR v.uploader_id = b.user_id)
+ORDER BY b.created_at DESC, v.id DESC LIMIT ? OFFSET ?
 `
 
-func (q *Queries) GetPublicAndNonAdultNonAdVideos(ctx context.Context) ([]Video, error) {
-	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideos)
+type GetBookmarkedVideosByUserParams struct {
+	UserID string
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetBookmarkedVideosByUser(ctx context.Context, arg GetBookmarkedVideosByUserParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getBookmarkedVideosByUser, arg.UserID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -265,6 +1094,33 @@ func (q *Queries) GetPublicAndNonAdultNonAdVideos(ctx context.Context) ([]Video,
 			&i.UploaderID,
 			&i.WatchCount,
 			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
 		); err != nil {
 			return nil, err
 		}
@@ -279,34 +1135,77 @@ func (q *Queries) GetPublicAndNonAdultNonAdVideos(ctx context.Context) ([]Video,
 	return items, nil
 }
 
-const getUser = `-- name: GetUser :one
-SELECT id, name, profile_image_url FROM user WHERE id = ? LIMIT 1
+const getComment = `-- name: GetComment :one
+SELECT id, video_id, text, created_at, updated_at, user_id, parent_comment_id FROM comment WHERE id = ? LIMIT 1
 `
 
-func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUser, id)
-	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.ProfileImageUrl)
+func (q *Queries) GetComment(ctx context.Context, id string) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, getComment, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.VideoID,
+		&i.Text,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.ParentCommentID,
+	)
 	return i, err
 }
 
-const getUserSubscribeChannelsID = `-- name: GetUserSubscribeChannelsID :many
-SELECT u.id FROM user AS u JOIN subscription AS s ON u.id = s.channel_id WHERE s.user_id = ?
+const getDownloadCount = `-- name: GetDownloadCount :one
+SELECT download_count FROM video WHERE id = ?
 `
 
-func (q *Queries) GetUserSubscribeChannelsID(ctx context.Context, userID string) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, getUserSubscribeChannelsID, userID)
+func (q *Queries) GetDownloadCount(ctx context.Context, id string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getDownloadCount, id)
+	var download_count int64
+	err := row.Scan(&download_count)
+	return download_count, err
+}
+
+const getHLSEncryptionKey = `-- name: GetHLSEncryptionKey :one
+SELECT key_id, video_id, key_bytes, iv, created_at FROM hls_encryption_keys WHERE key_id = ?
+`
+
+func (q *Queries) GetHLSEncryptionKey(ctx context.Context, keyID string) (HlsEncryptionKey, error) {
+	row := q.db.QueryRowContext(ctx, getHLSEncryptionKey, keyID)
+	var i HlsEncryptionKey
+	err := row.Scan(
+		&i.KeyID,
+		&i.VideoID,
+		&i.KeyBytes,
+		&i.Iv,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getModerationRecordsByVideoID = `-- name: GetModerationRecordsByVideoID :many
+SELECT id, video_id, moderator_id, action, reason, created_at FROM moderation_records WHERE video_id = ? ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) GetModerationRecordsByVideoID(ctx context.Context, videoID string) ([]ModerationRecord, error) {
+	rows, err := q.db.QueryContext(ctx, getModerationRecordsByVideoID, videoID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []string
+	var items []ModerationRecord
 	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
+		var i ModerationRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.ModeratorID,
+			&i.Action,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -317,78 +1216,125 @@ func (q *Queries) GetUserSubscribeChannelsID(ctx context.Context, userID string)
 	return items, nil
 }
 
-const getUserSubscriptionID = `-- name: GetUserSubscriptionID :one
-SELECT u.id FROM user AS u JOIN subscription AS s ON u.id = s.channel_id WHERE s.user_id = ? AND s.channel_id = ? LIMIT 1
+const getPlaylist = `-- name: GetPlaylist :one
+SELECT id, name, description, user_id, created_at, is_public, updated_at FROM playlist WHERE id = ? LIMIT 1
 `
 
-type GetUserSubscriptionIDParams struct {
-	UserID    string
-	ChannelID string
-}
-
-func (q *Queries) GetUserSubscriptionID(ctx context.Context, arg GetUserSubscriptionIDParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUserSubscriptionID, arg.UserID, arg.ChannelID)
-	var id string
-	err := row.Scan(&id)
-	return id, err
+func (q *Queries) GetPlaylist(ctx context.Context, id string) (Playlist, error) {
+	row := q.db.QueryRowContext(ctx, getPlaylist, id)
+	var i Playlist
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.IsPublic,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const getVideo = `-- name: GetVideo :one
-SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout FROM video WHERE id = ? LIMIT 1
+const getPlaylistForUpdate = `-- name: GetPlaylistForUpdate :one
+SELECT id, name, description, user_id, created_at, is_public, updated_at FROM playlist WHERE id = ? LIMIT 1 FOR UPDATE
 `
 
-func (q *Queries) GetVideo(ctx context.Context, id string) (Video, error) {
-	row := q.db.QueryRowContext(ctx, getVideo, id)
-	var i Video
+func (q *Queries) GetPlaylistForUpdate(ctx context.Context, id string) (Playlist, error) {
+	row := q.db.QueryRowContext(ctx, getPlaylistForUpdate, id)
+	var i Playlist
 	err := row.Scan(
 		&i.ID,
-		&i.VideoUrl,
-		&i.ThumbnailImageUrl,
-		&i.Title,
+		&i.Name,
 		&i.Description,
+		&i.UserID,
 		&i.CreatedAt,
+		&i.IsPublic,
 		&i.UpdatedAt,
-		&i.IsPrivate,
-		&i.IsAdult,
-		&i.IsAd,
-		&i.UploaderID,
-		&i.WatchCount,
-		&i.IsExternalCutout,
 	)
 	return i, err
 }
 
-const getVideoComments = `-- name: GetVideoComments :many
-SELECT c.id, c.video_id, c.text, c.created_at, c.updated_at, c.user_id , u.name  FROM comment c INNER JOIN user u ON c.user_id = u.id WHERE video_id = ?
+const getPlaylistVideoIDs = `-- name: GetPlaylistVideoIDs :many
+SELECT video_id FROM playlist_videos WHERE playlist_id = ? ORDER BY position ASC
 `
 
-type GetVideoCommentsRow struct {
-	ID        string
-	VideoID   string
-	Text      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    sql.NullString
-	Name      string
+func (q *Queries) GetPlaylistVideoIDs(ctx context.Context, playlistID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPlaylistVideoIDs, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var video_id string
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetVideoComments(ctx context.Context, videoID string) ([]GetVideoCommentsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getVideoComments, videoID)
+const getPublicAndNonAdByUploaderID = `-- name: GetPublicAndNonAdByUploaderID :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private   = false AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND uploader_id = ?
+`
+
+func (q *Queries) GetPublicAndNonAdByUploaderID(ctx context.Context, uploaderID string) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdByUploaderID, uploaderID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetVideoCommentsRow
+	var items []Video
 	for rows.Next() {
-		var i GetVideoCommentsRow
+		var i Video
 		if err := rows.Scan(
 			&i.ID,
-			&i.VideoID,
-			&i.Text,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.UserID,
-			&i.Name,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
 		); err != nil {
 			return nil, err
 		}
@@ -403,26 +1349,71 @@ func (q *Queries) GetVideoComments(ctx context.Context, videoID string) ([]GetVi
 	return items, nil
 }
 
-const getVideoDislikes = `-- name: GetVideoDislikes :many
-SELECT id, user_id, video_id, comment_id, is_like, created_at FROM like_dislike WHERE video_id = ? AND is_like = false
+const getPublicAndNonAdultNonAdVideosAfterCursor = `-- name: GetPublicAndNonAdultNonAdVideosAfterCursor :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (created_at < ? OR (created_at = ? AND id < ?)) ORDER BY created_at DESC, id DESC LIMIT ?
 `
 
-func (q *Queries) GetVideoDislikes(ctx context.Context, videoID sql.NullString) ([]LikeDislike, error) {
-	rows, err := q.db.QueryContext(ctx, getVideoDislikes, videoID)
+type GetPublicAndNonAdultNonAdVideosAfterCursorParams struct {
+	CreatedAt time.Time
+	ID        string
+	Limit     int32
+}
+
+func (q *Queries) GetPublicAndNonAdultNonAdVideosAfterCursor(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosAfterCursorParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideosAfterCursor,
+		arg.CreatedAt,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []LikeDislike
+	var items []Video
 	for rows.Next() {
-		var i LikeDislike
+		var i Video
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserID,
-			&i.VideoID,
-			&i.CommentID,
-			&i.IsLike,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
 		); err != nil {
 			return nil, err
 		}
@@ -437,26 +1428,91 @@ func (q *Queries) GetVideoDislikes(ctx context.Context, videoID sql.NullString)
 	return items, nil
 }
 
-const getVideoLikes = `-- name: GetVideoLikes :many
-SELECT id, user_id, video_id, comment_id, is_like, created_at FROM like_dislike WHERE video_id = ? AND is_like = true
+const getPublicAndNonAdultNonAdVideosByAllTags = `-- name: GetPublicAndNonAdultNonAdVideosByAllTags :many
+SELECT v.id, v.video_url, v.thumbnail_image_url, v.title, v.description, v.created_at, v.updated_at, v.is_private, v.is_adult, v.is_ad, v.uploader_id, v.watch_count, v.is_external_cutout, v.duration_ms, v.width, v.height, v.bitrate, v.preview_url, v.thumbnail_vtt_url, v.watch_duration_total, v.is_deleted, v.processing_status, v.processing_error, v.publish_at, v.expires_at, v.file_size_bytes, v.download_count, v.checksum, v.checksum_verified_at, v.qrcode_url, v.content_rating, v.allowed_regions, v.language, v.chapters_url, v.audio_tracks, v.analysis, v.hls_master_url, v.dash_manifest_url, v.version, v.is_banned FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (/*SLICE:tag_names*/?)
+        GROUP BY vt.video_id HAVING COUNT(DISTINCT t.id) = ?
+    )
+ORDER BY v.created_at DESC, v.id DESC LIMIT ? OFFSET ?
 `
 
-func (q *Queries) GetVideoLikes(ctx context.Context, videoID sql.NullString) ([]LikeDislike, error) {
-	rows, err := q.db.QueryContext(ctx, getVideoLikes, videoID)
+type GetPublicAndNonAdultNonAdVideosByAllTagsParams struct {
+	TagNames []string
+	TagCount int32
+	Limit    int32
+	Offset   int32
+}
+
+func (q *Queries) GetPublicAndNonAdultNonAdVideosByAllTags(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosByAllTagsParams) ([]Video, error) {
+	query := getPublicAndNonAdultNonAdVideosByAllTags
+	var queryParams []interface{}
+	if len(arg.TagNames) > 0 {
+		for _, v := range arg.TagNames {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", strings.Repeat(",?", len(arg.TagNames))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.TagCount)
+	queryParams = append(queryParams, arg.Limit)
+	queryParams = append(queryParams, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []LikeDislike
+	var items []Video
 	for rows.Next() {
-		var i LikeDislike
+		var i Video
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserID,
-			&i.VideoID,
-			&i.CommentID,
-			&i.IsLike,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
 		); err != nil {
 			return nil, err
 		}
@@ -471,20 +1527,89 @@ func (q *Queries) GetVideoLikes(ctx context.Context, videoID sql.NullString) ([]
 	return items, nil
 }
 
-const getVideoTags = `-- name: GetVideoTags :many
-SELECT t.id, t.tag_name FROM tag AS t JOIN video_tags AS vt ON t.id = vt.tag_id WHERE vt.video_id = ?
+const getPublicAndNonAdultNonAdVideosByAnyTags = `-- name: GetPublicAndNonAdultNonAdVideosByAnyTags :many
+SELECT v.id, v.video_url, v.thumbnail_image_url, v.title, v.description, v.created_at, v.updated_at, v.is_private, v.is_adult, v.is_ad, v.uploader_id, v.watch_count, v.is_external_cutout, v.duration_ms, v.width, v.height, v.bitrate, v.preview_url, v.thumbnail_vtt_url, v.watch_duration_total, v.is_deleted, v.processing_status, v.processing_error, v.publish_at, v.expires_at, v.file_size_bytes, v.download_count, v.checksum, v.checksum_verified_at, v.qrcode_url, v.content_rating, v.allowed_regions, v.language, v.chapters_url, v.audio_tracks, v.analysis, v.hls_master_url, v.dash_manifest_url, v.version, v.is_banned FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (/*SLICE:tag_names*/?)
+    )
+ORDER BY v.created_at DESC, v.id DESC LIMIT ? OFFSET ?
 `
 
-func (q *Queries) GetVideoTags(ctx context.Context, videoID string) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getVideoTags, videoID)
+type GetPublicAndNonAdultNonAdVideosByAnyTagsParams struct {
+	TagNames []string
+	Limit    int32
+	Offset   int32
+}
+
+func (q *Queries) GetPublicAndNonAdultNonAdVideosByAnyTags(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosByAnyTagsParams) ([]Video, error) {
+	query := getPublicAndNonAdultNonAdVideosByAnyTags
+	var queryParams []interface{}
+	if len(arg.TagNames) > 0 {
+		for _, v := range arg.TagNames {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", strings.Repeat(",?", len(arg.TagNames))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	queryParams = append(queryParams, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []Video
 	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagName); err != nil {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -498,47 +1623,2519 @@ func (q *Queries) GetVideoTags(ctx context.Context, videoID string) ([]Tag, erro
 	return items, nil
 }
 
-const getWatchCount = `-- name: GetWatchCount :one
-SELECT watch_count FROM video WHERE id = ?
-`
-
-func (q *Queries) GetWatchCount(ctx context.Context, id string) (int32, error) {
-	row := q.db.QueryRowContext(ctx, getWatchCount, id)
-	var watch_count int32
-	err := row.Scan(&watch_count)
-	return watch_count, err
-}
-
-const incrementWatchCount = `-- name: IncrementWatchCount :execresult
-UPDATE video SET watch_count = watch_count + 1 WHERE id = ?
+const getPublicAndNonAdultNonAdVideosFirstPage = `-- name: GetPublicAndNonAdultNonAdVideosFirstPage :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' ORDER BY created_at DESC, id DESC LIMIT ?
 `
 
-func (q *Queries) IncrementWatchCount(ctx context.Context, id string) (sql.Result, error) {
-	return q.db.ExecContext(ctx, incrementWatchCount, id)
+func (q *Queries) GetPublicAndNonAdultNonAdVideosFirstPage(ctx context.Context, limit int32) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideosFirstPage, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const subscribeChannel = `-- name: SubscribeChannel :execresult
-INSERT INTO subscription (user_id, channel_id) VALUES (?, ?)
+const getPublicAndNonAdultNonAdVideosInRange = `-- name: GetPublicAndNonAdultNonAdVideosInRange :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND created_at BETWEEN ? AND ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
 `
 
-type SubscribeChannelParams struct {
-	UserID    string
-	ChannelID string
+type GetPublicAndNonAdultNonAdVideosInRangeParams struct {
+	FromTime time.Time
+	ToTime   time.Time
+	Limit    int32
+	Offset   int32
 }
 
-func (q *Queries) SubscribeChannel(ctx context.Context, arg SubscribeChannelParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, subscribeChannel, arg.UserID, arg.ChannelID)
+func (q *Queries) GetPublicAndNonAdultNonAdVideosInRange(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosInRangeParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideosInRange,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const unSubscribeChannel = `-- name: UnSubscribeChannel :execresult
-DELETE FROM subscription WHERE user_id = ? AND channel_id = ?
+const getPublicAndNonAdultNonAdVideosPaged = `-- name: GetPublicAndNonAdultNonAdVideosPaged :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
 `
 
-type UnSubscribeChannelParams struct {
-	UserID    string
-	ChannelID string
+type GetPublicAndNonAdultNonAdVideosPagedParams struct {
+	Limit  int32
+	Offset int32
 }
 
-func (q *Queries) UnSubscribeChannel(ctx context.Context, arg UnSubscribeChannelParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, unSubscribeChannel, arg.UserID, arg.ChannelID)
+func (q *Queries) GetPublicAndNonAdultNonAdVideosPaged(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosPagedParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideosPaged, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicAndNonAdultNonAdVideosSorted = `-- name: GetPublicAndNonAdultNonAdVideosSorted :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready'
+ORDER BY
+    CASE WHEN ? = 'created_at_desc' THEN created_at END DESC,
+    CASE WHEN ? = 'created_at_asc' THEN created_at END ASC,
+    CASE WHEN ? = 'watch_count_desc' THEN watch_count END DESC,
+    CASE WHEN ? = 'title_asc' THEN title END ASC,
+    id DESC
+`
+
+type GetPublicAndNonAdultNonAdVideosSortedParams struct {
+	SortOrder interface{}
+}
+
+func (q *Queries) GetPublicAndNonAdultNonAdVideosSorted(ctx context.Context, arg GetPublicAndNonAdultNonAdVideosSortedParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, getPublicAndNonAdultNonAdVideosSorted,
+		arg.SortOrder,
+		arg.SortOrder,
+		arg.SortOrder,
+		arg.SortOrder,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublicNonAdVideosByContentRatings = `-- name: GetPublicNonAdVideosByContentRatings :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating IN (/*SLICE:content_ratings*/?) AND is_ad = false AND is_deleted = false AND processing_status = 'ready'
+`
+
+func (q *Queries) GetPublicNonAdVideosByContentRatings(ctx context.Context, contentRatings []string) ([]Video, error) {
+	query := getPublicNonAdVideosByContentRatings
+	var queryParams []interface{}
+	if len(contentRatings) > 0 {
+		for _, v := range contentRatings {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:content_ratings*/?", strings.Repeat(",?", len(contentRatings))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:content_ratings*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReactionCounts = `-- name: GetReactionCounts :one
+SELECT
+    COUNT(CASE WHEN reaction = 'like' THEN 1 END) AS likes,
+    COUNT(CASE WHEN reaction = 'dislike' THEN 1 END) AS dislikes
+FROM reactions WHERE video_id = ?
+`
+
+type GetReactionCountsRow struct {
+	Likes    int64
+	Dislikes int64
+}
+
+func (q *Queries) GetReactionCounts(ctx context.Context, videoID string) (GetReactionCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getReactionCounts, videoID)
+	var i GetReactionCountsRow
+	err := row.Scan(&i.Likes, &i.Dislikes)
+	return i, err
+}
+
+const getRelatedVideos = `-- name: GetRelatedVideos :many
+SELECT
+    v.id, v.video_url, v.thumbnail_image_url, v.title, v.description, v.created_at, v.updated_at, v.is_private, v.is_adult, v.is_ad, v.uploader_id, v.watch_count, v.is_external_cutout, v.duration_ms, v.width, v.height, v.bitrate, v.preview_url, v.thumbnail_vtt_url, v.watch_duration_total, v.is_deleted, v.processing_status, v.processing_error, v.publish_at, v.expires_at, v.file_size_bytes, v.download_count, v.checksum, v.checksum_verified_at, v.qrcode_url, v.content_rating, v.allowed_regions, v.language, v.chapters_url, v.audio_tracks, v.analysis, v.hls_master_url, v.dash_manifest_url, v.version, v.is_banned,
+    CAST(SUM(vt.tag_id IN (/*SLICE:tag_ids*/?)) / (CAST(? AS SIGNED) + COUNT(*) - SUM(vt.tag_id IN (/*SLICE:tag_ids*/?))) AS DOUBLE) AS similarity,
+    GROUP_CONCAT(t.tag_name SEPARATOR '\n') AS tag_names
+FROM
+    video v
+    INNER JOIN video_tags vt ON v.id = vt.video_id
+    INNER JOIN tag t ON vt.tag_id = t.id
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id <> ?
+    AND v.id IN (
+        SELECT vt2.video_id FROM video_tags vt2 WHERE vt2.tag_id IN (/*SLICE:tag_ids*/?)
+    )
+GROUP BY v.id
+ORDER BY similarity DESC, v.created_at DESC, v.id DESC
+LIMIT ?
+`
+
+type GetRelatedVideosParams struct {
+	TagIds         []int32
+	SourceTagCount int64
+	VideoID        string
+	Limit          int32
+}
+
+type GetRelatedVideosRow struct {
+	Video      Video
+	Similarity float64
+	TagNames   sql.NullString
+}
+
+func (q *Queries) GetRelatedVideos(ctx context.Context, arg GetRelatedVideosParams) ([]GetRelatedVideosRow, error) {
+	query := getRelatedVideos
+	var queryParams []interface{}
+	if len(arg.TagIds) > 0 {
+		for _, v := range arg.TagIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", strings.Repeat(",?", len(arg.TagIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.SourceTagCount)
+	if len(arg.TagIds) > 0 {
+		for _, v := range arg.TagIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", strings.Repeat(",?", len(arg.TagIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.VideoID)
+	if len(arg.TagIds) > 0 {
+		for _, v := range arg.TagIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", strings.Repeat(",?", len(arg.TagIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRelatedVideosRow
+	for rows.Next() {
+		var i GetRelatedVideosRow
+		if err := rows.Scan(
+			&i.Video.ID,
+			&i.Video.VideoUrl,
+			&i.Video.ThumbnailImageUrl,
+			&i.Video.Title,
+			&i.Video.Description,
+			&i.Video.CreatedAt,
+			&i.Video.UpdatedAt,
+			&i.Video.IsPrivate,
+			&i.Video.IsAdult,
+			&i.Video.IsAd,
+			&i.Video.UploaderID,
+			&i.Video.WatchCount,
+			&i.Video.IsExternalCutout,
+			&i.Video.DurationMs,
+			&i.Video.Width,
+			&i.Video.Height,
+			&i.Video.Bitrate,
+			&i.Video.PreviewUrl,
+			&i.Video.ThumbnailVttUrl,
+			&i.Video.WatchDurationTotal,
+			&i.Video.IsDeleted,
+			&i.Video.ProcessingStatus,
+			&i.Video.ProcessingError,
+			&i.Video.PublishAt,
+			&i.Video.ExpiresAt,
+			&i.Video.FileSizeBytes,
+			&i.Video.DownloadCount,
+			&i.Video.Checksum,
+			&i.Video.ChecksumVerifiedAt,
+			&i.Video.QrcodeUrl,
+			&i.Video.ContentRating,
+			&i.Video.AllowedRegions,
+			&i.Video.Language,
+			&i.Video.ChaptersUrl,
+			&i.Video.AudioTracks,
+			&i.Video.Analysis,
+			&i.Video.HlsMasterUrl,
+			&i.Video.DashManifestUrl,
+			&i.Video.Version,
+			&i.Video.IsBanned,
+			&i.Similarity,
+			&i.TagNames,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubtitleTrack = `-- name: GetSubtitleTrack :one
+SELECT id, video_id, language, format, url, created_at FROM subtitle_tracks WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetSubtitleTrack(ctx context.Context, id string) (SubtitleTrack, error) {
+	row := q.db.QueryRowContext(ctx, getSubtitleTrack, id)
+	var i SubtitleTrack
+	err := row.Scan(
+		&i.ID,
+		&i.VideoID,
+		&i.Language,
+		&i.Format,
+		&i.Url,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSubtitleTrackByLanguage = `-- name: GetSubtitleTrackByLanguage :one
+SELECT id, video_id, language, format, url, created_at FROM subtitle_tracks WHERE video_id = ? AND language = ? LIMIT 1
+`
+
+type GetSubtitleTrackByLanguageParams struct {
+	VideoID  string
+	Language string
+}
+
+func (q *Queries) GetSubtitleTrackByLanguage(ctx context.Context, arg GetSubtitleTrackByLanguageParams) (SubtitleTrack, error) {
+	row := q.db.QueryRowContext(ctx, getSubtitleTrackByLanguage, arg.VideoID, arg.Language)
+	var i SubtitleTrack
+	err := row.Scan(
+		&i.ID,
+		&i.VideoID,
+		&i.Language,
+		&i.Format,
+		&i.Url,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSubtitleTracksByVideoID = `-- name: GetSubtitleTracksByVideoID :many
+SELECT id, video_id, language, format, url, created_at FROM subtitle_tracks WHERE video_id = ? ORDER BY language
+`
+
+func (q *Queries) GetSubtitleTracksByVideoID(ctx context.Context, videoID string) ([]SubtitleTrack, error) {
+	rows, err := q.db.QueryContext(ctx, getSubtitleTracksByVideoID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SubtitleTrack
+	for rows.Next() {
+		var i SubtitleTrack
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.Language,
+			&i.Format,
+			&i.Url,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagByNameForUpdate = `-- name: GetTagByNameForUpdate :one
+SELECT id, tag_name FROM tag WHERE tag_name = ? LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetTagByNameForUpdate(ctx context.Context, tagName string) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByNameForUpdate, tagName)
+	var i Tag
+	err := row.Scan(&i.ID, &i.TagName)
+	return i, err
+}
+
+const getTagsByNamesOrderByUsage = `-- name: GetTagsByNamesOrderByUsage :many
+SELECT t.tag_name, COUNT(*) AS usage_count FROM tag t INNER JOIN video_tags vt ON vt.tag_id = t.id WHERE t.tag_name IN (/*SLICE:tag_names*/?) GROUP BY t.id, t.tag_name ORDER BY usage_count DESC, t.tag_name LIMIT ?
+`
+
+type GetTagsByNamesOrderByUsageParams struct {
+	TagNames []string
+	Limit    int32
+}
+
+type GetTagsByNamesOrderByUsageRow struct {
+	TagName    string
+	UsageCount int64
+}
+
+func (q *Queries) GetTagsByNamesOrderByUsage(ctx context.Context, arg GetTagsByNamesOrderByUsageParams) ([]GetTagsByNamesOrderByUsageRow, error) {
+	query := getTagsByNamesOrderByUsage
+	var queryParams []interface{}
+	if len(arg.TagNames) > 0 {
+		for _, v := range arg.TagNames {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", strings.Repeat(",?", len(arg.TagNames))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tag_names*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsByNamesOrderByUsageRow
+	for rows.Next() {
+		var i GetTagsByNamesOrderByUsageRow
+		if err := rows.Scan(&i.TagName, &i.UsageCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getThumbnailVariantsByVideoID = `-- name: GetThumbnailVariantsByVideoID :many
+SELECT id, video_id, thumbnail_url, assignment_weight, impressions, clicks, created_at FROM thumbnail_variants WHERE video_id = ? ORDER BY created_at, id
+`
+
+func (q *Queries) GetThumbnailVariantsByVideoID(ctx context.Context, videoID string) ([]ThumbnailVariant, error) {
+	rows, err := q.db.QueryContext(ctx, getThumbnailVariantsByVideoID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ThumbnailVariant
+	for rows.Next() {
+		var i ThumbnailVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.ThumbnailUrl,
+			&i.AssignmentWeight,
+			&i.Impressions,
+			&i.Clicks,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrendingVideos = `-- name: GetTrendingVideos :many
+SELECT
+    v.id, v.video_url, v.thumbnail_image_url, v.title, v.description, v.created_at, v.updated_at, v.is_private, v.is_adult, v.is_ad, v.uploader_id, v.watch_count, v.is_external_cutout, v.duration_ms, v.width, v.height, v.bitrate, v.preview_url, v.thumbnail_vtt_url, v.watch_duration_total, v.is_deleted, v.processing_status, v.processing_error, v.publish_at, v.expires_at, v.file_size_bytes, v.download_count, v.checksum, v.checksum_verified_at, v.qrcode_url, v.content_rating, v.allowed_regions, v.language, v.chapters_url, v.audio_tracks, v.analysis, v.hls_master_url, v.dash_manifest_url, v.version, v.is_banned,
+    CAST(MAX(s.watch_count) - MIN(s.watch_count) AS SIGNED) AS watch_count_delta
+FROM
+    watch_count_snapshots s
+    INNER JOIN video v ON s.video_id = v.id
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND s.snapshot_time >= ?
+GROUP BY v.id
+HAVING watch_count_delta > 0
+ORDER BY watch_count_delta DESC, v.id DESC
+LIMIT ?
+`
+
+type GetTrendingVideosParams struct {
+	WindowStart time.Time
+	Limit       int32
+}
+
+type GetTrendingVideosRow struct {
+	Video           Video
+	WatchCountDelta int64
+}
+
+func (q *Queries) GetTrendingVideos(ctx context.Context, arg GetTrendingVideosParams) ([]GetTrendingVideosRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrendingVideos, arg.WindowStart, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrendingVideosRow
+	for rows.Next() {
+		var i GetTrendingVideosRow
+		if err := rows.Scan(
+			&i.Video.ID,
+			&i.Video.VideoUrl,
+			&i.Video.ThumbnailImageUrl,
+			&i.Video.Title,
+			&i.Video.Description,
+			&i.Video.CreatedAt,
+			&i.Video.UpdatedAt,
+			&i.Video.IsPrivate,
+			&i.Video.IsAdult,
+			&i.Video.IsAd,
+			&i.Video.UploaderID,
+			&i.Video.WatchCount,
+			&i.Video.IsExternalCutout,
+			&i.Video.DurationMs,
+			&i.Video.Width,
+			&i.Video.Height,
+			&i.Video.Bitrate,
+			&i.Video.PreviewUrl,
+			&i.Video.ThumbnailVttUrl,
+			&i.Video.WatchDurationTotal,
+			&i.Video.IsDeleted,
+			&i.Video.ProcessingStatus,
+			&i.Video.ProcessingError,
+			&i.Video.PublishAt,
+			&i.Video.ExpiresAt,
+			&i.Video.FileSizeBytes,
+			&i.Video.DownloadCount,
+			&i.Video.Checksum,
+			&i.Video.ChecksumVerifiedAt,
+			&i.Video.QrcodeUrl,
+			&i.Video.ContentRating,
+			&i.Video.AllowedRegions,
+			&i.Video.Language,
+			&i.Video.ChaptersUrl,
+			&i.Video.AudioTracks,
+			&i.Video.Analysis,
+			&i.Video.HlsMasterUrl,
+			&i.Video.DashManifestUrl,
+			&i.Video.Version,
+			&i.Video.IsBanned,
+			&i.WatchCountDelta,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUndeletedVideo = `-- name: GetUndeletedVideo :one
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE id = ? AND is_deleted = false LIMIT 1
+`
+
+// 論理削除した動画は見つからないものとして扱う
+func (q *Queries) GetUndeletedVideo(ctx context.Context, id string) (Video, error) {
+	row := q.db.QueryRowContext(ctx, getUndeletedVideo, id)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.VideoUrl,
+		&i.ThumbnailImageUrl,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsPrivate,
+		&i.IsAdult,
+		&i.IsAd,
+		&i.UploaderID,
+		&i.WatchCount,
+		&i.IsExternalCutout,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.Bitrate,
+		&i.PreviewUrl,
+		&i.ThumbnailVttUrl,
+		&i.WatchDurationTotal,
+		&i.IsDeleted,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.FileSizeBytes,
+		&i.DownloadCount,
+		&i.Checksum,
+		&i.ChecksumVerifiedAt,
+		&i.QrcodeUrl,
+		&i.ContentRating,
+		&i.AllowedRegions,
+		&i.Language,
+		&i.ChaptersUrl,
+		&i.AudioTracks,
+		&i.Analysis,
+		&i.HlsMasterUrl,
+		&i.DashManifestUrl,
+		&i.Version,
+		&i.IsBanned,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, name, profile_image_url FROM user WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(&i.ID, &i.Name, &i.ProfileImageUrl)
+	return i, err
+}
+
+const getUserStorageQuota = `-- name: GetUserStorageQuota :one
+SELECT used_bytes, quota_bytes FROM user_storage_quotas WHERE user_id = ?
+`
+
+type GetUserStorageQuotaRow struct {
+	UsedBytes  int64
+	QuotaBytes int64
+}
+
+func (q *Queries) GetUserStorageQuota(ctx context.Context, userID string) (GetUserStorageQuotaRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserStorageQuota, userID)
+	var i GetUserStorageQuotaRow
+	err := row.Scan(&i.UsedBytes, &i.QuotaBytes)
+	return i, err
+}
+
+const getUserSubscribeChannelsID = `-- name: GetUserSubscribeChannelsID :many
+SELECT u.id FROM user AS u JOIN subscription AS s ON u.id = s.channel_id WHERE s.user_id = ?
+`
+
+func (q *Queries) GetUserSubscribeChannelsID(ctx context.Context, userID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getUserSubscribeChannelsID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSubscriptionID = `-- name: GetUserSubscriptionID :one
+SELECT u.id FROM user AS u JOIN subscription AS s ON u.id = s.channel_id WHERE s.user_id = ? AND s.channel_id = ? LIMIT 1
+`
+
+type GetUserSubscriptionIDParams struct {
+	UserID    string
+	ChannelID string
+}
+
+func (q *Queries) GetUserSubscriptionID(ctx context.Context, arg GetUserSubscriptionIDParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserSubscriptionID, arg.UserID, arg.ChannelID)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getVideo = `-- name: GetVideo :one
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetVideo(ctx context.Context, id string) (Video, error) {
+	row := q.db.QueryRowContext(ctx, getVideo, id)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.VideoUrl,
+		&i.ThumbnailImageUrl,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsPrivate,
+		&i.IsAdult,
+		&i.IsAd,
+		&i.UploaderID,
+		&i.WatchCount,
+		&i.IsExternalCutout,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.Bitrate,
+		&i.PreviewUrl,
+		&i.ThumbnailVttUrl,
+		&i.WatchDurationTotal,
+		&i.IsDeleted,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.FileSizeBytes,
+		&i.DownloadCount,
+		&i.Checksum,
+		&i.ChecksumVerifiedAt,
+		&i.QrcodeUrl,
+		&i.ContentRating,
+		&i.AllowedRegions,
+		&i.Language,
+		&i.ChaptersUrl,
+		&i.AudioTracks,
+		&i.Analysis,
+		&i.HlsMasterUrl,
+		&i.DashManifestUrl,
+		&i.Version,
+		&i.IsBanned,
+	)
+	return i, err
+}
+
+const getVideoComments = `-- name: GetVideoComments :many
+SELECT c.id, c.video_id, c.text, c.created_at, c.updated_at, c.user_id, c.parent_comment_id , u.name  FROM comment c INNER JOIN user u ON c.user_id = u.id WHERE video_id = ?
+`
+
+type GetVideoCommentsRow struct {
+	ID              string
+	VideoID         string
+	Text            string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          sql.NullString
+	ParentCommentID sql.NullString
+	Name            string
+}
+
+func (q *Queries) GetVideoComments(ctx context.Context, videoID string) ([]GetVideoCommentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoComments, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVideoCommentsRow
+	for rows.Next() {
+		var i GetVideoCommentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.Text,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ParentCommentID,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoCommentsPaged = `-- name: GetVideoCommentsPaged :many
+SELECT c.id, c.video_id, c.text, c.created_at, c.updated_at, c.user_id, c.parent_comment_id , u.name  FROM comment c INNER JOIN user u ON c.user_id = u.id WHERE video_id = ? ORDER BY c.created_at ASC, c.id ASC LIMIT ? OFFSET ?
+`
+
+type GetVideoCommentsPagedParams struct {
+	VideoID string
+	Limit   int32
+	Offset  int32
+}
+
+type GetVideoCommentsPagedRow struct {
+	ID              string
+	VideoID         string
+	Text            string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	UserID          sql.NullString
+	ParentCommentID sql.NullString
+	Name            string
+}
+
+func (q *Queries) GetVideoCommentsPaged(ctx context.Context, arg GetVideoCommentsPagedParams) ([]GetVideoCommentsPagedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoCommentsPaged, arg.VideoID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVideoCommentsPagedRow
+	for rows.Next() {
+		var i GetVideoCommentsPagedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.Text,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ParentCommentID,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoDescriptions = `-- name: GetVideoDescriptions :many
+SELECT language, description FROM video_descriptions WHERE video_id = ?
+`
+
+type GetVideoDescriptionsRow struct {
+	Language    string
+	Description string
+}
+
+func (q *Queries) GetVideoDescriptions(ctx context.Context, videoID string) ([]GetVideoDescriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoDescriptions, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVideoDescriptionsRow
+	for rows.Next() {
+		var i GetVideoDescriptionsRow
+		if err := rows.Scan(&i.Language, &i.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoDislikes = `-- name: GetVideoDislikes :many
+SELECT id, user_id, video_id, comment_id, is_like, created_at FROM like_dislike WHERE video_id = ? AND is_like = false
+`
+
+func (q *Queries) GetVideoDislikes(ctx context.Context, videoID sql.NullString) ([]LikeDislike, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoDislikes, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LikeDislike
+	for rows.Next() {
+		var i LikeDislike
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.VideoID,
+			&i.CommentID,
+			&i.IsLike,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoForUpdate = `-- name: GetVideoForUpdate :one
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE id = ? LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetVideoForUpdate(ctx context.Context, id string) (Video, error) {
+	row := q.db.QueryRowContext(ctx, getVideoForUpdate, id)
+	var i Video
+	err := row.Scan(
+		&i.ID,
+		&i.VideoUrl,
+		&i.ThumbnailImageUrl,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsPrivate,
+		&i.IsAdult,
+		&i.IsAd,
+		&i.UploaderID,
+		&i.WatchCount,
+		&i.IsExternalCutout,
+		&i.DurationMs,
+		&i.Width,
+		&i.Height,
+		&i.Bitrate,
+		&i.PreviewUrl,
+		&i.ThumbnailVttUrl,
+		&i.WatchDurationTotal,
+		&i.IsDeleted,
+		&i.ProcessingStatus,
+		&i.ProcessingError,
+		&i.PublishAt,
+		&i.ExpiresAt,
+		&i.FileSizeBytes,
+		&i.DownloadCount,
+		&i.Checksum,
+		&i.ChecksumVerifiedAt,
+		&i.QrcodeUrl,
+		&i.ContentRating,
+		&i.AllowedRegions,
+		&i.Language,
+		&i.ChaptersUrl,
+		&i.AudioTracks,
+		&i.Analysis,
+		&i.HlsMasterUrl,
+		&i.DashManifestUrl,
+		&i.Version,
+		&i.IsBanned,
+	)
+	return i, err
+}
+
+const getVideoLikes = `-- name: GetVideoLikes :many
+SELECT id, user_id, video_id, comment_id, is_like, created_at FROM like_dislike WHERE video_id = ? AND is_like = true
+`
+
+func (q *Queries) GetVideoLikes(ctx context.Context, videoID sql.NullString) ([]LikeDislike, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoLikes, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LikeDislike
+	for rows.Next() {
+		var i LikeDislike
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.VideoID,
+			&i.CommentID,
+			&i.IsLike,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoReportsByStatus = `-- name: GetVideoReportsByStatus :many
+SELECT id, video_id, reporter_id, reason, status, created_at FROM video_reports WHERE status = ? ORDER BY created_at, id LIMIT ? OFFSET ?
+`
+
+type GetVideoReportsByStatusParams struct {
+	Status string
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) GetVideoReportsByStatus(ctx context.Context, arg GetVideoReportsByStatusParams) ([]VideoReport, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoReportsByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VideoReport
+	for rows.Next() {
+		var i VideoReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.ReporterID,
+			&i.Reason,
+			&i.Status,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoReportsByVideoID = `-- name: GetVideoReportsByVideoID :many
+SELECT id, video_id, reporter_id, reason, status, created_at FROM video_reports WHERE video_id = ? ORDER BY created_at DESC, id
+`
+
+func (q *Queries) GetVideoReportsByVideoID(ctx context.Context, videoID string) ([]VideoReport, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoReportsByVideoID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []VideoReport
+	for rows.Next() {
+		var i VideoReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoID,
+			&i.ReporterID,
+			&i.Reason,
+			&i.Status,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoTags = `-- name: GetVideoTags :many
+SELECT t.id, t.tag_name FROM tag AS t JOIN video_tags AS vt ON t.id = vt.tag_id WHERE vt.video_id = ?
+`
+
+func (q *Queries) GetVideoTags(ctx context.Context, videoID string) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getVideoTags, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(&i.ID, &i.TagName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideoUploaderID = `-- name: GetVideoUploaderID :one
+SELECT uploader_id FROM video WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetVideoUploaderID(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getVideoUploaderID, id)
+	var uploader_id string
+	err := row.Scan(&uploader_id)
+	return uploader_id, err
+}
+
+const getVideosByIDs = `-- name: GetVideosByIDs :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE id IN (/*SLICE:ids*/?) AND is_deleted = false
+`
+
+func (q *Queries) GetVideosByIDs(ctx context.Context, ids []string) ([]Video, error) {
+	query := getVideosByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideosTagsByVideoIDs = `-- name: GetVideosTagsByVideoIDs :many
+SELECT
+    vt.video_id,
+    t.id AS tag_id,
+    t.tag_name
+FROM
+    video_tags vt
+    INNER JOIN tag t ON vt.tag_id = t.id
+WHERE
+    vt.video_id IN (/*SLICE:video_ids*/?)
+`
+
+type GetVideosTagsByVideoIDsRow struct {
+	VideoID string
+	TagID   int32
+	TagName string
+}
+
+func (q *Queries) GetVideosTagsByVideoIDs(ctx context.Context, videoIds []string) ([]GetVideosTagsByVideoIDsRow, error) {
+	query := getVideosTagsByVideoIDs
+	var queryParams []interface{}
+	if len(videoIds) > 0 {
+		for _, v := range videoIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", strings.Repeat(",?", len(videoIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetVideosTagsByVideoIDsRow
+	for rows.Next() {
+		var i GetVideosTagsByVideoIDsRow
+		if err := rows.Scan(&i.VideoID, &i.TagID, &i.TagName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWatchCount = `-- name: GetWatchCount :one
+SELECT watch_count FROM video WHERE id = ?
+`
+
+func (q *Queries) GetWatchCount(ctx context.Context, id string) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getWatchCount, id)
+	var watch_count int32
+	err := row.Scan(&watch_count)
+	return watch_count, err
+}
+
+const getWatchStats = `-- name: GetWatchStats :one
+SELECT watch_count, watch_duration_total FROM video WHERE id = ?
+`
+
+type GetWatchStatsRow struct {
+	WatchCount         int32
+	WatchDurationTotal int64
+}
+
+func (q *Queries) GetWatchStats(ctx context.Context, id string) (GetWatchStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getWatchStats, id)
+	var i GetWatchStatsRow
+	err := row.Scan(&i.WatchCount, &i.WatchDurationTotal)
+	return i, err
+}
+
+const incrementDownloadCount = `-- name: IncrementDownloadCount :execresult
+UPDATE video SET download_count = download_count + 1 WHERE id = ?
+`
+
+func (q *Queries) IncrementDownloadCount(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, incrementDownloadCount, id)
+}
+
+const incrementThumbnailVariantClicks = `-- name: IncrementThumbnailVariantClicks :execresult
+UPDATE thumbnail_variants SET clicks = clicks + 1 WHERE id = ?
+`
+
+func (q *Queries) IncrementThumbnailVariantClicks(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, incrementThumbnailVariantClicks, id)
+}
+
+const incrementThumbnailVariantImpressions = `-- name: IncrementThumbnailVariantImpressions :execresult
+UPDATE thumbnail_variants SET impressions = impressions + 1 WHERE id = ?
+`
+
+func (q *Queries) IncrementThumbnailVariantImpressions(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, incrementThumbnailVariantImpressions, id)
+}
+
+const incrementWatchCount = `-- name: IncrementWatchCount :execresult
+UPDATE video SET watch_count = watch_count + 1 WHERE id = ?
+`
+
+func (q *Queries) IncrementWatchCount(ctx context.Context, id string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, incrementWatchCount, id)
+}
+
+const listDueWebhookDeliveries = `-- name: ListDueWebhookDeliveries :many
+SELECT d.id, d.event_type, d.video_id, d.body, d.attempts, t.id AS target_id, t.url, t.secret
+FROM webhook_deliveries d
+JOIN webhook_targets t ON d.target_id = t.id
+WHERE d.attempts < ? AND d.next_attempt_at <= ?
+ORDER BY d.next_attempt_at
+LIMIT ?
+FOR UPDATE OF d SKIP LOCKED
+`
+
+type ListDueWebhookDeliveriesParams struct {
+	Attempts      int32
+	NextAttemptAt time.Time
+	Limit         int32
+}
+
+type ListDueWebhookDeliveriesRow struct {
+	ID        int64
+	EventType string
+	VideoID   string
+	Body      string
+	Attempts  int32
+	TargetID  string
+	Url       string
+	Secret    string
+}
+
+func (q *Queries) ListDueWebhookDeliveries(ctx context.Context, arg ListDueWebhookDeliveriesParams) ([]ListDueWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDueWebhookDeliveries, arg.Attempts, arg.NextAttemptAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDueWebhookDeliveriesRow
+	for rows.Next() {
+		var i ListDueWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.VideoID,
+			&i.Body,
+			&i.Attempts,
+			&i.TargetID,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredVideos = `-- name: ListExpiredVideos :many
+SELECT id FROM video WHERE expires_at IS NOT NULL AND expires_at <= ? AND is_private = false AND is_deleted = false
+`
+
+func (q *Queries) ListExpiredVideos(ctx context.Context, expiresAt sql.NullTime) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredVideos, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReplyCommentIDs = `-- name: ListReplyCommentIDs :many
+SELECT id FROM comment WHERE parent_comment_id IN (/*SLICE:parent_ids*/?) FOR UPDATE
+`
+
+func (q *Queries) ListReplyCommentIDs(ctx context.Context, parentIds []sql.NullString) ([]string, error) {
+	query := listReplyCommentIDs
+	var queryParams []interface{}
+	if len(parentIds) > 0 {
+		for _, v := range parentIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:parent_ids*/?", strings.Repeat(",?", len(parentIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:parent_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledVideosToPublish = `-- name: ListScheduledVideosToPublish :many
+SELECT id FROM video WHERE publish_at IS NOT NULL AND publish_at <= ? AND is_private = true AND is_deleted = false
+`
+
+func (q *Queries) ListScheduledVideosToPublish(ctx context.Context, publishAt sql.NullTime) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledVideosToPublish, publishAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagUsageCounts = `-- name: ListTagUsageCounts :many
+SELECT t.tag_name, COUNT(*) AS usage_count FROM tag t INNER JOIN video_tags vt ON vt.tag_id = t.id GROUP BY t.id, t.tag_name
+`
+
+type ListTagUsageCountsRow struct {
+	TagName    string
+	UsageCount int64
+}
+
+func (q *Queries) ListTagUsageCounts(ctx context.Context) ([]ListTagUsageCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagUsageCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagUsageCountsRow
+	for rows.Next() {
+		var i ListTagUsageCountsRow
+		if err := rows.Scan(&i.TagName, &i.UsageCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosByUploaderForUpdate = `-- name: ListVideosByUploaderForUpdate :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE uploader_id = ? FOR UPDATE
+`
+
+func (q *Queries) ListVideosByUploaderForUpdate(ctx context.Context, uploaderID string) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, listVideosByUploaderForUpdate, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosForIntegrityCheck = `-- name: ListVideosForIntegrityCheck :many
+SELECT id FROM video WHERE checksum IS NOT NULL AND processing_status = 'ready' ORDER BY checksum_verified_at LIMIT ?
+`
+
+func (q *Queries) ListVideosForIntegrityCheck(ctx context.Context, limit int32) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listVideosForIntegrityCheck, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookTargets = `-- name: ListWebhookTargets :many
+SELECT id, url, secret FROM webhook_targets ORDER BY created_at
+`
+
+type ListWebhookTargetsRow struct {
+	ID     string
+	Url    string
+	Secret string
+}
+
+func (q *Queries) ListWebhookTargets(ctx context.Context) ([]ListWebhookTargetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookTargets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListWebhookTargetsRow
+	for rows.Next() {
+		var i ListWebhookTargetsRow
+		if err := rows.Scan(&i.ID, &i.Url, &i.Secret); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveVideoTags = `-- name: MoveVideoTags :execresult
+UPDATE video_tags SET tag_id = ? WHERE tag_id = ?
+`
+
+type MoveVideoTagsParams struct {
+	TargetTagID int32
+	SourceTagID int32
+}
+
+func (q *Queries) MoveVideoTags(ctx context.Context, arg MoveVideoTagsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, moveVideoTags, arg.TargetTagID, arg.SourceTagID)
+}
+
+const publishScheduledVideo = `-- name: PublishScheduledVideo :execresult
+UPDATE video SET is_private = false, publish_at = NULL, updated_at = ? WHERE id = ? AND is_private = true AND publish_at IS NOT NULL
+`
+
+type PublishScheduledVideoParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) PublishScheduledVideo(ctx context.Context, arg PublishScheduledVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, publishScheduledVideo, arg.UpdatedAt, arg.ID)
+}
+
+const removePlaylistVideo = `-- name: RemovePlaylistVideo :execresult
+DELETE FROM playlist_videos WHERE playlist_id = ? AND video_id = ?
+`
+
+type RemovePlaylistVideoParams struct {
+	PlaylistID string
+	VideoID    string
+}
+
+func (q *Queries) RemovePlaylistVideo(ctx context.Context, arg RemovePlaylistVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, removePlaylistVideo, arg.PlaylistID, arg.VideoID)
+}
+
+const renameTag = `-- name: RenameTag :execresult
+UPDATE tag SET tag_name = ? WHERE id = ?
+`
+
+type RenameTagParams struct {
+	NewName string
+	ID      int32
+}
+
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, renameTag, arg.NewName, arg.ID)
+}
+
+const reorderPlaylistVideos = `-- name: ReorderPlaylistVideos :execresult
+UPDATE playlist_videos SET position = FIELD(video_id, /*SLICE:video_ids*/?) - 1 WHERE playlist_id = ? AND video_id IN (/*SLICE:video_ids*/?)
+`
+
+type ReorderPlaylistVideosParams struct {
+	VideoIds   []string
+	PlaylistID string
+}
+
+func (q *Queries) ReorderPlaylistVideos(ctx context.Context, arg ReorderPlaylistVideosParams) (sql.Result, error) {
+	query := reorderPlaylistVideos
+	var queryParams []interface{}
+	if len(arg.VideoIds) > 0 {
+		for _, v := range arg.VideoIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", strings.Repeat(",?", len(arg.VideoIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.PlaylistID)
+	if len(arg.VideoIds) > 0 {
+		for _, v := range arg.VideoIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", strings.Repeat(",?", len(arg.VideoIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:video_ids*/?", "NULL", 1)
+	}
+	return q.db.ExecContext(ctx, query, queryParams...)
+}
+
+const resolveVideoReports = `-- name: ResolveVideoReports :execresult
+UPDATE video_reports SET status = ? WHERE video_id = ? AND status = 'pending'
+`
+
+type ResolveVideoReportsParams struct {
+	Status  string
+	VideoID string
+}
+
+func (q *Queries) ResolveVideoReports(ctx context.Context, arg ResolveVideoReportsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, resolveVideoReports, arg.Status, arg.VideoID)
+}
+
+const restoreVideo = `-- name: RestoreVideo :execresult
+UPDATE video SET is_deleted = false, updated_at = ? WHERE id = ? AND is_banned = false
+`
+
+type RestoreVideoParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) RestoreVideo(ctx context.Context, arg RestoreVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, restoreVideo, arg.UpdatedAt, arg.ID)
+}
+
+const searchPublicAndNonAdultNonAdVideos = `-- name: SearchPublicAndNonAdultNonAdVideos :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE ? OR description LIKE ?) ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
+`
+
+type SearchPublicAndNonAdultNonAdVideosParams struct {
+	TitleKeyword       string
+	DescriptionKeyword sql.NullString
+	Limit              int32
+	Offset             int32
+}
+
+func (q *Queries) SearchPublicAndNonAdultNonAdVideos(ctx context.Context, arg SearchPublicAndNonAdultNonAdVideosParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, searchPublicAndNonAdultNonAdVideos,
+		arg.TitleKeyword,
+		arg.DescriptionKeyword,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPublicAndNonAdultNonAdVideosCaseSensitive = `-- name: SearchPublicAndNonAdultNonAdVideosCaseSensitive :many
+SELECT id, video_url, thumbnail_image_url, title, description, created_at, updated_at, is_private, is_adult, is_ad, uploader_id, watch_count, is_external_cutout, duration_ms, width, height, bitrate, preview_url, thumbnail_vtt_url, watch_duration_total, is_deleted, processing_status, processing_error, publish_at, expires_at, file_size_bytes, download_count, checksum, checksum_verified_at, qrcode_url, content_rating, allowed_regions, language, chapters_url, audio_tracks, analysis, hls_master_url, dash_manifest_url, version, is_banned FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE BINARY ? OR description LIKE BINARY ?) ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?
+`
+
+type SearchPublicAndNonAdultNonAdVideosCaseSensitiveParams struct {
+	TitleKeyword       string
+	DescriptionKeyword string
+	Limit              int32
+	Offset             int32
+}
+
+func (q *Queries) SearchPublicAndNonAdultNonAdVideosCaseSensitive(ctx context.Context, arg SearchPublicAndNonAdultNonAdVideosCaseSensitiveParams) ([]Video, error) {
+	rows, err := q.db.QueryContext(ctx, searchPublicAndNonAdultNonAdVideosCaseSensitive,
+		arg.TitleKeyword,
+		arg.DescriptionKeyword,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.VideoUrl,
+			&i.ThumbnailImageUrl,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsPrivate,
+			&i.IsAdult,
+			&i.IsAd,
+			&i.UploaderID,
+			&i.WatchCount,
+			&i.IsExternalCutout,
+			&i.DurationMs,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.PreviewUrl,
+			&i.ThumbnailVttUrl,
+			&i.WatchDurationTotal,
+			&i.IsDeleted,
+			&i.ProcessingStatus,
+			&i.ProcessingError,
+			&i.PublishAt,
+			&i.ExpiresAt,
+			&i.FileSizeBytes,
+			&i.DownloadCount,
+			&i.Checksum,
+			&i.ChecksumVerifiedAt,
+			&i.QrcodeUrl,
+			&i.ContentRating,
+			&i.AllowedRegions,
+			&i.Language,
+			&i.ChaptersUrl,
+			&i.AudioTracks,
+			&i.Analysis,
+			&i.HlsMasterUrl,
+			&i.DashManifestUrl,
+			&i.Version,
+			&i.IsBanned,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setVideosPrivacyByUploader = `-- name: SetVideosPrivacyByUploader :execresult
+UPDATE video SET is_private = ?, updated_at = ? WHERE uploader_id = ?
+`
+
+type SetVideosPrivacyByUploaderParams struct {
+	IsPrivate  bool
+	UpdatedAt  time.Time
+	UploaderID string
+}
+
+func (q *Queries) SetVideosPrivacyByUploader(ctx context.Context, arg SetVideosPrivacyByUploaderParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, setVideosPrivacyByUploader, arg.IsPrivate, arg.UpdatedAt, arg.UploaderID)
+}
+
+const softDeleteVideo = `-- name: SoftDeleteVideo :execresult
+UPDATE video SET is_deleted = true, updated_at = ? WHERE id = ?
+`
+
+type SoftDeleteVideoParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) SoftDeleteVideo(ctx context.Context, arg SoftDeleteVideoParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, softDeleteVideo, arg.UpdatedAt, arg.ID)
+}
+
+const subscribeChannel = `-- name: SubscribeChannel :execresult
+INSERT INTO subscription (user_id, channel_id) VALUES (?, ?)
+`
+
+type SubscribeChannelParams struct {
+	UserID    string
+	ChannelID string
+}
+
+func (q *Queries) SubscribeChannel(ctx context.Context, arg SubscribeChannelParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, subscribeChannel, arg.UserID, arg.ChannelID)
+}
+
+const subtractUserStorageUsage = `-- name: SubtractUserStorageUsage :execresult
+UPDATE user_storage_quotas SET used_bytes = GREATEST(used_bytes - ?, 0) WHERE user_id = ?
+`
+
+type SubtractUserStorageUsageParams struct {
+	Bytes  int64
+	UserID string
+}
+
+func (q *Queries) SubtractUserStorageUsage(ctx context.Context, arg SubtractUserStorageUsageParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, subtractUserStorageUsage, arg.Bytes, arg.UserID)
+}
+
+const touchPlaylist = `-- name: TouchPlaylist :execresult
+UPDATE playlist SET updated_at = ? WHERE id = ?
+`
+
+type TouchPlaylistParams struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) TouchPlaylist(ctx context.Context, arg TouchPlaylistParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, touchPlaylist, arg.UpdatedAt, arg.ID)
+}
+
+const unSubscribeChannel = `-- name: UnSubscribeChannel :execresult
+DELETE FROM subscription WHERE user_id = ? AND channel_id = ?
+`
+
+type UnSubscribeChannelParams struct {
+	UserID    string
+	ChannelID string
+}
+
+func (q *Queries) UnSubscribeChannel(ctx context.Context, arg UnSubscribeChannelParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, unSubscribeChannel, arg.UserID, arg.ChannelID)
+}
+
+const updateVideoAllowedRegions = `-- name: UpdateVideoAllowedRegions :execresult
+UPDATE video SET allowed_regions = ? WHERE id = ?
+`
+
+type UpdateVideoAllowedRegionsParams struct {
+	AllowedRegions sql.NullString
+	ID             string
+}
+
+func (q *Queries) UpdateVideoAllowedRegions(ctx context.Context, arg UpdateVideoAllowedRegionsParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoAllowedRegions, arg.AllowedRegions, arg.ID)
+}
+
+const updateVideoAnalysis = `-- name: UpdateVideoAnalysis :execresult
+UPDATE video SET analysis = ? WHERE id = ?
+`
+
+type UpdateVideoAnalysisParams struct {
+	Analysis sql.NullString
+	ID       string
+}
+
+func (q *Queries) UpdateVideoAnalysis(ctx context.Context, arg UpdateVideoAnalysisParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoAnalysis, arg.Analysis, arg.ID)
+}
+
+const updateVideoChaptersURL = `-- name: UpdateVideoChaptersURL :execresult
+UPDATE video SET chapters_url = ? WHERE id = ?
+`
+
+type UpdateVideoChaptersURLParams struct {
+	ChaptersUrl sql.NullString
+	ID          string
+}
+
+func (q *Queries) UpdateVideoChaptersURL(ctx context.Context, arg UpdateVideoChaptersURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoChaptersURL, arg.ChaptersUrl, arg.ID)
+}
+
+const updateVideoChecksum = `-- name: UpdateVideoChecksum :execresult
+UPDATE video SET checksum = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoChecksumParams struct {
+	Checksum  sql.NullString
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) UpdateVideoChecksum(ctx context.Context, arg UpdateVideoChecksumParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoChecksum, arg.Checksum, arg.UpdatedAt, arg.ID)
+}
+
+const updateVideoChecksumVerifiedAt = `-- name: UpdateVideoChecksumVerifiedAt :execresult
+UPDATE video SET checksum_verified_at = ? WHERE id = ?
+`
+
+type UpdateVideoChecksumVerifiedAtParams struct {
+	ChecksumVerifiedAt sql.NullTime
+	ID                 string
+}
+
+func (q *Queries) UpdateVideoChecksumVerifiedAt(ctx context.Context, arg UpdateVideoChecksumVerifiedAtParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoChecksumVerifiedAt, arg.ChecksumVerifiedAt, arg.ID)
+}
+
+const updateVideoContentRating = `-- name: UpdateVideoContentRating :execresult
+UPDATE video SET content_rating = ?, is_adult = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoContentRatingParams struct {
+	ContentRating string
+	IsAdult       bool
+	UpdatedAt     time.Time
+	ID            string
+}
+
+func (q *Queries) UpdateVideoContentRating(ctx context.Context, arg UpdateVideoContentRatingParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoContentRating,
+		arg.ContentRating,
+		arg.IsAdult,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+}
+
+const updateVideoDASHManifestURL = `-- name: UpdateVideoDASHManifestURL :execresult
+UPDATE video SET dash_manifest_url = ? WHERE id = ?
+`
+
+type UpdateVideoDASHManifestURLParams struct {
+	DashManifestUrl sql.NullString
+	ID              string
+}
+
+func (q *Queries) UpdateVideoDASHManifestURL(ctx context.Context, arg UpdateVideoDASHManifestURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoDASHManifestURL, arg.DashManifestUrl, arg.ID)
+}
+
+const updateVideoExpiresAt = `-- name: UpdateVideoExpiresAt :execresult
+UPDATE video SET expires_at = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoExpiresAtParams struct {
+	ExpiresAt sql.NullTime
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) UpdateVideoExpiresAt(ctx context.Context, arg UpdateVideoExpiresAtParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoExpiresAt, arg.ExpiresAt, arg.UpdatedAt, arg.ID)
+}
+
+const updateVideoHLSMasterURL = `-- name: UpdateVideoHLSMasterURL :execresult
+UPDATE video SET hls_master_url = ? WHERE id = ?
+`
+
+type UpdateVideoHLSMasterURLParams struct {
+	HlsMasterUrl sql.NullString
+	ID           string
+}
+
+func (q *Queries) UpdateVideoHLSMasterURL(ctx context.Context, arg UpdateVideoHLSMasterURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoHLSMasterURL, arg.HlsMasterUrl, arg.ID)
+}
+
+const updateVideoMetadata = `-- name: UpdateVideoMetadata :execresult
+UPDATE video SET title = ?, description = ?, is_private = ?, language = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?
+`
+
+type UpdateVideoMetadataParams struct {
+	Title       string
+	Description sql.NullString
+	IsPrivate   bool
+	Language    string
+	UpdatedAt   time.Time
+	ID          string
+	Version     int32
+}
+
+func (q *Queries) UpdateVideoMetadata(ctx context.Context, arg UpdateVideoMetadataParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoMetadata,
+		arg.Title,
+		arg.Description,
+		arg.IsPrivate,
+		arg.Language,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.Version,
+	)
+}
+
+const updateVideoPreviewURL = `-- name: UpdateVideoPreviewURL :execresult
+UPDATE video SET preview_url = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoPreviewURLParams struct {
+	PreviewUrl sql.NullString
+	UpdatedAt  time.Time
+	ID         string
+}
+
+func (q *Queries) UpdateVideoPreviewURL(ctx context.Context, arg UpdateVideoPreviewURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoPreviewURL, arg.PreviewUrl, arg.UpdatedAt, arg.ID)
+}
+
+const updateVideoProcessingStatus = `-- name: UpdateVideoProcessingStatus :execresult
+UPDATE video SET processing_status = ?, processing_error = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoProcessingStatusParams struct {
+	ProcessingStatus string
+	ProcessingError  sql.NullString
+	UpdatedAt        time.Time
+	ID               string
+}
+
+func (q *Queries) UpdateVideoProcessingStatus(ctx context.Context, arg UpdateVideoProcessingStatusParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoProcessingStatus,
+		arg.ProcessingStatus,
+		arg.ProcessingError,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+}
+
+const updateVideoQRCodeURL = `-- name: UpdateVideoQRCodeURL :execresult
+UPDATE video SET qrcode_url = ? WHERE id = ?
+`
+
+type UpdateVideoQRCodeURLParams struct {
+	QrcodeUrl sql.NullString
+	ID        string
+}
+
+func (q *Queries) UpdateVideoQRCodeURL(ctx context.Context, arg UpdateVideoQRCodeURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoQRCodeURL, arg.QrcodeUrl, arg.ID)
+}
+
+const updateVideoThumbnailImageURL = `-- name: UpdateVideoThumbnailImageURL :execresult
+UPDATE video SET thumbnail_image_url = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoThumbnailImageURLParams struct {
+	ThumbnailImageUrl string
+	UpdatedAt         time.Time
+	ID                string
+}
+
+func (q *Queries) UpdateVideoThumbnailImageURL(ctx context.Context, arg UpdateVideoThumbnailImageURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoThumbnailImageURL, arg.ThumbnailImageUrl, arg.UpdatedAt, arg.ID)
+}
+
+const updateVideoThumbnailVTTURL = `-- name: UpdateVideoThumbnailVTTURL :execresult
+UPDATE video SET thumbnail_vtt_url = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoThumbnailVTTURLParams struct {
+	ThumbnailVttUrl sql.NullString
+	UpdatedAt       time.Time
+	ID              string
+}
+
+func (q *Queries) UpdateVideoThumbnailVTTURL(ctx context.Context, arg UpdateVideoThumbnailVTTURLParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoThumbnailVTTURL, arg.ThumbnailVttUrl, arg.UpdatedAt, arg.ID)
+}
+
+const updateVideoUploader = `-- name: UpdateVideoUploader :execresult
+UPDATE video SET uploader_id = ?, qrcode_url = NULL, updated_at = ? WHERE id = ?
+`
+
+type UpdateVideoUploaderParams struct {
+	UploaderID string
+	UpdatedAt  time.Time
+	ID         string
+}
+
+func (q *Queries) UpdateVideoUploader(ctx context.Context, arg UpdateVideoUploaderParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateVideoUploader, arg.UploaderID, arg.UpdatedAt, arg.ID)
+}
+
+const updateWebhookDeliveryAttempt = `-- name: UpdateWebhookDeliveryAttempt :execresult
+UPDATE webhook_deliveries SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?
+`
+
+type UpdateWebhookDeliveryAttemptParams struct {
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     sql.NullString
+	ID            int64
+}
+
+func (q *Queries) UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateWebhookDeliveryAttempt,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.LastError,
+		arg.ID,
+	)
+}
+
+const upsertReaction = `-- name: UpsertReaction :execresult
+INSERT INTO reactions (id, video_id, user_id, reaction, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE reaction = VALUES(reaction), updated_at = VALUES(updated_at)
+`
+
+type UpsertReactionParams struct {
+	ID        string
+	VideoID   string
+	UserID    string
+	Reaction  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) UpsertReaction(ctx context.Context, arg UpsertReactionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertReaction,
+		arg.ID,
+		arg.VideoID,
+		arg.UserID,
+		arg.Reaction,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+}
+
+const upsertSubtitleTrack = `-- name: UpsertSubtitleTrack :execresult
+INSERT INTO subtitle_tracks (id, video_id, language, format, url, created_at) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE format = VALUES(format), url = VALUES(url)
+`
+
+type UpsertSubtitleTrackParams struct {
+	ID        string
+	VideoID   string
+	Language  string
+	Format    string
+	Url       string
+	CreatedAt time.Time
+}
+
+func (q *Queries) UpsertSubtitleTrack(ctx context.Context, arg UpsertSubtitleTrackParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertSubtitleTrack,
+		arg.ID,
+		arg.VideoID,
+		arg.Language,
+		arg.Format,
+		arg.Url,
+		arg.CreatedAt,
+	)
+}
+
+const upsertTag = `-- name: UpsertTag :execresult
+INSERT INTO tag (tag_name) VALUES (?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+`
+
+func (q *Queries) UpsertTag(ctx context.Context, tagName string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertTag, tagName)
+}
+
+const upsertVideoDescription = `-- name: UpsertVideoDescription :execresult
+INSERT INTO video_descriptions (video_id, language, description) VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE description = VALUES(description)
+`
+
+type UpsertVideoDescriptionParams struct {
+	VideoID     string
+	Language    string
+	Description string
+}
+
+func (q *Queries) UpsertVideoDescription(ctx context.Context, arg UpsertVideoDescriptionParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, upsertVideoDescription, arg.VideoID, arg.Language, arg.Description)
 }
@@ -19,15 +19,124 @@ SELECT u.id FROM user AS u JOIN subscription AS s ON u.id = s.channel_id WHERE s
 -- name: GetVideo :one
 SELECT * FROM video WHERE id = ? LIMIT 1;
 
--- name: GetPublicAndNonAdultNonAdVideos :many
-SELECT * FROM video WHERE is_private   = false AND is_adult = false AND is_ad = false;
+-- name: GetVideosByIDs :many
+SELECT * FROM video WHERE id IN (sqlc.slice('ids')) AND is_deleted = false;
+
+-- name: GetPublicNonAdVideosByContentRatings :many
+SELECT * FROM video WHERE is_private = false AND content_rating IN (sqlc.slice('content_ratings')) AND is_ad = false AND is_deleted = false AND processing_status = 'ready';
+
+-- name: GetPublicAndNonAdultNonAdVideosSorted :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready'
+ORDER BY
+    CASE WHEN sqlc.arg(sort_order) = 'created_at_desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg(sort_order) = 'created_at_asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg(sort_order) = 'watch_count_desc' THEN watch_count END DESC,
+    CASE WHEN sqlc.arg(sort_order) = 'title_asc' THEN title END ASC,
+    id DESC;
+
+-- name: GetPublicAndNonAdultNonAdVideosPaged :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?;
+
+-- name: GetPublicAndNonAdultNonAdVideosFirstPage :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' ORDER BY created_at DESC, id DESC LIMIT ?;
+
+-- name: GetPublicAndNonAdultNonAdVideosAfterCursor :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (created_at < sqlc.arg(created_at) OR (created_at = sqlc.arg(created_at) AND id < sqlc.arg(id))) ORDER BY created_at DESC, id DESC LIMIT ?;
+
+-- name: CountPublicAndNonAdultNonAdVideos :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready';
+
+-- name: GetPublicAndNonAdultNonAdVideosInRange :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND created_at BETWEEN sqlc.arg(from_time) AND sqlc.arg(to_time) ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?;
+
+-- name: CountPublicAndNonAdultNonAdVideosInRange :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND created_at BETWEEN sqlc.arg(from_time) AND sqlc.arg(to_time);
+
+-- name: SearchPublicAndNonAdultNonAdVideos :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE sqlc.arg(title_keyword) OR description LIKE sqlc.arg(description_keyword)) ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?;
+
+-- name: CountSearchPublicAndNonAdultNonAdVideos :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE sqlc.arg(title_keyword) OR description LIKE sqlc.arg(description_keyword));
+
+-- name: SearchPublicAndNonAdultNonAdVideosCaseSensitive :many
+SELECT * FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE BINARY sqlc.arg(title_keyword) OR description LIKE BINARY sqlc.arg(description_keyword)) ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?;
+
+-- name: CountSearchPublicAndNonAdultNonAdVideosCaseSensitive :one
+SELECT COUNT(*) FROM video WHERE is_private = false AND content_rating <> 'adult' AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND (title LIKE BINARY sqlc.arg(title_keyword) OR description LIKE BINARY sqlc.arg(description_keyword));
+
+-- name: GetPublicAndNonAdultNonAdVideosByAnyTags :many
+SELECT v.* FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (sqlc.slice('tag_names'))
+    )
+ORDER BY v.created_at DESC, v.id DESC LIMIT ? OFFSET ?;
+
+-- name: CountPublicAndNonAdultNonAdVideosByAnyTags :one
+SELECT COUNT(*) FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (sqlc.slice('tag_names'))
+    );
+
+-- name: GetPublicAndNonAdultNonAdVideosByAllTags :many
+SELECT v.* FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (sqlc.slice('tag_names'))
+        GROUP BY vt.video_id HAVING COUNT(DISTINCT t.id) = sqlc.arg(tag_count)
+    )
+ORDER BY v.created_at DESC, v.id DESC LIMIT ? OFFSET ?;
+
+-- name: CountPublicAndNonAdultNonAdVideosByAllTags :one
+SELECT COUNT(*) FROM video v
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id IN (
+        SELECT vt.video_id FROM video_tags vt INNER JOIN tag t ON vt.tag_id = t.id WHERE t.tag_name IN (sqlc.slice('tag_names'))
+        GROUP BY vt.video_id HAVING COUNT(DISTINCT t.id) = sqlc.arg(tag_count)
+    );
 
 -- name: GetPublicAndNonAdByUploaderID :many
-SELECT * FROM video WHERE is_private   = false AND is_ad = false AND uploader_id = ?;
+SELECT * FROM video WHERE is_private   = false AND is_ad = false AND is_deleted = false AND processing_status = 'ready' AND uploader_id = ?;
 
 -- name: GetVideoComments :many
 SELECT c.* , u.name  FROM comment c INNER JOIN user u ON c.user_id = u.id WHERE video_id = ?;
 
+-- name: GetVideoCommentsPaged :many
+SELECT c.* , u.name  FROM comment c INNER JOIN user u ON c.user_id = u.id WHERE video_id = ? ORDER BY c.created_at ASC, c.id ASC LIMIT ? OFFSET ?;
+
+-- name: CountVideoComments :one
+SELECT COUNT(*) FROM comment WHERE video_id = ?;
+
+-- name: GetComment :one
+SELECT * FROM comment WHERE id = ? LIMIT 1;
+
+-- name: ListReplyCommentIDs :many
+SELECT id FROM comment WHERE parent_comment_id IN (sqlc.slice('parent_ids')) FOR UPDATE;
+
+-- name: DeleteComments :execresult
+DELETE FROM comment WHERE id IN (sqlc.slice('ids'));
+
 -- name: GetVideoLikes :many
 SELECT * FROM like_dislike WHERE video_id = ? AND is_like = true;
 
@@ -44,8 +153,10 @@ FROM
     INNER JOIN video_tags vt ON v.id = vt.video_id
     INNER JOIN tag t ON vt.tag_id = t.id
 WHERE
-    v.is_adult = false
+    v.content_rating <> 'adult'
     AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
     AND v.is_private = false;
 
 -- name: GetAllVideosTagsByUserID :many
@@ -59,15 +170,30 @@ FROM
     INNER JOIN tag t ON vt.tag_id = t.id
 WHERE
     v.uploader_id = ?
-    AND v.is_adult = false
+    AND v.content_rating <> 'adult'
     AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
     AND v.is_private = false;
 
+-- name: GetVideosTagsByVideoIDs :many
+SELECT
+    vt.video_id,
+    t.id AS tag_id,
+    t.tag_name
+FROM
+    video_tags vt
+    INNER JOIN tag t ON vt.tag_id = t.id
+WHERE
+    vt.video_id IN (sqlc.slice('video_ids'));
+
 -- name: GetVideoTags :many
 SELECT t.id, t.tag_name FROM tag AS t JOIN video_tags AS vt ON t.id = vt.tag_id WHERE vt.video_id = ?;
 
+-- アップロードを再送しても同じIDの動画が重複しないように、既存の行はupdated_atだけ更新する
+-- 影響を受けた行の数は追加した場合は1、既存の行を更新した場合は2になる
 -- name: CreateVideo :execresult
-INSERT INTO video (id, title, description, video_url, thumbnail_image_url, is_private,is_external_cutout , is_adult, is_ad, uploader_id, created_at,updated_at,watch_count) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO video (id, title, description, video_url, thumbnail_image_url, is_private,is_external_cutout , content_rating, is_ad, uploader_id, created_at,updated_at,watch_count,duration_ms,width,height,bitrate,processing_status,publish_at,file_size_bytes,audio_tracks) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE updated_at = NOW();
 
 -- name: CreateVideoTags :execresult
 INSERT INTO video_tags (video_id, tag_id) VALUES (?, ?);
@@ -76,10 +202,414 @@ INSERT INTO video_tags (video_id, tag_id) VALUES (?, ?);
 INSERT INTO tag (tag_name) VALUES (?);
 
 -- name: CreateComment :execresult
-INSERT INTO comment (id, video_id, text, user_id, created_at,updated_at) VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO comment (id, video_id, text, user_id, created_at,updated_at,parent_comment_id) VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetWatchCount :one
 SELECT watch_count FROM video WHERE id = ?;
 
 -- name: IncrementWatchCount :execresult
-UPDATE video SET watch_count = watch_count + 1 WHERE id = ?;
\ No newline at end of file
+UPDATE video SET watch_count = watch_count + 1 WHERE id = ?;
+
+-- name: GetDownloadCount :one
+SELECT download_count FROM video WHERE id = ?;
+
+-- name: IncrementDownloadCount :execresult
+UPDATE video SET download_count = download_count + 1 WHERE id = ?;
+-- name: UpdateVideoPreviewURL :execresult
+UPDATE video SET preview_url = ?, updated_at = ? WHERE id = ?;
+
+-- name: UpdateVideoThumbnailVTTURL :execresult
+UPDATE video SET thumbnail_vtt_url = ?, updated_at = ? WHERE id = ?;
+
+-- name: UpdateVideoThumbnailImageURL :execresult
+UPDATE video SET thumbnail_image_url = ?, updated_at = ? WHERE id = ?;
+
+-- name: AddWatchCount :execresult
+UPDATE video SET watch_count = watch_count + ? WHERE id = ?;
+
+-- name: AddWatchDuration :execresult
+UPDATE video SET watch_duration_total = watch_duration_total + ? WHERE id = ?;
+
+-- name: GetWatchStats :one
+SELECT watch_count, watch_duration_total FROM video WHERE id = ?;
+
+-- name: UpsertReaction :execresult
+INSERT INTO reactions (id, video_id, user_id, reaction, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE reaction = VALUES(reaction), updated_at = VALUES(updated_at);
+
+-- name: GetReactionCounts :one
+SELECT
+    COUNT(CASE WHEN reaction = 'like' THEN 1 END) AS likes,
+    COUNT(CASE WHEN reaction = 'dislike' THEN 1 END) AS dislikes
+FROM reactions WHERE video_id = ?;
+
+-- name: CreatePlaylist :execresult
+INSERT INTO playlist (id, name, description, user_id, is_public, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetPlaylist :one
+SELECT * FROM playlist WHERE id = ? LIMIT 1;
+
+-- name: GetPlaylistForUpdate :one
+SELECT * FROM playlist WHERE id = ? LIMIT 1 FOR UPDATE;
+
+-- name: GetPlaylistVideoIDs :many
+SELECT video_id FROM playlist_videos WHERE playlist_id = ? ORDER BY position ASC;
+
+-- name: CountPlaylistVideos :one
+SELECT COUNT(*) FROM playlist_videos WHERE playlist_id = ?;
+
+-- name: AddPlaylistVideo :execresult
+INSERT INTO playlist_videos (playlist_id, video_id, position)
+SELECT sqlc.arg(playlist_id), sqlc.arg(video_id), COALESCE(MAX(pv.position) + 1, 0) FROM playlist_videos pv WHERE pv.playlist_id = sqlc.arg(playlist_id);
+
+-- name: RemovePlaylistVideo :execresult
+DELETE FROM playlist_videos WHERE playlist_id = ? AND video_id = ?;
+
+-- name: ReorderPlaylistVideos :execresult
+UPDATE playlist_videos SET position = FIELD(video_id, sqlc.slice('video_ids')) - 1 WHERE playlist_id = sqlc.arg(playlist_id) AND video_id IN (sqlc.slice('video_ids'));
+
+-- name: DeletePlaylistVideos :execresult
+DELETE FROM playlist_videos WHERE playlist_id = ?;
+
+-- name: DeletePlaylist :execresult
+DELETE FROM playlist WHERE id = ?;
+
+-- name: TouchPlaylist :execresult
+UPDATE playlist SET updated_at = ? WHERE id = ?;
+
+-- name: CreateBookmark :execresult
+INSERT IGNORE INTO bookmarks (id, user_id, video_id, created_at) VALUES (?, ?, ?, ?);
+
+-- name: DeleteBookmark :execresult
+DELETE FROM bookmarks WHERE user_id = ? AND video_id = ?;
+
+-- name: CountBookmark :one
+SELECT COUNT(*) FROM bookmarks WHERE user_id = ? AND video_id = ?;
+
+-- name: CountBookmarksByUser :one
+SELECT COUNT(*) FROM bookmarks WHERE user_id = ?;
+
+-- name: GetBookmarkedVideosByUser :many
+SELECT v.* FROM video v INNER JOIN bookmarks b ON v.id = b.video_id
+WHERE b.user_id = ? AND v.is_deleted = false AND (v.is_private = false OR v.uploader_id = b.user_id)
+ORDER BY b.created_at DESC, v.id DESC LIMIT ? OFFSET ?;
+
+-- name: CountBookmarkedVideosByUser :one
+SELECT COUNT(*) FROM video v INNER JOIN bookmarks b ON v.id = b.video_id
+WHERE b.user_id = ? AND v.is_deleted = false AND (v.is_private = false OR v.uploader_id = b.user_id);
+
+-- name: GetRelatedVideos :many
+SELECT
+    sqlc.embed(v),
+    CAST(SUM(vt.tag_id IN (sqlc.slice('tag_ids'))) / (CAST(sqlc.arg(source_tag_count) AS SIGNED) + COUNT(*) - SUM(vt.tag_id IN (sqlc.slice('tag_ids')))) AS DOUBLE) AS similarity,
+    GROUP_CONCAT(t.tag_name SEPARATOR '\n') AS tag_names
+FROM
+    video v
+    INNER JOIN video_tags vt ON v.id = vt.video_id
+    INNER JOIN tag t ON vt.tag_id = t.id
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND v.id <> sqlc.arg(video_id)
+    AND v.id IN (
+        SELECT vt2.video_id FROM video_tags vt2 WHERE vt2.tag_id IN (sqlc.slice('tag_ids'))
+    )
+GROUP BY v.id
+ORDER BY similarity DESC, v.created_at DESC, v.id DESC
+LIMIT ?;
+
+-- name: CreateWatchCountSnapshot :execresult
+INSERT INTO watch_count_snapshots (video_id, watch_count, snapshot_time)
+SELECT v.id, v.watch_count, sqlc.arg(snapshot_time) FROM video v WHERE v.id = sqlc.arg(video_id);
+
+-- name: DeleteWatchCountSnapshotsBefore :execresult
+DELETE FROM watch_count_snapshots WHERE snapshot_time < ?;
+
+-- name: GetTrendingVideos :many
+SELECT
+    sqlc.embed(v),
+    CAST(MAX(s.watch_count) - MIN(s.watch_count) AS SIGNED) AS watch_count_delta
+FROM
+    watch_count_snapshots s
+    INNER JOIN video v ON s.video_id = v.id
+WHERE
+    v.is_private = false
+    AND v.content_rating <> 'adult'
+    AND v.is_ad = false
+    AND v.is_deleted = false
+    AND v.processing_status = 'ready'
+    AND s.snapshot_time >= sqlc.arg(window_start)
+GROUP BY v.id
+HAVING watch_count_delta > 0
+ORDER BY watch_count_delta DESC, v.id DESC
+LIMIT ?;
+
+-- name: SoftDeleteVideo :execresult
+UPDATE video SET is_deleted = true, updated_at = ? WHERE id = ?;
+
+-- name: BanVideo :execresult
+UPDATE video SET is_deleted = true, is_banned = true, updated_at = ? WHERE id = ?;
+
+-- name: RestoreVideo :execresult
+UPDATE video SET is_deleted = false, updated_at = ? WHERE id = ? AND is_banned = false;
+
+-- name: DeleteLikeDislikesByVideoID :execresult
+DELETE FROM like_dislike WHERE like_dislike.video_id = sqlc.arg(video_id) OR like_dislike.comment_id IN (SELECT c.id FROM comment c WHERE c.video_id = sqlc.arg(comment_video_id));
+
+-- name: DeleteReportsByVideoID :execresult
+DELETE FROM report WHERE report.video_id = sqlc.arg(video_id) OR report.comment_id IN (SELECT c.id FROM comment c WHERE c.video_id = sqlc.arg(comment_video_id));
+
+-- name: DeleteCommentsByVideoID :execresult
+DELETE FROM comment WHERE video_id = ?;
+
+-- name: DeleteHistoryByVideoID :execresult
+DELETE FROM history WHERE video_id = ?;
+
+-- name: DeletePlaylistVideosByVideoID :execresult
+DELETE FROM playlist_videos WHERE video_id = ?;
+
+-- name: DeleteVideoCategoriesByVideoID :execresult
+DELETE FROM video_category WHERE video_id = ?;
+
+-- name: DeleteVideoTagsByVideoID :execresult
+DELETE FROM video_tags WHERE video_id = ?;
+
+-- name: DeleteVideoDescriptionsByVideoID :execresult
+DELETE FROM video_descriptions WHERE video_id = ?;
+
+-- name: DeleteSubtitleTracksByVideoID :execresult
+DELETE FROM subtitle_tracks WHERE video_id = ?;
+
+-- name: CreateHLSEncryptionKey :execresult
+INSERT INTO hls_encryption_keys (key_id, video_id, key_bytes, iv, created_at) VALUES (?, ?, ?, ?, ?);
+
+-- name: GetHLSEncryptionKey :one
+SELECT * FROM hls_encryption_keys WHERE key_id = ?;
+
+-- name: CountHLSEncryptionKeysByVideoID :one
+SELECT COUNT(*) FROM hls_encryption_keys WHERE video_id = ?;
+
+-- name: DeleteHLSEncryptionKeysByVideoID :execresult
+DELETE FROM hls_encryption_keys WHERE video_id = ?;
+
+-- name: DeleteThumbnailVariantsByVideoID :execresult
+DELETE FROM thumbnail_variants WHERE video_id = ?;
+
+-- name: DeleteVideoReportsByVideoID :execresult
+DELETE FROM video_reports WHERE video_id = ?;
+
+-- name: DeleteReactionsByVideoID :execresult
+DELETE FROM reactions WHERE video_id = ?;
+
+-- name: DeleteBookmarksByVideoID :execresult
+DELETE FROM bookmarks WHERE video_id = ?;
+
+-- name: DeleteWatchCountSnapshotsByVideoID :execresult
+DELETE FROM watch_count_snapshots WHERE video_id = ?;
+
+-- name: DeleteVideo :execresult
+DELETE FROM video WHERE id = ?;
+
+-- name: UpdateVideoProcessingStatus :execresult
+UPDATE video SET processing_status = ?, processing_error = ?, updated_at = ? WHERE id = ?;
+
+-- name: ListScheduledVideosToPublish :many
+SELECT id FROM video WHERE publish_at IS NOT NULL AND publish_at <= ? AND is_private = true AND is_deleted = false;
+
+-- name: PublishScheduledVideo :execresult
+UPDATE video SET is_private = false, publish_at = NULL, updated_at = ? WHERE id = ? AND is_private = true AND publish_at IS NOT NULL;
+
+-- name: ListExpiredVideos :many
+SELECT id FROM video WHERE expires_at IS NOT NULL AND expires_at <= ? AND is_private = false AND is_deleted = false;
+
+-- name: ExpireVideo :execresult
+UPDATE video SET is_private = true, updated_at = ? WHERE id = ? AND is_private = false;
+
+-- name: UpdateVideoExpiresAt :execresult
+UPDATE video SET expires_at = ?, updated_at = ? WHERE id = ?;
+
+-- name: GetVideoUploaderID :one
+SELECT uploader_id FROM video WHERE id = ? LIMIT 1;
+
+-- 論理削除した動画は見つからないものとして扱う
+-- name: GetUndeletedVideo :one
+SELECT * FROM video WHERE id = ? AND is_deleted = false LIMIT 1;
+
+-- name: GetVideoForUpdate :one
+SELECT * FROM video WHERE id = ? LIMIT 1 FOR UPDATE;
+
+-- name: UpdateVideoMetadata :execresult
+UPDATE video SET title = ?, description = ?, is_private = ?, language = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?;
+
+-- name: UpsertTag :execresult
+INSERT INTO tag (tag_name) VALUES (?) ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id);
+
+-- name: DeleteVideoTag :execresult
+DELETE FROM video_tags WHERE video_id = ? AND tag_id = ?;
+
+-- name: UpdateVideoUploader :execresult
+UPDATE video SET uploader_id = ?, qrcode_url = NULL, updated_at = ? WHERE id = ?;
+
+-- name: CreateAuditLog :execresult
+INSERT INTO audit_logs (entity_type, entity_id, action, actor_id, before_json, after_json, occurred_at) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetAuditLogsByEntity :many
+SELECT * FROM audit_logs WHERE entity_type = ? AND entity_id = ? ORDER BY occurred_at DESC, id DESC LIMIT ? OFFSET ?;
+
+-- name: ListVideosByUploaderForUpdate :many
+SELECT * FROM video WHERE uploader_id = ? FOR UPDATE;
+
+-- name: SetVideosPrivacyByUploader :execresult
+UPDATE video SET is_private = ?, updated_at = ? WHERE uploader_id = ?;
+
+-- name: GetUserStorageQuota :one
+SELECT used_bytes, quota_bytes FROM user_storage_quotas WHERE user_id = ?;
+
+-- name: AddUserStorageUsage :execresult
+INSERT INTO user_storage_quotas (user_id, used_bytes, quota_bytes) VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE used_bytes = used_bytes + VALUES(used_bytes);
+
+-- name: SubtractUserStorageUsage :execresult
+UPDATE user_storage_quotas SET used_bytes = GREATEST(used_bytes - sqlc.arg(bytes), 0) WHERE user_id = sqlc.arg(user_id);
+
+-- name: UpdateVideoChecksum :execresult
+UPDATE video SET checksum = ?, updated_at = ? WHERE id = ?;
+
+-- name: ListVideosForIntegrityCheck :many
+SELECT id FROM video WHERE checksum IS NOT NULL AND processing_status = 'ready' ORDER BY checksum_verified_at LIMIT ?;
+
+-- name: UpdateVideoChecksumVerifiedAt :execresult
+UPDATE video SET checksum_verified_at = ? WHERE id = ?;
+
+-- name: CreateWebhookTarget :execresult
+INSERT INTO webhook_targets (id, url, secret, created_at) VALUES (?, ?, ?, ?);
+
+-- name: ListWebhookTargets :many
+SELECT id, url, secret FROM webhook_targets ORDER BY created_at;
+
+-- name: CreateWebhookDelivery :execresult
+INSERT INTO webhook_deliveries (target_id, event_type, video_id, body, attempts, next_attempt_at, last_error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListDueWebhookDeliveries :many
+SELECT d.id, d.event_type, d.video_id, d.body, d.attempts, t.id AS target_id, t.url, t.secret
+FROM webhook_deliveries d
+JOIN webhook_targets t ON d.target_id = t.id
+WHERE d.attempts < ? AND d.next_attempt_at <= ?
+ORDER BY d.next_attempt_at
+LIMIT ?
+FOR UPDATE OF d SKIP LOCKED;
+
+-- name: ClaimWebhookDeliveries :execresult
+UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (sqlc.slice('ids'));
+
+-- name: UpdateWebhookDeliveryAttempt :execresult
+UPDATE webhook_deliveries SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?;
+
+-- name: DeleteWebhookDelivery :execresult
+DELETE FROM webhook_deliveries WHERE id = ?;
+
+-- name: UpdateVideoQRCodeURL :execresult
+UPDATE video SET qrcode_url = ? WHERE id = ?;
+
+-- name: UpdateVideoAllowedRegions :execresult
+UPDATE video SET allowed_regions = ? WHERE id = ?;
+
+-- name: UpdateVideoChaptersURL :execresult
+UPDATE video SET chapters_url = ? WHERE id = ?;
+
+-- name: UpdateVideoAnalysis :execresult
+UPDATE video SET analysis = ? WHERE id = ?;
+
+-- name: UpdateVideoHLSMasterURL :execresult
+UPDATE video SET hls_master_url = ? WHERE id = ?;
+
+-- name: UpdateVideoDASHManifestURL :execresult
+UPDATE video SET dash_manifest_url = ? WHERE id = ?;
+
+-- name: UpsertVideoDescription :execresult
+INSERT INTO video_descriptions (video_id, language, description) VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE description = VALUES(description);
+
+-- name: GetVideoDescriptions :many
+SELECT language, description FROM video_descriptions WHERE video_id = ?;
+
+-- name: UpsertSubtitleTrack :execresult
+INSERT INTO subtitle_tracks (id, video_id, language, format, url, created_at) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE format = VALUES(format), url = VALUES(url);
+
+-- name: GetSubtitleTrack :one
+SELECT * FROM subtitle_tracks WHERE id = ? LIMIT 1;
+
+-- name: GetSubtitleTrackByLanguage :one
+SELECT * FROM subtitle_tracks WHERE video_id = ? AND language = ? LIMIT 1;
+
+-- name: GetSubtitleTracksByVideoID :many
+SELECT * FROM subtitle_tracks WHERE video_id = ? ORDER BY language;
+
+-- name: DeleteSubtitleTrack :execresult
+DELETE FROM subtitle_tracks WHERE id = ?;
+
+-- name: CreateThumbnailVariant :execresult
+INSERT INTO thumbnail_variants (id, video_id, thumbnail_url, assignment_weight, created_at) VALUES (?, ?, ?, ?, ?);
+
+-- name: GetThumbnailVariantsByVideoID :many
+SELECT * FROM thumbnail_variants WHERE video_id = ? ORDER BY created_at, id;
+
+-- name: IncrementThumbnailVariantImpressions :execresult
+UPDATE thumbnail_variants SET impressions = impressions + 1 WHERE id = ?;
+
+-- name: IncrementThumbnailVariantClicks :execresult
+UPDATE thumbnail_variants SET clicks = clicks + 1 WHERE id = ?;
+
+-- name: CreateVideoReport :execresult
+INSERT IGNORE INTO video_reports (id, video_id, reporter_id, reason, created_at) VALUES (?, ?, ?, ?, ?);
+
+-- name: GetVideoReportsByVideoID :many
+SELECT * FROM video_reports WHERE video_id = ? ORDER BY created_at DESC, id;
+
+-- name: GetVideoReportsByStatus :many
+SELECT * FROM video_reports WHERE status = ? ORDER BY created_at, id LIMIT ? OFFSET ?;
+
+-- name: ResolveVideoReports :execresult
+UPDATE video_reports SET status = ? WHERE video_id = ? AND status = 'pending';
+
+-- name: UpdateVideoContentRating :execresult
+UPDATE video SET content_rating = ?, is_adult = ?, updated_at = ? WHERE id = ?;
+
+-- 監査のため、moderation_recordsの行は更新も削除もしない
+-- name: CreateModerationRecord :execresult
+INSERT INTO moderation_records (video_id, moderator_id, action, reason, created_at) VALUES (?, ?, ?, ?, ?);
+
+-- name: GetModerationRecordsByVideoID :many
+SELECT * FROM moderation_records WHERE video_id = ? ORDER BY created_at DESC, id DESC;
+
+-- name: GetTagByNameForUpdate :one
+SELECT * FROM tag WHERE tag_name = ? LIMIT 1 FOR UPDATE;
+
+-- name: RenameTag :execresult
+UPDATE tag SET tag_name = sqlc.arg(new_name) WHERE id = sqlc.arg(id);
+
+-- name: CountVideosByTagID :one
+SELECT COUNT(*) FROM video_tags WHERE tag_id = ?;
+
+-- 統合先のタグがすでに付いた動画では、主キーが重複するため統合元の行を消す
+-- name: DeleteDuplicateVideoTags :execresult
+DELETE s FROM video_tags s INNER JOIN video_tags t ON s.video_id = t.video_id WHERE s.tag_id = sqlc.arg(source_tag_id) AND t.tag_id = sqlc.arg(target_tag_id);
+
+-- name: MoveVideoTags :execresult
+UPDATE video_tags SET tag_id = sqlc.arg(target_tag_id) WHERE tag_id = sqlc.arg(source_tag_id);
+
+-- name: DeleteTag :execresult
+DELETE FROM tag WHERE id = ?;
+
+-- name: DeleteOrphanedTags :execresult
+DELETE FROM tag WHERE id NOT IN (SELECT DISTINCT tag_id FROM video_tags);
+
+-- name: GetTagsByNamesOrderByUsage :many
+SELECT t.tag_name, COUNT(*) AS usage_count FROM tag t INNER JOIN video_tags vt ON vt.tag_id = t.id WHERE t.tag_name IN (sqlc.slice('tag_names')) GROUP BY t.id, t.tag_name ORDER BY usage_count DESC, t.tag_name LIMIT ?;
+
+-- name: ListTagUsageCounts :many
+SELECT t.tag_name, COUNT(*) AS usage_count FROM tag t INNER JOIN video_tags vt ON vt.tag_id = t.id GROUP BY t.id, t.tag_name;
@@ -6,7 +6,9 @@ package mock_port
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/yuorei/video-server/app/domain"
@@ -35,161 +37,2411 @@ func (m *MockVideoInputPort) EXPECT() *MockVideoInputPortMockRecorder {
 	return m.recorder
 }
 
+// AnalyzeVideo mocks base method.
+func (m *MockVideoInputPort) AnalyzeVideo(arg0 context.Context, arg1, arg2 string) (*domain.VideoAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzeVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.VideoAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzeVideo indicates an expected call of AnalyzeVideo.
+func (mr *MockVideoInputPortMockRecorder) AnalyzeVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeVideo", reflect.TypeOf((*MockVideoInputPort)(nil).AnalyzeVideo), arg0, arg1, arg2)
+}
+
+// AutocompleteTags mocks base method.
+func (m *MockVideoInputPort) AutocompleteTags(arg0 context.Context, arg1 string, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutocompleteTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutocompleteTags indicates an expected call of AutocompleteTags.
+func (mr *MockVideoInputPortMockRecorder) AutocompleteTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutocompleteTags", reflect.TypeOf((*MockVideoInputPort)(nil).AutocompleteTags), arg0, arg1, arg2)
+}
+
+// BookmarkVideo mocks base method.
+func (m *MockVideoInputPort) BookmarkVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BookmarkVideo indicates an expected call of BookmarkVideo.
+func (mr *MockVideoInputPortMockRecorder) BookmarkVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkVideo", reflect.TypeOf((*MockVideoInputPort)(nil).BookmarkVideo), arg0, arg1, arg2)
+}
+
+// CheckVideoUploader mocks base method.
+func (m *MockVideoInputPort) CheckVideoUploader(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckVideoUploader", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckVideoUploader indicates an expected call of CheckVideoUploader.
+func (mr *MockVideoInputPortMockRecorder) CheckVideoUploader(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVideoUploader", reflect.TypeOf((*MockVideoInputPort)(nil).CheckVideoUploader), arg0, arg1, arg2)
+}
+
+// ConcatenateVideos mocks base method.
+func (m *MockVideoInputPort) ConcatenateVideos(arg0 context.Context, arg1 []string, arg2, arg3 string) (*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConcatenateVideos", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConcatenateVideos indicates an expected call of ConcatenateVideos.
+func (mr *MockVideoInputPortMockRecorder) ConcatenateVideos(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConcatenateVideos", reflect.TypeOf((*MockVideoInputPort)(nil).ConcatenateVideos), arg0, arg1, arg2, arg3)
+}
+
+// CreateThumbnailVariant mocks base method.
+func (m *MockVideoInputPort) CreateThumbnailVariant(arg0 context.Context, arg1, arg2 string, arg3 float64, arg4 string) (*domain.ThumbnailVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateThumbnailVariant", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*domain.ThumbnailVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateThumbnailVariant indicates an expected call of CreateThumbnailVariant.
+func (mr *MockVideoInputPortMockRecorder) CreateThumbnailVariant(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateThumbnailVariant", reflect.TypeOf((*MockVideoInputPort)(nil).CreateThumbnailVariant), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CutVideo mocks base method.
+func (m *MockVideoInputPort) CutVideo(arg0 context.Context, arg1, arg2 string, arg3, arg4 int, arg5 domain.CutOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CutVideo", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CutVideo indicates an expected call of CutVideo.
+func (mr *MockVideoInputPortMockRecorder) CutVideo(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CutVideo", reflect.TypeOf((*MockVideoInputPort)(nil).CutVideo), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// DeleteSubtitleTrack mocks base method.
+func (m *MockVideoInputPort) DeleteSubtitleTrack(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubtitleTrack", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubtitleTrack indicates an expected call of DeleteSubtitleTrack.
+func (mr *MockVideoInputPortMockRecorder) DeleteSubtitleTrack(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubtitleTrack", reflect.TypeOf((*MockVideoInputPort)(nil).DeleteSubtitleTrack), arg0, arg1, arg2)
+}
+
+// EncryptHLSSegments mocks base method.
+func (m *MockVideoInputPort) EncryptHLSSegments(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptHLSSegments", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptHLSSegments indicates an expected call of EncryptHLSSegments.
+func (mr *MockVideoInputPortMockRecorder) EncryptHLSSegments(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptHLSSegments", reflect.TypeOf((*MockVideoInputPort)(nil).EncryptHLSSegments), arg0, arg1, arg2, arg3)
+}
+
+// ExtractAudio mocks base method.
+func (m *MockVideoInputPort) ExtractAudio(arg0 context.Context, arg1, arg2, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractAudio", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtractAudio indicates an expected call of ExtractAudio.
+func (mr *MockVideoInputPortMockRecorder) ExtractAudio(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractAudio", reflect.TypeOf((*MockVideoInputPort)(nil).ExtractAudio), arg0, arg1, arg2, arg3)
+}
+
+// GenerateDownloadURL mocks base method.
+func (m *MockVideoInputPort) GenerateDownloadURL(arg0 context.Context, arg1, arg2, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDownloadURL", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateDownloadURL indicates an expected call of GenerateDownloadURL.
+func (mr *MockVideoInputPortMockRecorder) GenerateDownloadURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDownloadURL", reflect.TypeOf((*MockVideoInputPort)(nil).GenerateDownloadURL), arg0, arg1, arg2, arg3)
+}
+
+// GetAuditLogsForVideo mocks base method.
+func (m *MockVideoInputPort) GetAuditLogsForVideo(arg0 context.Context, arg1 string, arg2 domain.Page, arg3 string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsForVideo", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsForVideo indicates an expected call of GetAuditLogsForVideo.
+func (mr *MockVideoInputPortMockRecorder) GetAuditLogsForVideo(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsForVideo", reflect.TypeOf((*MockVideoInputPort)(nil).GetAuditLogsForVideo), arg0, arg1, arg2, arg3)
+}
+
+// GetAverageWatchDuration mocks base method.
+func (m *MockVideoInputPort) GetAverageWatchDuration(arg0 context.Context, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAverageWatchDuration", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAverageWatchDuration indicates an expected call of GetAverageWatchDuration.
+func (mr *MockVideoInputPortMockRecorder) GetAverageWatchDuration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAverageWatchDuration", reflect.TypeOf((*MockVideoInputPort)(nil).GetAverageWatchDuration), arg0, arg1)
+}
+
+// GetBookmarkedVideosByUser mocks base method.
+func (m *MockVideoInputPort) GetBookmarkedVideosByUser(arg0 context.Context, arg1 string, arg2 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarkedVideosByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBookmarkedVideosByUser indicates an expected call of GetBookmarkedVideosByUser.
+func (mr *MockVideoInputPortMockRecorder) GetBookmarkedVideosByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarkedVideosByUser", reflect.TypeOf((*MockVideoInputPort)(nil).GetBookmarkedVideosByUser), arg0, arg1, arg2)
+}
+
+// GetDownloadCount mocks base method.
+func (m *MockVideoInputPort) GetDownloadCount(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownloadCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownloadCount indicates an expected call of GetDownloadCount.
+func (mr *MockVideoInputPortMockRecorder) GetDownloadCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownloadCount", reflect.TypeOf((*MockVideoInputPort)(nil).GetDownloadCount), arg0, arg1)
+}
+
+// GetHLSEncryptionKey mocks base method.
+func (m *MockVideoInputPort) GetHLSEncryptionKey(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHLSEncryptionKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHLSEncryptionKey indicates an expected call of GetHLSEncryptionKey.
+func (mr *MockVideoInputPortMockRecorder) GetHLSEncryptionKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHLSEncryptionKey", reflect.TypeOf((*MockVideoInputPort)(nil).GetHLSEncryptionKey), arg0, arg1, arg2)
+}
+
+// GetModerationHistory mocks base method.
+func (m *MockVideoInputPort) GetModerationHistory(arg0 context.Context, arg1, arg2 string) ([]*domain.ModerationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.ModerationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationHistory indicates an expected call of GetModerationHistory.
+func (mr *MockVideoInputPortMockRecorder) GetModerationHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationHistory", reflect.TypeOf((*MockVideoInputPort)(nil).GetModerationHistory), arg0, arg1, arg2)
+}
+
+// GetOrGenerateQRCode mocks base method.
+func (m *MockVideoInputPort) GetOrGenerateQRCode(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrGenerateQRCode", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrGenerateQRCode indicates an expected call of GetOrGenerateQRCode.
+func (mr *MockVideoInputPortMockRecorder) GetOrGenerateQRCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrGenerateQRCode", reflect.TypeOf((*MockVideoInputPort)(nil).GetOrGenerateQRCode), arg0, arg1)
+}
+
+// GetPendingReports mocks base method.
+func (m *MockVideoInputPort) GetPendingReports(arg0 context.Context, arg1 domain.Page, arg2 string) ([]*domain.VideoReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReports", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.VideoReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReports indicates an expected call of GetPendingReports.
+func (mr *MockVideoInputPortMockRecorder) GetPendingReports(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReports", reflect.TypeOf((*MockVideoInputPort)(nil).GetPendingReports), arg0, arg1, arg2)
+}
+
+// GetReactionCounts mocks base method.
+func (m *MockVideoInputPort) GetReactionCounts(arg0 context.Context, arg1 string) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactionCounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReactionCounts indicates an expected call of GetReactionCounts.
+func (mr *MockVideoInputPortMockRecorder) GetReactionCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactionCounts", reflect.TypeOf((*MockVideoInputPort)(nil).GetReactionCounts), arg0, arg1)
+}
+
+// GetRelatedVideos mocks base method.
+func (m *MockVideoInputPort) GetRelatedVideos(arg0 context.Context, arg1 string, arg2 int) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelatedVideos", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelatedVideos indicates an expected call of GetRelatedVideos.
+func (mr *MockVideoInputPortMockRecorder) GetRelatedVideos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelatedVideos", reflect.TypeOf((*MockVideoInputPort)(nil).GetRelatedVideos), arg0, arg1, arg2)
+}
+
+// GetSubtitleTracksByVideoID mocks base method.
+func (m *MockVideoInputPort) GetSubtitleTracksByVideoID(arg0 context.Context, arg1 string) ([]*domain.SubtitleTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtitleTracksByVideoID", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.SubtitleTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtitleTracksByVideoID indicates an expected call of GetSubtitleTracksByVideoID.
+func (mr *MockVideoInputPortMockRecorder) GetSubtitleTracksByVideoID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtitleTracksByVideoID", reflect.TypeOf((*MockVideoInputPort)(nil).GetSubtitleTracksByVideoID), arg0, arg1)
+}
+
+// GetThumbnailVariantStats mocks base method.
+func (m *MockVideoInputPort) GetThumbnailVariantStats(arg0 context.Context, arg1, arg2 string) ([]domain.ThumbnailStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThumbnailVariantStats", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.ThumbnailStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThumbnailVariantStats indicates an expected call of GetThumbnailVariantStats.
+func (mr *MockVideoInputPortMockRecorder) GetThumbnailVariantStats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThumbnailVariantStats", reflect.TypeOf((*MockVideoInputPort)(nil).GetThumbnailVariantStats), arg0, arg1, arg2)
+}
+
+// GetTrendingVideos mocks base method.
+func (m *MockVideoInputPort) GetTrendingVideos(arg0 context.Context, arg1 time.Duration, arg2 int) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrendingVideos", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrendingVideos indicates an expected call of GetTrendingVideos.
+func (mr *MockVideoInputPortMockRecorder) GetTrendingVideos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrendingVideos", reflect.TypeOf((*MockVideoInputPort)(nil).GetTrendingVideos), arg0, arg1, arg2)
+}
+
+// GetUniqueViewerCount mocks base method.
+func (m *MockVideoInputPort) GetUniqueViewerCount(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUniqueViewerCount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUniqueViewerCount indicates an expected call of GetUniqueViewerCount.
+func (mr *MockVideoInputPortMockRecorder) GetUniqueViewerCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUniqueViewerCount", reflect.TypeOf((*MockVideoInputPort)(nil).GetUniqueViewerCount), arg0, arg1)
+}
+
 // GetVideo mocks base method.
 func (m *MockVideoInputPort) GetVideo(arg0 context.Context, arg1 string) (*domain.Video, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideo", arg0, arg1)
-	ret0, _ := ret[0].(*domain.Video)
+	ret := m.ctrl.Call(m, "GetVideo", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideo indicates an expected call of GetVideo.
+func (mr *MockVideoInputPortMockRecorder) GetVideo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideo", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideo), arg0, arg1)
+}
+
+// GetVideoChapters mocks base method.
+func (m *MockVideoInputPort) GetVideoChapters(arg0 context.Context, arg1 string) ([]domain.Chapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoChapters", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Chapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoChapters indicates an expected call of GetVideoChapters.
+func (mr *MockVideoInputPortMockRecorder) GetVideoChapters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoChapters", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideoChapters), arg0, arg1)
+}
+
+// GetVideoDescriptionForLanguage mocks base method.
+func (m *MockVideoInputPort) GetVideoDescriptionForLanguage(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoDescriptionForLanguage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoDescriptionForLanguage indicates an expected call of GetVideoDescriptionForLanguage.
+func (mr *MockVideoInputPortMockRecorder) GetVideoDescriptionForLanguage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoDescriptionForLanguage", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideoDescriptionForLanguage), arg0, arg1, arg2)
+}
+
+// GetVideoReports mocks base method.
+func (m *MockVideoInputPort) GetVideoReports(arg0 context.Context, arg1, arg2 string) ([]*domain.VideoReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoReports", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.VideoReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoReports indicates an expected call of GetVideoReports.
+func (mr *MockVideoInputPortMockRecorder) GetVideoReports(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoReports", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideoReports), arg0, arg1, arg2)
+}
+
+// GetVideos mocks base method.
+func (m *MockVideoInputPort) GetVideos(arg0 context.Context, arg1 domain.ContentFilter) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideos", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideos indicates an expected call of GetVideos.
+func (mr *MockVideoInputPortMockRecorder) GetVideos(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideos", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideos), arg0, arg1)
+}
+
+// GetVideosAfterCursor mocks base method.
+func (m *MockVideoInputPort) GetVideosAfterCursor(arg0 context.Context, arg1 string, arg2 int) ([]*domain.Video, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosAfterCursor", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosAfterCursor indicates an expected call of GetVideosAfterCursor.
+func (mr *MockVideoInputPortMockRecorder) GetVideosAfterCursor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosAfterCursor", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosAfterCursor), arg0, arg1, arg2)
+}
+
+// GetVideosByIDs mocks base method.
+func (m *MockVideoInputPort) GetVideosByIDs(arg0 context.Context, arg1 []string) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosByIDs indicates an expected call of GetVideosByIDs.
+func (mr *MockVideoInputPortMockRecorder) GetVideosByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByIDs", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosByIDs), arg0, arg1)
+}
+
+// GetVideosByTags mocks base method.
+func (m *MockVideoInputPort) GetVideosByTags(arg0 context.Context, arg1 []string, arg2 bool, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosByTags indicates an expected call of GetVideosByTags.
+func (mr *MockVideoInputPortMockRecorder) GetVideosByTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByTags", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosByTags), arg0, arg1, arg2, arg3)
+}
+
+// GetVideosByUserID mocks base method.
+func (m *MockVideoInputPort) GetVideosByUserID(arg0 context.Context, arg1 string) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosByUserID indicates an expected call of GetVideosByUserID.
+func (mr *MockVideoInputPortMockRecorder) GetVideosByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByUserID", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosByUserID), arg0, arg1)
+}
+
+// GetVideosInRange mocks base method.
+func (m *MockVideoInputPort) GetVideosInRange(arg0 context.Context, arg1, arg2 time.Time, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosInRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosInRange indicates an expected call of GetVideosInRange.
+func (mr *MockVideoInputPortMockRecorder) GetVideosInRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosInRange", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosInRange), arg0, arg1, arg2, arg3)
+}
+
+// GetVideosPaged mocks base method.
+func (m *MockVideoInputPort) GetVideosPaged(arg0 context.Context, arg1 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosPaged", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosPaged indicates an expected call of GetVideosPaged.
+func (mr *MockVideoInputPortMockRecorder) GetVideosPaged(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosPaged", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosPaged), arg0, arg1)
+}
+
+// GetVideosSorted mocks base method.
+func (m *MockVideoInputPort) GetVideosSorted(arg0 context.Context, arg1 domain.SortOrder) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosSorted", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosSorted indicates an expected call of GetVideosSorted.
+func (mr *MockVideoInputPortMockRecorder) GetVideosSorted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosSorted", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosSorted), arg0, arg1)
+}
+
+// GetWatchCount mocks base method.
+func (m *MockVideoInputPort) GetWatchCount(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchCount indicates an expected call of GetWatchCount.
+func (mr *MockVideoInputPortMockRecorder) GetWatchCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchCount", reflect.TypeOf((*MockVideoInputPort)(nil).GetWatchCount), arg0, arg1)
+}
+
+// HardDeleteVideo mocks base method.
+func (m *MockVideoInputPort) HardDeleteVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HardDeleteVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HardDeleteVideo indicates an expected call of HardDeleteVideo.
+func (mr *MockVideoInputPortMockRecorder) HardDeleteVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HardDeleteVideo", reflect.TypeOf((*MockVideoInputPort)(nil).HardDeleteVideo), arg0, arg1, arg2)
+}
+
+// IncrementDownloadCount mocks base method.
+func (m *MockVideoInputPort) IncrementDownloadCount(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementDownloadCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementDownloadCount indicates an expected call of IncrementDownloadCount.
+func (mr *MockVideoInputPortMockRecorder) IncrementDownloadCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementDownloadCount", reflect.TypeOf((*MockVideoInputPort)(nil).IncrementDownloadCount), arg0, arg1, arg2)
+}
+
+// IncrementWatchCount mocks base method.
+func (m *MockVideoInputPort) IncrementWatchCount(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementWatchCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementWatchCount indicates an expected call of IncrementWatchCount.
+func (mr *MockVideoInputPortMockRecorder) IncrementWatchCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementWatchCount", reflect.TypeOf((*MockVideoInputPort)(nil).IncrementWatchCount), arg0, arg1, arg2)
+}
+
+// IsVideoBookmarked mocks base method.
+func (m *MockVideoInputPort) IsVideoBookmarked(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVideoBookmarked", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsVideoBookmarked indicates an expected call of IsVideoBookmarked.
+func (mr *MockVideoInputPortMockRecorder) IsVideoBookmarked(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVideoBookmarked", reflect.TypeOf((*MockVideoInputPort)(nil).IsVideoBookmarked), arg0, arg1, arg2)
+}
+
+// IssueHLSKeyToken mocks base method.
+func (m *MockVideoInputPort) IssueHLSKeyToken(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueHLSKeyToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueHLSKeyToken indicates an expected call of IssueHLSKeyToken.
+func (mr *MockVideoInputPortMockRecorder) IssueHLSKeyToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueHLSKeyToken", reflect.TypeOf((*MockVideoInputPort)(nil).IssueHLSKeyToken), arg0, arg1, arg2)
+}
+
+// IssueVideoStatusToken mocks base method.
+func (m *MockVideoInputPort) IssueVideoStatusToken(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueVideoStatusToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueVideoStatusToken indicates an expected call of IssueVideoStatusToken.
+func (mr *MockVideoInputPortMockRecorder) IssueVideoStatusToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueVideoStatusToken", reflect.TypeOf((*MockVideoInputPort)(nil).IssueVideoStatusToken), arg0, arg1, arg2)
+}
+
+// MaxVideoSize mocks base method.
+func (m *MockVideoInputPort) MaxVideoSize() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxVideoSize")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// MaxVideoSize indicates an expected call of MaxVideoSize.
+func (mr *MockVideoInputPortMockRecorder) MaxVideoSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxVideoSize", reflect.TypeOf((*MockVideoInputPort)(nil).MaxVideoSize))
+}
+
+// MergeTags mocks base method.
+func (m *MockVideoInputPort) MergeTags(arg0 context.Context, arg1, arg2, arg3 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTags indicates an expected call of MergeTags.
+func (mr *MockVideoInputPortMockRecorder) MergeTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTags", reflect.TypeOf((*MockVideoInputPort)(nil).MergeTags), arg0, arg1, arg2, arg3)
+}
+
+// ModerateVideo mocks base method.
+func (m *MockVideoInputPort) ModerateVideo(arg0 context.Context, arg1, arg2 string, arg3 domain.ModerationAction, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateVideo", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateVideo indicates an expected call of ModerateVideo.
+func (mr *MockVideoInputPortMockRecorder) ModerateVideo(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateVideo", reflect.TypeOf((*MockVideoInputPort)(nil).ModerateVideo), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RecordThumbnailClick mocks base method.
+func (m *MockVideoInputPort) RecordThumbnailClick(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordThumbnailClick", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordThumbnailClick indicates an expected call of RecordThumbnailClick.
+func (mr *MockVideoInputPortMockRecorder) RecordThumbnailClick(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordThumbnailClick", reflect.TypeOf((*MockVideoInputPort)(nil).RecordThumbnailClick), arg0, arg1)
+}
+
+// RecordThumbnailImpression mocks base method.
+func (m *MockVideoInputPort) RecordThumbnailImpression(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordThumbnailImpression", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordThumbnailImpression indicates an expected call of RecordThumbnailImpression.
+func (mr *MockVideoInputPortMockRecorder) RecordThumbnailImpression(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordThumbnailImpression", reflect.TypeOf((*MockVideoInputPort)(nil).RecordThumbnailImpression), arg0, arg1)
+}
+
+// RecordWatchDuration mocks base method.
+func (m *MockVideoInputPort) RecordWatchDuration(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWatchDuration", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWatchDuration indicates an expected call of RecordWatchDuration.
+func (mr *MockVideoInputPortMockRecorder) RecordWatchDuration(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWatchDuration", reflect.TypeOf((*MockVideoInputPort)(nil).RecordWatchDuration), arg0, arg1, arg2, arg3)
+}
+
+// RegisterWebhookTarget mocks base method.
+func (m *MockVideoInputPort) RegisterWebhookTarget(arg0 context.Context, arg1 domain.WebhookTarget, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhookTarget", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterWebhookTarget indicates an expected call of RegisterWebhookTarget.
+func (mr *MockVideoInputPortMockRecorder) RegisterWebhookTarget(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhookTarget", reflect.TypeOf((*MockVideoInputPort)(nil).RegisterWebhookTarget), arg0, arg1, arg2)
+}
+
+// RenameTag mocks base method.
+func (m *MockVideoInputPort) RenameTag(arg0 context.Context, arg1, arg2, arg3 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTag", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameTag indicates an expected call of RenameTag.
+func (mr *MockVideoInputPortMockRecorder) RenameTag(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTag", reflect.TypeOf((*MockVideoInputPort)(nil).RenameTag), arg0, arg1, arg2, arg3)
+}
+
+// RestoreVideo mocks base method.
+func (m *MockVideoInputPort) RestoreVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVideo indicates an expected call of RestoreVideo.
+func (mr *MockVideoInputPortMockRecorder) RestoreVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVideo", reflect.TypeOf((*MockVideoInputPort)(nil).RestoreVideo), arg0, arg1, arg2)
+}
+
+// SearchVideos mocks base method.
+func (m *MockVideoInputPort) SearchVideos(arg0 context.Context, arg1 string, arg2 domain.SearchOptions, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchVideos", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchVideos indicates an expected call of SearchVideos.
+func (mr *MockVideoInputPortMockRecorder) SearchVideos(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchVideos", reflect.TypeOf((*MockVideoInputPort)(nil).SearchVideos), arg0, arg1, arg2, arg3)
+}
+
+// SelectThumbnailForUser mocks base method.
+func (m *MockVideoInputPort) SelectThumbnailForUser(arg0 context.Context, arg1, arg2 string) (*domain.ThumbnailVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectThumbnailForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.ThumbnailVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectThumbnailForUser indicates an expected call of SelectThumbnailForUser.
+func (mr *MockVideoInputPortMockRecorder) SelectThumbnailForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectThumbnailForUser", reflect.TypeOf((*MockVideoInputPort)(nil).SelectThumbnailForUser), arg0, arg1, arg2)
+}
+
+// SetAllVideosByUserPrivacy mocks base method.
+func (m *MockVideoInputPort) SetAllVideosByUserPrivacy(arg0 context.Context, arg1 string, arg2 bool, arg3 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAllVideosByUserPrivacy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAllVideosByUserPrivacy indicates an expected call of SetAllVideosByUserPrivacy.
+func (mr *MockVideoInputPortMockRecorder) SetAllVideosByUserPrivacy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllVideosByUserPrivacy", reflect.TypeOf((*MockVideoInputPort)(nil).SetAllVideosByUserPrivacy), arg0, arg1, arg2, arg3)
+}
+
+// SetVideoChapters mocks base method.
+func (m *MockVideoInputPort) SetVideoChapters(arg0 context.Context, arg1 string, arg2 []domain.Chapter, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVideoChapters", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVideoChapters indicates an expected call of SetVideoChapters.
+func (mr *MockVideoInputPortMockRecorder) SetVideoChapters(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoChapters", reflect.TypeOf((*MockVideoInputPort)(nil).SetVideoChapters), arg0, arg1, arg2, arg3)
+}
+
+// SetVideoDescription mocks base method.
+func (m *MockVideoInputPort) SetVideoDescription(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVideoDescription", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVideoDescription indicates an expected call of SetVideoDescription.
+func (mr *MockVideoInputPortMockRecorder) SetVideoDescription(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoDescription", reflect.TypeOf((*MockVideoInputPort)(nil).SetVideoDescription), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SetVideoRegionRestrictions mocks base method.
+func (m *MockVideoInputPort) SetVideoRegionRestrictions(arg0 context.Context, arg1 string, arg2 []string, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVideoRegionRestrictions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVideoRegionRestrictions indicates an expected call of SetVideoRegionRestrictions.
+func (mr *MockVideoInputPortMockRecorder) SetVideoRegionRestrictions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoRegionRestrictions", reflect.TypeOf((*MockVideoInputPort)(nil).SetVideoRegionRestrictions), arg0, arg1, arg2, arg3)
+}
+
+// SoftDeleteVideo mocks base method.
+func (m *MockVideoInputPort) SoftDeleteVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteVideo indicates an expected call of SoftDeleteVideo.
+func (mr *MockVideoInputPortMockRecorder) SoftDeleteVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteVideo", reflect.TypeOf((*MockVideoInputPort)(nil).SoftDeleteVideo), arg0, arg1, arg2)
+}
+
+// SplitVideo mocks base method.
+func (m *MockVideoInputPort) SplitVideo(arg0 context.Context, arg1 string, arg2 int, arg3 string) ([2]*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitVideo", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([2]*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitVideo indicates an expected call of SplitVideo.
+func (mr *MockVideoInputPortMockRecorder) SplitVideo(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitVideo", reflect.TypeOf((*MockVideoInputPort)(nil).SplitVideo), arg0, arg1, arg2, arg3)
+}
+
+// SubmitVideoReport mocks base method.
+func (m *MockVideoInputPort) SubmitVideoReport(arg0 context.Context, arg1 domain.VideoReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitVideoReport", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitVideoReport indicates an expected call of SubmitVideoReport.
+func (mr *MockVideoInputPortMockRecorder) SubmitVideoReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitVideoReport", reflect.TypeOf((*MockVideoInputPort)(nil).SubmitVideoReport), arg0, arg1)
+}
+
+// SubscribeToVideoStatus mocks base method.
+func (m *MockVideoInputPort) SubscribeToVideoStatus(arg0 context.Context, arg1, arg2 string) (<-chan domain.ProcessingStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeToVideoStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan domain.ProcessingStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeToVideoStatus indicates an expected call of SubscribeToVideoStatus.
+func (mr *MockVideoInputPortMockRecorder) SubscribeToVideoStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToVideoStatus", reflect.TypeOf((*MockVideoInputPort)(nil).SubscribeToVideoStatus), arg0, arg1, arg2)
+}
+
+// SuggestTagsForTitle mocks base method.
+func (m *MockVideoInputPort) SuggestTagsForTitle(arg0 context.Context, arg1 string, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestTagsForTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestTagsForTitle indicates an expected call of SuggestTagsForTitle.
+func (mr *MockVideoInputPortMockRecorder) SuggestTagsForTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestTagsForTitle", reflect.TypeOf((*MockVideoInputPort)(nil).SuggestTagsForTitle), arg0, arg1, arg2)
+}
+
+// TransferVideoOwnership mocks base method.
+func (m *MockVideoInputPort) TransferVideoOwnership(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferVideoOwnership", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferVideoOwnership indicates an expected call of TransferVideoOwnership.
+func (mr *MockVideoInputPortMockRecorder) TransferVideoOwnership(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferVideoOwnership", reflect.TypeOf((*MockVideoInputPort)(nil).TransferVideoOwnership), arg0, arg1, arg2, arg3)
+}
+
+// UnbookmarkVideo mocks base method.
+func (m *MockVideoInputPort) UnbookmarkVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbookmarkVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbookmarkVideo indicates an expected call of UnbookmarkVideo.
+func (mr *MockVideoInputPortMockRecorder) UnbookmarkVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbookmarkVideo", reflect.TypeOf((*MockVideoInputPort)(nil).UnbookmarkVideo), arg0, arg1, arg2)
+}
+
+// UpdateVideo mocks base method.
+func (m *MockVideoInputPort) UpdateVideo(arg0 context.Context, arg1, arg2 string, arg3 int, arg4 domain.VideoUpdate) (*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideo", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVideo indicates an expected call of UpdateVideo.
+func (mr *MockVideoInputPortMockRecorder) UpdateVideo(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideo", reflect.TypeOf((*MockVideoInputPort)(nil).UpdateVideo), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateVideoExpiry mocks base method.
+func (m *MockVideoInputPort) UpdateVideoExpiry(arg0 context.Context, arg1, arg2 string, arg3 *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideoExpiry", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVideoExpiry indicates an expected call of UpdateVideoExpiry.
+func (mr *MockVideoInputPortMockRecorder) UpdateVideoExpiry(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideoExpiry", reflect.TypeOf((*MockVideoInputPort)(nil).UpdateVideoExpiry), arg0, arg1, arg2, arg3)
+}
+
+// UpdateVideoProcessingStatus mocks base method.
+func (m *MockVideoInputPort) UpdateVideoProcessingStatus(arg0 context.Context, arg1 string, arg2 domain.ProcessingStatus, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideoProcessingStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVideoProcessingStatus indicates an expected call of UpdateVideoProcessingStatus.
+func (mr *MockVideoInputPortMockRecorder) UpdateVideoProcessingStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideoProcessingStatus", reflect.TypeOf((*MockVideoInputPort)(nil).UpdateVideoProcessingStatus), arg0, arg1, arg2, arg3)
+}
+
+// UploadSubtitleTrack mocks base method.
+func (m *MockVideoInputPort) UploadSubtitleTrack(arg0 context.Context, arg1, arg2, arg3 string, arg4 io.Reader, arg5 string) (*domain.SubtitleTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSubtitleTrack", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*domain.SubtitleTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSubtitleTrack indicates an expected call of UploadSubtitleTrack.
+func (mr *MockVideoInputPortMockRecorder) UploadSubtitleTrack(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSubtitleTrack", reflect.TypeOf((*MockVideoInputPort)(nil).UploadSubtitleTrack), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// UploadVideo mocks base method.
+func (m *MockVideoInputPort) UploadVideo(arg0 context.Context, arg1 *domain.UploadVideo, arg2, arg3 string) (*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadVideo", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadVideo indicates an expected call of UploadVideo.
+func (mr *MockVideoInputPortMockRecorder) UploadVideo(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadVideo", reflect.TypeOf((*MockVideoInputPort)(nil).UploadVideo), arg0, arg1, arg2, arg3)
+}
+
+// UploadWatermark mocks base method.
+func (m *MockVideoInputPort) UploadWatermark(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadWatermark", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadWatermark indicates an expected call of UploadWatermark.
+func (mr *MockVideoInputPortMockRecorder) UploadWatermark(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadWatermark", reflect.TypeOf((*MockVideoInputPort)(nil).UploadWatermark), arg0, arg1, arg2)
+}
+
+// UpsertReaction mocks base method.
+func (m *MockVideoInputPort) UpsertReaction(arg0 context.Context, arg1, arg2 string, arg3 domain.ReactionType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReaction", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReaction indicates an expected call of UpsertReaction.
+func (mr *MockVideoInputPortMockRecorder) UpsertReaction(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReaction", reflect.TypeOf((*MockVideoInputPort)(nil).UpsertReaction), arg0, arg1, arg2, arg3)
+}
+
+// MockVideoRepository is a mock of VideoRepository interface.
+type MockVideoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVideoRepositoryMockRecorder
+}
+
+// MockVideoRepositoryMockRecorder is the mock recorder for MockVideoRepository.
+type MockVideoRepositoryMockRecorder struct {
+	mock *MockVideoRepository
+}
+
+// NewMockVideoRepository creates a new mock instance.
+func NewMockVideoRepository(ctrl *gomock.Controller) *MockVideoRepository {
+	mock := &MockVideoRepository{ctrl: ctrl}
+	mock.recorder = &MockVideoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVideoRepository) EXPECT() *MockVideoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToUserStorageUsage mocks base method.
+func (m *MockVideoRepository) AddToUserStorageUsage(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToUserStorageUsage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToUserStorageUsage indicates an expected call of AddToUserStorageUsage.
+func (mr *MockVideoRepositoryMockRecorder) AddToUserStorageUsage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToUserStorageUsage", reflect.TypeOf((*MockVideoRepository)(nil).AddToUserStorageUsage), arg0, arg1, arg2)
+}
+
+// AnalyzeVideo mocks base method.
+func (m *MockVideoRepository) AnalyzeVideo(arg0 context.Context, arg1 string) (*domain.VideoAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzeVideo", arg0, arg1)
+	ret0, _ := ret[0].(*domain.VideoAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzeVideo indicates an expected call of AnalyzeVideo.
+func (mr *MockVideoRepositoryMockRecorder) AnalyzeVideo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeVideo", reflect.TypeOf((*MockVideoRepository)(nil).AnalyzeVideo), arg0, arg1)
+}
+
+// AutocompleteTags mocks base method.
+func (m *MockVideoRepository) AutocompleteTags(arg0 context.Context, arg1 string, arg2 int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutocompleteTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AutocompleteTags indicates an expected call of AutocompleteTags.
+func (mr *MockVideoRepositoryMockRecorder) AutocompleteTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutocompleteTags", reflect.TypeOf((*MockVideoRepository)(nil).AutocompleteTags), arg0, arg1, arg2)
+}
+
+// BookmarkVideo mocks base method.
+func (m *MockVideoRepository) BookmarkVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BookmarkVideo indicates an expected call of BookmarkVideo.
+func (mr *MockVideoRepositoryMockRecorder) BookmarkVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkVideo", reflect.TypeOf((*MockVideoRepository)(nil).BookmarkVideo), arg0, arg1, arg2)
+}
+
+// ChechWatchCount mocks base method.
+func (m *MockVideoRepository) ChechWatchCount(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChechWatchCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChechWatchCount indicates an expected call of ChechWatchCount.
+func (mr *MockVideoRepositoryMockRecorder) ChechWatchCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChechWatchCount", reflect.TypeOf((*MockVideoRepository)(nil).ChechWatchCount), arg0, arg1, arg2)
+}
+
+// CheckDownloadCount mocks base method.
+func (m *MockVideoRepository) CheckDownloadCount(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDownloadCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckDownloadCount indicates an expected call of CheckDownloadCount.
+func (mr *MockVideoRepositoryMockRecorder) CheckDownloadCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDownloadCount", reflect.TypeOf((*MockVideoRepository)(nil).CheckDownloadCount), arg0, arg1, arg2)
+}
+
+// CheckUserStorageQuota mocks base method.
+func (m *MockVideoRepository) CheckUserStorageQuota(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckUserStorageQuota", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckUserStorageQuota indicates an expected call of CheckUserStorageQuota.
+func (mr *MockVideoRepositoryMockRecorder) CheckUserStorageQuota(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserStorageQuota", reflect.TypeOf((*MockVideoRepository)(nil).CheckUserStorageQuota), arg0, arg1, arg2)
+}
+
+// CheckVideoUploader mocks base method.
+func (m *MockVideoRepository) CheckVideoUploader(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckVideoUploader", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckVideoUploader indicates an expected call of CheckVideoUploader.
+func (mr *MockVideoRepositoryMockRecorder) CheckVideoUploader(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckVideoUploader", reflect.TypeOf((*MockVideoRepository)(nil).CheckVideoUploader), arg0, arg1, arg2)
+}
+
+// ConcatenateVideos mocks base method.
+func (m *MockVideoRepository) ConcatenateVideos(arg0 context.Context, arg1 []string, arg2, arg3 string) (*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConcatenateVideos", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConcatenateVideos indicates an expected call of ConcatenateVideos.
+func (mr *MockVideoRepositoryMockRecorder) ConcatenateVideos(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConcatenateVideos", reflect.TypeOf((*MockVideoRepository)(nil).ConcatenateVideos), arg0, arg1, arg2, arg3)
+}
+
+// ConvertVideoHLS mocks base method.
+func (m *MockVideoRepository) ConvertVideoHLS(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertVideoHLS", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConvertVideoHLS indicates an expected call of ConvertVideoHLS.
+func (mr *MockVideoRepositoryMockRecorder) ConvertVideoHLS(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertVideoHLS", reflect.TypeOf((*MockVideoRepository)(nil).ConvertVideoHLS), arg0, arg1)
+}
+
+// CreateThumbnailVariant mocks base method.
+func (m *MockVideoRepository) CreateThumbnailVariant(arg0 context.Context, arg1, arg2 string, arg3 float64, arg4 string) (*domain.ThumbnailVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateThumbnailVariant", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*domain.ThumbnailVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateThumbnailVariant indicates an expected call of CreateThumbnailVariant.
+func (mr *MockVideoRepositoryMockRecorder) CreateThumbnailVariant(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateThumbnailVariant", reflect.TypeOf((*MockVideoRepository)(nil).CreateThumbnailVariant), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CutVideo mocks base method.
+func (m *MockVideoRepository) CutVideo(arg0 context.Context, arg1, arg2 string, arg3, arg4 int, arg5 domain.CutOptions) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CutVideo", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CutVideo indicates an expected call of CutVideo.
+func (mr *MockVideoRepositoryMockRecorder) CutVideo(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CutVideo", reflect.TypeOf((*MockVideoRepository)(nil).CutVideo), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// DeleteSubtitleTrack mocks base method.
+func (m *MockVideoRepository) DeleteSubtitleTrack(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubtitleTrack", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubtitleTrack indicates an expected call of DeleteSubtitleTrack.
+func (mr *MockVideoRepositoryMockRecorder) DeleteSubtitleTrack(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubtitleTrack", reflect.TypeOf((*MockVideoRepository)(nil).DeleteSubtitleTrack), arg0, arg1, arg2)
+}
+
+// EncryptHLSSegments mocks base method.
+func (m *MockVideoRepository) EncryptHLSSegments(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptHLSSegments", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EncryptHLSSegments indicates an expected call of EncryptHLSSegments.
+func (mr *MockVideoRepositoryMockRecorder) EncryptHLSSegments(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptHLSSegments", reflect.TypeOf((*MockVideoRepository)(nil).EncryptHLSSegments), arg0, arg1, arg2)
+}
+
+// EnqueueVideoProcessingJob mocks base method.
+func (m *MockVideoRepository) EnqueueVideoProcessingJob(arg0 context.Context, arg1 domain.VideoProcessingJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueVideoProcessingJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueVideoProcessingJob indicates an expected call of EnqueueVideoProcessingJob.
+func (mr *MockVideoRepositoryMockRecorder) EnqueueVideoProcessingJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueVideoProcessingJob", reflect.TypeOf((*MockVideoRepository)(nil).EnqueueVideoProcessingJob), arg0, arg1)
+}
+
+// ExtractAudio mocks base method.
+func (m *MockVideoRepository) ExtractAudio(arg0 context.Context, arg1, arg2, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtractAudio", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtractAudio indicates an expected call of ExtractAudio.
+func (mr *MockVideoRepositoryMockRecorder) ExtractAudio(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractAudio", reflect.TypeOf((*MockVideoRepository)(nil).ExtractAudio), arg0, arg1, arg2, arg3)
+}
+
+// GenerateAnimatedPreview mocks base method.
+func (m *MockVideoRepository) GenerateAnimatedPreview(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateAnimatedPreview", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateAnimatedPreview indicates an expected call of GenerateAnimatedPreview.
+func (mr *MockVideoRepositoryMockRecorder) GenerateAnimatedPreview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAnimatedPreview", reflect.TypeOf((*MockVideoRepository)(nil).GenerateAnimatedPreview), arg0, arg1)
+}
+
+// GenerateThumbnailSprite mocks base method.
+func (m *MockVideoRepository) GenerateThumbnailSprite(arg0 context.Context, arg1 string, arg2 int) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateThumbnailSprite", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateThumbnailSprite indicates an expected call of GenerateThumbnailSprite.
+func (mr *MockVideoRepositoryMockRecorder) GenerateThumbnailSprite(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateThumbnailSprite", reflect.TypeOf((*MockVideoRepository)(nil).GenerateThumbnailSprite), arg0, arg1, arg2)
+}
+
+// GetAuditLogsForVideo mocks base method.
+func (m *MockVideoRepository) GetAuditLogsForVideo(arg0 context.Context, arg1 string, arg2 domain.Page) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsForVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsForVideo indicates an expected call of GetAuditLogsForVideo.
+func (mr *MockVideoRepositoryMockRecorder) GetAuditLogsForVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsForVideo", reflect.TypeOf((*MockVideoRepository)(nil).GetAuditLogsForVideo), arg0, arg1, arg2)
+}
+
+// GetAverageWatchDuration mocks base method.
+func (m *MockVideoRepository) GetAverageWatchDuration(arg0 context.Context, arg1 string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAverageWatchDuration", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAverageWatchDuration indicates an expected call of GetAverageWatchDuration.
+func (mr *MockVideoRepositoryMockRecorder) GetAverageWatchDuration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAverageWatchDuration", reflect.TypeOf((*MockVideoRepository)(nil).GetAverageWatchDuration), arg0, arg1)
+}
+
+// GetBookmarkedVideosByUser mocks base method.
+func (m *MockVideoRepository) GetBookmarkedVideosByUser(arg0 context.Context, arg1 string, arg2 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarkedVideosByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBookmarkedVideosByUser indicates an expected call of GetBookmarkedVideosByUser.
+func (mr *MockVideoRepositoryMockRecorder) GetBookmarkedVideosByUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarkedVideosByUser", reflect.TypeOf((*MockVideoRepository)(nil).GetBookmarkedVideosByUser), arg0, arg1, arg2)
+}
+
+// GetDownloadCount mocks base method.
+func (m *MockVideoRepository) GetDownloadCount(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDownloadCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDownloadCount indicates an expected call of GetDownloadCount.
+func (mr *MockVideoRepositoryMockRecorder) GetDownloadCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDownloadCount", reflect.TypeOf((*MockVideoRepository)(nil).GetDownloadCount), arg0, arg1)
+}
+
+// GetHLSEncryptionKey mocks base method.
+func (m *MockVideoRepository) GetHLSEncryptionKey(arg0 context.Context, arg1, arg2 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHLSEncryptionKey", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHLSEncryptionKey indicates an expected call of GetHLSEncryptionKey.
+func (mr *MockVideoRepositoryMockRecorder) GetHLSEncryptionKey(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHLSEncryptionKey", reflect.TypeOf((*MockVideoRepository)(nil).GetHLSEncryptionKey), arg0, arg1, arg2)
+}
+
+// GetModerationHistory mocks base method.
+func (m *MockVideoRepository) GetModerationHistory(arg0 context.Context, arg1 string) ([]*domain.ModerationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.ModerationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationHistory indicates an expected call of GetModerationHistory.
+func (mr *MockVideoRepositoryMockRecorder) GetModerationHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationHistory", reflect.TypeOf((*MockVideoRepository)(nil).GetModerationHistory), arg0, arg1)
+}
+
+// GetOrGenerateQRCode mocks base method.
+func (m *MockVideoRepository) GetOrGenerateQRCode(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrGenerateQRCode", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrGenerateQRCode indicates an expected call of GetOrGenerateQRCode.
+func (mr *MockVideoRepositoryMockRecorder) GetOrGenerateQRCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrGenerateQRCode", reflect.TypeOf((*MockVideoRepository)(nil).GetOrGenerateQRCode), arg0, arg1)
+}
+
+// GetPendingReports mocks base method.
+func (m *MockVideoRepository) GetPendingReports(arg0 context.Context, arg1 domain.Page) ([]*domain.VideoReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReports", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.VideoReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReports indicates an expected call of GetPendingReports.
+func (mr *MockVideoRepositoryMockRecorder) GetPendingReports(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReports", reflect.TypeOf((*MockVideoRepository)(nil).GetPendingReports), arg0, arg1)
+}
+
+// GetReactionCounts mocks base method.
+func (m *MockVideoRepository) GetReactionCounts(arg0 context.Context, arg1 string) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactionCounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReactionCounts indicates an expected call of GetReactionCounts.
+func (mr *MockVideoRepositoryMockRecorder) GetReactionCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactionCounts", reflect.TypeOf((*MockVideoRepository)(nil).GetReactionCounts), arg0, arg1)
+}
+
+// GetRelatedVideos mocks base method.
+func (m *MockVideoRepository) GetRelatedVideos(arg0 context.Context, arg1 string, arg2 int) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelatedVideos", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelatedVideos indicates an expected call of GetRelatedVideos.
+func (mr *MockVideoRepositoryMockRecorder) GetRelatedVideos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelatedVideos", reflect.TypeOf((*MockVideoRepository)(nil).GetRelatedVideos), arg0, arg1, arg2)
+}
+
+// GetSubtitleTrack mocks base method.
+func (m *MockVideoRepository) GetSubtitleTrack(arg0 context.Context, arg1 string) (*domain.SubtitleTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtitleTrack", arg0, arg1)
+	ret0, _ := ret[0].(*domain.SubtitleTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtitleTrack indicates an expected call of GetSubtitleTrack.
+func (mr *MockVideoRepositoryMockRecorder) GetSubtitleTrack(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtitleTrack", reflect.TypeOf((*MockVideoRepository)(nil).GetSubtitleTrack), arg0, arg1)
+}
+
+// GetSubtitleTracksByVideoID mocks base method.
+func (m *MockVideoRepository) GetSubtitleTracksByVideoID(arg0 context.Context, arg1 string) ([]*domain.SubtitleTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtitleTracksByVideoID", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.SubtitleTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtitleTracksByVideoID indicates an expected call of GetSubtitleTracksByVideoID.
+func (mr *MockVideoRepositoryMockRecorder) GetSubtitleTracksByVideoID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtitleTracksByVideoID", reflect.TypeOf((*MockVideoRepository)(nil).GetSubtitleTracksByVideoID), arg0, arg1)
+}
+
+// GetThumbnailVariantStats mocks base method.
+func (m *MockVideoRepository) GetThumbnailVariantStats(arg0 context.Context, arg1 string) ([]domain.ThumbnailStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThumbnailVariantStats", arg0, arg1)
+	ret0, _ := ret[0].([]domain.ThumbnailStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThumbnailVariantStats indicates an expected call of GetThumbnailVariantStats.
+func (mr *MockVideoRepositoryMockRecorder) GetThumbnailVariantStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThumbnailVariantStats", reflect.TypeOf((*MockVideoRepository)(nil).GetThumbnailVariantStats), arg0, arg1)
+}
+
+// GetTrendingVideos mocks base method.
+func (m *MockVideoRepository) GetTrendingVideos(arg0 context.Context, arg1 time.Duration, arg2 int) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrendingVideos", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrendingVideos indicates an expected call of GetTrendingVideos.
+func (mr *MockVideoRepositoryMockRecorder) GetTrendingVideos(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrendingVideos", reflect.TypeOf((*MockVideoRepository)(nil).GetTrendingVideos), arg0, arg1, arg2)
+}
+
+// GetUniqueViewerCount mocks base method.
+func (m *MockVideoRepository) GetUniqueViewerCount(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUniqueViewerCount", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUniqueViewerCount indicates an expected call of GetUniqueViewerCount.
+func (mr *MockVideoRepositoryMockRecorder) GetUniqueViewerCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUniqueViewerCount", reflect.TypeOf((*MockVideoRepository)(nil).GetUniqueViewerCount), arg0, arg1)
+}
+
+// GetVideoChapters mocks base method.
+func (m *MockVideoRepository) GetVideoChapters(arg0 context.Context, arg1 string) ([]domain.Chapter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoChapters", arg0, arg1)
+	ret0, _ := ret[0].([]domain.Chapter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoChapters indicates an expected call of GetVideoChapters.
+func (mr *MockVideoRepositoryMockRecorder) GetVideoChapters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoChapters", reflect.TypeOf((*MockVideoRepository)(nil).GetVideoChapters), arg0, arg1)
+}
+
+// GetVideoDescriptionForLanguage mocks base method.
+func (m *MockVideoRepository) GetVideoDescriptionForLanguage(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoDescriptionForLanguage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoDescriptionForLanguage indicates an expected call of GetVideoDescriptionForLanguage.
+func (mr *MockVideoRepositoryMockRecorder) GetVideoDescriptionForLanguage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoDescriptionForLanguage", reflect.TypeOf((*MockVideoRepository)(nil).GetVideoDescriptionForLanguage), arg0, arg1, arg2)
+}
+
+// GetVideoFromDB mocks base method.
+func (m *MockVideoRepository) GetVideoFromDB(arg0 context.Context, arg1 string) (*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoFromDB", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoFromDB indicates an expected call of GetVideoFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideoFromDB(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideoFromDB), arg0, arg1)
+}
+
+// GetVideoReports mocks base method.
+func (m *MockVideoRepository) GetVideoReports(arg0 context.Context, arg1 string) ([]*domain.VideoReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideoReports", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.VideoReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideoReports indicates an expected call of GetVideoReports.
+func (mr *MockVideoRepositoryMockRecorder) GetVideoReports(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoReports", reflect.TypeOf((*MockVideoRepository)(nil).GetVideoReports), arg0, arg1)
+}
+
+// GetVideosByIDsFromDB mocks base method.
+func (m *MockVideoRepository) GetVideosByIDsFromDB(arg0 context.Context, arg1 []string) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByIDsFromDB", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosByIDsFromDB indicates an expected call of GetVideosByIDsFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosByIDsFromDB(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByIDsFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosByIDsFromDB), arg0, arg1)
+}
+
+// GetVideosByTagsFromDB mocks base method.
+func (m *MockVideoRepository) GetVideosByTagsFromDB(arg0 context.Context, arg1 []string, arg2 bool, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByTagsFromDB", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosByTagsFromDB indicates an expected call of GetVideosByTagsFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosByTagsFromDB(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByTagsFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosByTagsFromDB), arg0, arg1, arg2, arg3)
+}
+
+// GetVideosByUserIDFromDB mocks base method.
+func (m *MockVideoRepository) GetVideosByUserIDFromDB(arg0 context.Context, arg1 string) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosByUserIDFromDB", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosByUserIDFromDB indicates an expected call of GetVideosByUserIDFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosByUserIDFromDB(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByUserIDFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosByUserIDFromDB), arg0, arg1)
+}
+
+// GetVideosFromDB mocks base method.
+func (m *MockVideoRepository) GetVideosFromDB(arg0 context.Context, arg1 domain.ContentFilter) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosFromDB", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosFromDB indicates an expected call of GetVideosFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosFromDB(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosFromDB), arg0, arg1)
+}
+
+// GetVideosFromDBAfterCursor mocks base method.
+func (m *MockVideoRepository) GetVideosFromDBAfterCursor(arg0 context.Context, arg1 string, arg2 int) ([]*domain.Video, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosFromDBAfterCursor", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosFromDBAfterCursor indicates an expected call of GetVideosFromDBAfterCursor.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosFromDBAfterCursor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosFromDBAfterCursor", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosFromDBAfterCursor), arg0, arg1, arg2)
+}
+
+// GetVideosFromDBInRange mocks base method.
+func (m *MockVideoRepository) GetVideosFromDBInRange(arg0 context.Context, arg1, arg2 time.Time, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosFromDBInRange", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosFromDBInRange indicates an expected call of GetVideosFromDBInRange.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosFromDBInRange(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosFromDBInRange", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosFromDBInRange), arg0, arg1, arg2, arg3)
+}
+
+// GetVideosFromDBPaged mocks base method.
+func (m *MockVideoRepository) GetVideosFromDBPaged(arg0 context.Context, arg1 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosFromDBPaged", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetVideosFromDBPaged indicates an expected call of GetVideosFromDBPaged.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosFromDBPaged(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosFromDBPaged", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosFromDBPaged), arg0, arg1)
+}
+
+// GetVideosSortedFromDB mocks base method.
+func (m *MockVideoRepository) GetVideosSortedFromDB(arg0 context.Context, arg1 domain.SortOrder) ([]*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideosSortedFromDB", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideosSortedFromDB indicates an expected call of GetVideosSortedFromDB.
+func (mr *MockVideoRepositoryMockRecorder) GetVideosSortedFromDB(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosSortedFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosSortedFromDB), arg0, arg1)
+}
+
+// GetWatchCount mocks base method.
+func (m *MockVideoRepository) GetWatchCount(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchCount", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchCount indicates an expected call of GetWatchCount.
+func (mr *MockVideoRepositoryMockRecorder) GetWatchCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchCount", reflect.TypeOf((*MockVideoRepository)(nil).GetWatchCount), arg0, arg1)
+}
+
+// HardDeleteVideo mocks base method.
+func (m *MockVideoRepository) HardDeleteVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HardDeleteVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HardDeleteVideo indicates an expected call of HardDeleteVideo.
+func (mr *MockVideoRepositoryMockRecorder) HardDeleteVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HardDeleteVideo", reflect.TypeOf((*MockVideoRepository)(nil).HardDeleteVideo), arg0, arg1, arg2)
+}
+
+// IncrementDownloadCount mocks base method.
+func (m *MockVideoRepository) IncrementDownloadCount(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementDownloadCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementDownloadCount indicates an expected call of IncrementDownloadCount.
+func (mr *MockVideoRepositoryMockRecorder) IncrementDownloadCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementDownloadCount", reflect.TypeOf((*MockVideoRepository)(nil).IncrementDownloadCount), arg0, arg1, arg2)
+}
+
+// IncrementWatchCount mocks base method.
+func (m *MockVideoRepository) IncrementWatchCount(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementWatchCount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementWatchCount indicates an expected call of IncrementWatchCount.
+func (mr *MockVideoRepositoryMockRecorder) IncrementWatchCount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementWatchCount", reflect.TypeOf((*MockVideoRepository)(nil).IncrementWatchCount), arg0, arg1, arg2)
+}
+
+// InsertPendingVideo mocks base method.
+func (m *MockVideoRepository) InsertPendingVideo(arg0 context.Context, arg1, arg2, arg3 string, arg4 *string, arg5 string, arg6 []string, arg7 domain.ContentRating, arg8, arg9, arg10 bool, arg11 *domain.VideoMetadata, arg12 *time.Time) (*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPendingVideo", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12)
+	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPendingVideo indicates an expected call of InsertPendingVideo.
+func (mr *MockVideoRepositoryMockRecorder) InsertPendingVideo(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPendingVideo", reflect.TypeOf((*MockVideoRepository)(nil).InsertPendingVideo), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12)
+}
+
+// InsertVideo mocks base method.
+func (m *MockVideoRepository) InsertVideo(arg0 context.Context, arg1, arg2, arg3, arg4 string, arg5 *string, arg6 string, arg7 []string, arg8 domain.ContentRating, arg9, arg10, arg11 bool, arg12 *domain.VideoMetadata, arg13 string, arg14 *time.Time) (*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertVideo", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14)
+	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertVideo indicates an expected call of InsertVideo.
+func (mr *MockVideoRepositoryMockRecorder) InsertVideo(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVideo", reflect.TypeOf((*MockVideoRepository)(nil).InsertVideo), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14)
+}
+
+// IsAdmin mocks base method.
+func (m *MockVideoRepository) IsAdmin(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAdmin", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsAdmin indicates an expected call of IsAdmin.
+func (mr *MockVideoRepositoryMockRecorder) IsAdmin(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdmin", reflect.TypeOf((*MockVideoRepository)(nil).IsAdmin), arg0)
+}
+
+// IsRetriedUpload mocks base method.
+func (m *MockVideoRepository) IsRetriedUpload(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRetriedUpload", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRetriedUpload indicates an expected call of IsRetriedUpload.
+func (mr *MockVideoRepositoryMockRecorder) IsRetriedUpload(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRetriedUpload", reflect.TypeOf((*MockVideoRepository)(nil).IsRetriedUpload), arg0, arg1, arg2)
+}
+
+// IsVideoBookmarked mocks base method.
+func (m *MockVideoRepository) IsVideoBookmarked(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVideoBookmarked", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsVideoBookmarked indicates an expected call of IsVideoBookmarked.
+func (mr *MockVideoRepositoryMockRecorder) IsVideoBookmarked(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVideoBookmarked", reflect.TypeOf((*MockVideoRepository)(nil).IsVideoBookmarked), arg0, arg1, arg2)
+}
+
+// IssueHLSKeyToken mocks base method.
+func (m *MockVideoRepository) IssueHLSKeyToken(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueHLSKeyToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueHLSKeyToken indicates an expected call of IssueHLSKeyToken.
+func (mr *MockVideoRepositoryMockRecorder) IssueHLSKeyToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueHLSKeyToken", reflect.TypeOf((*MockVideoRepository)(nil).IssueHLSKeyToken), arg0, arg1, arg2)
+}
+
+// IssueVideoStatusToken mocks base method.
+func (m *MockVideoRepository) IssueVideoStatusToken(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueVideoStatusToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueVideoStatusToken indicates an expected call of IssueVideoStatusToken.
+func (mr *MockVideoRepositoryMockRecorder) IssueVideoStatusToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueVideoStatusToken", reflect.TypeOf((*MockVideoRepository)(nil).IssueVideoStatusToken), arg0, arg1, arg2)
+}
+
+// MaxVideoSize mocks base method.
+func (m *MockVideoRepository) MaxVideoSize() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxVideoSize")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// MaxVideoSize indicates an expected call of MaxVideoSize.
+func (mr *MockVideoRepositoryMockRecorder) MaxVideoSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxVideoSize", reflect.TypeOf((*MockVideoRepository)(nil).MaxVideoSize))
+}
+
+// MergeTags mocks base method.
+func (m *MockVideoRepository) MergeTags(arg0 context.Context, arg1, arg2, arg3 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTags", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTags indicates an expected call of MergeTags.
+func (mr *MockVideoRepositoryMockRecorder) MergeTags(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTags", reflect.TypeOf((*MockVideoRepository)(nil).MergeTags), arg0, arg1, arg2, arg3)
+}
+
+// ModerateVideo mocks base method.
+func (m *MockVideoRepository) ModerateVideo(arg0 context.Context, arg1, arg2 string, arg3 domain.ModerationAction, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateVideo", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateVideo indicates an expected call of ModerateVideo.
+func (mr *MockVideoRepositoryMockRecorder) ModerateVideo(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateVideo", reflect.TypeOf((*MockVideoRepository)(nil).ModerateVideo), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PresignVideoDownloadURL mocks base method.
+func (m *MockVideoRepository) PresignVideoDownloadURL(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignVideoDownloadURL", arg0, arg1)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideo indicates an expected call of GetVideo.
-func (mr *MockVideoInputPortMockRecorder) GetVideo(arg0, arg1 interface{}) *gomock.Call {
+// PresignVideoDownloadURL indicates an expected call of PresignVideoDownloadURL.
+func (mr *MockVideoRepositoryMockRecorder) PresignVideoDownloadURL(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideo", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideo), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignVideoDownloadURL", reflect.TypeOf((*MockVideoRepository)(nil).PresignVideoDownloadURL), arg0, arg1)
 }
 
-// GetVideos mocks base method.
-func (m *MockVideoInputPort) GetVideos(arg0 context.Context) ([]*domain.Video, error) {
+// ProbeVideoMetadata mocks base method.
+func (m *MockVideoRepository) ProbeVideoMetadata(arg0 context.Context, arg1 string) (*domain.VideoMetadata, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideos", arg0)
-	ret0, _ := ret[0].([]*domain.Video)
+	ret := m.ctrl.Call(m, "ProbeVideoMetadata", arg0, arg1)
+	ret0, _ := ret[0].(*domain.VideoMetadata)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideos indicates an expected call of GetVideos.
-func (mr *MockVideoInputPortMockRecorder) GetVideos(arg0 interface{}) *gomock.Call {
+// ProbeVideoMetadata indicates an expected call of ProbeVideoMetadata.
+func (mr *MockVideoRepositoryMockRecorder) ProbeVideoMetadata(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideos", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideos), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeVideoMetadata", reflect.TypeOf((*MockVideoRepository)(nil).ProbeVideoMetadata), arg0, arg1)
 }
 
-// GetVideosByUserID mocks base method.
-func (m *MockVideoInputPort) GetVideosByUserID(arg0 context.Context, arg1 string) ([]*domain.Video, error) {
+// RecordThumbnailClick mocks base method.
+func (m *MockVideoRepository) RecordThumbnailClick(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideosByUserID", arg0, arg1)
+	ret := m.ctrl.Call(m, "RecordThumbnailClick", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordThumbnailClick indicates an expected call of RecordThumbnailClick.
+func (mr *MockVideoRepositoryMockRecorder) RecordThumbnailClick(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordThumbnailClick", reflect.TypeOf((*MockVideoRepository)(nil).RecordThumbnailClick), arg0, arg1)
+}
+
+// RecordThumbnailImpression mocks base method.
+func (m *MockVideoRepository) RecordThumbnailImpression(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordThumbnailImpression", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordThumbnailImpression indicates an expected call of RecordThumbnailImpression.
+func (mr *MockVideoRepositoryMockRecorder) RecordThumbnailImpression(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordThumbnailImpression", reflect.TypeOf((*MockVideoRepository)(nil).RecordThumbnailImpression), arg0, arg1)
+}
+
+// RecordWatchDuration mocks base method.
+func (m *MockVideoRepository) RecordWatchDuration(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWatchDuration", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWatchDuration indicates an expected call of RecordWatchDuration.
+func (mr *MockVideoRepositoryMockRecorder) RecordWatchDuration(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWatchDuration", reflect.TypeOf((*MockVideoRepository)(nil).RecordWatchDuration), arg0, arg1, arg2, arg3)
+}
+
+// RegisterWebhookTarget mocks base method.
+func (m *MockVideoRepository) RegisterWebhookTarget(arg0 context.Context, arg1 domain.WebhookTarget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhookTarget", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterWebhookTarget indicates an expected call of RegisterWebhookTarget.
+func (mr *MockVideoRepositoryMockRecorder) RegisterWebhookTarget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhookTarget", reflect.TypeOf((*MockVideoRepository)(nil).RegisterWebhookTarget), arg0, arg1)
+}
+
+// ReleaseUploadRateLimit mocks base method.
+func (m *MockVideoRepository) ReleaseUploadRateLimit(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseUploadRateLimit", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseUploadRateLimit indicates an expected call of ReleaseUploadRateLimit.
+func (mr *MockVideoRepositoryMockRecorder) ReleaseUploadRateLimit(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseUploadRateLimit", reflect.TypeOf((*MockVideoRepository)(nil).ReleaseUploadRateLimit), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RenameTag mocks base method.
+func (m *MockVideoRepository) RenameTag(arg0 context.Context, arg1, arg2, arg3 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTag", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameTag indicates an expected call of RenameTag.
+func (mr *MockVideoRepositoryMockRecorder) RenameTag(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTag", reflect.TypeOf((*MockVideoRepository)(nil).RenameTag), arg0, arg1, arg2, arg3)
+}
+
+// RestoreVideo mocks base method.
+func (m *MockVideoRepository) RestoreVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVideo indicates an expected call of RestoreVideo.
+func (mr *MockVideoRepositoryMockRecorder) RestoreVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVideo", reflect.TypeOf((*MockVideoRepository)(nil).RestoreVideo), arg0, arg1, arg2)
+}
+
+// SearchVideosFromDB mocks base method.
+func (m *MockVideoRepository) SearchVideosFromDB(arg0 context.Context, arg1 string, arg2 domain.SearchOptions, arg3 domain.Page) ([]*domain.Video, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchVideosFromDB", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]*domain.Video)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchVideosFromDB indicates an expected call of SearchVideosFromDB.
+func (mr *MockVideoRepositoryMockRecorder) SearchVideosFromDB(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchVideosFromDB", reflect.TypeOf((*MockVideoRepository)(nil).SearchVideosFromDB), arg0, arg1, arg2, arg3)
+}
+
+// SelectThumbnailForUser mocks base method.
+func (m *MockVideoRepository) SelectThumbnailForUser(arg0 context.Context, arg1, arg2 string) (*domain.ThumbnailVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectThumbnailForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.ThumbnailVariant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideosByUserID indicates an expected call of GetVideosByUserID.
-func (mr *MockVideoInputPortMockRecorder) GetVideosByUserID(arg0, arg1 interface{}) *gomock.Call {
+// SelectThumbnailForUser indicates an expected call of SelectThumbnailForUser.
+func (mr *MockVideoRepositoryMockRecorder) SelectThumbnailForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByUserID", reflect.TypeOf((*MockVideoInputPort)(nil).GetVideosByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectThumbnailForUser", reflect.TypeOf((*MockVideoRepository)(nil).SelectThumbnailForUser), arg0, arg1, arg2)
 }
 
-// UploadVideo mocks base method.
-func (m *MockVideoInputPort) UploadVideo(arg0 context.Context, arg1 *domain.UploadVideo) (*domain.UploadVideoResponse, error) {
+// SetAllVideosByUserPrivacy mocks base method.
+func (m *MockVideoRepository) SetAllVideosByUserPrivacy(arg0 context.Context, arg1 string, arg2 bool, arg3 string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadVideo", arg0, arg1)
-	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret := m.ctrl.Call(m, "SetAllVideosByUserPrivacy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UploadVideo indicates an expected call of UploadVideo.
-func (mr *MockVideoInputPortMockRecorder) UploadVideo(arg0, arg1 interface{}) *gomock.Call {
+// SetAllVideosByUserPrivacy indicates an expected call of SetAllVideosByUserPrivacy.
+func (mr *MockVideoRepositoryMockRecorder) SetAllVideosByUserPrivacy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadVideo", reflect.TypeOf((*MockVideoInputPort)(nil).UploadVideo), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllVideosByUserPrivacy", reflect.TypeOf((*MockVideoRepository)(nil).SetAllVideosByUserPrivacy), arg0, arg1, arg2, arg3)
 }
 
-// MockVideoRepository is a mock of VideoRepository interface.
-type MockVideoRepository struct {
-	ctrl     *gomock.Controller
-	recorder *MockVideoRepositoryMockRecorder
+// SetVideoChapters mocks base method.
+func (m *MockVideoRepository) SetVideoChapters(arg0 context.Context, arg1 string, arg2 []domain.Chapter, arg3 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVideoChapters", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockVideoRepositoryMockRecorder is the mock recorder for MockVideoRepository.
-type MockVideoRepositoryMockRecorder struct {
-	mock *MockVideoRepository
+// SetVideoChapters indicates an expected call of SetVideoChapters.
+func (mr *MockVideoRepositoryMockRecorder) SetVideoChapters(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoChapters", reflect.TypeOf((*MockVideoRepository)(nil).SetVideoChapters), arg0, arg1, arg2, arg3)
 }
 
-// NewMockVideoRepository creates a new mock instance.
-func NewMockVideoRepository(ctrl *gomock.Controller) *MockVideoRepository {
-	mock := &MockVideoRepository{ctrl: ctrl}
-	mock.recorder = &MockVideoRepositoryMockRecorder{mock}
-	return mock
+// SetVideoDescription mocks base method.
+func (m *MockVideoRepository) SetVideoDescription(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVideoDescription", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockVideoRepository) EXPECT() *MockVideoRepositoryMockRecorder {
-	return m.recorder
+// SetVideoDescription indicates an expected call of SetVideoDescription.
+func (mr *MockVideoRepositoryMockRecorder) SetVideoDescription(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoDescription", reflect.TypeOf((*MockVideoRepository)(nil).SetVideoDescription), arg0, arg1, arg2, arg3, arg4)
 }
 
-// ConvertVideoHLS mocks base method.
-func (m *MockVideoRepository) ConvertVideoHLS(arg0 context.Context, arg1 *domain.VideoFile) error {
+// SetVideoRegionRestrictions mocks base method.
+func (m *MockVideoRepository) SetVideoRegionRestrictions(arg0 context.Context, arg1 string, arg2 []string, arg3 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConvertVideoHLS", arg0, arg1)
+	ret := m.ctrl.Call(m, "SetVideoRegionRestrictions", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ConvertVideoHLS indicates an expected call of ConvertVideoHLS.
-func (mr *MockVideoRepositoryMockRecorder) ConvertVideoHLS(arg0, arg1 interface{}) *gomock.Call {
+// SetVideoRegionRestrictions indicates an expected call of SetVideoRegionRestrictions.
+func (mr *MockVideoRepositoryMockRecorder) SetVideoRegionRestrictions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertVideoHLS", reflect.TypeOf((*MockVideoRepository)(nil).ConvertVideoHLS), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVideoRegionRestrictions", reflect.TypeOf((*MockVideoRepository)(nil).SetVideoRegionRestrictions), arg0, arg1, arg2, arg3)
 }
 
-// GetVideoFromDB mocks base method.
-func (m *MockVideoRepository) GetVideoFromDB(arg0 context.Context, arg1 string) (*domain.Video, error) {
+// SoftDeleteVideo mocks base method.
+func (m *MockVideoRepository) SoftDeleteVideo(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideoFromDB", arg0, arg1)
-	ret0, _ := ret[0].(*domain.Video)
+	ret := m.ctrl.Call(m, "SoftDeleteVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteVideo indicates an expected call of SoftDeleteVideo.
+func (mr *MockVideoRepositoryMockRecorder) SoftDeleteVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteVideo", reflect.TypeOf((*MockVideoRepository)(nil).SoftDeleteVideo), arg0, arg1, arg2)
+}
+
+// SplitVideo mocks base method.
+func (m *MockVideoRepository) SplitVideo(arg0 context.Context, arg1 string, arg2 int, arg3 string) ([2]*domain.UploadVideoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitVideo", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([2]*domain.UploadVideoResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideoFromDB indicates an expected call of GetVideoFromDB.
-func (mr *MockVideoRepositoryMockRecorder) GetVideoFromDB(arg0, arg1 interface{}) *gomock.Call {
+// SplitVideo indicates an expected call of SplitVideo.
+func (mr *MockVideoRepositoryMockRecorder) SplitVideo(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideoFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideoFromDB), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitVideo", reflect.TypeOf((*MockVideoRepository)(nil).SplitVideo), arg0, arg1, arg2, arg3)
 }
 
-// GetVideosByUserIDFromDB mocks base method.
-func (m *MockVideoRepository) GetVideosByUserIDFromDB(arg0 context.Context, arg1 string) ([]*domain.Video, error) {
+// SubmitVideoReport mocks base method.
+func (m *MockVideoRepository) SubmitVideoReport(arg0 context.Context, arg1 domain.VideoReport) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideosByUserIDFromDB", arg0, arg1)
-	ret0, _ := ret[0].([]*domain.Video)
+	ret := m.ctrl.Call(m, "SubmitVideoReport", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitVideoReport indicates an expected call of SubmitVideoReport.
+func (mr *MockVideoRepositoryMockRecorder) SubmitVideoReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitVideoReport", reflect.TypeOf((*MockVideoRepository)(nil).SubmitVideoReport), arg0, arg1)
+}
+
+// SubscribeToVideoStatus mocks base method.
+func (m *MockVideoRepository) SubscribeToVideoStatus(arg0 context.Context, arg1, arg2 string) (<-chan domain.ProcessingStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeToVideoStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan domain.ProcessingStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideosByUserIDFromDB indicates an expected call of GetVideosByUserIDFromDB.
-func (mr *MockVideoRepositoryMockRecorder) GetVideosByUserIDFromDB(arg0, arg1 interface{}) *gomock.Call {
+// SubscribeToVideoStatus indicates an expected call of SubscribeToVideoStatus.
+func (mr *MockVideoRepositoryMockRecorder) SubscribeToVideoStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosByUserIDFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosByUserIDFromDB), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeToVideoStatus", reflect.TypeOf((*MockVideoRepository)(nil).SubscribeToVideoStatus), arg0, arg1, arg2)
 }
 
-// GetVideosFromDB mocks base method.
-func (m *MockVideoRepository) GetVideosFromDB(arg0 context.Context) ([]*domain.Video, error) {
+// SuggestTagsForTitle mocks base method.
+func (m *MockVideoRepository) SuggestTagsForTitle(arg0 context.Context, arg1 string, arg2 int) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetVideosFromDB", arg0)
-	ret0, _ := ret[0].([]*domain.Video)
+	ret := m.ctrl.Call(m, "SuggestTagsForTitle", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetVideosFromDB indicates an expected call of GetVideosFromDB.
-func (mr *MockVideoRepositoryMockRecorder) GetVideosFromDB(arg0 interface{}) *gomock.Call {
+// SuggestTagsForTitle indicates an expected call of SuggestTagsForTitle.
+func (mr *MockVideoRepositoryMockRecorder) SuggestTagsForTitle(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideosFromDB", reflect.TypeOf((*MockVideoRepository)(nil).GetVideosFromDB), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestTagsForTitle", reflect.TypeOf((*MockVideoRepository)(nil).SuggestTagsForTitle), arg0, arg1, arg2)
 }
 
-// InsertVideo mocks base method.
-func (m *MockVideoRepository) InsertVideo(arg0 context.Context, arg1, arg2, arg3, arg4 string, arg5 *string, arg6 string) (*domain.UploadVideoResponse, error) {
+// TakeDownloadRateLimit mocks base method.
+func (m *MockVideoRepository) TakeDownloadRateLimit(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertVideo", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
-	ret0, _ := ret[0].(*domain.UploadVideoResponse)
+	ret := m.ctrl.Call(m, "TakeDownloadRateLimit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TakeDownloadRateLimit indicates an expected call of TakeDownloadRateLimit.
+func (mr *MockVideoRepositoryMockRecorder) TakeDownloadRateLimit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeDownloadRateLimit", reflect.TypeOf((*MockVideoRepository)(nil).TakeDownloadRateLimit), arg0, arg1, arg2)
+}
+
+// TakeUploadAPIRateLimit mocks base method.
+func (m *MockVideoRepository) TakeUploadAPIRateLimit(arg0 context.Context, arg1 string) (int, time.Time, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeUploadAPIRateLimit", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(string)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// TakeUploadAPIRateLimit indicates an expected call of TakeUploadAPIRateLimit.
+func (mr *MockVideoRepositoryMockRecorder) TakeUploadAPIRateLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeUploadAPIRateLimit", reflect.TypeOf((*MockVideoRepository)(nil).TakeUploadAPIRateLimit), arg0, arg1)
+}
+
+// TakeUploadIPRateLimit mocks base method.
+func (m *MockVideoRepository) TakeUploadIPRateLimit(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeUploadIPRateLimit", arg0, arg1)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertVideo indicates an expected call of InsertVideo.
-func (mr *MockVideoRepositoryMockRecorder) InsertVideo(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+// TakeUploadIPRateLimit indicates an expected call of TakeUploadIPRateLimit.
+func (mr *MockVideoRepositoryMockRecorder) TakeUploadIPRateLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeUploadIPRateLimit", reflect.TypeOf((*MockVideoRepository)(nil).TakeUploadIPRateLimit), arg0, arg1)
+}
+
+// TransferVideoOwnership mocks base method.
+func (m *MockVideoRepository) TransferVideoOwnership(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferVideoOwnership", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferVideoOwnership indicates an expected call of TransferVideoOwnership.
+func (mr *MockVideoRepositoryMockRecorder) TransferVideoOwnership(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferVideoOwnership", reflect.TypeOf((*MockVideoRepository)(nil).TransferVideoOwnership), arg0, arg1, arg2, arg3)
+}
+
+// UnbookmarkVideo mocks base method.
+func (m *MockVideoRepository) UnbookmarkVideo(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbookmarkVideo", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbookmarkVideo indicates an expected call of UnbookmarkVideo.
+func (mr *MockVideoRepositoryMockRecorder) UnbookmarkVideo(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbookmarkVideo", reflect.TypeOf((*MockVideoRepository)(nil).UnbookmarkVideo), arg0, arg1, arg2)
+}
+
+// UpdateVideo mocks base method.
+func (m *MockVideoRepository) UpdateVideo(arg0 context.Context, arg1, arg2 string, arg3 int, arg4 domain.VideoUpdate) (*domain.Video, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideo", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*domain.Video)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVideo indicates an expected call of UpdateVideo.
+func (mr *MockVideoRepositoryMockRecorder) UpdateVideo(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertVideo", reflect.TypeOf((*MockVideoRepository)(nil).InsertVideo), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideo", reflect.TypeOf((*MockVideoRepository)(nil).UpdateVideo), arg0, arg1, arg2, arg3, arg4)
+}
+
+// UpdateVideoExpiry mocks base method.
+func (m *MockVideoRepository) UpdateVideoExpiry(arg0 context.Context, arg1, arg2 string, arg3 *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideoExpiry", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVideoExpiry indicates an expected call of UpdateVideoExpiry.
+func (mr *MockVideoRepositoryMockRecorder) UpdateVideoExpiry(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideoExpiry", reflect.TypeOf((*MockVideoRepository)(nil).UpdateVideoExpiry), arg0, arg1, arg2, arg3)
+}
+
+// UpdateVideoProcessingStatus mocks base method.
+func (m *MockVideoRepository) UpdateVideoProcessingStatus(arg0 context.Context, arg1 string, arg2 domain.ProcessingStatus, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVideoProcessingStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVideoProcessingStatus indicates an expected call of UpdateVideoProcessingStatus.
+func (mr *MockVideoRepositoryMockRecorder) UpdateVideoProcessingStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVideoProcessingStatus", reflect.TypeOf((*MockVideoRepository)(nil).UpdateVideoProcessingStatus), arg0, arg1, arg2, arg3)
+}
+
+// UploadSubtitleTrack mocks base method.
+func (m *MockVideoRepository) UploadSubtitleTrack(arg0 context.Context, arg1, arg2, arg3 string, arg4 io.Reader, arg5 string) (*domain.SubtitleTrack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadSubtitleTrack", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*domain.SubtitleTrack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadSubtitleTrack indicates an expected call of UploadSubtitleTrack.
+func (mr *MockVideoRepositoryMockRecorder) UploadSubtitleTrack(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadSubtitleTrack", reflect.TypeOf((*MockVideoRepository)(nil).UploadSubtitleTrack), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // UploadVideoForStorage mocks base method.
@@ -206,3 +2458,76 @@ func (mr *MockVideoRepositoryMockRecorder) UploadVideoForStorage(arg0, arg1 inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadVideoForStorage", reflect.TypeOf((*MockVideoRepository)(nil).UploadVideoForStorage), arg0, arg1)
 }
+
+// UploadWatermark mocks base method.
+func (m *MockVideoRepository) UploadWatermark(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadWatermark", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadWatermark indicates an expected call of UploadWatermark.
+func (mr *MockVideoRepositoryMockRecorder) UploadWatermark(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadWatermark", reflect.TypeOf((*MockVideoRepository)(nil).UploadWatermark), arg0, arg1, arg2)
+}
+
+// UpsertReaction mocks base method.
+func (m *MockVideoRepository) UpsertReaction(arg0 context.Context, arg1, arg2 string, arg3 domain.ReactionType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReaction", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReaction indicates an expected call of UpsertReaction.
+func (mr *MockVideoRepositoryMockRecorder) UpsertReaction(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReaction", reflect.TypeOf((*MockVideoRepository)(nil).UpsertReaction), arg0, arg1, arg2, arg3)
+}
+
+// ValidateVideoSize mocks base method.
+func (m *MockVideoRepository) ValidateVideoSize(arg0 context.Context, arg1 io.Reader, arg2 int64) (io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateVideoSize", arg0, arg1, arg2)
+	ret0, _ := ret[0].(io.Reader)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateVideoSize indicates an expected call of ValidateVideoSize.
+func (mr *MockVideoRepositoryMockRecorder) ValidateVideoSize(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateVideoSize", reflect.TypeOf((*MockVideoRepository)(nil).ValidateVideoSize), arg0, arg1, arg2)
+}
+
+// ValidationVideo mocks base method.
+func (m *MockVideoRepository) ValidationVideo(arg0 io.ReadSeeker) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidationVideo", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidationVideo indicates an expected call of ValidationVideo.
+func (mr *MockVideoRepositoryMockRecorder) ValidationVideo(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationVideo", reflect.TypeOf((*MockVideoRepository)(nil).ValidationVideo), arg0)
+}
+
+// VideoDurationRange mocks base method.
+func (m *MockVideoRepository) VideoDurationRange() (time.Duration, time.Duration) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VideoDurationRange")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(time.Duration)
+	return ret0, ret1
+}
+
+// VideoDurationRange indicates an expected call of VideoDurationRange.
+func (mr *MockVideoRepositoryMockRecorder) VideoDurationRange() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VideoDurationRange", reflect.TypeOf((*MockVideoRepository)(nil).VideoDurationRange))
+}